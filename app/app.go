@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	stdhtml "html"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -21,8 +22,10 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
 )
 
 const (
@@ -40,7 +43,8 @@ const (
 )
 
 var (
-	matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
+	// a word after a dot starts a nested field name (e.g. "Links[0].Title"), not a new word
+	matchFirstCap = regexp.MustCompile("([^.])([A-Z][a-z]+)")
 	matchAllCap   = regexp.MustCompile("([a-z0-9])([A-Z])")
 )
 
@@ -194,6 +198,37 @@ func MarkdownToHTML(in string) (out string, err error) {
 	return
 }
 
+// MarkdownLinks returns the destinations of the links in the Markdown, in order of appearance,
+// including the URLs that are linked automatically. Links in raw HTML are not included.
+func MarkdownLinks(in string) (links []string) {
+	src := []byte(in)
+	doc := mdRenderer.Parser().Parse(text.NewReader(src))
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch l := n.(type) {
+		case *ast.Link:
+			links = append(links, string(l.Destination))
+		case *ast.AutoLink:
+			links = append(links, string(l.URL(src)))
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return links
+}
+
+// PlainText strips the markup of the HTML, leaving only its text.
+func PlainText(html string) string {
+	text := bluemonday.StrictPolicy().Sanitize(html)
+
+	return strings.TrimSpace(stdhtml.UnescapeString(text))
+}
+
 // HumanTime formats a timestamp into a human-readable relative time string.
 //
 // The function compares the given time with the current time and returns
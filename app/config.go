@@ -1,11 +1,17 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/gopl-dev/server/logging"
 	"gopkg.in/yaml.v3"
 )
 
@@ -26,23 +32,32 @@ type ConfigT struct {
 		APIBasePath   string `yaml:"api_base_path"`
 		Addr          string `yaml:"addr"`
 		AutocertHosts string `yaml:"autocert_hosts"`
+		// ShutdownDrainSeconds is how long readiness fails before the server stops accepting connections.
+		ShutdownDrainSeconds int `yaml:"shutdown_drain_seconds"`
 	} `yaml:"server"`
 
 	DB struct {
 		Host       string `yaml:"host"`
 		Port       string `yaml:"port"`
 		User       string `yaml:"user"`
-		Password   string `yaml:"password"` //nolint:gosec
+		Password   string `yaml:"password" secret:"true"` //nolint:gosec
 		Name       string `yaml:"name"`
 		LogQueries bool   `yaml:"log_queries"`
 	} `yaml:"db"`
 
+	Log struct {
+		// debug | info | warn | error
+		Level string `yaml:"level"`
+		// text | json
+		Format string `yaml:"format"`
+	} `yaml:"log"`
+
 	Tracing struct {
 		Enabled bool `yaml:"enabled"`
 		// uptrace | log
 		Driver string `yaml:"driver"`
 		// https://uptrace.dev/
-		UptraceDSN string `yaml:"uptrace_dsn"`
+		UptraceDSN string `yaml:"uptrace_dsn" secret:"true"`
 	} `yaml:"tracing"`
 
 	Files struct {
@@ -65,6 +80,21 @@ type ConfigT struct {
 		} `yaml:"books"`
 	} `yaml:"entities"`
 
+	// BookMetadata configures the lookup of book metadata (title, authors, cover, etc.) by ISBN.
+	BookMetadata struct {
+		Enabled bool `yaml:"enabled"`
+		// Driver can be: openlibrary or fixture
+		Driver         string `yaml:"driver"`
+		TimeoutSeconds int    `yaml:"timeout_seconds"`
+		OpenLibrary    struct {
+			BaseURL string `yaml:"base_url"`
+		} `yaml:"open_library"`
+		Fixture struct {
+			// Path of the JSON file with metadata by ISBN. If empty, the built-in fixtures are used.
+			Path string `yaml:"path"`
+		} `yaml:"fixture"`
+	} `yaml:"book_metadata"`
+
 	Email struct {
 		// Driver can be: smtp or test
 		Driver string `yaml:"driver"`
@@ -73,35 +103,71 @@ type ConfigT struct {
 			Host     string `yaml:"host"`
 			Port     int    `yaml:"port"`
 			Username string `yaml:"username"`
-			Password string `yaml:"password"` //nolint:gosec
+			Password string `yaml:"password" secret:"true"` //nolint:gosec
 		} `yaml:"smtp"`
 	} `yaml:"email"`
 
 	Session struct {
 		DurationHours int    `yaml:"duration_hours"`
-		Key           string `yaml:"key"`
+		Key           string `yaml:"key" secret:"true"`
 	} `yaml:"session"`
 
+	RateLimit struct {
+		Enabled bool `yaml:"enabled"`
+		// Driver can be: postgres or memory
+		Driver string `yaml:"driver"`
+		// ClientIPHeader is the header set by a reverse proxy that holds the client IP (e.g. X-Real-IP).
+		// If empty, the remote address of the connection is used.
+		ClientIPHeader string `yaml:"client_ip_header"`
+		// LockoutAttempts is the number of failed sign-in attempts after which the account is locked.
+		LockoutAttempts int `yaml:"lockout_attempts"`
+		// LockoutMinutes is both the window in which failed attempts are counted and the lock duration.
+		LockoutMinutes int `yaml:"lockout_minutes"`
+	} `yaml:"rate_limit"`
+
+	// Metrics are served in the Prometheus format on a separate listener,
+	// so they are not exposed on the public address.
+	Metrics struct {
+		Enabled bool   `yaml:"enabled"`
+		Host    string `yaml:"host"`
+		Port    string `yaml:"port"`
+	} `yaml:"metrics"`
+
 	OpenAPI struct {
 		Enabled   bool   `yaml:"enabled"`
 		ServePath string `yaml:"serve_path"`
 	} `yaml:"openapi"`
 
-	GoogleOAuth struct {
-		ClientID     string `yaml:"client_id"`
-		ClientSecret string `yaml:"client_secret"` //nolint:gosec
-	} `yaml:"google_oauth"`
+	// OAuth maps provider type (google, github, gitlab, bitbucket, oidc) to its settings.
+	// Only providers listed here with a client ID are enabled.
+	OAuth map[string]OAuthProviderConfig `yaml:"oauth"`
 
-	GithubOAuth struct {
-		ClientID     string `yaml:"client_id"`
-		ClientSecret string `yaml:"client_secret"` //nolint:gosec
-	} `yaml:"github_oauth"`
+	// Deprecated: use oauth.google and oauth.github.
+	// The settings are still read into OAuth by ApplyDeprecated, with a warning.
+	GoogleOAuth OAuthProviderConfig `yaml:"google_oauth"`
+	GithubOAuth OAuthProviderConfig `yaml:"github_oauth"`
 
 	// Admins is a list of user IDs with administrative privileges.
 	// This is a temporary solution until  ACL is implemented.
 	Admins []string `yaml:"admins"`
 }
 
+// OAuthProviderConfig holds the settings of a single OAuth provider.
+type OAuthProviderConfig struct {
+	// Name is the display name used on sign-in buttons; defaults to the provider name.
+	Name         string   `yaml:"name"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret" secret:"true"` //nolint:gosec
+	Scopes       []string `yaml:"scopes"`
+	// DiscoveryURL is the OpenID Connect discovery document URL. Used by "oidc" only.
+	DiscoveryURL string `yaml:"discovery_url"`
+}
+
+// Enabled reports whether the provider is configured.
+func (c OAuthProviderConfig) Enabled() bool {
+	return c.ClientID != ""
+}
+
 // IsDevEnv returns true if the application environment is set to dev.
 func (c *ConfigT) IsDevEnv() bool {
 	return c.App.Env == DevEnv
@@ -125,11 +191,13 @@ func (c *ConfigT) TracingDisabled() bool {
 var loadConfigOnce sync.Once
 var conf *ConfigT
 
-// Config returns config from .config.yaml.
+// Config returns config from .config.yaml (or the file set by GOPL_CONFIG_FILE)
+// with the overrides from environment variables, see ConfigEnvPrefix.
+// It panics if the config is invalid, listing all invalid settings.
 func Config() *ConfigT {
 	loadConfigOnce.Do(func() {
 		var err error
-		conf, err = ConfigFromFile(defaultConfigFile)
+		conf, err = LoadConfig()
 		if err != nil {
 			panic(err)
 		}
@@ -143,6 +211,152 @@ func Config() *ConfigT {
 	return conf
 }
 
+// LoadConfig reads the config file, applies the environment overrides and validates the result.
+// The default config file may be missing, so the config can be set by environment variables only.
+func LoadConfig() (*ConfigT, error) {
+	filename, custom := os.LookupEnv(ConfigFileEnv)
+	if !custom {
+		filename = defaultConfigFile
+	}
+
+	c, err := ConfigFromFile(filename)
+	if errors.Is(err, os.ErrNotExist) && !custom {
+		c, err = new(ConfigT), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = ApplyEnv(c, os.Environ())
+	for _, w := range c.ApplyDeprecated() {
+		slog.Warn("deprecated config", "warning", w)
+	}
+
+	err = errors.Join(err, c.Validate())
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n%w", ErrInvalidConfig, err)
+	}
+
+	return c, nil
+}
+
+// ApplyDeprecated moves the settings of deprecated keys to their replacements
+// and returns a warning for each deprecated key in use.
+// The replacement wins if both are set.
+func (c *ConfigT) ApplyDeprecated() (warnings []string) {
+	legacy := []struct {
+		key      string
+		provider string
+		conf     OAuthProviderConfig
+	}{
+		{"google_oauth", "google", c.GoogleOAuth},
+		{"github_oauth", "github", c.GithubOAuth},
+	}
+
+	for _, l := range legacy {
+		if !l.conf.Enabled() {
+			continue
+		}
+
+		if c.OAuth[l.provider].Enabled() {
+			warnings = append(warnings, fmt.Sprintf("%q is deprecated and ignored as \"oauth.%s\" is set, remove it",
+				l.key, l.provider))
+			continue
+		}
+
+		if c.OAuth == nil {
+			c.OAuth = map[string]OAuthProviderConfig{}
+		}
+		c.OAuth[l.provider] = l.conf
+		warnings = append(warnings, fmt.Sprintf("%q is deprecated, move its settings to \"oauth.%s\"",
+			l.key, l.provider))
+	}
+
+	return warnings
+}
+
+// ErrInvalidConfig is returned by LoadConfig, wrapping the list of invalid settings.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Validate checks that required settings are set and the values are supported.
+// All problems are reported at once.
+func (c *ConfigT) Validate() error {
+	var errs []error
+	check := func(ok bool, path, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{path}, args...)...))
+		}
+	}
+	required := func(val, path string) {
+		check(strings.TrimSpace(val) != "", path, "required")
+	}
+	oneOf := func(val, path string, allowed ...string) {
+		check(slices.Contains(allowed, val), path, "%q is not one of %s", val, strings.Join(allowed, ", "))
+	}
+
+	oneOf(c.App.Env, "app.env", DevEnv, TestEnv, StagingEnv, ProductionEnv)
+
+	required(c.Server.Port, "server.port")
+	required(c.Server.Addr, "server.addr")
+	if c.Server.Addr != "" {
+		u, err := url.Parse(c.Server.Addr)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"server.addr", "expected absolute http(s) URL, got %q", c.Server.Addr)
+	}
+	check(c.Server.ShutdownDrainSeconds >= 0, "server.shutdown_drain_seconds", "must not be negative")
+
+	required(c.DB.Host, "db.host")
+	required(c.DB.Port, "db.port")
+	required(c.DB.User, "db.user")
+	required(c.DB.Name, "db.name")
+
+	_, err := logging.NewHandler(io.Discard, c.Log.Level, c.Log.Format)
+	check(err == nil, "log", "%v", err)
+
+	if c.Tracing.Enabled {
+		oneOf(c.Tracing.Driver, "tracing.driver", "uptrace", "log")
+		if c.Tracing.Driver == "uptrace" {
+			required(c.Tracing.UptraceDSN, "tracing.uptrace_dsn")
+		}
+	}
+
+	if c.Metrics.Enabled {
+		required(c.Metrics.Port, "metrics.port")
+	}
+
+	oneOf(c.Files.StorageDriver, "files.storage_driver", "local-fs", "in-memory-fs")
+	if c.Files.StorageDriver == "local-fs" {
+		required(c.Files.LocalFS.StoragePath, "files.local_fs.storage_path")
+	}
+
+	if c.BookMetadata.Enabled {
+		oneOf(c.BookMetadata.Driver, "book_metadata.driver", "openlibrary", "fixture")
+		check(c.BookMetadata.TimeoutSeconds >= 0, "book_metadata.timeout_seconds", "must not be negative")
+	}
+
+	oneOf(c.Email.Driver, "email.driver", "smtp", "test")
+	if c.Email.Driver == "smtp" {
+		required(c.Email.From, "email.from")
+		required(c.Email.SMTP.Host, "email.smtp.host")
+		check(c.Email.SMTP.Port > 0, "email.smtp.port", "required")
+	}
+
+	required(c.Session.Key, "session.key")
+	check(c.Session.DurationHours > 0, "session.duration_hours", "must be positive")
+
+	if c.RateLimit.Enabled {
+		oneOf(c.RateLimit.Driver, "rate_limit.driver", "postgres", "memory")
+	}
+
+	for name, p := range c.OAuth {
+		if p.Enabled() {
+			required(p.ClientSecret, "oauth."+name+".client_secret")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // ConfigFromFile returns new config from given YAML file.
 func ConfigFromFile(filename string) (*ConfigT, error) {
 	data, err := os.ReadFile(filename) //nolint:gosec
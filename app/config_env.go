@@ -0,0 +1,243 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ConfigEnvPrefix is the prefix of environment variables overriding the config.
+	// The name of a variable is the prefix followed by the YAML path of the setting
+	// in upper case, joined by "_": "db.password" is overridden by GOPL_DB_PASSWORD.
+	// Settings of the map-based sections include the key: GOPL_OAUTH_GITHUB_CLIENT_ID.
+	ConfigEnvPrefix = "GOPL"
+
+	// ConfigEnvFileSuffix is the suffix of the variable that holds the path of a file
+	// with the value instead of the value itself (e.g. a mounted secret): GOPL_DB_PASSWORD_FILE.
+	// The trailing newline of the file is trimmed.
+	ConfigEnvFileSuffix = "_FILE"
+
+	// ConfigFileEnv is the variable that holds the path of the config file.
+	ConfigFileEnv = ConfigEnvPrefix + "_CONFIG_FILE"
+
+	// RedactedValue replaces non-empty secrets in the printed config.
+	RedactedValue = "[REDACTED]"
+)
+
+// ErrInvalidEnvValue indicates that the value of an environment variable can't be used for the setting.
+var ErrInvalidEnvValue = errors.New("invalid value")
+
+// ApplyEnv overrides the settings of c with the environment variables, see ConfigEnvPrefix.
+// Environment is given as "KEY=value" pairs, as returned by os.Environ.
+// All invalid variables are reported at once.
+func ApplyEnv(c *ConfigT, environ []string) error {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(k, ConfigEnvPrefix+"_") {
+			env[k] = v
+		}
+	}
+
+	var errs []error
+	walkConfig(reflect.ValueOf(c).Elem(), []string{ConfigEnvPrefix}, func(name string, _ reflect.StructField, v reflect.Value) {
+		err := applyEnvValue(env, name, v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}, func(name string, m reflect.Value) {
+		errs = append(errs, applyEnvToMap(env, name, m)...)
+	})
+
+	return errors.Join(errs...)
+}
+
+// applyEnvValue sets the setting from the variable, or from the file of its "_FILE" variant.
+// The setting is left as is if neither is set.
+func applyEnvValue(env map[string]string, name string, v reflect.Value) error {
+	if path, ok := env[name+ConfigEnvFileSuffix]; ok {
+		data, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", name, ConfigEnvFileSuffix, err)
+		}
+
+		err = setConfigValue(v, strings.TrimRight(string(data), "\r\n"))
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", name, ConfigEnvFileSuffix, err)
+		}
+
+		return nil
+	}
+
+	val, ok := env[name]
+	if !ok {
+		return nil
+	}
+
+	err := setConfigValue(v, val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+// applyEnvToMap overrides the map of structs (e.g. OAuth providers) from variables named
+// PREFIX_<KEY>_<FIELD>. Entries missing in the map are created.
+func applyEnvToMap(env map[string]string, name string, m reflect.Value) (errs []error) {
+	elemT := m.Type().Elem()
+	fields := make([]string, 0, elemT.NumField())
+	for i := range elemT.NumField() {
+		fields = append(fields, envName(elemT.Field(i)))
+	}
+
+	// "CLIENT_SECRET" must be matched before "SECRET" (if any), so longer names go first
+	sort.Slice(fields, func(i, j int) bool { return len(fields[i]) > len(fields[j]) })
+
+	keys := map[string]bool{}
+	for k := range env {
+		k = strings.TrimSuffix(k, ConfigEnvFileSuffix)
+		rest, ok := strings.CutPrefix(k, name+"_")
+		if !ok {
+			continue
+		}
+		for _, f := range fields {
+			key, ok := strings.CutSuffix(rest, "_"+f)
+			if ok && key != "" {
+				keys[strings.ToLower(key)] = true
+				break
+			}
+		}
+	}
+
+	if len(keys) > 0 && m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	for key := range keys {
+		elem := reflect.New(elemT).Elem()
+		if existing := m.MapIndex(reflect.ValueOf(key)); existing.IsValid() {
+			elem.Set(existing)
+		}
+
+		prefix := name + "_" + strings.ToUpper(key)
+		walkConfig(elem, []string{prefix}, func(name string, _ reflect.StructField, v reflect.Value) {
+			err := applyEnvValue(env, name, v)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}, nil)
+
+		m.SetMapIndex(reflect.ValueOf(key), elem)
+	}
+
+	return errs
+}
+
+// setConfigValue parses the string into the setting of a supported kind.
+// Lists are comma-separated.
+func setConfigValue(v reflect.Value, val string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("%w: expected bool, got %q", ErrInvalidEnvValue, val)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: expected integer, got %q", ErrInvalidEnvValue, val)
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		items := []string{}
+		for item := range strings.SplitSeq(val, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%w: unsupported setting type %s", ErrInvalidEnvValue, v.Type())
+	}
+
+	return nil
+}
+
+// walkConfig calls leaf for every setting of the struct, and mapFn for every map of structs.
+// Names are built from the YAML tags: DB.Password of the prefix "GOPL" is "GOPL_DB_PASSWORD".
+func walkConfig(v reflect.Value, path []string,
+	leaf func(name string, f reflect.StructField, v reflect.Value),
+	mapFn func(name string, m reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], envName(f))
+		name := strings.Join(fieldPath, "_")
+		fv := v.Field(i)
+
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			walkConfig(fv, fieldPath, leaf, mapFn)
+		case f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Struct:
+			if mapFn != nil {
+				mapFn(name, fv)
+			}
+		default:
+			leaf(name, f, fv)
+		}
+	}
+}
+
+// envName returns the name of the field used in the variable names.
+func envName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "" {
+		name = CamelCaseToSnakeCase(f.Name)
+	}
+
+	return strings.ToUpper(name)
+}
+
+// Redacted returns a copy of the config with the settings tagged `secret:"true"` replaced
+// by RedactedValue, so the config can be printed.
+func (c *ConfigT) Redacted() *ConfigT {
+	cp := *c
+	cp.Admins = append([]string(nil), c.Admins...)
+
+	redact := func(_ string, f reflect.StructField, v reflect.Value) {
+		if f.Tag.Get("secret") == "true" && v.Kind() == reflect.String && v.String() != "" {
+			v.SetString(RedactedValue)
+		}
+	}
+
+	walkConfig(reflect.ValueOf(&cp).Elem(), nil, redact, func(_ string, m reflect.Value) {
+		if m.IsNil() {
+			return
+		}
+
+		redacted := reflect.MakeMap(m.Type())
+		for _, key := range m.MapKeys() {
+			elem := reflect.New(m.Type().Elem()).Elem()
+			elem.Set(m.MapIndex(key))
+			walkConfig(elem, nil, redact, nil)
+			redacted.SetMapIndex(key, elem)
+		}
+		m.Set(redacted)
+	})
+
+	return &cp
+}
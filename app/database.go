@@ -2,32 +2,18 @@ package app
 
 import (
 	"context"
-	"embed"
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/url"
-	"path"
 	"regexp"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var (
-	// ErrNoFilenameSeparator indicates a migration filename is missing the required '_' separator.
-	ErrNoFilenameSeparator = errors.New("no required filename separator '_' found")
-	// ErrMultipleSameVersion indicates two or more migration files share the same version number.
-	ErrMultipleSameVersion = errors.New("multiple migrations of same version found")
-)
-
 // DB wraps the pgxpool.Pool.
 type DB struct {
 	*pgxpool.Pool
@@ -43,9 +29,7 @@ func NewDB(ctx context.Context) (db *DB, err error) {
 		return nil, err
 	}
 
-	// For now, I just print logs
-	// TODO: make proper logging
-	conf.ConnConfig.Tracer = NewLoggingQueryTracer()
+	conf.ConnConfig.Tracer = NewLoggingQueryTracer(c.LogQueries)
 
 	pool, err := pgxpool.NewWithConfig(ctx, conf)
 	if err != nil {
@@ -64,159 +48,6 @@ func NewDB(ctx context.Context) (db *DB, err error) {
 	return db, err
 }
 
-//go:embed db_migrations/*.sql
-var mgFiles embed.FS
-
-const (
-	mgTable = "db_migrations"
-	mgDir   = "db_migrations"
-)
-
-type migration struct {
-	Version    int64
-	Name       string
-	SQL        string
-	MigratedAt *time.Time
-}
-
-// MigrateDB runs SQL scripts from the './migrations' directory that haven't been committed yet.
-// It reads migration files, compares them with the migrations already applied to the database,
-// and executes the new migrations in a transaction.
-func MigrateDB(ctx context.Context, db *DB) (err error) {
-	defer func() {
-		if err != nil {
-			err = fmt.Errorf("[ERROR] [MIGRATE]: %w", err)
-		}
-	}()
-
-	allMg := make([]migration, 0)
-	completedMg := make([]migration, 0)
-	newMg := make([]migration, 0)
-
-	dir, err := mgFiles.ReadDir(mgDir)
-	if err != nil {
-		return fmt.Errorf("read migrations: %w", err)
-	}
-
-	for _, file := range dir {
-		mgData, err := mgFiles.ReadFile(path.Join(mgDir, file.Name()))
-		if err != nil {
-			return fmt.Errorf("read '%s': %w", file.Name(), err)
-		}
-
-		name := strings.TrimSuffix(file.Name(), ".sql")
-
-		idx := strings.Index(name, "_")
-		if idx < 0 {
-			return fmt.Errorf("filename %s: %w", name, ErrNoFilenameSeparator)
-		}
-
-		version, err := strconv.ParseInt(name[:idx], 10, 64)
-		if err != nil {
-			return fmt.Errorf("parse version from migration file: %s: %w", name, err)
-		}
-
-		name = name[idx+1:]
-
-		for _, m := range allMg {
-			if m.Version == version {
-				return fmt.Errorf("version '%d': %w", version, ErrMultipleSameVersion)
-			}
-		}
-
-		allMg = append(allMg, migration{
-			Version:    version,
-			Name:       name,
-			SQL:        string(mgData),
-			MigratedAt: nil,
-		})
-	}
-
-	if len(allMg) == 0 {
-		fmt.Println("no migrations found in " + mgDir)
-
-		return
-	}
-
-selectAll:
-	err = pgxscan.Select(ctx, db, &completedMg, `SELECT * FROM `+mgTable)
-
-	if err != nil {
-		// on clean db run migrations table not exists yet
-		// check this by code returned and table name
-		// if so, create table and retry
-		pgErr, ok := errors.AsType[*pgconn.PgError](err)
-		if ok && pgErr.Code == "42P01" && strings.Contains(err.Error(), mgTable) {
-			_, err = db.Exec(ctx, `
-       CREATE TABLE `+mgTable+` (
-          version    BIGINT NOT NULL PRIMARY KEY,
-          name       TEXT NOT NULL,
-          migrated_at TIMESTAMPTZ NOT NULL
-       );`)
-			if err != nil {
-				return fmt.Errorf("init migrations table: %w", err)
-			}
-
-			goto selectAll
-		}
-
-		return err
-	}
-
-	if err != nil {
-		return fmt.Errorf("load completed migrations: %w", err)
-	}
-
-iterate:
-	for _, m := range allMg {
-		for _, c := range completedMg {
-			if m.Version == c.Version {
-				continue iterate
-			}
-		}
-
-		newMg = append(newMg, m)
-	}
-
-	if len(newMg) == 0 {
-		fmt.Println("[MIGRATION] ✅ Nothing to migrate")
-
-		return
-	}
-
-	sort.Slice(newMg, func(i, j int) bool {
-		return newMg[i].Version < newMg[j].Version
-	})
-
-	for _, m := range newMg {
-		err = RunInTx(ctx, db, func(ctx context.Context, tx pgx.Tx) error {
-			now := time.Now()
-
-			_, err = tx.Exec(ctx, m.SQL)
-			if err != nil {
-				return fmt.Errorf("❌ %d %s: %w", m.Version, m.Name, err)
-			}
-
-			m.MigratedAt = &now
-
-			_, err = tx.Exec(ctx, "INSERT INTO "+mgTable+" (version, name, migrated_at) VALUES ($1, $2, $3)",
-				m.Version, m.Name, m.MigratedAt)
-			if err != nil {
-				return fmt.Errorf("❌ save migration %d %s: %w", m.Version, m.Name, err)
-			}
-
-			fmt.Printf("[MIGRATION] %d\n✅ %s\n%s\n", m.Version, m.Name, time.Since(now).String())
-
-			return nil
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // RunInTx executes a function within transaction.
 func RunInTx(ctx context.Context, db *DB,
 	f func(ctx context.Context, tx pgx.Tx) error) (err error) {
@@ -302,12 +133,15 @@ type queryTraceData struct {
 	StartTime time.Time
 }
 
-// LoggingQueryTracer ...
-type LoggingQueryTracer struct{}
+// LoggingQueryTracer logs executed queries with their duration.
+// Failed queries are always logged, successful ones only if logQueries is set.
+type LoggingQueryTracer struct {
+	logQueries bool
+}
 
 // NewLoggingQueryTracer creates and returns a new LoggingQueryTracer instance.
-func NewLoggingQueryTracer() *LoggingQueryTracer {
-	return &LoggingQueryTracer{}
+func NewLoggingQueryTracer(logQueries bool) *LoggingQueryTracer {
+	return &LoggingQueryTracer{logQueries: logQueries}
 }
 
 // TraceQueryStart is called before a query is sent to the database.
@@ -323,7 +157,6 @@ func (l *LoggingQueryTracer) TraceQueryStart(
 		StartTime: time.Now(),
 	}
 
-	// Помещаем структуру в контекст и возвращаем новый контекст.
 	return context.WithValue(ctx, queryTraceKey{}, traceData)
 }
 
@@ -333,17 +166,21 @@ func (l *LoggingQueryTracer) TraceQueryStart(
 func (l *LoggingQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, endData pgx.TraceQueryEndData) {
 	data, ok := ctx.Value(queryTraceKey{}).(queryTraceData)
 	if !ok {
-		log.Println("[ERROR] TraceQueryEnd: could not retrieve trace data from context")
+		slog.ErrorContext(ctx, "TraceQueryEnd: could not retrieve trace data from context")
 		return
 	}
 
-	dur := time.Since(data.StartTime)
-	sql := interpolateSQL(data.SQL, data.Args)
-
 	if endData.Err != nil {
-		log.Printf("[%s] %s;\n[ERROR] %s", dur, sql, endData.Err)
+		slog.ErrorContext(ctx, "query failed",
+			"sql", interpolateSQL(data.SQL, data.Args),
+			"duration", time.Since(data.StartTime),
+			"error", endData.Err)
 		return
 	}
 
-	log.Printf("[%s] %s;\n", dur, sql)
+	if l.logQueries {
+		slog.InfoContext(ctx, "query",
+			"sql", interpolateSQL(data.SQL, data.Args),
+			"duration", time.Since(data.StartTime))
+	}
 }
@@ -0,0 +1,7 @@
+CREATE TABLE rate_limit_hits
+(
+    key    TEXT        NOT NULL,
+    hit_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_hits_key_hit_at ON rate_limit_hits (key, hit_at);
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN bio                  TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN avatar_file_id       uuid REFERENCES files (id),
+    ADD COLUMN links                JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN show_books           BOOL  NOT NULL DEFAULT true,
+    ADD COLUMN show_pages           BOOL  NOT NULL DEFAULT true,
+    ADD COLUMN show_change_requests BOOL  NOT NULL DEFAULT true,
+    ADD COLUMN show_activity        BOOL  NOT NULL DEFAULT true;
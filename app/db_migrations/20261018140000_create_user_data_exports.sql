@@ -0,0 +1,16 @@
+CREATE TABLE user_data_exports
+(
+    id           UUID PRIMARY KEY NOT NULL,
+    user_id      UUID             NOT NULL REFERENCES users (id),
+    -- pending | ready | failed
+    status       TEXT             NOT NULL,
+    token        TEXT             NOT NULL UNIQUE,
+    path         TEXT             NOT NULL DEFAULT '',
+    size         BIGINT           NOT NULL DEFAULT 0,
+    error        TEXT             NOT NULL DEFAULT '',
+    expires_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ      NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_user_data_exports_status ON user_data_exports (status);
@@ -0,0 +1,2 @@
+ALTER TABLE entities
+    ADD COLUMN published_at TIMESTAMPTZ;
@@ -0,0 +1,10 @@
+-- +notransaction
+-- approval time of existing entities is unknown, creation time is the closest guess
+UPDATE entities
+SET published_at = created_at
+WHERE status = 'approved' AND published_at IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_entities_type_published_at ON entities (type, published_at DESC);
+
+-- +down
+DROP INDEX IF EXISTS idx_entities_type_published_at;
@@ -0,0 +1,36 @@
+CREATE TABLE webhooks
+(
+    id          UUID PRIMARY KEY NOT NULL,
+    url         TEXT             NOT NULL,
+    secret      TEXT             NOT NULL,
+    -- event log types the webhook is subscribed to; empty means all
+    event_types TEXT[]           NOT NULL DEFAULT '{}',
+    description TEXT             NOT NULL DEFAULT '',
+    active      BOOLEAN          NOT NULL DEFAULT TRUE,
+    created_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ      NOT NULL,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+
+CREATE TABLE webhook_deliveries
+(
+    id              UUID PRIMARY KEY NOT NULL,
+    webhook_id      UUID             NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_log_id    UUID REFERENCES event_logs (id) ON DELETE SET NULL,
+    event_type      TEXT             NOT NULL,
+    payload         JSONB            NOT NULL,
+    -- pending | succeeded | failed
+    status          TEXT             NOT NULL,
+    attempts        INT              NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    response_status INT              NOT NULL DEFAULT 0,
+    response_body   TEXT             NOT NULL DEFAULT '',
+    error           TEXT             NOT NULL DEFAULT '',
+    duration_ms     INT              NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ      NOT NULL,
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
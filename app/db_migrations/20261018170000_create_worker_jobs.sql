@@ -0,0 +1,27 @@
+CREATE TABLE worker_jobs
+(
+    name             TEXT PRIMARY KEY NOT NULL,
+    paused           BOOLEAN          NOT NULL DEFAULT FALSE,
+    -- set when a run is requested manually, cleared once the worker picks it up
+    run_requested_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ      NOT NULL,
+    updated_at       TIMESTAMPTZ
+);
+
+CREATE TABLE worker_job_runs
+(
+    id              UUID PRIMARY KEY NOT NULL,
+    job_name        TEXT             NOT NULL,
+    -- schedule | manual | cli
+    trigger         TEXT             NOT NULL,
+    -- running | succeeded | failed
+    status          TEXT             NOT NULL,
+    items_processed INT              NOT NULL DEFAULT 0,
+    error           TEXT             NOT NULL DEFAULT '',
+    duration_ms     INT              NOT NULL DEFAULT 0,
+    started_at      TIMESTAMPTZ      NOT NULL,
+    finished_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_worker_job_runs_job_name ON worker_job_runs (job_name, started_at DESC);
+CREATE INDEX idx_worker_job_runs_started_at ON worker_job_runs (started_at);
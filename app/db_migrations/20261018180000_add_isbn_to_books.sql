@@ -0,0 +1,8 @@
+ALTER TABLE books
+    ADD COLUMN isbn10 TEXT NOT NULL DEFAULT '',
+    ADD COLUMN isbn13 TEXT NOT NULL DEFAULT '';
+
+-- +down
+ALTER TABLE books
+    DROP COLUMN isbn10,
+    DROP COLUMN isbn13;
@@ -0,0 +1,19 @@
+CREATE TABLE book_editions
+(
+    id           uuid PRIMARY KEY NOT NULL,
+    book_id      uuid             NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    position     INT              NOT NULL,
+    number       INT              NOT NULL,
+    go_version   TEXT             NOT NULL DEFAULT '',
+    publisher    TEXT             NOT NULL DEFAULT '',
+    isbn10       TEXT             NOT NULL DEFAULT '',
+    isbn13       TEXT             NOT NULL DEFAULT '',
+    page_count   INT              NOT NULL DEFAULT 0,
+    release_date TEXT             NOT NULL DEFAULT '',
+    links        JSONB            NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_book_editions_book_id ON book_editions (book_id, position);
+
+-- +down
+DROP TABLE book_editions;
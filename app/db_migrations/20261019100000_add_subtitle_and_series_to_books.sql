@@ -0,0 +1,14 @@
+ALTER TABLE books
+    ADD COLUMN subtitle        TEXT NOT NULL DEFAULT '',
+    ADD COLUMN series          TEXT NOT NULL DEFAULT '',
+    -- position of the book in the series, 0 if unknown or the book isn't in a series
+    ADD COLUMN series_position INT  NOT NULL DEFAULT 0,
+    -- lower-cased title without leading articles, see ds.TitleSortKey
+    ADD COLUMN title_sort      TEXT NOT NULL DEFAULT '';
+
+-- +down
+ALTER TABLE books
+    DROP COLUMN subtitle,
+    DROP COLUMN series,
+    DROP COLUMN series_position,
+    DROP COLUMN title_sort;
@@ -0,0 +1,10 @@
+-- +notransaction
+UPDATE books b
+SET title_sort = lower(btrim(regexp_replace(e.title, '^\s*(the|a|an)\s+', '', 'i')))
+FROM entities e
+WHERE e.id = b.id AND b.title_sort = '';
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_books_title_sort ON books (title_sort);
+
+-- +down
+DROP INDEX IF EXISTS idx_books_title_sort;
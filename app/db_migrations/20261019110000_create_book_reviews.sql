@@ -0,0 +1,46 @@
+CREATE TABLE book_reviews
+(
+    id           UUID PRIMARY KEY NOT NULL,
+    book_id      UUID             NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    -- NULL once the reviewer's account is deleted; the review stays, anonymized
+    user_id      UUID REFERENCES users (id) ON DELETE SET NULL,
+    rating       SMALLINT         NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body_raw     TEXT             NOT NULL DEFAULT '',
+    body         TEXT             NOT NULL DEFAULT '',
+    -- hidden reviews are not listed and not counted in the rating of the book
+    hidden       BOOLEAN          NOT NULL DEFAULT FALSE,
+    hidden_by    UUID REFERENCES users (id) ON DELETE SET NULL,
+    hidden_at    TIMESTAMPTZ,
+    report_count INT              NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ      NOT NULL,
+    updated_at   TIMESTAMPTZ
+);
+
+-- one review per user per book
+CREATE UNIQUE INDEX idx_book_reviews_book_id_user_id ON book_reviews (book_id, user_id);
+CREATE INDEX idx_book_reviews_book_id ON book_reviews (book_id, created_at DESC);
+CREATE INDEX idx_book_reviews_reported ON book_reviews (report_count) WHERE report_count > 0;
+
+CREATE TABLE book_review_reports
+(
+    id         UUID PRIMARY KEY NOT NULL,
+    review_id  UUID             NOT NULL REFERENCES book_reviews (id) ON DELETE CASCADE,
+    user_id    UUID REFERENCES users (id) ON DELETE SET NULL,
+    reason     TEXT             NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ      NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_book_review_reports_review_id_user_id ON book_review_reports (review_id, user_id);
+
+ALTER TABLE books
+    -- average rating of the visible reviews, 0 if there are none
+    ADD COLUMN rating       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT              NOT NULL DEFAULT 0;
+
+-- +down
+ALTER TABLE books
+    DROP COLUMN rating,
+    DROP COLUMN rating_count;
+
+DROP TABLE book_review_reports;
+DROP TABLE book_reviews;
@@ -0,0 +1,5 @@
+-- +notransaction
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_books_rating ON books (rating DESC, rating_count DESC);
+
+-- +down
+DROP INDEX IF EXISTS idx_books_rating;
@@ -0,0 +1,79 @@
+CREATE TABLE authors
+(
+    id             UUID PRIMARY KEY NOT NULL,
+    slug           TEXT             NOT NULL,
+    name           TEXT             NOT NULL,
+    bio_raw        TEXT             NOT NULL DEFAULT '',
+    bio            TEXT             NOT NULL DEFAULT '',
+    -- [{"title": "...", "url": "..."}]
+    links          JSONB            NOT NULL DEFAULT '[]',
+    -- set once the author is merged into another one as a duplicate
+    merged_into_id UUID REFERENCES authors (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ      NOT NULL,
+    updated_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_authors_slug ON authors (slug);
+CREATE INDEX idx_authors_name ON authors (lower(name));
+
+CREATE TABLE book_authors
+(
+    book_id   UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES authors (id) ON DELETE CASCADE,
+    -- order of the author among the authors of the book
+    position  INT  NOT NULL,
+    PRIMARY KEY (book_id, author_id)
+);
+
+CREATE INDEX idx_book_authors_author_id ON book_authors (author_id);
+
+-- authors of the books, with the slug they are deduplicated by,
+-- e.g. "Alan A. A. Donovan" and "alan a a donovan" become the same author
+CREATE TEMPORARY TABLE migrate_book_authors ON COMMIT DROP AS
+SELECT b.id                                               AS book_id,
+       a.ord::INT - 1                                     AS position,
+       btrim(a.author ->> 'name')                         AS name,
+       btrim(COALESCE(a.author ->> 'link', ''))           AS link,
+       COALESCE(
+           NULLIF(btrim(regexp_replace(lower(btrim(a.author ->> 'name')), '[^a-z0-9]+', '-', 'g'), '-'), ''),
+           'author-' || left(md5(lower(btrim(a.author ->> 'name'))), 8)
+       )                                                  AS slug
+FROM books b
+CROSS JOIN LATERAL jsonb_array_elements(COALESCE(b.authors, '[]')) WITH ORDINALITY AS a(author, ord)
+WHERE btrim(COALESCE(a.author ->> 'name', '')) <> '';
+
+-- the most used spelling becomes the name of the author, the links given to the author become its links
+INSERT INTO authors (id, slug, name, links, created_at)
+SELECT gen_random_uuid(),
+       slug,
+       mode() WITHIN GROUP (ORDER BY name),
+       COALESCE(jsonb_agg(DISTINCT jsonb_build_object('title', 'Homepage', 'url', link)) FILTER (WHERE link <> ''), '[]'),
+       NOW()
+FROM migrate_book_authors
+GROUP BY slug;
+
+INSERT INTO book_authors (book_id, author_id, position)
+SELECT DISTINCT ON (m.book_id, a.id) m.book_id, a.id, m.position
+FROM migrate_book_authors m
+JOIN authors a ON a.slug = m.slug
+ORDER BY m.book_id, a.id, m.position;
+
+ALTER TABLE books DROP COLUMN authors;
+
+-- +down
+ALTER TABLE books ADD COLUMN authors JSONB;
+
+UPDATE books b
+SET authors = s.authors
+FROM (
+    SELECT ba.book_id,
+           jsonb_agg(jsonb_build_object('name', a.name, 'link', COALESCE(a.links -> 0 ->> 'url', ''))
+                     ORDER BY ba.position) AS authors
+    FROM book_authors ba
+    JOIN authors a ON a.id = ba.author_id
+    GROUP BY ba.book_id
+) s
+WHERE b.id = s.book_id;
+
+DROP TABLE book_authors;
+DROP TABLE authors;
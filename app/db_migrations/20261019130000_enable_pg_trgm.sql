@@ -0,0 +1,5 @@
+-- trigram similarity of titles is used to detect duplicate books
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- +down
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,51 @@
+CREATE TABLE external_links
+(
+    id            UUID PRIMARY KEY NOT NULL,
+    url           TEXT             NOT NULL,
+    -- pending | ok | failing | broken | skipped
+    status        TEXT             NOT NULL,
+    status_code   INT              NOT NULL DEFAULT 0,
+    error         TEXT             NOT NULL DEFAULT '',
+    -- consecutive failed checks, reset by a successful one
+    fail_count    INT              NOT NULL DEFAULT 0,
+    checked_at    TIMESTAMPTZ,
+    next_check_at TIMESTAMPTZ      NOT NULL,
+    created_at    TIMESTAMPTZ      NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_external_links_url ON external_links (url);
+CREATE INDEX idx_external_links_next_check_at ON external_links (next_check_at);
+CREATE INDEX idx_external_links_status ON external_links (status);
+
+-- where the link is found: a book (homepage, editions), a page (content) or an author (links)
+CREATE TABLE external_link_sources
+(
+    link_id     UUID NOT NULL REFERENCES external_links (id) ON DELETE CASCADE,
+    source_type TEXT NOT NULL,
+    source_id   UUID NOT NULL,
+    field       TEXT NOT NULL,
+    PRIMARY KEY (link_id, source_type, source_id, field)
+);
+
+CREATE INDEX idx_external_link_sources_source ON external_link_sources (source_type, source_id);
+
+-- history of checks of the link, the latest ones are kept
+CREATE TABLE external_link_checks
+(
+    id          UUID PRIMARY KEY NOT NULL,
+    link_id     UUID             NOT NULL REFERENCES external_links (id) ON DELETE CASCADE,
+    status      TEXT             NOT NULL,
+    method      TEXT             NOT NULL DEFAULT '',
+    status_code INT              NOT NULL DEFAULT 0,
+    error       TEXT             NOT NULL DEFAULT '',
+    duration_ms INT              NOT NULL DEFAULT 0,
+    checked_at  TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX idx_external_link_checks_link_id ON external_link_checks (link_id, checked_at DESC);
+
+-- +down
+
+DROP TABLE external_link_checks;
+DROP TABLE external_link_sources;
+DROP TABLE external_links;
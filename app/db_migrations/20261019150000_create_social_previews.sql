@@ -0,0 +1,10 @@
+-- the current social preview image of the entity, so earlier versions can be deleted
+CREATE TABLE social_previews
+(
+    entity_id  UUID PRIMARY KEY NOT NULL REFERENCES entities (id) ON DELETE CASCADE,
+    path       TEXT             NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL
+);
+
+-- +down
+DROP TABLE social_previews;
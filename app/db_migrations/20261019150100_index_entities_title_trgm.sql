@@ -0,0 +1,6 @@
+-- +notransaction
+-- lets the duplicate check find similar titles with the % operator without scanning all entities
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_entities_title_trgm ON entities USING gin (title gin_trgm_ops);
+
+-- +down
+DROP INDEX IF EXISTS idx_entities_title_trgm;
@@ -0,0 +1,65 @@
+package ds
+
+import (
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/lithammer/shortuuid"
+)
+
+// Author is a person who wrote one or more books.
+type Author struct {
+	ID     ID     `json:"id"`
+	Slug   string `json:"slug"`
+	Name   string `json:"name"`
+	BioRaw string `json:"bio_raw"`
+	Bio    string `json:"bio"`
+	// Links are the homepage, blog, social accounts, etc. of the author.
+	Links []AuthorLink `json:"links"`
+	// MergedIntoID is set once the author is merged into another one as a duplicate.
+	// A merged author has no books and its page redirects to the author it's merged into.
+	MergedIntoID *ID `json:"merged_into_id"`
+	// BookCount is the number of (approved and public) books of the author, loaded by AuthorsFilter.
+	BookCount int        `json:"book_count" db:"book_count"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// AuthorLink is a link to a page of the author.
+type AuthorLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Merged reports whether the author is merged into another one.
+func (a *Author) Merged() bool {
+	return a.MergedIntoID != nil
+}
+
+// ViewURL returns the public-facing URL path for viewing the author.
+func (a *Author) ViewURL() string {
+	return "/authors/" + a.Slug + "/"
+}
+
+// AuthorSlug returns the slug of the author's name, or a random one if the name has nothing to make it of.
+func AuthorSlug(name string) string {
+	slug := app.Slug(name)
+	if slug == "" {
+		slug = "author-" + shortuuid.New()
+	}
+
+	return slug
+}
+
+// AuthorsFilter is used to filter and paginate authors.
+type AuthorsFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	Name      *FilterString
+	// WithMerged includes the authors merged into other ones.
+	WithMerged bool
+	// OnlyPublished limits the authors to the ones with approved public books,
+	// so the authors of the books under review and of the private ones are not disclosed.
+	OnlyPublished bool
+}
@@ -2,8 +2,10 @@ package ds
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	z "github.com/Oudwins/zog"
 	"github.com/gopl-dev/server/app/ds/prop"
@@ -15,27 +17,39 @@ var bookCtxKey ctxKey = "book"
 type Book struct {
 	*Entity
 
-	DescriptionRaw  string       `json:"-"`
-	Description     string       `json:"description"`
-	CoverFileID     ID           `json:"cover_file_id"`
-	Authors         []BookAuthor `json:"authors"`
-	Homepage        string       `json:"homepage"`
-	ReleaseDate     string       `json:"release_date"`
-	ReleaseDateSort time.Time    `json:"-"`
+	Subtitle        string        `json:"subtitle"`
+	Series          string        `json:"series"`
+	SeriesPosition  int           `json:"series_position"`
+	TitleSort       string        `json:"-"`
+	DescriptionRaw  string        `json:"-"`
+	Description     string        `json:"description"`
+	CoverFileID     ID            `json:"cover_file_id"`
+	Authors         []BookAuthor  `json:"authors" db:"-"`
+	Homepage        string        `json:"homepage"`
+	ReleaseDate     string        `json:"release_date"`
+	ReleaseDateSort time.Time     `json:"-"`
+	ISBN10          string        `json:"isbn10" db:"isbn10"`
+	ISBN13          string        `json:"isbn13" db:"isbn13"`
+	Editions        []BookEdition `json:"editions" db:"-"`
+	// Rating is the average rating of the visible reviews of the book, kept up to date along with RatingCount.
+	Rating      float64 `json:"rating"`
+	RatingCount int     `json:"rating_count"`
 }
 
 // Data returns the editable fields of the Book as a key-value map.
 func (b *Book) Data() map[string]any {
-	if b.Authors == nil {
-		b.Authors = make([]BookAuthor, 0)
-	}
-
 	return b.WithEntityData(map[string]any{
-		"cover_file_id": b.CoverFileID,
-		"description":   b.DescriptionRaw,
-		"homepage":      b.Homepage,
-		"release_date":  b.ReleaseDate,
-		"authors":       b.Authors,
+		"subtitle":        b.Subtitle,
+		"series":          b.Series,
+		"series_position": b.SeriesPosition,
+		"cover_file_id":   b.CoverFileID,
+		"description":     b.DescriptionRaw,
+		"homepage":        b.Homepage,
+		"release_date":    b.ReleaseDate,
+		"authors":         b.AuthorsData(),
+		"isbn10":          b.ISBN10,
+		"isbn13":          b.ISBN13,
+		"editions":        b.EditionsData(),
 	})
 }
 
@@ -50,8 +64,12 @@ func (b *Book) PropertyType(key string) prop.Type {
 		return prop.URL
 	case "release_date":
 		return prop.String
-	case "authors":
+	case "authors", "editions":
 		return prop.List
+	case "isbn10", "isbn13", "subtitle", "series":
+		return prop.String
+	case "series_position":
+		return prop.Number
 	}
 
 	return b.Entity.PropertyType(key)
@@ -65,32 +83,79 @@ var ReleaseDateLayouts = []string{
 	"January 2, 2006",
 }
 
+// ParseReleaseDate parses the release date in one of ReleaseDateLayouts.
+func ParseReleaseDate(date string) (time.Time, bool) {
+	if date == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range ReleaseDateLayouts {
+		t, err := time.Parse(layout, date)
+		if err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
 // CreateRules provides the validation map used when saving a new book.
 func (b *Book) CreateRules() z.Shape {
 	return z.Shape{
-		"Title":       z.String().Trim().Required(),
+		"Title":    z.String().Trim().Required(),
+		"Subtitle": z.String().Trim(),
+		"Series":   z.String().Trim(),
+		"SeriesPosition": z.Int().GTE(0, z.Message("must not be negative")).
+			TestFunc(func(val *int, _ z.Ctx) bool {
+				return *val == 0 || b.Series != ""
+			}, z.Message("series is required")),
 		"Description": z.String().Required(),
 		"Homepage":    z.String().Trim().URL(),
 		"ReleaseDate": z.CustomFunc(func(val *string, _ z.Ctx) bool {
-			if val == nil || *val == "" {
+			if val == nil {
 				return false
 			}
 
-			for _, layout := range ReleaseDateLayouts {
-				t, err := time.Parse(layout, *val)
-				if err == nil {
-					b.ReleaseDateSort = t
-					return true
-				}
+			t, ok := ParseReleaseDate(*val)
+			if ok {
+				b.ReleaseDateSort = t
 			}
 
-			return false
+			return ok
 		}, z.Message("format must one of: "+strings.Join(ReleaseDateLayouts, "; "))),
 		"Authors": z.Slice(z.Struct(z.Shape{
 			"Name": z.String().Trim().Required(),
 			"Link": z.String().Trim().URL(),
 		})).Min(1).Required(),
+		"ISBN10": z.String().TestFunc(func(val *string, _ z.Ctx) bool {
+			return *val == "" || ValidISBN10(*val)
+		}, z.Message("invalid ISBN-10")),
+		"ISBN13": z.String().TestFunc(func(val *string, _ z.Ctx) bool {
+			return *val == "" || ValidISBN13(*val)
+		}, z.Message("invalid ISBN-13")),
+		"Editions": z.Slice(z.Struct(BookEditionRules())),
+	}
+}
+
+// AuthorsData returns the editable data of the book authors: the names (and links of new authors),
+// leaving out the author records they are resolved to.
+func (b *Book) AuthorsData() []BookAuthor {
+	authors := make([]BookAuthor, len(b.Authors))
+	for i, a := range b.Authors {
+		authors[i] = BookAuthor{Name: a.Name, Link: a.Link}
 	}
+
+	return authors
+}
+
+// EditionsData returns the editable data of the book editions, see BookEdition.Data.
+func (b *Book) EditionsData() []BookEdition {
+	editions := make([]BookEdition, len(b.Editions))
+	for i := range b.Editions {
+		editions[i] = b.Editions[i].Data()
+	}
+
+	return editions
 }
 
 // UpdateRules provides the validation map used when editing an existing book.
@@ -114,15 +179,75 @@ func BookFromContext(ctx context.Context) *Book {
 	return nil
 }
 
+// BookSort is the order of books in a list.
+type BookSort string
+
+// Supported orders of books.
+const (
+	// BookSortReleaseDate lists the latest released books first.
+	BookSortReleaseDate BookSort = "release_date"
+	// BookSortTitle lists books by title A-Z, ignoring leading articles (see TitleSortKey).
+	BookSortTitle BookSort = "title"
+	// BookSortAdded lists the recently added books first.
+	BookSortAdded BookSort = "added"
+	// BookSortUpdated lists the recently updated books first.
+	BookSortUpdated BookSort = "updated"
+	// BookSortRating lists the highest rated books first.
+	BookSortRating BookSort = "rating"
+)
+
+// BookSorts lists all supported orders of books, the first one is the default.
+var BookSorts = []BookSort{
+	BookSortReleaseDate,
+	BookSortTitle,
+	BookSortAdded,
+	BookSortUpdated,
+	BookSortRating,
+}
+
+// Valid reports whether the sort is one of BookSorts.
+func (s BookSort) Valid() bool {
+	return slices.Contains(BookSorts, s)
+}
+
 // BooksFilter is used to filter and paginate user queries.
 type BooksFilter struct {
 	EntitiesFilter
 
 	Author string
+	// Sort overrides OrderBy and OrderDirection of EntitiesFilter if set.
+	Sort BookSort
 }
 
-// BookAuthor represents an author of a book.
+// titleArticles are the leading words ignored when sorting by title.
+var titleArticles = []string{"the", "a", "an"}
+
+// TitleSortKey returns the key the title is sorted by: lower-cased, without a leading article,
+// so "The Go Programming Language" is sorted under "g".
+func TitleSortKey(title string) string {
+	key := strings.ToLower(strings.TrimSpace(title))
+	for _, a := range titleArticles {
+		rest, ok := strings.CutPrefix(key, a)
+		if ok && rest != strings.TrimLeftFunc(rest, unicode.IsSpace) {
+			return strings.TrimSpace(rest)
+		}
+	}
+
+	return key
+}
+
+// BookAuthor is an author of a book, as listed on the book. Authors are linked to books by name:
+// a name not matching an existing author creates one (see Service.resolveBookAuthors).
 type BookAuthor struct {
+	// ID and Slug refer to the record of the author, they are empty until the author is resolved.
+	ID   ID     `json:"id"`
+	Slug string `json:"slug"`
 	Name string `json:"name"`
-	Link string `json:"link"`
+	// Link is only used if the author is created along with the book: it becomes the homepage of the author.
+	Link string `json:"link,omitempty"`
+}
+
+// ViewURL returns the public-facing URL path for viewing the author.
+func (a BookAuthor) ViewURL() string {
+	return "/authors/" + a.Slug + "/"
 }
@@ -0,0 +1,100 @@
+package ds
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// BookDuplicateMinTitleSimilarity is the min trigram similarity of the titles of books sharing an author
+	// to consider them duplicates.
+	BookDuplicateMinTitleSimilarity = 0.3
+	// BookDuplicateSameTitleSimilarity is the min trigram similarity of the titles to consider the books duplicates
+	// regardless of their authors.
+	BookDuplicateSameTitleSimilarity = 0.6
+)
+
+// BookDuplicate is an existing book that is likely the same book as another one.
+type BookDuplicate struct {
+	ID       ID     `json:"id"`
+	PublicID string `json:"public_id"`
+	Title    string `json:"title"`
+	// Status is shown to admins only, as the others can only find the approved public books.
+	Status EntityStatus `json:"status,omitempty"`
+	// TitleSimilarity is the trigram similarity of the titles, from 0 to 1.
+	TitleSimilarity float64 `json:"title_similarity"`
+	// SharedAuthors is the number of authors the books have in common.
+	SharedAuthors int `json:"shared_authors"`
+	// SameISBN reports whether the books have an ISBN in common, including the ISBNs of their editions.
+	SameISBN bool `json:"same_isbn" db:"same_isbn"`
+}
+
+// Likely reports whether the book is likely a duplicate: the books have an ISBN in common,
+// or similar titles and an author in common, or nearly the same titles.
+func (d BookDuplicate) Likely() bool {
+	return d.SameISBN ||
+		(d.SharedAuthors > 0 && d.TitleSimilarity >= BookDuplicateMinTitleSimilarity) ||
+		d.TitleSimilarity >= BookDuplicateSameTitleSimilarity
+}
+
+// ViewURL returns the public-facing URL path for viewing the book.
+func (d BookDuplicate) ViewURL() string {
+	return "/books/" + d.PublicID + "/"
+}
+
+// BookDuplicatesQuery describes the book to find the duplicates of.
+type BookDuplicatesQuery struct {
+	// ExcludeID is the ID of the book itself, if it exists.
+	ExcludeID ID
+	Title     string
+	// Authors are the lowercase names of the authors.
+	Authors []string
+	// AuthorSlugs are the slugs of the names of the authors.
+	AuthorSlugs []string
+	ISBNs       []string
+	// OnlyPublic limits the candidates to the approved public books,
+	// so the books under review and the private ones of other users are not disclosed.
+	OnlyPublic bool
+}
+
+// NewBookDuplicatesQuery returns the query to find the duplicates of the book.
+func NewBookDuplicatesQuery(b *Book) BookDuplicatesQuery {
+	q := BookDuplicatesQuery{
+		Title:       strings.TrimSpace(b.Title),
+		Authors:     make([]string, 0, len(b.Authors)),
+		AuthorSlugs: make([]string, 0, len(b.Authors)),
+		ISBNs:       make([]string, 0),
+	}
+	if b.Entity != nil {
+		q.ExcludeID = b.ID
+	}
+
+	for _, a := range b.Authors {
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			continue
+		}
+
+		q.Authors = append(q.Authors, strings.ToLower(name))
+		q.AuthorSlugs = append(q.AuthorSlugs, AuthorSlug(name))
+	}
+
+	// an ISBN-10 matches the ISBN-13 of the same book, and vice versa
+	addISBN := func(isbn string) {
+		isbn = NormalizeISBN(isbn)
+		for _, v := range []string{isbn, ISBN10To13(isbn), ISBN13To10(isbn)} {
+			if v != "" && !slices.Contains(q.ISBNs, v) {
+				q.ISBNs = append(q.ISBNs, v)
+			}
+		}
+	}
+
+	addISBN(b.ISBN10)
+	addISBN(b.ISBN13)
+	for _, e := range b.Editions {
+		addISBN(e.ISBN10)
+		addISBN(e.ISBN13)
+	}
+
+	return q
+}
@@ -0,0 +1,138 @@
+package ds
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	z "github.com/Oudwins/zog"
+)
+
+// BookFormat is the format a book edition is available in.
+type BookFormat string
+
+// Supported book formats.
+const (
+	BookFormatPrint  BookFormat = "print"
+	BookFormatEbook  BookFormat = "ebook"
+	BookFormatOnline BookFormat = "online"
+)
+
+// BookFormats lists all supported book formats.
+var BookFormats = []BookFormat{
+	BookFormatPrint,
+	BookFormatEbook,
+	BookFormatOnline,
+}
+
+// Valid reports whether the format is one of BookFormats.
+func (f BookFormat) Valid() bool {
+	return slices.Contains(BookFormats, f)
+}
+
+// goVersionRe matches the Go versions covered by an edition, e.g. "1.22".
+var goVersionRe = regexp.MustCompile(`^1\.\d{1,2}$`)
+
+// BookEdition is an edition of a book (1st, 2nd, ...), stored as a child record of the book.
+// Editions are edited as a whole along with the book, so they have no identity of their own
+// outside the database.
+type BookEdition struct {
+	ID       ID  `json:"-"`
+	BookID   ID  `json:"-"`
+	Position int `json:"-"`
+
+	// Number is the number of the edition, starting with 1.
+	Number int `json:"number"`
+	// GoVersion is the version of Go the edition covers, e.g. "1.22". Empty if unknown.
+	GoVersion   string            `json:"go_version"`
+	Publisher   string            `json:"publisher"`
+	ISBN10      string            `json:"isbn10" db:"isbn10"`
+	ISBN13      string            `json:"isbn13" db:"isbn13"`
+	PageCount   int               `json:"page_count"`
+	ReleaseDate string            `json:"release_date"`
+	Links       []BookEditionLink `json:"links"`
+}
+
+// BookEditionLink is a link to buy or read an edition in a format.
+type BookEditionLink struct {
+	Format BookFormat `json:"format"`
+	URL    string     `json:"url"`
+	// Title is the name of the store or the site, optional.
+	Title string `json:"title"`
+}
+
+// Title returns the title of the edition, e.g. "2nd edition (Go 1.22)".
+func (e *BookEdition) Title() string {
+	t := ordinal(e.Number) + " edition"
+	if e.GoVersion != "" {
+		t += " (Go " + e.GoVersion + ")"
+	}
+
+	return t
+}
+
+// Data returns the editable fields of the edition, leaving out the database identity,
+// so editions loaded from the database compare equal to the same editions submitted by a user.
+func (e *BookEdition) Data() BookEdition {
+	links := make([]BookEditionLink, len(e.Links))
+	copy(links, e.Links)
+
+	return BookEdition{
+		Number:      e.Number,
+		GoVersion:   e.GoVersion,
+		Publisher:   e.Publisher,
+		ISBN10:      e.ISBN10,
+		ISBN13:      e.ISBN13,
+		PageCount:   e.PageCount,
+		ReleaseDate: e.ReleaseDate,
+		Links:       links,
+	}
+}
+
+// BookEditionRules provides the validation map of a book edition.
+func BookEditionRules() z.Shape {
+	return z.Shape{
+		"Number": z.Int().Required(z.Message("must be 1 or greater")).GTE(1, z.Message("must be 1 or greater")),
+		"GoVersion": z.String().Trim().TestFunc(func(val *string, _ z.Ctx) bool {
+			return *val == "" || goVersionRe.MatchString(*val)
+		}, z.Message("must be a Go version, e.g. 1.22")),
+		"Publisher": z.String().Trim(),
+		"ISBN10": z.String().TestFunc(func(val *string, _ z.Ctx) bool {
+			return *val == "" || ValidISBN10(*val)
+		}, z.Message("invalid ISBN-10")),
+		"ISBN13": z.String().TestFunc(func(val *string, _ z.Ctx) bool {
+			return *val == "" || ValidISBN13(*val)
+		}, z.Message("invalid ISBN-13")),
+		"PageCount": z.Int().GTE(0, z.Message("must not be negative")),
+		"ReleaseDate": z.String().TestFunc(func(val *string, _ z.Ctx) bool {
+			_, ok := ParseReleaseDate(*val)
+			return *val == "" || ok
+		}, z.Message("format must one of: "+strings.Join(ReleaseDateLayouts, "; "))),
+		"Links": z.Slice(z.Struct(z.Shape{
+			"Format": z.CustomFunc(func(val *BookFormat, _ z.Ctx) bool {
+				return val.Valid()
+			}, z.Message("must be one of: print, ebook, online")),
+			"URL":   z.String().Trim().URL().Required(),
+			"Title": z.String().Trim(),
+		})),
+	}
+}
+
+// ordinal returns the number with its English ordinal suffix, e.g. "2nd".
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 { //nolint:mnd
+	case 1:
+		suffix = "st"
+	case 2: //nolint:mnd
+		suffix = "nd"
+	case 3: //nolint:mnd
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 { //nolint:mnd
+		suffix = "th"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,59 @@
+package ds
+
+// BookImportRow is a book to be created by a bulk import, as read from a CSV or JSON file.
+type BookImportRow struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Authors     []BookAuthor `json:"authors"`
+	ReleaseDate string       `json:"release_date"`
+	Homepage    string       `json:"homepage"`
+	// Topics are public IDs or names of book topics.
+	Topics []string `json:"topics"`
+	// Cover is the URL of the cover image, or the path of a local file (CLI imports only).
+	Cover string `json:"cover"`
+	// ISBN is ISBN-10 or ISBN-13. The fields left empty are filled from the book metadata found by it.
+	ISBN string `json:"isbn"`
+}
+
+// BookImportStatus is the outcome of a single row of an import.
+type BookImportStatus string
+
+// BookImportStatus values.
+const (
+	// BookImportCreated means the book was created.
+	BookImportCreated BookImportStatus = "created"
+
+	// BookImportValid means the book would be created (dry run).
+	BookImportValid BookImportStatus = "valid"
+
+	// BookImportDuplicate means the book already exists (or occurs earlier in the import) and was skipped.
+	BookImportDuplicate BookImportStatus = "duplicate"
+
+	// BookImportFailed means the row is invalid or the book couldn't be created.
+	BookImportFailed BookImportStatus = "failed"
+)
+
+// BookImportResult is the outcome of a single row of an import.
+type BookImportResult struct {
+	// Row is the 1-based number of the row (not counting the CSV header).
+	Row      int              `json:"row"`
+	Title    string           `json:"title"`
+	Status   BookImportStatus `json:"status"`
+	BookID   ID               `json:"book_id,omitzero"`
+	PublicID string           `json:"public_id,omitempty"`
+	// Error describes why the row failed or was skipped.
+	Error string `json:"error,omitempty"`
+	// InputErrors are the validation errors by field.
+	InputErrors map[string]string `json:"input_errors,omitempty"`
+}
+
+// BookImportReport is the outcome of an import.
+type BookImportReport struct {
+	DryRun     bool               `json:"dry_run"`
+	Created    int                `json:"created"`
+	Valid      int                `json:"valid"`
+	Duplicates int                `json:"duplicates"`
+	Failed     int                `json:"failed"`
+	Rows       []BookImportResult `json:"rows"`
+}
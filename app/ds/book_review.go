@@ -0,0 +1,107 @@
+package ds
+
+import (
+	"math"
+	"time"
+)
+
+// Book review ratings range from BookReviewMinRating to BookReviewMaxRating stars.
+const (
+	BookReviewMinRating = 1
+	BookReviewMaxRating = 5
+)
+
+// BookReview is a short review of a book written by a user. A user can review a book once,
+// writing the review again updates it.
+type BookReview struct {
+	ID     ID `json:"id"`
+	BookID ID `json:"book_id"`
+	// UserID is nil once the reviewer's account is deleted, the review is then shown as anonymous.
+	UserID *ID `json:"user_id"`
+	// Username is the username of the reviewer, loaded along with the review. Empty if anonymous.
+	Username    string     `json:"username" db:"username"`
+	Rating      int        `json:"rating"`
+	BodyRaw     string     `json:"body_raw"`
+	Body        string     `json:"body"`
+	Hidden      bool       `json:"hidden"`
+	HiddenBy    *ID        `json:"-"`
+	HiddenAt    *time.Time `json:"-"`
+	ReportCount int        `json:"report_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// Anonymous reports whether the reviewer's account has been deleted.
+func (r *BookReview) Anonymous() bool {
+	return r.UserID == nil
+}
+
+// Author returns the name the review is shown under.
+func (r *BookReview) Author() string {
+	if r.Anonymous() || r.Username == "" {
+		return "Anonymous"
+	}
+
+	return r.Username
+}
+
+// BookReviewReport is a report of an inappropriate review, sent to moderators.
+type BookReviewReport struct {
+	ID        ID        `json:"id"`
+	ReviewID  ID        `json:"review_id"`
+	UserID    *ID       `json:"user_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BookRating is the aggregate rating of a book over its visible reviews.
+type BookRating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+	// Distribution holds the number of reviews by rating, Distribution[0] is the number of 1-star reviews.
+	Distribution [BookReviewMaxRating]int `json:"distribution"`
+}
+
+// NewBookRating returns the rating of the given number of reviews by rating (1-5).
+// Ratings out of range are ignored.
+func NewBookRating(counts map[int]int) BookRating {
+	var r BookRating
+	sum := 0
+	for rating, n := range counts {
+		if rating < BookReviewMinRating || rating > BookReviewMaxRating {
+			continue
+		}
+
+		r.Distribution[rating-1] = n
+		r.Count += n
+		sum += rating * n
+	}
+
+	if r.Count > 0 {
+		r.Average = math.Round(float64(sum)/float64(r.Count)*100) / 100 //nolint:mnd
+	}
+
+	return r
+}
+
+// Percent returns the share of reviews with the given rating, in percent (0-100).
+func (r BookRating) Percent(rating int) int {
+	if r.Count == 0 || rating < BookReviewMinRating || rating > BookReviewMaxRating {
+		return 0
+	}
+
+	return int(math.Round(float64(r.Distribution[rating-1]) * 100 / float64(r.Count))) //nolint:mnd
+}
+
+// BookReviewsFilter is used to filter book reviews.
+type BookReviewsFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	BookID    *ID
+	UserID    *ID
+	// Hidden filters reviews by hidden state, nil means both.
+	Hidden *bool
+	// Reported limits the result to reviews reported at least once, most reported first.
+	Reported bool
+}
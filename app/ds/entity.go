@@ -142,6 +142,8 @@ type EntitiesFilter struct {
 	DeletedAt      *FilterDT
 	Deleted        bool
 	Title          *FilterString
+	OwnerID        *ID
+	Type           EntityType
 	Visibility     []EntityVisibility
 	Status         []EntityStatus
 	Topics         []string
@@ -48,5 +48,13 @@ type ChangeRequestsFilter struct {
 	Page      int
 	PerPage   int
 	Status    EntityChangeStatus
+	UserID    *ID
 	WithCount bool
+
+	// EntityStatus and EntityVisibility filter by the entity the change is requested for.
+	EntityStatus     []EntityStatus
+	EntityVisibility []EntityVisibility
+
+	// OrderDirection of the creation date, oldest first by default.
+	OrderDirection string
 }
@@ -102,10 +102,16 @@ func (l EventLog) RenderMessage() string {
 	return b.String()
 }
 
+// RenderText returns RenderMessage as plain text, with markup removed.
+func (l EventLog) RenderText() string {
+	return app.PlainText(l.RenderMessage())
+}
+
 // EventLogsFilter is used to filter and paginate event logs.
 type EventLogsFilter struct {
 	Page       int
 	PerPage    int
 	OnlyPublic bool
 	WithCount  bool
+	UserID     *ID
 }
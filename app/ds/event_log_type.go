@@ -44,6 +44,26 @@ const (
 	// active and eligible to appear in public-facing activity feeds.
 	EventLogUserAccountActivated EventLogType = "user_account_activated"
 
+	// EventLogUserOAuthAccountLinked is recorded when a user links
+	// an OAuth provider to their account.
+	EventLogUserOAuthAccountLinked EventLogType = "user_oauth_account_linked"
+
+	// EventLogUserOAuthAccountUnlinked is recorded when a user unlinks
+	// an OAuth provider from their account.
+	EventLogUserOAuthAccountUnlinked EventLogType = "user_oauth_account_unlinked"
+
+	// EventLogUserAccountLocked is recorded when a user account is
+	// temporarily locked after repeated failed sign-in attempts.
+	EventLogUserAccountLocked EventLogType = "user_account_locked"
+
+	// EventLogUserProfileUpdated is recorded when a user updates
+	// their public profile.
+	EventLogUserProfileUpdated EventLogType = "user_profile_updated"
+
+	// EventLogUserDataExportRequested is recorded when a user requests
+	// a copy of their personal data.
+	EventLogUserDataExportRequested EventLogType = "user_data_export_requested"
+
 	// EventLogEntitySubmitted is recorded when an entity is submitted
 	// for moderation or review.
 	EventLogEntitySubmitted EventLogType = "entity_submitted"
@@ -66,6 +86,26 @@ const (
 
 	// EventLogEntityRenamed is recorded when an entity is only renamed.
 	EventLogEntityRenamed EventLogType = "entity_renamed"
+
+	// EventLogChangeRequestSubmitted is recorded when a user submits (or revises)
+	// changes to an entity for review.
+	EventLogChangeRequestSubmitted EventLogType = "change_request_submitted"
+
+	// EventLogBookReviewed is recorded when a user reviews a book
+	// for the first time.
+	EventLogBookReviewed EventLogType = "book_reviewed"
+
+	// EventLogBookReviewReported is recorded when a user reports
+	// a review of a book.
+	EventLogBookReviewReported EventLogType = "book_review_reported"
+
+	// EventLogBookReviewHidden is recorded when a moderator hides
+	// a review of a book.
+	EventLogBookReviewHidden EventLogType = "book_review_hidden"
+
+	// EventLogBookReviewUnhidden is recorded when a moderator makes
+	// a hidden review of a book visible again.
+	EventLogBookReviewUnhidden EventLogType = "book_review_unhidden"
 )
 
 // EventLogTypes lists all supported event log types.
@@ -81,6 +121,11 @@ var EventLogTypes = []EventLogType{
 	EventLogUserEmailChangeRequested,
 	EventLogUserEmailChanged,
 	EventLogUserUsernameChanged,
+	EventLogUserOAuthAccountLinked,
+	EventLogUserOAuthAccountUnlinked,
+	EventLogUserAccountLocked,
+	EventLogUserProfileUpdated,
+	EventLogUserDataExportRequested,
 	// Entity events
 	EventLogEntitySubmitted,
 	EventLogEntityApproved,
@@ -88,6 +133,12 @@ var EventLogTypes = []EventLogType{
 	EventLogEntityAdded,
 	EventLogEntityUpdated,
 	EventLogEntityRenamed,
+	EventLogChangeRequestSubmitted,
+	// Review events
+	EventLogBookReviewed,
+	EventLogBookReviewReported,
+	EventLogBookReviewHidden,
+	EventLogBookReviewUnhidden,
 }
 
 // Verb returns a short, human-readable verb describing the event.
@@ -109,6 +160,16 @@ func (t EventLogType) Verb() string {
 		return "email changed"
 	case EventLogUserUsernameChanged:
 		return "username changed"
+	case EventLogUserOAuthAccountLinked:
+		return "linked account"
+	case EventLogUserOAuthAccountUnlinked:
+		return "unlinked account"
+	case EventLogUserAccountLocked:
+		return "account locked"
+	case EventLogUserProfileUpdated:
+		return "updated profile"
+	case EventLogUserDataExportRequested:
+		return "data export requested"
 	case EventLogUserAccountActivated:
 		return "joined"
 	case EventLogEntitySubmitted:
@@ -123,6 +184,16 @@ func (t EventLogType) Verb() string {
 		return "updated"
 	case EventLogEntityRenamed:
 		return "renamed"
+	case EventLogChangeRequestSubmitted:
+		return "submitted changes to"
+	case EventLogBookReviewed:
+		return "reviewed"
+	case EventLogBookReviewReported:
+		return "reported a review of"
+	case EventLogBookReviewHidden:
+		return "hid a review of"
+	case EventLogBookReviewUnhidden:
+		return "unhid a review of"
 	}
 
 	return ""
@@ -0,0 +1,166 @@
+package ds
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// ExternalLinkBrokenAfter is how many consecutive failed checks make the link broken.
+	// A single failure only marks it as failing, as sites go down for a while.
+	ExternalLinkBrokenAfter = 2
+
+	// ExternalLinkChecksKept is how many of the latest checks are kept in the history of the link.
+	ExternalLinkChecksKept = 20
+)
+
+// ExternalLinkStatus defines the state of an external link, as of its latest check.
+type ExternalLinkStatus string
+
+const (
+	// ExternalLinkPending marks a link that was not checked yet.
+	ExternalLinkPending ExternalLinkStatus = "pending"
+
+	// ExternalLinkOK marks a link that responded with 2xx, after following redirects.
+	ExternalLinkOK ExternalLinkStatus = "ok"
+
+	// ExternalLinkFailing marks a link the latest check of which failed,
+	// but not enough times in a row to consider it broken.
+	ExternalLinkFailing ExternalLinkStatus = "failing"
+
+	// ExternalLinkBroken marks a link that failed ExternalLinkBrokenAfter checks in a row.
+	ExternalLinkBroken ExternalLinkStatus = "broken"
+
+	// ExternalLinkSkipped marks a link we are not allowed to check by robots.txt of the site.
+	ExternalLinkSkipped ExternalLinkStatus = "skipped"
+
+	// ExternalLinkRateLimited marks a check the site responded to with 429 Too Many Requests.
+	// It is only recorded in the history; the link keeps the status it had.
+	ExternalLinkRateLimited ExternalLinkStatus = "rate_limited"
+)
+
+// ExternalLinkSourceType defines what kind of record an external link is found in.
+type ExternalLinkSourceType string
+
+const (
+	// ExternalLinkSourceBook is a book: its homepage and the links of its editions.
+	ExternalLinkSourceBook ExternalLinkSourceType = "book"
+
+	// ExternalLinkSourcePage is a page: the links in its Markdown content.
+	ExternalLinkSourcePage ExternalLinkSourceType = "page"
+
+	// ExternalLinkSourceAuthor is an author: the links of the author's profile.
+	ExternalLinkSourceAuthor ExternalLinkSourceType = "author"
+)
+
+// ExternalLink is a URL to another site found in books, pages or authors,
+// along with the result of its latest check.
+type ExternalLink struct {
+	ID          ID                 `json:"id"`
+	URL         string             `json:"url"`
+	Status      ExternalLinkStatus `json:"status"`
+	StatusCode  int                `json:"status_code"`
+	Error       string             `json:"error"`
+	FailCount   int                `json:"fail_count"`
+	CheckedAt   *time.Time         `json:"checked_at"`
+	NextCheckAt time.Time          `json:"next_check_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+
+	// Sources are the records the link is found in.
+	Sources []ExternalLinkSource `json:"sources" db:"-"`
+}
+
+// Host returns the host of the link, with the port if any.
+func (l *ExternalLink) Host() string {
+	u, err := url.Parse(l.URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
+
+// ExternalLinkSource is a record an external link is found in.
+type ExternalLinkSource struct {
+	LinkID     ID                     `json:"-"`
+	SourceType ExternalLinkSourceType `json:"source_type"`
+	SourceID   ID                     `json:"source_id"`
+	// Field is where in the record the link is, e.g. "homepage".
+	Field string `json:"field"`
+
+	// Title and PublicID (the slug, for authors) are of the record, loaded along with the source.
+	Title    string `json:"title"`
+	PublicID string `json:"public_id"`
+}
+
+// ViewURL returns the public-facing URL path for viewing the record the link is found in.
+func (s ExternalLinkSource) ViewURL() string {
+	switch s.SourceType {
+	case ExternalLinkSourceBook:
+		return "/books/" + s.PublicID + "/"
+	case ExternalLinkSourcePage:
+		return "/" + s.PublicID + "/"
+	case ExternalLinkSourceAuthor:
+		return "/authors/" + s.PublicID + "/"
+	}
+
+	return "/"
+}
+
+// ExternalLinkRef is a URL found in a record, before it's stored as an external link.
+type ExternalLinkRef struct {
+	URL        string
+	SourceType ExternalLinkSourceType
+	SourceID   ID
+	Field      string
+}
+
+// ExternalLinkCheck is a single check of an external link.
+type ExternalLinkCheck struct {
+	ID         ID                 `json:"id"`
+	LinkID     ID                 `json:"link_id"`
+	Status     ExternalLinkStatus `json:"status"`
+	Method     string             `json:"method"`
+	StatusCode int                `json:"status_code"`
+	Error      string             `json:"error"`
+	DurationMS int                `json:"duration_ms" db:"duration_ms"`
+	CheckedAt  time.Time          `json:"checked_at"`
+}
+
+// ExternalLinksFilter is used to filter external links.
+type ExternalLinksFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	Status    ExternalLinkStatus
+}
+
+// ExternalLinkChecksFilter is used to filter the history of checks of an external link.
+type ExternalLinkChecksFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	LinkID    ID
+}
+
+// ExternalLinkURL returns the URL in the form external links are stored in,
+// or false if it's not an absolute http(s) URL. The fragment is dropped,
+// as it points within the page and is never sent to the site.
+func ExternalLinkURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", false
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String(), true
+}
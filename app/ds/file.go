@@ -73,14 +73,24 @@ func (f *File) IsBookCover() bool {
 	return f.Type == file.TypeImage && f.Purpose == FilePurposeBookCover
 }
 
+// IsUserAvatar reports whether the file is an image intended to be used
+// as a user avatar.
+func (f *File) IsUserAvatar() bool {
+	return f.Type == file.TypeImage && f.Purpose == FilePurposeUserAvatar
+}
+
 // FilesFilter is used to filter, sort, and paginate file queries.
 type FilesFilter struct {
 	Page           int
 	PerPage        int
 	WithCount      bool
+	OwnerID        *ID
 	CreatedAt      *FilterDT
 	DeletedAt      *FilterDT
 	Deleted        bool
 	OrderBy        string
 	OrderDirection string
+
+	// Committed selects only the files that are no longer temporary.
+	Committed bool
 }
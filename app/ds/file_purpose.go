@@ -8,10 +8,14 @@ type FilePurpose string
 const (
 	// FilePurposeBookCover marks a file used as a book cover image.
 	FilePurposeBookCover FilePurpose = "book-cover"
+
+	// FilePurposeUserAvatar marks a file used as a user avatar image.
+	FilePurposeUserAvatar FilePurpose = "user-avatar"
 )
 
 var filePurposes = []FilePurpose{
 	FilePurposeBookCover,
+	FilePurposeUserAvatar,
 }
 
 // Valid ...
@@ -0,0 +1,131 @@
+package ds
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// ISBN10Len is the number of digits in ISBN-10 (the last one may be X).
+	ISBN10Len = 10
+
+	// ISBN13Len is the number of digits in ISBN-13.
+	ISBN13Len = 13
+
+	// isbn13Prefix is the prefix of ISBN-13 converted from ISBN-10.
+	isbn13Prefix = "978"
+)
+
+// NormalizeISBN removes hyphens and spaces from the ISBN, and upper-cases the X check digit.
+func NormalizeISBN(isbn string) string {
+	isbn = strings.ToUpper(strings.TrimSpace(isbn))
+	isbn = strings.TrimPrefix(isbn, "ISBN")
+	isbn = strings.TrimPrefix(isbn, "-10")
+	isbn = strings.TrimPrefix(isbn, "-13")
+	isbn = strings.TrimPrefix(isbn, ":")
+
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, isbn)
+}
+
+// ValidISBN10 reports whether the normalized ISBN-10 has a valid check digit.
+func ValidISBN10(isbn string) bool {
+	if len(isbn) != ISBN10Len {
+		return false
+	}
+
+	sum := 0
+	for i, r := range isbn {
+		var d int
+		switch {
+		case r >= '0' && r <= '9':
+			d = int(r - '0')
+		case r == 'X' && i == ISBN10Len-1:
+			d = 10
+		default:
+			return false
+		}
+
+		sum += (ISBN10Len - i) * d
+	}
+
+	return sum%11 == 0
+}
+
+// ValidISBN13 reports whether the normalized ISBN-13 has a valid check digit.
+func ValidISBN13(isbn string) bool {
+	if len(isbn) != ISBN13Len || !isDigits(isbn) {
+		return false
+	}
+
+	return isbn13CheckDigit(isbn[:ISBN13Len-1]) == isbn[ISBN13Len-1]
+}
+
+// ValidISBN reports whether the normalized ISBN is a valid ISBN-10 or ISBN-13.
+func ValidISBN(isbn string) bool {
+	return ValidISBN10(isbn) || ValidISBN13(isbn)
+}
+
+// ISBN10To13 converts the valid ISBN-10 to ISBN-13.
+// It returns an empty string if the ISBN-10 is invalid.
+func ISBN10To13(isbn string) string {
+	if !ValidISBN10(isbn) {
+		return ""
+	}
+
+	isbn = isbn13Prefix + isbn[:ISBN10Len-1]
+
+	return isbn + string(isbn13CheckDigit(isbn))
+}
+
+// ISBN13To10 converts the valid ISBN-13 to ISBN-10.
+// It returns an empty string if the ISBN-13 is invalid or has no ISBN-10 equivalent (979 prefix).
+func ISBN13To10(isbn string) string {
+	if !ValidISBN13(isbn) || !strings.HasPrefix(isbn, isbn13Prefix) {
+		return ""
+	}
+
+	isbn = isbn[len(isbn13Prefix) : ISBN13Len-1]
+
+	sum := 0
+	for i, r := range isbn {
+		sum += (ISBN10Len - i) * int(r-'0')
+	}
+
+	check := (11 - sum%11) % 11 //nolint:mnd
+	if check == 10 {            //nolint:mnd
+		return isbn + "X"
+	}
+
+	return isbn + strconv.Itoa(check)
+}
+
+// isbn13CheckDigit returns the check digit of the first 12 digits of ISBN-13.
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i, r := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += weight * int(r-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10) //nolint:mnd
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
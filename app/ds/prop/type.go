@@ -16,6 +16,7 @@ const (
 	URL      Type = "url"
 	Image    Type = "image"
 	List     Type = "list"
+	Number   Type = "number"
 )
 
 // Patchable returns true if the property type can be modified through patch operations.
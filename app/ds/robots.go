@@ -0,0 +1,156 @@
+package ds
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RobotsRules are the rules of robots.txt of a site that apply to a user agent.
+// A nil RobotsRules allows everything, as does a site without robots.txt.
+type RobotsRules struct {
+	rules []robotsRule
+
+	// CrawlDelay is the delay between requests the site asks for, zero if none.
+	CrawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// ParseRobots parses robots.txt and returns the rules of the groups matching the user agent,
+// or of the "*" group if none does. Unknown lines are ignored.
+func ParseRobots(content, userAgent string) *RobotsRules {
+	var (
+		groups []*robotsGroup
+		g      *robotsGroup
+		// user-agent lines that follow each other share the group
+		inAgents bool
+	)
+
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		if key == "user-agent" {
+			if !inAgents {
+				g = &robotsGroup{}
+				groups = append(groups, g)
+			}
+			g.agents = append(g.agents, strings.ToLower(val))
+			inAgents = true
+			continue
+		}
+
+		inAgents = false
+		if g == nil {
+			continue
+		}
+
+		switch key {
+		case "allow", "disallow":
+			// an empty Disallow allows everything
+			if val != "" {
+				g.rules = append(g.rules, robotsRule{allow: key == "allow", pattern: val})
+			}
+		case "crawl-delay":
+			sec, err := strconv.ParseFloat(val, 64)
+			if err == nil && sec > 0 {
+				g.crawlDelay = time.Duration(sec * float64(time.Second))
+			}
+		}
+	}
+
+	userAgent = strings.ToLower(userAgent)
+	match := func(specific bool) *RobotsRules {
+		var r *RobotsRules
+		for _, g := range groups {
+			for _, a := range g.agents {
+				if (specific && a != "*" && a != "" && strings.Contains(userAgent, a)) || (!specific && a == "*") {
+					if r == nil {
+						r = &RobotsRules{}
+					}
+					r.rules = append(r.rules, g.rules...)
+					r.CrawlDelay = max(r.CrawlDelay, g.crawlDelay)
+
+					break
+				}
+			}
+		}
+
+		return r
+	}
+
+	if r := match(true); r != nil {
+		return r
+	}
+
+	return match(false)
+}
+
+// Allowed reports whether the path (with the query, if any) may be requested.
+// The longest matching rule wins; on a tie Allow wins.
+func (r *RobotsRules) Allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !robotsPatternMatch(rule.pattern, path) {
+			continue
+		}
+
+		if l := len(rule.pattern); l > longest || (l == longest && rule.allow) {
+			allowed, longest = rule.allow, l
+		}
+	}
+
+	return allowed
+}
+
+// robotsPatternMatch matches the path against the pattern of a rule,
+// which may contain "*" for any sequence of characters and end with "$" to anchor it to the end of the path.
+func robotsPatternMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	rest, ok := strings.CutPrefix(path, parts[0])
+	if !ok {
+		return false
+	}
+
+	for i, p := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, p)
+		}
+
+		idx := strings.Index(rest, p)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(p):]
+	}
+
+	return !anchored || rest == ""
+}
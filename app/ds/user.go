@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // DeletedUsername is the placeholder username for soft-deleted users.
@@ -28,6 +30,17 @@ type User struct {
 	DeletedAt      *time.Time `json:"-"`
 	CleanedAt      *time.Time `json:"-"`
 
+	// Public profile.
+	Bio          string     `json:"-"`
+	AvatarFileID ID         `json:"-"`
+	Links        []UserLink `json:"-"`
+
+	// Profile sections the user has chosen to show publicly.
+	ShowBooks          bool `json:"-"`
+	ShowPages          bool `json:"-"`
+	ShowChangeRequests bool `json:"-"`
+	ShowActivity       bool `json:"-"`
+
 	// IsAdmin is true if the user ID is listed in "admins" key in config file.
 	// This field is set by the auth middleware.
 	// Until proper RBAC/ACL is implemented, we trust authority generously granted by the devs themselves.
@@ -46,6 +59,28 @@ func (u *User) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&a)
 }
 
+// UserLink is a link displayed on the user's public profile.
+type UserLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// UserProfile is the public profile of a user along with their contributions.
+// Sections hidden by the user are left empty.
+type UserProfile struct {
+	User           *User
+	Books          []Entity
+	Pages          []Entity
+	ChangeRequests []EntityChangeRequest
+	EventLogs      []EventLog
+}
+
+// Deleted reports whether the profile belongs to a deleted user
+// and should be rendered as a tombstone.
+func (p *UserProfile) Deleted() bool {
+	return p.User == nil || p.User.Deleted()
+}
+
 // UsersFilter is used to filter and paginate user queries.
 type UsersFilter struct {
 	Page           int
@@ -64,6 +99,22 @@ func (u *User) Deleted() bool {
 	return u.DeletedAt != nil
 }
 
+// ProfileURL returns the public-facing URL path of the user's profile.
+func (u *User) ProfileURL() string {
+	if u.Deleted() {
+		return "/users/" + DeletedUsername + "/"
+	}
+
+	return "/users/" + u.Username + "/"
+}
+
+// HasPassword reports whether the user has a password they can sign in with.
+// Users created via OAuth get a random non-hash placeholder instead.
+func (u *User) HasPassword() bool {
+	_, err := bcrypt.Cost([]byte(u.Password))
+	return err == nil
+}
+
 // ToContext adds the given user object to the provided context.
 func (u *User) ToContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, userCtxKey, u)
@@ -0,0 +1,62 @@
+package ds
+
+import (
+	"time"
+)
+
+// UserDataExportTTL defines how long a ready export can be downloaded.
+const UserDataExportTTL = 7 * 24 * time.Hour
+
+// UserDataExportStatus defines the processing state of a user data export.
+type UserDataExportStatus string
+
+const (
+	// UserDataExportPending marks an export waiting to be built by the worker.
+	UserDataExportPending UserDataExportStatus = "pending"
+
+	// UserDataExportReady marks an export that is built and can be downloaded.
+	UserDataExportReady UserDataExportStatus = "ready"
+
+	// UserDataExportFailed marks an export that could not be built.
+	UserDataExportFailed UserDataExportStatus = "failed"
+)
+
+// UserDataExport represents a request of a user to get a copy of their personal data.
+// The archive is stored at Path and is available by Token until ExpiresAt.
+type UserDataExport struct {
+	ID          ID                   `json:"id"`
+	UserID      ID                   `json:"-"`
+	Status      UserDataExportStatus `json:"status"`
+	Token       string               `json:"-"`
+	Path        string               `json:"-"`
+	Size        int64                `json:"size"`
+	Error       string               `json:"-"`
+	ExpiresAt   *time.Time           `json:"expires_at"`
+	CreatedAt   time.Time            `json:"created_at"`
+	CompletedAt *time.Time           `json:"completed_at"`
+}
+
+// Expired returns true if the export can no longer be downloaded.
+func (e *UserDataExport) Expired() bool {
+	return e.ExpiresAt != nil && e.ExpiresAt.Before(time.Now())
+}
+
+// Downloadable returns true if the export is built and not expired yet.
+func (e *UserDataExport) Downloadable() bool {
+	return e.Status == UserDataExportReady && !e.Expired()
+}
+
+// Filename returns the name of the archive offered on download.
+func (e *UserDataExport) Filename() string {
+	return "data-export-" + e.CreatedAt.Format(time.DateOnly) + ".zip"
+}
+
+// UserDataExportsFilter is used to filter user data export queries.
+type UserDataExportsFilter struct {
+	Page      int
+	PerPage   int
+	UserID    *ID
+	Status    UserDataExportStatus
+	ExpiresAt *FilterDT
+	CreatedAt *FilterDT
+}
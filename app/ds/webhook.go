@@ -0,0 +1,83 @@
+package ds
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// WebhookEventPing is the event type of the sample payload sent to test a webhook.
+const WebhookEventPing EventLogType = "ping"
+
+// Webhook is an admin-configured subscription that receives event logs
+// as signed HTTP POST requests to URL.
+type Webhook struct {
+	ID          ID         `json:"id"`
+	URL         string     `json:"url"`
+	Secret      string     `json:"-"`
+	EventTypes  []string   `json:"event_types"`
+	Description string     `json:"description"`
+	Active      bool       `json:"active"`
+	CreatedBy   *ID        `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	DeletedAt   *time.Time `json:"-"`
+}
+
+// Subscribed reports whether the webhook receives events of the given type.
+// A webhook without event types is subscribed to all of them.
+func (w *Webhook) Subscribed(t EventLogType) bool {
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, string(t))
+}
+
+// WebhookDeliveryStatus defines the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending marks a delivery waiting for the (next) attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+
+	// WebhookDeliverySucceeded marks a delivery the receiver responded with 2xx to.
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+
+	// WebhookDeliveryFailed marks a delivery that ran out of attempts.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a single event sent (or to be sent) to a webhook,
+// along with the result of the latest attempt.
+type WebhookDelivery struct {
+	ID             ID                    `json:"id"`
+	WebhookID      ID                    `json:"webhook_id"`
+	EventLogID     *ID                   `json:"event_log_id"`
+	EventType      EventLogType          `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at"`
+	ResponseStatus int                   `json:"response_status"`
+	ResponseBody   string                `json:"response_body"`
+	Error          string                `json:"error"`
+	DurationMS     int                   `json:"duration_ms" db:"duration_ms"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+}
+
+// WebhookPayload is the JSON body of a webhook delivery.
+type WebhookPayload struct {
+	// ID is the ID of the event log; it stays the same on redelivery.
+	ID        ID             `json:"id"`
+	Event     EventLogType   `json:"event"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      *EventLog      `json:"data"`
+	Meta      map[string]any `json:"meta,omitempty"`
+}
+
+// WebhookDeliveriesFilter is used to filter webhook deliveries.
+type WebhookDeliveriesFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	WebhookID *ID
+	Status    WebhookDeliveryStatus
+}
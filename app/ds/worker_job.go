@@ -0,0 +1,102 @@
+package ds
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	workerJobRunCtxKey ctxKey = "worker_job_run"
+
+	// CleanupWorkerJobRunsAfterDays defines how long the history of job runs is kept.
+	CleanupWorkerJobRunsAfterDays = 30
+)
+
+// WorkerJob is the state of a background job registered by the worker.
+type WorkerJob struct {
+	Name           string     `json:"name"`
+	Paused         bool       `json:"paused"`
+	RunRequestedAt *time.Time `json:"run_requested_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+
+	// LastRun is the latest run of the job, if any.
+	LastRun *WorkerJobRun `json:"last_run" db:"-"`
+}
+
+// WorkerJobTrigger defines what started a job run.
+type WorkerJobTrigger string
+
+const (
+	// WorkerJobTriggerSchedule marks a run started by the scheduler.
+	WorkerJobTriggerSchedule WorkerJobTrigger = "schedule"
+
+	// WorkerJobTriggerManual marks a run requested by an admin.
+	WorkerJobTriggerManual WorkerJobTrigger = "manual"
+
+	// WorkerJobTriggerCLI marks a run started from the command line.
+	WorkerJobTriggerCLI WorkerJobTrigger = "cli"
+)
+
+// WorkerJobRunStatus defines the state of a job run.
+type WorkerJobRunStatus string
+
+const (
+	// WorkerJobRunRunning marks a run in progress.
+	WorkerJobRunRunning WorkerJobRunStatus = "running"
+
+	// WorkerJobRunSucceeded marks a run finished without error.
+	WorkerJobRunSucceeded WorkerJobRunStatus = "succeeded"
+
+	// WorkerJobRunFailed marks a run finished with error.
+	WorkerJobRunFailed WorkerJobRunStatus = "failed"
+
+	// WorkerJobRunSkipped marks a requested run that didn't start, as the job was already running.
+	WorkerJobRunSkipped WorkerJobRunStatus = "skipped"
+)
+
+// WorkerJobRun is a single execution of a background job.
+type WorkerJobRun struct {
+	ID             ID                 `json:"id"`
+	JobName        string             `json:"job_name"`
+	Trigger        WorkerJobTrigger   `json:"trigger"`
+	Status         WorkerJobRunStatus `json:"status"`
+	ItemsProcessed int                `json:"items_processed"`
+	Error          string             `json:"error"`
+	DurationMS     int                `json:"duration_ms" db:"duration_ms"`
+	StartedAt      time.Time          `json:"started_at"`
+	FinishedAt     *time.Time         `json:"finished_at"`
+}
+
+// ToContext adds the given job run to the provided context.
+func (r *WorkerJobRun) ToContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, workerJobRunCtxKey, r)
+}
+
+// WorkerJobRunFromContext attempts to retrieve job run from the context.
+func WorkerJobRunFromContext(ctx context.Context) *WorkerJobRun {
+	if v := ctx.Value(workerJobRunCtxKey); v != nil {
+		if r, ok := v.(*WorkerJobRun); ok {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// AddProcessedItems adds n to the number of items processed by the job run in the context.
+// It does nothing when called outside of a job run.
+func AddProcessedItems(ctx context.Context, n int) {
+	if r := WorkerJobRunFromContext(ctx); r != nil {
+		r.ItemsProcessed += n
+	}
+}
+
+// WorkerJobRunsFilter is used to filter job runs.
+type WorkerJobRunsFilter struct {
+	Page      int
+	PerPage   int
+	WithCount bool
+	JobName   string
+	Status    WorkerJobRunStatus
+}
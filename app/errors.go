@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
@@ -34,6 +35,10 @@ const (
 type Error struct {
 	Code    int
 	Message string
+
+	// RetryAfter tells the client how long to wait before retrying.
+	// Used with CodeTooManyRequests, zero means unknown.
+	RetryAfter time.Duration
 }
 
 // Error implements the standard Go error interface.
@@ -42,6 +47,13 @@ func (e Error) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is an Error with the same code and message,
+// so an error that only differs in RetryAfter still matches its sentinel.
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error) //nolint:errorlint
+	return ok && t.Code == e.Code && t.Message == e.Message
+}
+
 // NewError is a factory function that creates a new structured Error.
 func NewError(code int, message string, params ...any) error {
 	if len(params) > 0 {
@@ -0,0 +1,717 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrNoFilenameSeparator indicates a migration filename is missing the required '_' separator.
+	ErrNoFilenameSeparator = errors.New("no required filename separator '_' found")
+	// ErrMultipleSameVersion indicates two or more migration files share the same version number.
+	ErrMultipleSameVersion = errors.New("multiple migrations of same version found")
+	// ErrMigrationModified indicates that the file of an applied migration was edited afterwards.
+	ErrMigrationModified = errors.New("applied migration was modified")
+	// ErrNoDownMigration indicates that the migration to roll back has no down section.
+	ErrNoDownMigration = errors.New("migration has no down section")
+	// ErrMigrationFileNotFound indicates that the applied migration has no file anymore.
+	ErrMigrationFileNotFound = errors.New("migration file not found")
+	// ErrNothingToRollback indicates that no migrations are applied.
+	ErrNothingToRollback = errors.New("no applied migrations")
+	// ErrInvalidMigrationSteps indicates that the number of migrations to roll back is not positive.
+	ErrInvalidMigrationSteps = errors.New("number of migrations must be positive")
+)
+
+//go:embed db_migrations/*.sql
+var mgFiles embed.FS
+
+const (
+	mgTable = "db_migrations"
+	mgDir   = "db_migrations"
+
+	// MigrationDownMarker is the line that separates the SQL applying the migration
+	// from the (optional) SQL rolling it back. Everything after the marker is the down section.
+	MigrationDownMarker = "-- +down"
+
+	// MigrationNoTxDirective is the line that makes the migration run outside a transaction,
+	// statement by statement, as required by CREATE INDEX CONCURRENTLY. Such a migration
+	// is not atomic, so it should only contain statements that can be safely re-run
+	// (e.g. CREATE INDEX CONCURRENTLY IF NOT EXISTS).
+	MigrationNoTxDirective = "-- +notransaction"
+)
+
+type migration struct {
+	Version  int64
+	Name     string
+	SQL      string
+	DownSQL  string
+	Checksum string
+	NoTx     bool
+}
+
+// fullName returns the name of the migration file without the extension.
+func (m migration) fullName() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// appliedMigration is a row of the migrations table.
+type appliedMigration struct {
+	Version    int64
+	Name       string
+	Checksum   *string
+	MigratedAt time.Time
+}
+
+// MigrationStatus describes a migration and whether it's applied to the database.
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	// AppliedAt is nil if the migration is pending.
+	AppliedAt *time.Time
+	// Modified is set if the file was edited after the migration was applied.
+	Modified bool
+	// HasDown is set if the migration can be rolled back.
+	HasDown bool
+	// Missing is set if the migration is applied, but its file no longer exists.
+	Missing bool
+}
+
+// Migrator applies and rolls back the migrations embedded from the "db_migrations" directory.
+// In dry-run mode the SQL is printed to Out instead of being executed, and the database is not changed.
+type Migrator struct {
+	DB     *DB
+	DryRun bool
+	Out    io.Writer
+}
+
+// NewMigrator returns the migrator of the database that prints to stdout.
+func NewMigrator(db *DB) *Migrator {
+	return &Migrator{
+		DB:  db,
+		Out: os.Stdout,
+	}
+}
+
+// MigrateDB runs SQL scripts from the './migrations' directory that haven't been committed yet.
+// It reads migration files, compares them with the migrations already applied to the database,
+// and executes the new migrations in a transaction.
+func MigrateDB(ctx context.Context, db *DB) (err error) {
+	err = NewMigrator(db).Up(ctx)
+	if err != nil {
+		err = fmt.Errorf("[ERROR] [MIGRATE]: %w", err)
+	}
+
+	return
+}
+
+// PendingMigrations returns the names of migrations that haven't been applied to the database yet.
+func PendingMigrations(ctx context.Context, db *DB) (names []string, err error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := loadAppliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range newMigrations(allMg, applied) {
+		names = append(names, m.fullName())
+	}
+
+	return names, nil
+}
+
+// Status returns all migrations, known by files or by the migrations table, sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := loadAppliedMigrations(ctx, m.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(allMg))
+	for _, mg := range allMg {
+		s := MigrationStatus{
+			Version: mg.Version,
+			Name:    mg.Name,
+			HasDown: mg.DownSQL != "",
+		}
+
+		if a, ok := findApplied(applied, mg.Version); ok {
+			s.AppliedAt = &a.MigratedAt
+			s.Modified = a.Checksum != nil && *a.Checksum != mg.Checksum
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	for _, a := range applied {
+		if _, ok := findMigration(allMg, a.Version); !ok {
+			statuses = append(statuses, MigrationStatus{
+				Version:   a.Version,
+				Name:      a.Name,
+				AppliedAt: &a.MigratedAt,
+				Missing:   true,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Up applies the pending migrations, each in its own transaction (unless marked with MigrationNoTxDirective).
+// Operations of the pending migrations that may lock tables for long are logged as warnings, see LintMigrations.
+// It fails with ErrMigrationModified if the file of an applied migration was edited;
+// use Redo to re-apply the edited latest migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	if len(allMg) == 0 {
+		slog.WarnContext(ctx, "no migrations found", "dir", mgDir)
+
+		return nil
+	}
+
+	applied, err := m.prepare(ctx, allMg)
+	if err != nil {
+		return err
+	}
+
+	var modified []string
+	for _, a := range applied {
+		mg, ok := findMigration(allMg, a.Version)
+		if ok && a.Checksum != nil && *a.Checksum != mg.Checksum {
+			modified = append(modified, mg.fullName())
+		}
+	}
+
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s", ErrMigrationModified, strings.Join(modified, ", "))
+	}
+
+	newMg := newMigrations(allMg, applied)
+	if len(newMg) == 0 {
+		slog.InfoContext(ctx, "nothing to migrate")
+
+		return nil
+	}
+
+	// tables of a clean database are empty, nothing to lock for long
+	if len(applied) > 0 {
+		for _, issue := range lintMigrations(allMg, newMg) {
+			slog.WarnContext(ctx, "risky migration", "migration", issue.Migration, "rule", issue.Rule,
+				"message", issue.Message, "statement", issue.Statement)
+		}
+	}
+
+	for _, mg := range newMg {
+		err = m.up(ctx, mg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the latest n applied migrations, newest first.
+// Nothing is rolled back unless every one of them has a down section.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	if n < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidMigrationSteps, n)
+	}
+
+	toRollback, err := m.latestApplied(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	for _, mg := range toRollback {
+		err = m.down(ctx, mg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Redo rolls back the latest applied migration and applies it again,
+// which is handy while the migration is being written.
+func (m *Migrator) Redo(ctx context.Context) error {
+	toRedo, err := m.latestApplied(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	mg := toRedo[0]
+
+	err = m.down(ctx, mg)
+	if err != nil {
+		return err
+	}
+
+	return m.up(ctx, mg)
+}
+
+// prepare creates the migrations table (or adds the checksum column to the table of older versions)
+// and returns the applied migrations. Checksums of migrations applied before checksums were introduced
+// are set from the current files. In dry-run mode the database is not changed.
+func (m *Migrator) prepare(ctx context.Context, allMg []migration) ([]appliedMigration, error) {
+	if !m.DryRun {
+		_, err := m.DB.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS `+mgTable+` (
+				version     BIGINT NOT NULL PRIMARY KEY,
+				name        TEXT NOT NULL,
+				migrated_at TIMESTAMPTZ NOT NULL
+			);
+			ALTER TABLE `+mgTable+` ADD COLUMN IF NOT EXISTS checksum TEXT;`)
+		if err != nil {
+			return nil, fmt.Errorf("init migrations table: %w", err)
+		}
+	}
+
+	applied, err := loadAppliedMigrations(ctx, m.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.DryRun {
+		return applied, nil
+	}
+
+	for i, a := range applied {
+		mg, ok := findMigration(allMg, a.Version)
+		if a.Checksum != nil || !ok {
+			continue
+		}
+
+		_, err = m.DB.Exec(ctx, "UPDATE "+mgTable+" SET checksum = $1 WHERE version = $2", mg.Checksum, mg.Version)
+		if err != nil {
+			return nil, fmt.Errorf("save checksum of %s: %w", mg.fullName(), err)
+		}
+
+		applied[i].Checksum = &mg.Checksum
+	}
+
+	return applied, nil
+}
+
+// latestApplied returns up to n latest applied migrations, newest first,
+// and checks that all of them can be rolled back.
+func (m *Migrator) latestApplied(ctx context.Context, n int) ([]migration, error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := loadAppliedMigrations(ctx, m.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(applied) == 0 {
+		return nil, ErrNothingToRollback
+	}
+
+	slices.Reverse(applied)
+	applied = applied[:min(n, len(applied))]
+
+	list := make([]migration, 0, len(applied))
+	for _, a := range applied {
+		mg, ok := findMigration(allMg, a.Version)
+		if !ok {
+			return nil, fmt.Errorf("%d_%s: %w", a.Version, a.Name, ErrMigrationFileNotFound)
+		}
+
+		if mg.DownSQL == "" {
+			return nil, fmt.Errorf("%s: %w", mg.fullName(), ErrNoDownMigration)
+		}
+
+		list = append(list, mg)
+	}
+
+	return list, nil
+}
+
+// up applies the migration and records it.
+func (m *Migrator) up(ctx context.Context, mg migration) error {
+	return m.exec(ctx, "up", mg, mg.SQL, func(ctx context.Context, db execer) error {
+		_, err := db.Exec(ctx, "INSERT INTO "+mgTable+" (version, name, checksum, migrated_at) VALUES ($1, $2, $3, $4)",
+			mg.Version, mg.Name, mg.Checksum, time.Now())
+		if err != nil {
+			return fmt.Errorf("❌ save migration %s: %w", mg.fullName(), err)
+		}
+
+		return nil
+	})
+}
+
+// down rolls back the migration and removes its record.
+func (m *Migrator) down(ctx context.Context, mg migration) error {
+	return m.exec(ctx, "down", mg, mg.DownSQL, func(ctx context.Context, db execer) error {
+		_, err := db.Exec(ctx, "DELETE FROM "+mgTable+" WHERE version = $1", mg.Version)
+		if err != nil {
+			return fmt.Errorf("❌ delete migration %s: %w", mg.fullName(), err)
+		}
+
+		return nil
+	})
+}
+
+// execer is implemented by both pgx.Tx and a single connection of the pool.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// exec executes the SQL of the migration and records the result in a single transaction,
+// or statement by statement on a single connection if the migration is marked with MigrationNoTxDirective.
+// In dry-run mode the statements are printed instead.
+func (m *Migrator) exec(ctx context.Context, direction string, mg migration, sql string,
+	record func(ctx context.Context, db execer) error) error {
+	if m.DryRun {
+		header := fmt.Sprintf("-- %s %s", direction, mg.fullName())
+		if mg.NoTx {
+			header += " (no transaction)"
+		}
+
+		_, err := fmt.Fprintln(m.Out, header)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range sqlStatements(sql) {
+			_, err = fmt.Fprintln(m.Out, stmt)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	apply := func(ctx context.Context, db execer) error {
+		now := time.Now()
+
+		statements := []string{sql}
+		if mg.NoTx {
+			// each statement runs in its own implicit transaction
+			statements = splitSQL(sql)
+		}
+
+		for _, stmt := range statements {
+			_, err := db.Exec(ctx, stmt)
+			if err != nil {
+				return fmt.Errorf("❌ %s %s: %w", direction, mg.fullName(), err)
+			}
+		}
+
+		err := record(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		slog.InfoContext(ctx, "migrated", "direction", direction,
+			"version", mg.Version, "name", mg.Name, "duration", time.Since(now))
+
+		return nil
+	}
+
+	if mg.NoTx {
+		conn, err := m.DB.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("acquire connection: %w", err)
+		}
+		defer conn.Release()
+
+		return apply(ctx, conn)
+	}
+
+	return RunInTx(ctx, m.DB, func(ctx context.Context, tx pgx.Tx) error {
+		return apply(ctx, tx)
+	})
+}
+
+// ParseMigration splits the content of a migration file into the SQL applying the migration
+// and the SQL rolling it back, see MigrationDownMarker. The down SQL is empty if the file
+// has no down section, or if the section contains only comments.
+func ParseMigration(src string) (up, down string) {
+	offset := 0
+	for line := range strings.Lines(src) {
+		if strings.TrimSpace(line) == MigrationDownMarker {
+			up = strings.TrimSpace(src[:offset])
+			down = strings.TrimSpace(src[offset+len(line):])
+
+			if len(sqlStatements(down)) == 0 {
+				down = ""
+			}
+
+			return up, down
+		}
+
+		offset += len(line)
+	}
+
+	return strings.TrimSpace(src), ""
+}
+
+// MigrationChecksum returns the checksum of the SQL applying the migration.
+// The down section is not included, so it can be added to an applied migration.
+func MigrationChecksum(up string) string {
+	sum := sha256.Sum256([]byte(up))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// sqlStatements returns the statements of the SQL, each formatted as a single line.
+func sqlStatements(sql string) []string {
+	stmts := splitSQL(sql)
+	for i, stmt := range stmts {
+		stmts[i] = prettyPrintSQL(stmt)
+	}
+
+	return stmts
+}
+
+var dollarQuoteTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z_0-9]*)?\$`)
+
+// splitSQL splits the SQL into statements terminated by ";" and removes comments.
+// Quoted strings and identifiers, and dollar-quoted bodies (e.g. of DO blocks) are kept intact.
+func splitSQL(sql string) []string {
+	var (
+		stmts []string
+		b     strings.Builder
+	)
+
+	flush := func() {
+		stmt := strings.TrimSpace(b.String())
+		if stmt != "" && stmt != ";" {
+			stmts = append(stmts, stmt)
+		}
+		b.Reset()
+	}
+
+	for i := 0; i < len(sql); {
+		rest := sql[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			n := strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			b.WriteByte(' ')
+			i += n
+		case strings.HasPrefix(rest, "/*"):
+			n := strings.Index(rest, "*/")
+			if n < 0 {
+				n = len(rest)
+			} else {
+				n += 2
+			}
+			b.WriteByte(' ')
+			i += n
+		case rest[0] == '\'' || rest[0] == '"':
+			n := quotedLen(rest)
+			b.WriteString(rest[:n])
+			i += n
+		case rest[0] == '$' && dollarQuoteTag.MatchString(rest):
+			tag := dollarQuoteTag.FindString(rest)
+			n := strings.Index(rest[len(tag):], tag)
+			if n < 0 {
+				n = len(rest)
+			} else {
+				n += 2 * len(tag)
+			}
+			b.WriteString(rest[:n])
+			i += n
+		case rest[0] == ';':
+			b.WriteByte(';')
+			flush()
+			i++
+		default:
+			b.WriteByte(rest[0])
+			i++
+		}
+	}
+
+	flush()
+
+	return stmts
+}
+
+// quotedLen returns the length of the quoted string or identifier the text starts with,
+// including the quotes. Doubled quotes are escapes.
+func quotedLen(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		if s[i] != q {
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == q {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(s)
+}
+
+// loadMigrations reads migration files from the embedded directory.
+func loadMigrations() ([]migration, error) {
+	allMg := make([]migration, 0)
+
+	dir, err := mgFiles.ReadDir(mgDir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	for _, file := range dir {
+		mgData, err := mgFiles.ReadFile(path.Join(mgDir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read '%s': %w", file.Name(), err)
+		}
+
+		mg, err := parseMigrationFile(file.Name(), string(mgData))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range allMg {
+			if m.Version == mg.Version {
+				return nil, fmt.Errorf("version '%d': %w", mg.Version, ErrMultipleSameVersion)
+			}
+		}
+
+		allMg = append(allMg, mg)
+	}
+
+	return allMg, nil
+}
+
+// parseMigrationFile returns the migration of the file named "<version>_<name>.sql".
+func parseMigrationFile(filename, src string) (migration, error) {
+	name := strings.TrimSuffix(filename, ".sql")
+
+	idx := strings.Index(name, "_")
+	if idx < 0 {
+		return migration{}, fmt.Errorf("filename %s: %w", name, ErrNoFilenameSeparator)
+	}
+
+	version, err := strconv.ParseInt(name[:idx], 10, 64)
+	if err != nil {
+		return migration{}, fmt.Errorf("parse version from migration file: %s: %w", name, err)
+	}
+
+	up, down := ParseMigration(src)
+
+	return migration{
+		Version:  version,
+		Name:     name[idx+1:],
+		SQL:      up,
+		DownSQL:  down,
+		Checksum: MigrationChecksum(up),
+		NoTx:     hasMigrationDirective(src, MigrationNoTxDirective),
+	}, nil
+}
+
+// hasMigrationDirective reports whether the directive is on its own line in the migration.
+func hasMigrationDirective(src, directive string) bool {
+	for line := range strings.Lines(src) {
+		if strings.TrimSpace(line) == directive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadAppliedMigrations returns the migrations applied to the database, sorted by version.
+// On a clean database (no migrations table yet) the list is empty.
+func loadAppliedMigrations(ctx context.Context, db *DB) ([]appliedMigration, error) {
+	applied := make([]appliedMigration, 0)
+
+	// "SELECT *" as the checksum column may not exist yet
+	err := pgxscan.Select(ctx, db, &applied, `SELECT * FROM `+mgTable+` ORDER BY version`)
+	if err != nil && !isNoMigrationsTable(err) {
+		return nil, fmt.Errorf("load completed migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// newMigrations returns migrations that are not completed yet, sorted by version.
+func newMigrations(allMg []migration, applied []appliedMigration) []migration {
+	newMg := make([]migration, 0)
+
+	for _, m := range allMg {
+		if _, ok := findApplied(applied, m.Version); !ok {
+			newMg = append(newMg, m)
+		}
+	}
+
+	sort.Slice(newMg, func(i, j int) bool {
+		return newMg[i].Version < newMg[j].Version
+	})
+
+	return newMg
+}
+
+func findMigration(allMg []migration, version int64) (migration, bool) {
+	i := slices.IndexFunc(allMg, func(m migration) bool { return m.Version == version })
+	if i < 0 {
+		return migration{}, false
+	}
+
+	return allMg[i], true
+}
+
+func findApplied(applied []appliedMigration, version int64) (appliedMigration, bool) {
+	i := slices.IndexFunc(applied, func(a appliedMigration) bool { return a.Version == version })
+	if i < 0 {
+		return appliedMigration{}, false
+	}
+
+	return applied[i], true
+}
+
+// isNoMigrationsTable reports whether err is caused by the missing migrations table (clean database).
+func isNoMigrationsTable(err error) bool {
+	pgErr, ok := errors.AsType[*pgconn.PgError](err)
+
+	return ok && pgErr.Code == "42P01" && strings.Contains(err.Error(), mgTable)
+}
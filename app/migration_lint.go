@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Rules of the migration lint. Each flags an operation that may lock a table for long
+// (or fail) when applied to a database in use.
+const (
+	// LintIndexNotConcurrent flags indexes built on existing tables without CONCURRENTLY,
+	// which blocks writes to the table until the index is built.
+	LintIndexNotConcurrent = "index-not-concurrent"
+
+	// LintConcurrentlyInTx flags CONCURRENTLY operations in migrations that run in a transaction,
+	// which Postgres rejects.
+	LintConcurrentlyInTx = "concurrently-in-transaction"
+
+	// LintSetNotNull flags SET NOT NULL without a check constraint added by an earlier migration,
+	// which scans the whole table under an exclusive lock.
+	LintSetNotNull = "set-not-null"
+
+	// LintTableRewrite flags operations that rewrite the whole table under an exclusive lock.
+	LintTableRewrite = "table-rewrite"
+
+	// LintConstraintValidation flags constraints added without NOT VALID,
+	// which are validated against all rows under a lock.
+	LintConstraintValidation = "constraint-validation"
+
+	// LintBackfillInTx flags updates of a table altered in the same transaction,
+	// which keep the table locked by ALTER TABLE until the update is done.
+	LintBackfillInTx = "backfill-in-transaction"
+)
+
+// MigrationIssue is an operation of a migration flagged by the lint.
+type MigrationIssue struct {
+	Migration string
+	Rule      string
+	Statement string
+	Message   string
+}
+
+// String implements fmt.Stringer.
+func (i MigrationIssue) String() string {
+	return fmt.Sprintf("%s: [%s] %s\n\t%s", i.Migration, i.Rule, i.Message, i.Statement)
+}
+
+// LintMigrations checks the migrations that haven't been applied to the database yet.
+func LintMigrations(ctx context.Context, db *DB) ([]MigrationIssue, error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := loadAppliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return lintMigrations(allMg, newMigrations(allMg, applied)), nil
+}
+
+// LintAllMigrations checks all migrations, including applied ones.
+func LintAllMigrations() ([]MigrationIssue, error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return lintMigrations(allMg, allMg), nil
+}
+
+// LintMigrationFile checks the migration file that is not embedded yet (e.g. a new one),
+// in the context of the embedded migrations of earlier versions.
+func LintMigrationFile(path, src string) ([]MigrationIssue, error) {
+	allMg, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	mg, err := parseMigrationFile(filepath.Base(path), src)
+	if err != nil {
+		return nil, err
+	}
+
+	allMg = slices.DeleteFunc(allMg, func(m migration) bool { return m.Version == mg.Version })
+
+	return lintMigrations(append(allMg, mg), []migration{mg}), nil
+}
+
+// lintMigrations checks the migrations of toLint; allMg provide the context,
+// e.g. check constraints added by earlier migrations.
+func lintMigrations(allMg, toLint []migration) []MigrationIssue {
+	issues := make([]MigrationIssue, 0)
+
+	for _, mg := range toLint {
+		notNullChecks := map[string]bool{}
+		for _, earlier := range allMg {
+			if earlier.Version >= mg.Version {
+				continue
+			}
+
+			for _, stmt := range splitSQL(earlier.SQL) {
+				addNotNullChecks(notNullChecks, normalizeSQL(stmt))
+			}
+		}
+
+		issues = append(issues, lintMigration(mg, notNullChecks)...)
+	}
+
+	return issues
+}
+
+var (
+	lintCreateTable   = regexp.MustCompile(`^CREATE (?:UNLOGGED |TEMP |TEMPORARY )?TABLE (?:IF NOT EXISTS )?([^\s(]+)`)
+	lintCreateIndex   = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?.*? ON (?:ONLY )?([^\s(]+)`)
+	lintConcurrently  = regexp.MustCompile(`^(?:CREATE|DROP|REINDEX)\b.*\bCONCURRENTLY\b`)
+	lintAlterTable    = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([^\s(]+) (.*)$`)
+	lintSetNotNull    = regexp.MustCompile(`ALTER (?:COLUMN )?([^\s,]+) SET NOT NULL`)
+	lintAlterType     = regexp.MustCompile(`ALTER (?:COLUMN )?([^\s,]+) (?:SET DATA )?TYPE `)
+	lintAddColumn     = regexp.MustCompile(`ADD (?:COLUMN )?(?:IF NOT EXISTS )?[^,]*`)
+	lintVolatileDef   = regexp.MustCompile(`DEFAULT \(?\s*(?:RANDOM|GEN_RANDOM_UUID|UUID_GENERATE_V[14]\w*|CLOCK_TIMESTAMP|TIMEOFDAY|NEXTVAL)\s*\(`)
+	lintSerialType    = regexp.MustCompile(`^ADD (?:COLUMN )?(?:IF NOT EXISTS )?\S+ (?:SMALL|BIG)?SERIAL\b`)
+	lintStoredColumn  = regexp.MustCompile(`GENERATED ALWAYS AS .* STORED`)
+	lintAddConstraint = regexp.MustCompile(`ADD (?:CONSTRAINT \S+ )?(FOREIGN KEY|CHECK|UNIQUE|PRIMARY KEY|EXCLUDE)\b`)
+	lintRewriteTable  = regexp.MustCompile(`\bSET (?:TABLESPACE|LOGGED|UNLOGGED)\b`)
+	lintVacuumFull    = regexp.MustCompile(`^(?:VACUUM (?:\()?FULL|CLUSTER)\b`)
+	lintUpdate        = regexp.MustCompile(`\bUPDATE (?:ONLY )?([^\s(]+) SET\b`)
+	lintNotNullCheck  = regexp.MustCompile(`CHECK \(\s*\(?([^\s()]+) IS NOT NULL\)?\s*\)`)
+)
+
+// lintMigration checks the statements of the migration. Tables created by the migration
+// are empty, so the operations on them are not flagged.
+func lintMigration(mg migration, notNullChecks map[string]bool) []MigrationIssue {
+	issues := make([]MigrationIssue, 0)
+	flag := func(stmt, rule, msg string, args ...any) {
+		issues = append(issues, MigrationIssue{
+			Migration: mg.fullName(),
+			Rule:      rule,
+			Statement: shortStatement(stmt),
+			Message:   fmt.Sprintf(msg, args...),
+		})
+	}
+
+	created := map[string]bool{}
+	altered := map[string]bool{}
+
+	for _, stmt := range splitSQL(mg.SQL) {
+		sql := normalizeSQL(stmt)
+
+		if m := lintCreateTable.FindStringSubmatch(sql); m != nil {
+			created[tableName(m[1])] = true
+			continue
+		}
+
+		if !mg.NoTx && lintConcurrently.MatchString(sql) {
+			flag(stmt, LintConcurrentlyInTx, "CONCURRENTLY can't run in a transaction, add the '%s' line to the migration",
+				MigrationNoTxDirective)
+		}
+
+		if m := lintCreateIndex.FindStringSubmatch(sql); m != nil {
+			table := tableName(m[2])
+			if m[1] == "" && !created[table] {
+				flag(stmt, LintIndexNotConcurrent,
+					"index on %s blocks writes until it's built, use CREATE INDEX CONCURRENTLY in a migration marked '%s'",
+					table, MigrationNoTxDirective)
+			}
+
+			continue
+		}
+
+		if lintVacuumFull.MatchString(sql) {
+			flag(stmt, LintTableRewrite, "rewrites the table under an exclusive lock")
+			continue
+		}
+
+		if m := lintUpdate.FindStringSubmatch(sql); m != nil {
+			table := tableName(m[1])
+			if !mg.NoTx && altered[table] {
+				flag(stmt, LintBackfillInTx,
+					"%s stays locked by ALTER TABLE until the update is done, move the update to a separate migration", table)
+			}
+		}
+
+		m := lintAlterTable.FindStringSubmatch(sql)
+		if m == nil {
+			continue
+		}
+
+		table := tableName(m[1])
+		if created[table] {
+			continue
+		}
+
+		altered[table] = true
+		actions := m[2]
+
+		for _, col := range lintSetNotNull.FindAllStringSubmatch(actions, -1) {
+			if !notNullChecks[table+"."+columnName(col[1])] {
+				flag(stmt, LintSetNotNull,
+					"SET NOT NULL on %s.%s scans the table under an exclusive lock, add CHECK (%[2]s IS NOT NULL) NOT VALID "+
+						"and VALIDATE it in earlier migrations first", table, columnName(col[1]))
+			}
+		}
+
+		if lintAlterType.MatchString(actions) {
+			flag(stmt, LintTableRewrite, "changing the column type of %s may rewrite the table under an exclusive lock", table)
+		}
+
+		for _, add := range lintAddColumn.FindAllString(actions, -1) {
+			if lintAddConstraint.MatchString(add) {
+				continue
+			}
+
+			if lintVolatileDef.MatchString(add) || lintSerialType.MatchString(add) || lintStoredColumn.MatchString(add) {
+				flag(stmt, LintTableRewrite,
+					"a column with a volatile default or a stored generated column rewrites %s under an exclusive lock", table)
+			}
+		}
+
+		if lintRewriteTable.MatchString(actions) {
+			flag(stmt, LintTableRewrite, "rewrites %s under an exclusive lock", table)
+		}
+
+		for _, c := range lintAddConstraint.FindAllStringSubmatch(actions, -1) {
+			switch c[1] {
+			case "FOREIGN KEY", "CHECK":
+				if !strings.Contains(actions, "NOT VALID") {
+					flag(stmt, LintConstraintValidation,
+						"the %s constraint is validated against all rows of %s under a lock, "+
+							"add it NOT VALID and VALIDATE it in a separate migration", strings.ToLower(c[1]), table)
+				}
+			default:
+				if !strings.Contains(actions, "USING INDEX") {
+					flag(stmt, LintIndexNotConcurrent,
+						"the %s constraint builds an index on %s that blocks writes, "+
+							"build the index CONCURRENTLY first and add the constraint USING INDEX", strings.ToLower(c[1]), table)
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// addNotNullChecks records "table.column" of the CHECK (column IS NOT NULL) constraints added by the statement.
+func addNotNullChecks(checks map[string]bool, sql string) {
+	m := lintAlterTable.FindStringSubmatch(sql)
+	if m == nil {
+		return
+	}
+
+	for _, c := range lintNotNullCheck.FindAllStringSubmatch(m[2], -1) {
+		checks[tableName(m[1])+"."+columnName(c[1])] = true
+	}
+}
+
+// normalizeSQL returns the statement in upper case with whitespace collapsed, for matching.
+func normalizeSQL(stmt string) string {
+	return strings.ToUpper(strings.TrimSpace(replaceSpaces.ReplaceAllString(stmt, " ")))
+}
+
+// tableName returns the unquoted table name in lower case, without the default schema.
+func tableName(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, `"`, ""))
+	name = strings.TrimSuffix(name, ";")
+
+	return strings.TrimPrefix(name, "public.")
+}
+
+// columnName returns the unquoted column name in lower case.
+func columnName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, `"`, ""))
+}
+
+// lintStatementMaxLen limits the length of the statement shown with the issue.
+const lintStatementMaxLen = 120
+
+// shortStatement returns the statement as a single line, truncated if it's too long.
+func shortStatement(stmt string) string {
+	stmt = prettyPrintSQL(stmt)
+	if len(stmt) <= lintStatementMaxLen {
+		return stmt
+	}
+
+	return strings.ToValidUTF8(stmt[:lintStatementMaxLen], "") + "..."
+}
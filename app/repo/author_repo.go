@@ -0,0 +1,264 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// ErrAuthorNotFound is returned when an author is not found.
+var ErrAuthorNotFound = app.ErrNotFound("author not found")
+
+// authorBookCount counts the approved public books of the author "a".
+const authorBookCount = `(
+	SELECT COUNT(*)
+	FROM book_authors ba
+	JOIN entities e ON e.id = ba.book_id
+	WHERE ba.author_id = a.id
+	  AND e.status = 'approved'
+	  AND e.visibility = 'public'
+	  AND e.deleted_at IS NULL
+) AS book_count`
+
+// authorPublished limits the authors "a" to the ones with approved public books.
+const authorPublished = `EXISTS (
+	SELECT 1
+	FROM book_authors ba
+	JOIN entities e ON e.id = ba.book_id
+	WHERE ba.author_id = a.id
+	  AND e.status = 'approved'
+	  AND e.visibility = 'public'
+	  AND e.deleted_at IS NULL
+)`
+
+// CreateAuthor inserts a new author into the database.
+func (r *Repo) CreateAuthor(ctx context.Context, a *ds.Author) error {
+	_, span := r.tracer.Start(ctx, "CreateAuthor")
+	defer span.End()
+
+	if a.ID.IsNil() {
+		a.ID = ds.NewID()
+	}
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+
+	if a.Links == nil {
+		a.Links = make([]ds.AuthorLink, 0)
+	}
+
+	return r.insert(ctx, "authors", data{
+		"id":         a.ID,
+		"slug":       a.Slug,
+		"name":       a.Name,
+		"bio_raw":    a.BioRaw,
+		"bio":        a.Bio,
+		"links":      a.Links,
+		"created_at": a.CreatedAt,
+	})
+}
+
+// UpdateAuthor updates the editable fields of the author.
+func (r *Repo) UpdateAuthor(ctx context.Context, a *ds.Author) error {
+	_, span := r.tracer.Start(ctx, "UpdateAuthor")
+	defer span.End()
+
+	if a.Links == nil {
+		a.Links = make([]ds.AuthorLink, 0)
+	}
+
+	a.UpdatedAt = new(time.Now())
+
+	return r.update(ctx, a.ID, "authors", data{
+		"slug":       a.Slug,
+		"name":       a.Name,
+		"bio_raw":    a.BioRaw,
+		"bio":        a.Bio,
+		"links":      a.Links,
+		"updated_at": a.UpdatedAt,
+	})
+}
+
+// GetAuthorByID retrieves an author by its ID.
+func (r *Repo) GetAuthorByID(ctx context.Context, id ds.ID) (*ds.Author, error) {
+	_, span := r.tracer.Start(ctx, "GetAuthorByID")
+	defer span.End()
+
+	a := new(ds.Author)
+	err := pgxscan.Get(ctx, r.getDB(ctx), a, `SELECT a.*, `+authorBookCount+` FROM authors a WHERE a.id = $1`, id)
+	if noRows(err) {
+		return nil, ErrAuthorNotFound
+	}
+
+	return a, err
+}
+
+// GetAuthorBySlug retrieves an author by its slug.
+func (r *Repo) GetAuthorBySlug(ctx context.Context, slug string) (*ds.Author, error) {
+	_, span := r.tracer.Start(ctx, "GetAuthorBySlug")
+	defer span.End()
+
+	a := new(ds.Author)
+	err := pgxscan.Get(ctx, r.getDB(ctx), a, `SELECT a.*, `+authorBookCount+` FROM authors a WHERE a.slug = $1`, slug)
+	if noRows(err) {
+		return nil, ErrAuthorNotFound
+	}
+
+	return a, err
+}
+
+// FindAuthorByName finds the author by name (case-insensitively) or by the slug of the name.
+// Authors not merged into other ones are preferred.
+func (r *Repo) FindAuthorByName(ctx context.Context, name, slug string) (*ds.Author, error) {
+	_, span := r.tracer.Start(ctx, "FindAuthorByName")
+	defer span.End()
+
+	const query = `SELECT * FROM authors
+		WHERE lower(name) = lower($1) OR slug = $2
+		ORDER BY merged_into_id IS NOT NULL, lower(name) = lower($1) DESC, created_at
+		LIMIT 1`
+
+	a := new(ds.Author)
+	err := pgxscan.Get(ctx, r.getDB(ctx), a, query, name, slug)
+	if noRows(err) {
+		return nil, ErrAuthorNotFound
+	}
+
+	return a, err
+}
+
+// AuthorSlugExists reports whether an author has the given slug.
+func (r *Repo) AuthorSlugExists(ctx context.Context, slug string) (exists bool, err error) {
+	_, span := r.tracer.Start(ctx, "AuthorSlugExists")
+	defer span.End()
+
+	err = r.getDB(ctx).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM authors WHERE slug = $1)`, slug).Scan(&exists)
+	return
+}
+
+// FilterAuthors returns authors matching the filter, ordered by name.
+func (r *Repo) FilterAuthors(ctx context.Context, f ds.AuthorsFilter) (authors []ds.Author, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterAuthors")
+	defer span.End()
+
+	count, err = r.filter("authors a", "a").
+		columns("a.*", authorBookCount).
+		whereIf(!f.WithMerged, "a.merged_into_id IS NULL", nil).
+		whereIf(f.OnlyPublished, authorPublished, nil).
+		filterString("a.name", f.Name).
+		paginate(f.Page, f.PerPage).
+		order("a.name", "asc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &authors)
+
+	return
+}
+
+// BookAuthors returns the authors of the book in their order.
+func (r *Repo) BookAuthors(ctx context.Context, bookID ds.ID) ([]ds.BookAuthor, error) {
+	_, span := r.tracer.Start(ctx, "BookAuthors")
+	defer span.End()
+
+	authors := make([]ds.BookAuthor, 0)
+	const query = `SELECT a.id, a.slug, a.name
+		FROM book_authors ba
+		JOIN authors a ON a.id = ba.author_id
+		WHERE ba.book_id = $1
+		ORDER BY ba.position`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &authors, query, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("select book authors: %w", err)
+	}
+
+	return authors, nil
+}
+
+// BooksAuthors returns the authors of the books in their order, by book ID.
+func (r *Repo) BooksAuthors(ctx context.Context, bookIDs []ds.ID) (map[ds.ID][]ds.BookAuthor, error) {
+	_, span := r.tracer.Start(ctx, "BooksAuthors")
+	defer span.End()
+
+	var rows []struct {
+		BookID ds.ID
+		ds.BookAuthor
+	}
+
+	const query = `SELECT ba.book_id, a.id, a.slug, a.name
+		FROM book_authors ba
+		JOIN authors a ON a.id = ba.author_id
+		WHERE ba.book_id = ANY($1)
+		ORDER BY ba.position`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &rows, query, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make(map[ds.ID][]ds.BookAuthor, len(bookIDs))
+	for _, id := range bookIDs {
+		authors[id] = make([]ds.BookAuthor, 0)
+	}
+	for _, row := range rows {
+		authors[row.BookID] = append(authors[row.BookID], row.BookAuthor)
+	}
+
+	return authors, nil
+}
+
+// ReplaceBookAuthors replaces the authors of the book with the given (resolved) ones, stored in the given order.
+func (r *Repo) ReplaceBookAuthors(ctx context.Context, bookID ds.ID, authors []ds.BookAuthor) error {
+	_, span := r.tracer.Start(ctx, "ReplaceBookAuthors")
+	defer span.End()
+
+	err := r.exec(ctx, "DELETE FROM book_authors WHERE book_id = $1", bookID)
+	if err != nil {
+		return fmt.Errorf("delete book authors: %w", err)
+	}
+
+	if len(authors) == 0 {
+		return nil
+	}
+
+	rows := make([]data, len(authors))
+	for i, a := range authors {
+		rows[i] = data{
+			"book_id":   bookID,
+			"author_id": a.ID,
+			"position":  i,
+		}
+	}
+
+	return r.insert(ctx, "book_authors", rows...)
+}
+
+// MergeAuthor moves the books of the source author to the target one and marks the source
+// (and the authors merged into it before) as merged into the target.
+// Books listing both authors keep the target at its own position.
+func (r *Repo) MergeAuthor(ctx context.Context, sourceID, targetID ds.ID) error {
+	_, span := r.tracer.Start(ctx, "MergeAuthor")
+	defer span.End()
+
+	const deleteShared = `DELETE FROM book_authors s
+		USING book_authors t
+		WHERE s.author_id = $1 AND t.author_id = $2 AND s.book_id = t.book_id`
+
+	err := r.exec(ctx, deleteShared, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("delete shared book authors: %w", err)
+	}
+
+	err = r.exec(ctx, `UPDATE book_authors SET author_id = $2 WHERE author_id = $1`, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("move book authors: %w", err)
+	}
+
+	return r.exec(ctx, `UPDATE authors SET merged_into_id = $2, updated_at = NOW() WHERE id = $1 OR merged_into_id = $1`,
+		sourceID, targetID)
+}
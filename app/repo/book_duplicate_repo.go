@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// bookDuplicatesLimit is the max number of duplicate candidates returned.
+const bookDuplicatesLimit = 10
+
+// FindBookDuplicates returns the books (not rejected or deleted) that have an ISBN in common with the query,
+// or a title similar to it, most likely duplicates first.
+// With q.OnlyPublic, only the approved public books are returned.
+// Whether the candidates are likely duplicates is up to the caller, see ds.BookDuplicate.Likely.
+// Similar titles are matched with the % operator, so the trigram index of the titles is used;
+// its threshold is set to ds.BookDuplicateMinTitleSimilarity for the transaction of the query.
+func (r *Repo) FindBookDuplicates(ctx context.Context, q ds.BookDuplicatesQuery) ([]ds.BookDuplicate, error) {
+	_, span := r.tracer.Start(ctx, "FindBookDuplicates")
+	defer span.End()
+
+	const query = `
+		WITH candidates AS (
+			SELECT id FROM entities WHERE title % $1
+			UNION
+			SELECT id FROM books WHERE isbn10 = ANY($4) OR isbn13 = ANY($4)
+			UNION
+			SELECT book_id FROM book_editions WHERE isbn10 = ANY($4) OR isbn13 = ANY($4)
+		)
+		SELECT *
+		FROM (
+			SELECT e.id,
+			       e.public_id,
+			       e.title,
+			       e.status,
+			       similarity(e.title, $1) AS title_similarity,
+			       (
+			         SELECT COUNT(*)
+			         FROM book_authors ba
+			         JOIN authors a ON a.id = ba.author_id
+			         WHERE ba.book_id = b.id
+			           AND (lower(a.name) = ANY($2) OR a.slug = ANY($3))
+			       ) AS shared_authors,
+			       (
+			         b.isbn10 = ANY($4) OR b.isbn13 = ANY($4) OR EXISTS (
+			           SELECT 1
+			           FROM book_editions be
+			           WHERE be.book_id = b.id
+			             AND (be.isbn10 = ANY($4) OR be.isbn13 = ANY($4))
+			         )
+			       ) AS same_isbn
+			FROM candidates c
+			JOIN entities e ON e.id = c.id
+			JOIN books b ON b.id = e.id
+			WHERE e.type = 'book'
+			  AND e.status <> 'rejected'
+			  AND e.deleted_at IS NULL
+			  AND e.id <> $5
+			  AND (NOT $8 OR (e.status = 'approved' AND e.visibility = 'public'))
+		) d
+		WHERE d.same_isbn OR d.title_similarity >= $6
+		ORDER BY d.same_isbn DESC, d.shared_authors > 0 DESC, d.title_similarity DESC
+		LIMIT $7
+	`
+
+	duplicates := make([]ds.BookDuplicate, 0)
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		const setThreshold = `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`
+
+		_, err := r.getDB(ctx).Exec(ctx, setThreshold, fmt.Sprint(ds.BookDuplicateMinTitleSimilarity))
+		if err != nil {
+			return fmt.Errorf("set similarity threshold: %w", err)
+		}
+
+		return pgxscan.Select(ctx, r.getDB(ctx), &duplicates, query,
+			q.Title, q.Authors, q.AuthorSlugs, q.ISBNs, q.ExcludeID,
+			ds.BookDuplicateMinTitleSimilarity, bookDuplicatesLimit, q.OnlyPublic)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find book duplicates: %w", err)
+	}
+
+	return duplicates, nil
+}
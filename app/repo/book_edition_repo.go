@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// BookEditions returns the editions of the book in their order.
+func (r *Repo) BookEditions(ctx context.Context, bookID ds.ID) ([]ds.BookEdition, error) {
+	_, span := r.tracer.Start(ctx, "BookEditions")
+	defer span.End()
+
+	editions := make([]ds.BookEdition, 0)
+	const query = `SELECT * FROM book_editions WHERE book_id = $1 ORDER BY position`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &editions, query, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("select book editions: %w", err)
+	}
+
+	return editions, nil
+}
+
+// ReplaceBookEditions replaces the editions of the book with the given ones,
+// which are stored in the given order with new IDs.
+func (r *Repo) ReplaceBookEditions(ctx context.Context, bookID ds.ID, editions []ds.BookEdition) error {
+	_, span := r.tracer.Start(ctx, "ReplaceBookEditions")
+	defer span.End()
+
+	err := r.exec(ctx, "DELETE FROM book_editions WHERE book_id = $1", bookID)
+	if err != nil {
+		return fmt.Errorf("delete book editions: %w", err)
+	}
+
+	if len(editions) == 0 {
+		return nil
+	}
+
+	rows := make([]data, len(editions))
+	for i := range editions {
+		e := &editions[i]
+		e.ID = ds.NewID()
+		e.BookID = bookID
+		e.Position = i
+		if e.Links == nil {
+			e.Links = make([]ds.BookEditionLink, 0)
+		}
+
+		rows[i] = data{
+			"id":           e.ID,
+			"book_id":      e.BookID,
+			"position":     e.Position,
+			"number":       e.Number,
+			"go_version":   e.GoVersion,
+			"publisher":    e.Publisher,
+			"isbn10":       e.ISBN10,
+			"isbn13":       e.ISBN13,
+			"page_count":   e.PageCount,
+			"release_date": e.ReleaseDate,
+			"links":        e.Links,
+		}
+	}
+
+	return r.insert(ctx, "book_editions", rows...)
+}
@@ -22,16 +22,37 @@ func (r *Repo) CreateBook(ctx context.Context, b *ds.Book) error {
 
 	return r.insert(ctx, "books", data{
 		"id":                b.ID,
+		"subtitle":          b.Subtitle,
+		"series":            b.Series,
+		"series_position":   b.SeriesPosition,
+		"title_sort":        ds.TitleSortKey(b.Title),
 		"description_raw":   b.DescriptionRaw,
 		"description":       b.Description,
 		"cover_file_id":     b.CoverFileID,
-		"authors":           b.Authors,
 		"homepage":          b.Homepage,
 		"release_date":      b.ReleaseDate,
 		"release_date_sort": b.ReleaseDateSort,
+		"isbn10":            b.ISBN10,
+		"isbn13":            b.ISBN13,
 	})
 }
 
+// FindBookEntitiesByPublicIDsOrTitles returns the book entities (not deleted) having one of the public IDs,
+// or one of the titles; titles are compared case-insensitively and must be given in lower case.
+func (r *Repo) FindBookEntitiesByPublicIDsOrTitles(ctx context.Context, publicIDs, titles []string) ([]ds.Entity, error) {
+	_, span := r.tracer.Start(ctx, "FindBookEntitiesByPublicIDsOrTitles")
+	defer span.End()
+
+	entities := make([]ds.Entity, 0)
+	const query = `
+		SELECT * FROM entities
+		WHERE type = $1 AND deleted_at IS NULL AND (public_id = ANY($2) OR lower(title) = ANY($3))`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &entities, query, ds.EntityTypeBook, publicIDs, titles)
+
+	return entities, err
+}
+
 // GetBookByID retrieves a book by its ID.
 func (r *Repo) GetBookByID(ctx context.Context, id ds.ID) (*ds.Book, error) {
 	_, span := r.tracer.Start(ctx, "GetBookByID")
@@ -53,6 +74,16 @@ func (r *Repo) GetBookByID(ctx context.Context, id ds.ID) (*ds.Book, error) {
 		return nil, err
 	}
 
+	book.Editions, err = r.BookEditions(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	book.Authors, err = r.BookAuthors(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return book, err
 }
 
@@ -74,6 +105,16 @@ func (r *Repo) GetBookByPublicID(ctx context.Context, publicID string) (*ds.Book
 		return nil, err
 	}
 
+	book.Editions, err = r.BookEditions(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	book.Authors, err = r.BookAuthors(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return book, err
 }
 
@@ -83,13 +124,18 @@ func (r *Repo) UpdateBook(ctx context.Context, b *ds.Book) error {
 	defer span.End()
 
 	err := r.update(ctx, b.ID, "books", data{
+		"subtitle":          b.Subtitle,
+		"series":            b.Series,
+		"series_position":   b.SeriesPosition,
+		"title_sort":        ds.TitleSortKey(b.Title),
 		"description_raw":   b.DescriptionRaw,
 		"description":       b.Description,
 		"cover_file_id":     b.CoverFileID,
-		"authors":           b.Authors,
 		"homepage":          b.Homepage,
 		"release_date":      b.ReleaseDate,
 		"release_date_sort": b.ReleaseDateSort,
+		"isbn10":            b.ISBN10,
+		"isbn13":            b.ISBN13,
 	})
 	if err != nil {
 		return fmt.Errorf("update book: %w", err)
@@ -130,7 +176,14 @@ func (r *Repo) FilterBooks(ctx context.Context, f ds.BooksFilter) (books []ds.Bo
 
 	var whereAuthor string
 	if f.Author != "" {
-		whereAuthor = `EXISTS (SELECT 1 FROM jsonb_to_recordset(b.authors) AS a(name text) WHERE a.name = ?)`
+		whereAuthor = `
+		EXISTS (
+		  SELECT 1
+		  FROM book_authors ba
+		  JOIN authors a ON a.id = ba.author_id
+		  WHERE ba.book_id = b.id
+			AND (a.slug = ? OR lower(a.name) = lower(?))
+		)`
 	}
 
 	count, err = r.filter("entities e", "e").
@@ -143,27 +196,33 @@ func (r *Repo) FilterBooks(ctx context.Context, f ds.BooksFilter) (books []ds.Bo
 		  e.summary,
 		  e.visibility,
 		  e.status,
+		  e.published_at,
 		  e.created_at,
 		  e.updated_at,
 		  e.deleted_at,
 		
+		  b.subtitle,
+		  b.series,
+		  b.series_position,
 		  b.cover_file_id,
-		  b.authors,
 		  b.homepage,
 		  b.release_date,
+		  b.rating,
+		  b.rating_count,
 
 		  u.username AS "owner"`).
 		join("LEFT JOIN books b USING (id)").
 		join("LEFT JOIN users u ON e.owner_id = u.id").
 		where("e.type", ds.EntityTypeBook).
+		whereIf(f.OwnerID != nil, "e.owner_id", f.OwnerID).
 		whereRaw(whereTopics, f.Topics).
-		whereRaw(whereAuthor, f.Author).
+		whereRaw(whereAuthor, f.Author, f.Author).
 		filterString("e.title", f.Title).
 		paginate(f.Page, f.PerPage).
 		createdAt(f.CreatedAt).
 		deletedAt(f.DeletedAt).
 		deleted(f.Deleted).
-		order(f.OrderBy, f.OrderDirection).
+		order(bookOrder(f)).
 		apply(
 			whereIn("e.status", f.Status),
 			whereIn("e.visibility", f.Visibility),
@@ -204,30 +263,65 @@ func (r *Repo) FilterBooks(ctx context.Context, f ds.BooksFilter) (books []ds.Bo
 		for i := range books {
 			books[i].Topics = topicsByEntity[books[i].ID]
 		}
+
+		var authorsByBook map[ds.ID][]ds.BookAuthor
+		authorsByBook, err = r.BooksAuthors(ctx, ids)
+		if err != nil {
+			err = fmt.Errorf("filter books: select authors: %w", err)
+			return nil, 0, err
+		}
+
+		for i := range books {
+			books[i].Authors = authorsByBook[books[i].ID]
+		}
 	}
 
 	return
 }
 
-// SearchBookAuthors searches book authors by name.
-func (r *Repo) SearchBookAuthors(ctx context.Context, query string) ([]ds.BookAuthor, error) {
+// bookOrder returns the column and direction the books are ordered by.
+func bookOrder(f ds.BooksFilter) (column, direction string) {
+	switch f.Sort {
+	case ds.BookSortReleaseDate:
+		return "b.release_date_sort", "desc"
+	case ds.BookSortTitle:
+		return "b.title_sort", "asc"
+	case ds.BookSortAdded:
+		return "e.created_at", "desc"
+	case ds.BookSortUpdated:
+		return "COALESCE(e.updated_at, e.created_at)", "desc"
+	case ds.BookSortRating:
+		// books with the same rating: the more reviews, the higher
+		return "b.rating DESC, b.rating_count", "desc"
+	}
+
+	return f.OrderBy, f.OrderDirection
+}
+
+// SearchBookAuthors searches the authors of approved public books by name.
+func (r *Repo) SearchBookAuthors(ctx context.Context, query string) ([]ds.Author, error) {
 	_, span := r.tracer.Start(ctx, "SearchBookAuthors")
 	defer span.End()
 
 	sql := `
-		SELECT DISTINCT a.name
-		FROM entities e
-		JOIN books b ON b.id = e.id
-		JOIN LATERAL jsonb_to_recordset(b.authors) AS a(name text) ON true
-		WHERE e.type = 'book'
-		  AND e.status = 'approved'
-		  AND e.visibility = 'public'
-		  AND e.deleted_at IS NULL
+		SELECT a.*
+		FROM authors a
+		WHERE a.merged_into_id IS NULL
 		  AND a.name ILIKE $1
+		  AND EXISTS (
+			SELECT 1
+			FROM book_authors ba
+			JOIN entities e ON e.id = ba.book_id
+			WHERE ba.author_id = a.id
+			  AND e.status = 'approved'
+			  AND e.visibility = 'public'
+			  AND e.deleted_at IS NULL
+		  )
+		ORDER BY a.name
 		LIMIT 25
 	`
 
-	var rows []ds.BookAuthor
+	var rows []ds.Author
 	err := pgxscan.Select(ctx, r.db, &rows, sql, "%"+query+"%")
 	if err != nil {
 		return nil, fmt.Errorf("search book authors: %w", err)
@@ -0,0 +1,241 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// ErrBookReviewNotFound is returned when a book review is not found.
+var ErrBookReviewNotFound = app.ErrNotFound("review not found")
+
+// CreateBookReview inserts a new book review into the database.
+func (r *Repo) CreateBookReview(ctx context.Context, rev *ds.BookReview) error {
+	_, span := r.tracer.Start(ctx, "CreateBookReview")
+	defer span.End()
+
+	if rev.ID.IsNil() {
+		rev.ID = ds.NewID()
+	}
+
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now()
+	}
+
+	return r.insert(ctx, "book_reviews", data{
+		"id":         rev.ID,
+		"book_id":    rev.BookID,
+		"user_id":    rev.UserID,
+		"rating":     rev.Rating,
+		"body_raw":   rev.BodyRaw,
+		"body":       rev.Body,
+		"created_at": rev.CreatedAt,
+	})
+}
+
+// UpdateBookReview updates the rating and the body of the review.
+func (r *Repo) UpdateBookReview(ctx context.Context, rev *ds.BookReview) error {
+	_, span := r.tracer.Start(ctx, "UpdateBookReview")
+	defer span.End()
+
+	rev.UpdatedAt = new(time.Now())
+
+	return r.update(ctx, rev.ID, "book_reviews", data{
+		"rating":     rev.Rating,
+		"body_raw":   rev.BodyRaw,
+		"body":       rev.Body,
+		"updated_at": rev.UpdatedAt,
+	})
+}
+
+// SetBookReviewHidden hides the review (or makes it visible again if hidden is false).
+func (r *Repo) SetBookReviewHidden(ctx context.Context, rev *ds.BookReview, hidden bool, by ds.ID) error {
+	_, span := r.tracer.Start(ctx, "SetBookReviewHidden")
+	defer span.End()
+
+	rev.Hidden = hidden
+	rev.HiddenBy = nil
+	rev.HiddenAt = nil
+	if hidden {
+		rev.HiddenBy = new(by)
+		rev.HiddenAt = new(time.Now())
+	}
+
+	return r.update(ctx, rev.ID, "book_reviews", data{
+		"hidden":    rev.Hidden,
+		"hidden_by": rev.HiddenBy,
+		"hidden_at": rev.HiddenAt,
+	})
+}
+
+// GetBookReviewByID retrieves a book review by its ID.
+func (r *Repo) GetBookReviewByID(ctx context.Context, id ds.ID) (*ds.BookReview, error) {
+	_, span := r.tracer.Start(ctx, "GetBookReviewByID")
+	defer span.End()
+
+	const query = `SELECT r.*, COALESCE(u.username, '') AS username
+		FROM book_reviews r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.id = $1`
+
+	rev := new(ds.BookReview)
+	err := pgxscan.Get(ctx, r.getDB(ctx), rev, query, id)
+	if noRows(err) {
+		return nil, ErrBookReviewNotFound
+	}
+
+	return rev, err
+}
+
+// GetBookReviewByUser retrieves the review of the book written by the user.
+func (r *Repo) GetBookReviewByUser(ctx context.Context, bookID, userID ds.ID) (*ds.BookReview, error) {
+	_, span := r.tracer.Start(ctx, "GetBookReviewByUser")
+	defer span.End()
+
+	const query = `SELECT r.*, u.username
+		FROM book_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.book_id = $1 AND r.user_id = $2`
+
+	rev := new(ds.BookReview)
+	err := pgxscan.Get(ctx, r.getDB(ctx), rev, query, bookID, userID)
+	if noRows(err) {
+		return nil, ErrBookReviewNotFound
+	}
+
+	return rev, err
+}
+
+// DeleteBookReview permanently deletes the review along with its reports.
+func (r *Repo) DeleteBookReview(ctx context.Context, id ds.ID) error {
+	_, span := r.tracer.Start(ctx, "DeleteBookReview")
+	defer span.End()
+
+	return r.hardDelete(ctx, "book_reviews", id)
+}
+
+// FilterBookReviews returns book reviews matching the filter, newest first
+// (most reported first if f.Reported is set).
+func (r *Repo) FilterBookReviews(ctx context.Context, f ds.BookReviewsFilter) (
+	reviews []ds.BookReview, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterBookReviews")
+	defer span.End()
+
+	orderBy := "r.created_at"
+	if f.Reported {
+		orderBy = "r.report_count"
+	}
+
+	count, err = r.filter("book_reviews r", "r").
+		columns("r.*", "COALESCE(u.username, '') AS username").
+		join("LEFT JOIN users u ON u.id = r.user_id").
+		whereIf(f.BookID != nil, "r.book_id", f.BookID).
+		whereIf(f.UserID != nil, "r.user_id", f.UserID).
+		whereIf(f.Hidden != nil, "r.hidden", f.Hidden).
+		whereIf(f.Reported, "r.report_count > 0", nil).
+		paginate(f.Page, f.PerPage).
+		order(orderBy, "desc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &reviews)
+
+	return
+}
+
+// CreateBookReviewReport stores the report of the review and updates the number of its reports.
+// Reporting the same review again by the same user is a no-op; reported reports whether the report was new.
+func (r *Repo) CreateBookReviewReport(ctx context.Context, rep *ds.BookReviewReport) (reported bool, err error) {
+	_, span := r.tracer.Start(ctx, "CreateBookReviewReport")
+	defer span.End()
+
+	if rep.ID.IsNil() {
+		rep.ID = ds.NewID()
+	}
+
+	if rep.CreatedAt.IsZero() {
+		rep.CreatedAt = time.Now()
+	}
+
+	const query = `INSERT INTO book_review_reports (id, review_id, user_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (review_id, user_id) DO NOTHING`
+
+	tag, err := r.getDB(ctx).Exec(ctx, query, rep.ID, rep.ReviewID, rep.UserID, rep.Reason, rep.CreatedAt)
+	if err != nil || tag.RowsAffected() == 0 {
+		return false, err
+	}
+
+	err = r.exec(ctx, `UPDATE book_reviews SET report_count = report_count + 1 WHERE id = $1`, rep.ReviewID)
+	return err == nil, err
+}
+
+// GetBookReviewReports returns the reports of the review, newest first.
+func (r *Repo) GetBookReviewReports(ctx context.Context, reviewID ds.ID) (reports []ds.BookReviewReport, err error) {
+	_, span := r.tracer.Start(ctx, "GetBookReviewReports")
+	defer span.End()
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &reports,
+		`SELECT * FROM book_review_reports WHERE review_id = $1 ORDER BY created_at DESC`, reviewID)
+	return
+}
+
+// BookRatingCounts returns the number of visible reviews of the book by rating.
+func (r *Repo) BookRatingCounts(ctx context.Context, bookID ds.ID) (map[int]int, error) {
+	_, span := r.tracer.Start(ctx, "BookRatingCounts")
+	defer span.End()
+
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+
+	const query = `SELECT rating, COUNT(*) AS count
+		FROM book_reviews
+		WHERE book_id = $1 AND hidden IS FALSE
+		GROUP BY rating`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &rows, query, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.Rating] = row.Count
+	}
+
+	return counts, nil
+}
+
+// RefreshBookRating recalculates the rating of the book from its visible reviews.
+func (r *Repo) RefreshBookRating(ctx context.Context, bookID ds.ID) error {
+	_, span := r.tracer.Start(ctx, "RefreshBookRating")
+	defer span.End()
+
+	const query = `UPDATE books b
+		SET rating = COALESCE(s.rating, 0), rating_count = s.count
+		FROM (
+			SELECT ROUND(AVG(rating), 2)::DOUBLE PRECISION AS rating, COUNT(*) AS count
+			FROM book_reviews
+			WHERE book_id = $1 AND hidden IS FALSE
+		) s
+		WHERE b.id = $1`
+
+	return r.exec(ctx, query, bookID)
+}
+
+// AnonymizeBookReviewsByUser detaches the reviews and the review reports of the user from their account.
+func (r *Repo) AnonymizeBookReviewsByUser(ctx context.Context, userID ds.ID) error {
+	_, span := r.tracer.Start(ctx, "AnonymizeBookReviewsByUser")
+	defer span.End()
+
+	err := r.exec(ctx, `UPDATE book_reviews SET user_id = NULL WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return r.exec(ctx, `UPDATE book_review_reports SET user_id = NULL WHERE user_id = $1`, userID)
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"time"
@@ -96,9 +97,14 @@ func (r *Repo) FilterChangeRequests(ctx context.Context, f ds.ChangeRequestsFilt
 `).
 		paginate(f.Page, f.PerPage).
 		where("r.status", f.Status).
+		whereIf(f.UserID != nil, "r.user_id", f.UserID).
 		whereRaw("e.deleted_at IS NULL").
+		apply(
+			whereIn("e.status", f.EntityStatus),
+			whereIn("e.visibility", f.EntityVisibility),
+		).
 		withCount(f.WithCount).
-		order("r.created_at", "asc").
+		order("r.created_at", cmp.Or(f.OrderDirection, "asc")).
 		withoutSoftDelete()
 
 	count, err = b.scan(ctx, &reqs)
@@ -132,6 +138,38 @@ func (r *Repo) GetChangeRequestByID(ctx context.Context, id ds.ID) (req *ds.Enti
 	return req, err
 }
 
+// GetChangeRequestsByUserID returns all change requests made by the user, regardless of status, oldest first.
+func (r *Repo) GetChangeRequestsByUserID(ctx context.Context, userID ds.ID) ([]ds.EntityChangeRequest, error) {
+	_, span := r.tracer.Start(ctx, "GetChangeRequestsByUserID")
+	defer span.End()
+
+	const query = `SELECT 
+    		r.id as "id",
+    		r.entity_id,
+    		r.user_id,
+			r.status,
+			r.diff,
+			COALESCE(r.message, '') as "message",
+			COALESCE(r.revision, 0) as "revision",
+			r.reviewed_at,
+			COALESCE(r.review_note, '') as "review_note",
+			r.created_at,
+			r.updated_at,
+
+			e.type as "entity_type",
+			e.title as "entity_title",
+			e.public_id as "entity_public_id"
+    FROM entity_change_requests r 
+    JOIN entities e ON r.entity_id = e.id
+    WHERE r.user_id = $1
+    ORDER BY r.created_at`
+
+	reqs := make([]ds.EntityChangeRequest, 0)
+	err := pgxscan.Select(ctx, r.getDB(ctx), &reqs, query, userID)
+
+	return reqs, err
+}
+
 // CommitChangeRequest marks a change request as committed.
 func (r *Repo) CommitChangeRequest(ctx context.Context, req *ds.EntityChangeRequest) error {
 	_, span := r.tracer.Start(ctx, "CommitChangeRequest")
@@ -168,3 +206,13 @@ func (r *Repo) RejectChangeRequest(ctx context.Context, id, reviewerID ds.ID, no
 
 	return nil
 }
+
+// CountPendingChangeRequests returns the number of change requests awaiting review.
+func (r *Repo) CountPendingChangeRequests(ctx context.Context) (n int, err error) {
+	ctx, span := r.tracer.Start(ctx, "CountPendingChangeRequests")
+	defer span.End()
+
+	err = pgxscan.Get(ctx, r.getDB(ctx), &n, `SELECT count(*) FROM entity_change_requests WHERE status = $1`, ds.EntityChangePending)
+
+	return
+}
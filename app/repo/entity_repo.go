@@ -73,6 +73,7 @@ func (r *Repo) CreateEntity(ctx context.Context, e *ds.Entity) error {
 		"summary":         e.Summary,
 		"visibility":      e.Visibility,
 		"status":          e.Status,
+		"published_at":    e.PublishedAt,
 		"created_at":      e.CreatedAt,
 		"updated_at":      e.UpdatedAt,
 		"deleted_at":      e.DeletedAt,
@@ -115,11 +116,45 @@ func (r *Repo) ApplyChangesToEntity(ctx context.Context, id ds.ID, changes map[s
 }
 
 // ChangeEntityStatus updates the status field of the specified entity.
+// The first approval of the entity is recorded as its publication time.
 func (r *Repo) ChangeEntityStatus(ctx context.Context, entityID ds.ID, status ds.EntityStatus) error {
 	_, span := r.tracer.Start(ctx, "ChangeEntityStatus")
 	defer span.End()
 
+	if status == ds.EntityStatusApproved {
+		return r.exec(ctx, `UPDATE entities SET status = $1, published_at = COALESCE(published_at, NOW()) WHERE id = $2`,
+			status, entityID)
+	}
+
 	return r.update(ctx, entityID, "entities", data{
 		"status": status,
 	})
 }
+
+// FilterEntities retrieves a paginated list of entities matching the given filter.
+func (r *Repo) FilterEntities(ctx context.Context, f ds.EntitiesFilter) (entities []ds.Entity, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterEntities")
+	defer span.End()
+
+	count, err = r.filter("entities e", "e").
+		columns("e.*").
+		whereIf(f.Type != "", "e.type", f.Type).
+		whereIf(f.OwnerID != nil, "e.owner_id", f.OwnerID).
+		filterString("e.title", f.Title).
+		paginate(f.Page, f.PerPage).
+		createdAt(f.CreatedAt).
+		deletedAt(f.DeletedAt).
+		deleted(f.Deleted).
+		order(f.OrderBy, f.OrderDirection).
+		apply(
+			whereIn("e.status", f.Status),
+			whereIn("e.visibility", f.Visibility),
+		).
+		withCount(f.WithCount).
+		scan(ctx, &entities)
+	if err != nil {
+		err = fmt.Errorf("filter entities: %w", err)
+	}
+
+	return
+}
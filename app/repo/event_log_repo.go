@@ -73,6 +73,10 @@ func (r *Repo) FilterEventLogs(ctx context.Context, f ds.EventLogsFilter) (logs
 		b.whereRaw("is_public IS TRUE")
 	}
 
+	if f.UserID != nil {
+		b.where("l.user_id", *f.UserID)
+	}
+
 	count, err = b.scan(ctx, &logs)
 	return
 }
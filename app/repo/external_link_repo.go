@@ -0,0 +1,333 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// ErrExternalLinkNotFound is returned when an external link is not found.
+var ErrExternalLinkNotFound = app.ErrNotFound("external link not found")
+
+// externalLinksInsertBatchSize keeps batch inserts well under the limit of query parameters.
+const externalLinksInsertBatchSize = 1000
+
+// publishedEntity limits the entities "e" to the approved public ones that are not deleted.
+// Only their links are checked: submissions are not reviewed yet,
+// and the server must not be made to request whatever a user links to.
+const publishedEntity = `e.deleted_at IS NULL AND e.status = 'approved' AND e.visibility = 'public'`
+
+// ExternalLinkRefs returns the URLs stored in the approved public books (homepage, links of editions)
+// and in the authors (links) of such books, except for merged authors.
+// URLs in Markdown content are not included, see PagesContent.
+func (r *Repo) ExternalLinkRefs(ctx context.Context) (refs []ds.ExternalLinkRef, err error) {
+	_, span := r.tracer.Start(ctx, "ExternalLinkRefs")
+	defer span.End()
+
+	const query = `SELECT b.homepage AS url, 'book' AS source_type, b.id AS source_id, 'homepage' AS field
+		FROM books b
+		JOIN entities e USING (id)
+		WHERE ` + publishedEntity + ` AND COALESCE(b.homepage, '') <> ''
+		UNION ALL
+		SELECT l ->> 'url', 'book', be.book_id, 'editions'
+		FROM book_editions be
+		JOIN entities e ON e.id = be.book_id
+		CROSS JOIN jsonb_array_elements(be.links) l
+		WHERE ` + publishedEntity + ` AND COALESCE(l ->> 'url', '') <> ''
+		UNION ALL
+		SELECT l ->> 'url', 'author', a.id, 'links'
+		FROM authors a
+		CROSS JOIN jsonb_array_elements(a.links) l
+		WHERE a.merged_into_id IS NULL AND COALESCE(l ->> 'url', '') <> ''
+		  AND EXISTS (
+		    SELECT 1
+		    FROM book_authors ba
+		    JOIN entities e ON e.id = ba.book_id
+		    WHERE ba.author_id = a.id AND ` + publishedEntity + `
+		  )`
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &refs, query)
+	return
+}
+
+// PagesContent returns the Markdown content of the approved public pages, by page ID.
+func (r *Repo) PagesContent(ctx context.Context) (map[ds.ID]string, error) {
+	_, span := r.tracer.Start(ctx, "PagesContent")
+	defer span.End()
+
+	var rows []struct {
+		ID         ds.ID
+		ContentRaw string
+	}
+
+	const query = `SELECT e.id, COALESCE(p.content_raw, '') AS content_raw
+		FROM entities e
+		JOIN pages p USING (id)
+		WHERE ` + publishedEntity
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	content := make(map[ds.ID]string, len(rows))
+	for _, row := range rows {
+		content[row.ID] = row.ContentRaw
+	}
+
+	return content, nil
+}
+
+// SyncExternalLinks makes the stored external links and their sources match the given refs:
+// new URLs are added as pending links, the sources are replaced,
+// and links no longer found anywhere are deleted along with their history.
+// The refs are expected to have no duplicates.
+func (r *Repo) SyncExternalLinks(ctx context.Context, refs []ds.ExternalLinkRef) error {
+	_, span := r.tracer.Start(ctx, "SyncExternalLinks")
+	defer span.End()
+
+	var existing []struct {
+		ID  ds.ID
+		URL string
+	}
+	err := pgxscan.Select(ctx, r.getDB(ctx), &existing, `SELECT id, url FROM external_links`)
+	if err != nil {
+		return fmt.Errorf("select external links: %w", err)
+	}
+
+	ids := make(map[string]ds.ID, len(existing))
+	for _, l := range existing {
+		ids[l.URL] = l.ID
+	}
+
+	now := time.Now()
+	links := make([]data, 0)
+	sources := make([]data, 0, len(refs))
+	for _, ref := range refs {
+		id, ok := ids[ref.URL]
+		if !ok {
+			id = ds.NewID()
+			ids[ref.URL] = id
+			links = append(links, data{
+				"id":            id,
+				"url":           ref.URL,
+				"status":        ds.ExternalLinkPending,
+				"next_check_at": now,
+				"created_at":    now,
+			})
+		}
+
+		sources = append(sources, data{
+			"link_id":     id,
+			"source_type": ref.SourceType,
+			"source_id":   ref.SourceID,
+			"field":       ref.Field,
+		})
+	}
+
+	err = r.insertBatches(ctx, "external_links", links)
+	if err != nil {
+		return fmt.Errorf("insert external links: %w", err)
+	}
+
+	err = r.exec(ctx, "DELETE FROM external_link_sources")
+	if err != nil {
+		return fmt.Errorf("delete external link sources: %w", err)
+	}
+
+	err = r.insertBatches(ctx, "external_link_sources", sources)
+	if err != nil {
+		return fmt.Errorf("insert external link sources: %w", err)
+	}
+
+	const deleteOrphans = `DELETE FROM external_links l
+		WHERE NOT EXISTS (SELECT 1 FROM external_link_sources s WHERE s.link_id = l.id)`
+
+	return r.exec(ctx, deleteOrphans)
+}
+
+func (r *Repo) insertBatches(ctx context.Context, table string, rows []data) error {
+	for start := 0; start < len(rows); start += externalLinksInsertBatchSize {
+		end := min(start+externalLinksInsertBatchSize, len(rows))
+
+		err := r.insert(ctx, table, rows[start:end]...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDueExternalLinks returns the links whose next check is due, the longest waiting first.
+func (r *Repo) GetDueExternalLinks(ctx context.Context, limit int) (links []ds.ExternalLink, err error) {
+	_, span := r.tracer.Start(ctx, "GetDueExternalLinks")
+	defer span.End()
+
+	const query = `SELECT * FROM external_links
+		WHERE next_check_at <= NOW()
+		ORDER BY next_check_at
+		LIMIT $1`
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &links, query, limit)
+	return
+}
+
+// GetExternalLinkByID retrieves an external link by its ID.
+func (r *Repo) GetExternalLinkByID(ctx context.Context, id ds.ID) (*ds.ExternalLink, error) {
+	_, span := r.tracer.Start(ctx, "GetExternalLinkByID")
+	defer span.End()
+
+	l := new(ds.ExternalLink)
+	err := pgxscan.Get(ctx, r.getDB(ctx), l, `SELECT * FROM external_links WHERE id = $1`, id)
+	if noRows(err) {
+		return nil, ErrExternalLinkNotFound
+	}
+
+	return l, err
+}
+
+// UpdateExternalLink updates the result of the latest check of the link and when to check it next.
+func (r *Repo) UpdateExternalLink(ctx context.Context, l *ds.ExternalLink) error {
+	_, span := r.tracer.Start(ctx, "UpdateExternalLink")
+	defer span.End()
+
+	return r.update(ctx, l.ID, "external_links", data{
+		"status":        l.Status,
+		"status_code":   l.StatusCode,
+		"error":         l.Error,
+		"fail_count":    l.FailCount,
+		"checked_at":    l.CheckedAt,
+		"next_check_at": l.NextCheckAt,
+	})
+}
+
+// CreateExternalLinkCheck adds the check to the history of the link,
+// keeping only the latest ds.ExternalLinkChecksKept checks.
+func (r *Repo) CreateExternalLinkCheck(ctx context.Context, c *ds.ExternalLinkCheck) error {
+	_, span := r.tracer.Start(ctx, "CreateExternalLinkCheck")
+	defer span.End()
+
+	if c.ID.IsNil() {
+		c.ID = ds.NewID()
+	}
+
+	if c.CheckedAt.IsZero() {
+		c.CheckedAt = time.Now()
+	}
+
+	err := r.insert(ctx, "external_link_checks", data{
+		"id":          c.ID,
+		"link_id":     c.LinkID,
+		"status":      c.Status,
+		"method":      c.Method,
+		"status_code": c.StatusCode,
+		"error":       c.Error,
+		"duration_ms": c.DurationMS,
+		"checked_at":  c.CheckedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	const prune = `DELETE FROM external_link_checks
+		WHERE link_id = $1 AND id NOT IN (
+			SELECT id FROM external_link_checks WHERE link_id = $1 ORDER BY checked_at DESC LIMIT $2
+		)`
+
+	return r.exec(ctx, prune, c.LinkID, ds.ExternalLinkChecksKept)
+}
+
+// FilterExternalLinks returns external links matching the filter, in order of URL,
+// so the links of the same site are listed together.
+func (r *Repo) FilterExternalLinks(ctx context.Context, f ds.ExternalLinksFilter) (
+	links []ds.ExternalLink, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterExternalLinks")
+	defer span.End()
+
+	count, err = r.filter("external_links").
+		paginate(f.Page, f.PerPage).
+		whereIf(f.Status != "", "status", f.Status).
+		order("url", "asc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &links)
+
+	return
+}
+
+// FilterExternalLinkChecks returns the history of checks of a link, newest first.
+func (r *Repo) FilterExternalLinkChecks(ctx context.Context, f ds.ExternalLinkChecksFilter) (
+	checks []ds.ExternalLinkCheck, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterExternalLinkChecks")
+	defer span.End()
+
+	count, err = r.filter("external_link_checks").
+		paginate(f.Page, f.PerPage).
+		where("link_id", f.LinkID).
+		order("checked_at", "desc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &checks)
+
+	return
+}
+
+// ExternalLinksSources returns the sources of the links, with the title and the public ID
+// of the records they are found in, by link ID.
+func (r *Repo) ExternalLinksSources(ctx context.Context, linkIDs []ds.ID) (map[ds.ID][]ds.ExternalLinkSource, error) {
+	_, span := r.tracer.Start(ctx, "ExternalLinksSources")
+	defer span.End()
+
+	var rows []ds.ExternalLinkSource
+	const query = `SELECT s.link_id, s.source_type, s.source_id, s.field,
+			COALESCE(e.title, a.name, '') AS title,
+			COALESCE(e.public_id, a.slug, '') AS public_id
+		FROM external_link_sources s
+		LEFT JOIN entities e ON s.source_type IN ($2, $3) AND e.id = s.source_id
+		LEFT JOIN authors a ON s.source_type = $4 AND a.id = s.source_id
+		WHERE s.link_id = ANY($1)
+		ORDER BY s.source_type, title`
+
+	err := pgxscan.Select(ctx, r.getDB(ctx), &rows, query, linkIDs,
+		ds.ExternalLinkSourceBook, ds.ExternalLinkSourcePage, ds.ExternalLinkSourceAuthor)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[ds.ID][]ds.ExternalLinkSource, len(linkIDs))
+	for _, id := range linkIDs {
+		sources[id] = make([]ds.ExternalLinkSource, 0)
+	}
+	for _, s := range rows {
+		sources[s.LinkID] = append(sources[s.LinkID], s)
+	}
+
+	return sources, nil
+}
+
+// GetExternalLinksOfSource returns the links found in the record with the given statuses, in order of URL.
+func (r *Repo) GetExternalLinksOfSource(ctx context.Context, sourceType ds.ExternalLinkSourceType, sourceID ds.ID,
+	statuses ...ds.ExternalLinkStatus) (links []ds.ExternalLink, err error) {
+	_, span := r.tracer.Start(ctx, "GetExternalLinksOfSource")
+	defer span.End()
+
+	ss := make([]string, len(statuses))
+	for i, st := range statuses {
+		ss[i] = string(st)
+	}
+
+	const query = `SELECT l.* FROM external_links l
+		WHERE l.status = ANY($3) AND EXISTS (
+			SELECT 1 FROM external_link_sources s
+			WHERE s.link_id = l.id AND s.source_type = $1 AND s.source_id = $2
+		)
+		ORDER BY l.url`
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &links, query, sourceType, sourceID, ss)
+	return
+}
@@ -126,6 +126,8 @@ func (r *Repo) FilterFiles(ctx context.Context, f ds.FilesFilter) (files []ds.Fi
 
 	count, err = r.filter("files").
 		paginate(f.Page, f.PerPage).
+		whereIf(f.OwnerID != nil, "owner_id", f.OwnerID).
+		whereIf(f.Committed, "temp", false).
 		createdAt(f.CreatedAt).
 		deletedAt(f.DeletedAt).
 		deleted(f.Deleted).
@@ -52,3 +52,23 @@ func (r *Repo) GetOAuthUserAccount(
 
 	return account, err
 }
+
+// GetOAuthUserAccountsByUserID returns all OAuth accounts linked to the given user.
+func (r *Repo) GetOAuthUserAccountsByUserID(ctx context.Context, userID ds.ID) ([]ds.OAuthUserAccount, error) {
+	_, span := r.tracer.Start(ctx, "GetOAuthUserAccountsByUserID")
+	defer span.End()
+
+	accounts := make([]ds.OAuthUserAccount, 0)
+	err := pgxscan.Select(ctx, r.getDB(ctx), &accounts,
+		`SELECT * FROM oauth_user_accounts WHERE user_id = $1 ORDER BY created_at`, userID)
+
+	return accounts, err
+}
+
+// DeleteOAuthUserAccount permanently removes the OAuth account link.
+func (r *Repo) DeleteOAuthUserAccount(ctx context.Context, id ds.ID) error {
+	_, span := r.tracer.Start(ctx, "DeleteOAuthUserAccount")
+	defer span.End()
+
+	return r.hardDelete(ctx, "oauth_user_accounts", id)
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// CreateRateLimitHit records a single hit of the rate limit key.
+func (r *Repo) CreateRateLimitHit(ctx context.Context, key string, at time.Time) error {
+	_, span := r.tracer.Start(ctx, "CreateRateLimitHit")
+	defer span.End()
+
+	return r.insert(ctx, "rate_limit_hits", data{
+		"key":    key,
+		"hit_at": at,
+	})
+}
+
+// CountRateLimitHits returns the number of hits of the key made after since,
+// and the time of the oldest of them (nil if there are none).
+func (r *Repo) CountRateLimitHits(ctx context.Context, key string, since time.Time) (
+	count int, oldest *time.Time, err error) {
+	_, span := r.tracer.Start(ctx, "CountRateLimitHits")
+	defer span.End()
+
+	const query = `SELECT COUNT(*), MIN(hit_at) FROM rate_limit_hits WHERE key = $1 AND hit_at > $2`
+	err = r.getDB(ctx).QueryRow(ctx, query, key, since).Scan(&count, &oldest)
+	return
+}
+
+// DeleteRateLimitHits removes all hits of the key.
+func (r *Repo) DeleteRateLimitHits(ctx context.Context, key string) error {
+	_, span := r.tracer.Start(ctx, "DeleteRateLimitHits")
+	defer span.End()
+
+	return r.exec(ctx, `DELETE FROM rate_limit_hits WHERE key = $1`, key)
+}
+
+// DeleteRateLimitHitsBefore removes all hits made before the given time.
+func (r *Repo) DeleteRateLimitHitsBefore(ctx context.Context, before time.Time) error {
+	_, span := r.tracer.Start(ctx, "DeleteRateLimitHitsBefore")
+	defer span.End()
+
+	return r.exec(ctx, `DELETE FROM rate_limit_hits WHERE hit_at < $1`, before)
+}
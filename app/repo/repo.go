@@ -54,6 +54,22 @@ func New(db *app.DB, t trace.Tracer) *Repo {
 	}
 }
 
+// Ping verifies the database connection.
+func (r *Repo) Ping(ctx context.Context) error {
+	ctx, span := r.tracer.Start(ctx, "Ping")
+	defer span.End()
+
+	return r.db.Ping(ctx)
+}
+
+// PendingMigrations returns the names of migrations that haven't been applied yet.
+func (r *Repo) PendingMigrations(ctx context.Context) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "PendingMigrations")
+	defer span.End()
+
+	return app.PendingMigrations(ctx, r.db)
+}
+
 // WithTx wraps app.RunInTx and puts the transaction into the context.
 func (r *Repo) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	return app.RunInTx(ctx, r.db, func(ctx context.Context, tx pgx.Tx) error {
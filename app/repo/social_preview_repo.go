@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// SwapSocialPreviewPath sets the path of the current social preview image of the entity
+// and returns the path of the previous one, or an empty string if there was none.
+func (r *Repo) SwapSocialPreviewPath(ctx context.Context, entityID ds.ID, path string) (previous string, err error) {
+	_, span := r.tracer.Start(ctx, "SwapSocialPreviewPath")
+	defer span.End()
+
+	const query = `WITH old AS (SELECT path FROM social_previews WHERE entity_id = $1 FOR UPDATE)
+		INSERT INTO social_previews (entity_id, path, updated_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (entity_id) DO UPDATE SET path = EXCLUDED.path, updated_at = EXCLUDED.updated_at
+		RETURNING COALESCE((SELECT path FROM old), '')`
+
+	err = r.getDB(ctx).QueryRow(ctx, query, entityID, path).Scan(&previous)
+	return
+}
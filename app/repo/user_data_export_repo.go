@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+var (
+	// ErrUserDataExportNotFound is returned when a user data export is not found.
+	ErrUserDataExportNotFound = app.ErrNotFound("data export not found")
+)
+
+// CreateUserDataExport inserts a new user data export into the database.
+func (r *Repo) CreateUserDataExport(ctx context.Context, e *ds.UserDataExport) error {
+	_, span := r.tracer.Start(ctx, "CreateUserDataExport")
+	defer span.End()
+
+	if e.ID.IsNil() {
+		e.ID = ds.NewID()
+	}
+
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	return r.insert(ctx, "user_data_exports", data{
+		"id":           e.ID,
+		"user_id":      e.UserID,
+		"status":       e.Status,
+		"token":        e.Token,
+		"path":         e.Path,
+		"size":         e.Size,
+		"error":        e.Error,
+		"expires_at":   e.ExpiresAt,
+		"created_at":   e.CreatedAt,
+		"completed_at": e.CompletedAt,
+	})
+}
+
+// UpdateUserDataExport updates the processing state of the user data export.
+func (r *Repo) UpdateUserDataExport(ctx context.Context, e *ds.UserDataExport) error {
+	_, span := r.tracer.Start(ctx, "UpdateUserDataExport")
+	defer span.End()
+
+	return r.update(ctx, e.ID, "user_data_exports", data{
+		"status":       e.Status,
+		"path":         e.Path,
+		"size":         e.Size,
+		"error":        e.Error,
+		"expires_at":   e.ExpiresAt,
+		"completed_at": e.CompletedAt,
+	})
+}
+
+// GetUserDataExportByToken retrieves a user data export by its download token.
+func (r *Repo) GetUserDataExportByToken(ctx context.Context, token string) (*ds.UserDataExport, error) {
+	_, span := r.tracer.Start(ctx, "GetUserDataExportByToken")
+	defer span.End()
+
+	e := new(ds.UserDataExport)
+	err := pgxscan.Get(ctx, r.getDB(ctx), e, `SELECT * FROM user_data_exports WHERE token = $1`, token)
+	if noRows(err) {
+		return nil, ErrUserDataExportNotFound
+	}
+
+	return e, err
+}
+
+// FilterUserDataExports returns user data exports matching the filter, oldest first.
+func (r *Repo) FilterUserDataExports(ctx context.Context, f ds.UserDataExportsFilter) (
+	exports []ds.UserDataExport, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterUserDataExports")
+	defer span.End()
+
+	count, err = r.filter("user_data_exports").
+		paginate(f.Page, f.PerPage).
+		whereIf(f.UserID != nil, "user_id", f.UserID).
+		whereIf(f.Status != "", "status", f.Status).
+		dtRange("expires_at", f.ExpiresAt).
+		createdAt(f.CreatedAt).
+		order("created_at", "asc").
+		withoutSoftDelete().
+		scan(ctx, &exports)
+
+	return
+}
+
+// DeleteUserDataExport permanently removes the user data export record.
+func (r *Repo) DeleteUserDataExport(ctx context.Context, id ds.ID) error {
+	_, span := r.tracer.Start(ctx, "DeleteUserDataExport")
+	defer span.End()
+
+	return r.hardDelete(ctx, "user_data_exports", id)
+}
+
+// DeleteUserDataExportsByUser removes all data exports of the user.
+func (r *Repo) DeleteUserDataExportsByUser(ctx context.Context, userID ds.ID) error {
+	_, span := r.tracer.Start(ctx, "DeleteUserDataExportsByUser")
+	defer span.End()
+
+	return r.exec(ctx, `DELETE FROM user_data_exports WHERE user_id = $1`, userID)
+}
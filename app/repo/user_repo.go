@@ -160,6 +160,10 @@ func (r *Repo) UpdateUser(ctx context.Context, u *ds.User) error {
 		"username":   u.Username,
 		"password":   u.Password,
 		"cleaned_at": u.CleanedAt,
+
+		"bio":            u.Bio,
+		"avatar_file_id": u.AvatarFileID,
+		"links":          u.Links,
 	})
 	if err != nil {
 		return fmt.Errorf("update user: %w", err)
@@ -167,3 +171,25 @@ func (r *Repo) UpdateUser(ctx context.Context, u *ds.User) error {
 
 	return nil
 }
+
+// UpdateUserProfile updates the public profile fields of a user.
+func (r *Repo) UpdateUserProfile(ctx context.Context, u *ds.User) error {
+	_, span := r.tracer.Start(ctx, "UpdateUserProfile")
+	defer span.End()
+
+	err := r.update(ctx, u.ID, "users", data{
+		"bio":                  u.Bio,
+		"avatar_file_id":       u.AvatarFileID,
+		"links":                u.Links,
+		"show_books":           u.ShowBooks,
+		"show_pages":           u.ShowPages,
+		"show_change_requests": u.ShowChangeRequests,
+		"show_activity":        u.ShowActivity,
+		"updated_at":           time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("update user profile: %w", err)
+	}
+
+	return nil
+}
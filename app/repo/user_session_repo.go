@@ -47,6 +47,18 @@ func (r *Repo) GetUserSessionByID(ctx context.Context, id ds.ID) (sess *ds.UserS
 	return
 }
 
+// GetUserSessionsByUserID returns all sessions of the given user, oldest first.
+func (r *Repo) GetUserSessionsByUserID(ctx context.Context, userID ds.ID) ([]ds.UserSession, error) {
+	_, span := r.tracer.Start(ctx, "GetUserSessionsByUserID")
+	defer span.End()
+
+	sessions := make([]ds.UserSession, 0)
+	err := pgxscan.Select(ctx, r.getDB(ctx), &sessions,
+		`SELECT * FROM user_sessions WHERE user_id = $1 ORDER BY created_at`, userID)
+
+	return sessions, err
+}
+
 // ProlongUserSession updates the expiration timestamp of an existing user session.
 func (r *Repo) ProlongUserSession(ctx context.Context, id ds.ID) (err error) {
 	_, span := r.tracer.Start(ctx, "ProlongUserSession")
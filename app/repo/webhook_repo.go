@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+var (
+	// ErrWebhookNotFound is returned when a webhook is not found.
+	ErrWebhookNotFound = app.ErrNotFound("webhook not found")
+
+	// ErrWebhookDeliveryNotFound is returned when a webhook delivery is not found.
+	ErrWebhookDeliveryNotFound = app.ErrNotFound("webhook delivery not found")
+)
+
+// CreateWebhook inserts a new webhook into the database.
+func (r *Repo) CreateWebhook(ctx context.Context, w *ds.Webhook) error {
+	_, span := r.tracer.Start(ctx, "CreateWebhook")
+	defer span.End()
+
+	if w.ID.IsNil() {
+		w.ID = ds.NewID()
+	}
+
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+
+	return r.insert(ctx, "webhooks", data{
+		"id":          w.ID,
+		"url":         w.URL,
+		"secret":      w.Secret,
+		"event_types": w.EventTypes,
+		"description": w.Description,
+		"active":      w.Active,
+		"created_by":  w.CreatedBy,
+		"created_at":  w.CreatedAt,
+	})
+}
+
+// UpdateWebhook updates the editable fields of the webhook.
+func (r *Repo) UpdateWebhook(ctx context.Context, w *ds.Webhook) error {
+	_, span := r.tracer.Start(ctx, "UpdateWebhook")
+	defer span.End()
+
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+
+	w.UpdatedAt = new(time.Now())
+
+	return r.update(ctx, w.ID, "webhooks", data{
+		"url":         w.URL,
+		"secret":      w.Secret,
+		"event_types": w.EventTypes,
+		"description": w.Description,
+		"active":      w.Active,
+		"updated_at":  w.UpdatedAt,
+	})
+}
+
+// GetWebhookByID retrieves a webhook by its ID.
+func (r *Repo) GetWebhookByID(ctx context.Context, id ds.ID) (*ds.Webhook, error) {
+	_, span := r.tracer.Start(ctx, "GetWebhookByID")
+	defer span.End()
+
+	w := new(ds.Webhook)
+	err := pgxscan.Get(ctx, r.getDB(ctx), w, `SELECT * FROM webhooks WHERE id = $1 AND deleted_at IS NULL`, id)
+	if noRows(err) {
+		return nil, ErrWebhookNotFound
+	}
+
+	return w, err
+}
+
+// GetWebhooks returns all webhooks, oldest first.
+func (r *Repo) GetWebhooks(ctx context.Context) (webhooks []ds.Webhook, err error) {
+	_, span := r.tracer.Start(ctx, "GetWebhooks")
+	defer span.End()
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &webhooks,
+		`SELECT * FROM webhooks WHERE deleted_at IS NULL ORDER BY created_at`)
+	return
+}
+
+// GetActiveWebhooksByEventType returns active webhooks subscribed to the event type.
+func (r *Repo) GetActiveWebhooksByEventType(ctx context.Context, t ds.EventLogType) (webhooks []ds.Webhook, err error) {
+	_, span := r.tracer.Start(ctx, "GetActiveWebhooksByEventType")
+	defer span.End()
+
+	const query = `SELECT * FROM webhooks
+		WHERE active IS TRUE AND deleted_at IS NULL
+		  AND (cardinality(event_types) = 0 OR $1 = ANY(event_types))`
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &webhooks, query, string(t))
+	return
+}
+
+// DeleteWebhook soft-deletes the webhook.
+func (r *Repo) DeleteWebhook(ctx context.Context, id ds.ID) error {
+	_, span := r.tracer.Start(ctx, "DeleteWebhook")
+	defer span.End()
+
+	return r.delete(ctx, "webhooks", id)
+}
+
+// CreateWebhookDelivery inserts a new webhook delivery into the database.
+func (r *Repo) CreateWebhookDelivery(ctx context.Context, d *ds.WebhookDelivery) error {
+	_, span := r.tracer.Start(ctx, "CreateWebhookDelivery")
+	defer span.End()
+
+	if d.ID.IsNil() {
+		d.ID = ds.NewID()
+	}
+
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+
+	return r.insert(ctx, "webhook_deliveries", data{
+		"id":              d.ID,
+		"webhook_id":      d.WebhookID,
+		"event_log_id":    d.EventLogID,
+		"event_type":      d.EventType,
+		"payload":         d.Payload,
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"created_at":      d.CreatedAt,
+	})
+}
+
+// UpdateWebhookDelivery stores the result of a delivery attempt.
+func (r *Repo) UpdateWebhookDelivery(ctx context.Context, d *ds.WebhookDelivery) error {
+	_, span := r.tracer.Start(ctx, "UpdateWebhookDelivery")
+	defer span.End()
+
+	return r.update(ctx, d.ID, "webhook_deliveries", data{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"response_status": d.ResponseStatus,
+		"response_body":   d.ResponseBody,
+		"error":           d.Error,
+		"duration_ms":     d.DurationMS,
+		"delivered_at":    d.DeliveredAt,
+	})
+}
+
+// GetWebhookDeliveryByID retrieves a webhook delivery by its ID.
+func (r *Repo) GetWebhookDeliveryByID(ctx context.Context, id ds.ID) (*ds.WebhookDelivery, error) {
+	_, span := r.tracer.Start(ctx, "GetWebhookDeliveryByID")
+	defer span.End()
+
+	d := new(ds.WebhookDelivery)
+	err := pgxscan.Get(ctx, r.getDB(ctx), d, `SELECT * FROM webhook_deliveries WHERE id = $1`, id)
+	if noRows(err) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	return d, err
+}
+
+// ClaimDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due, the longest waiting ones,
+// and postpones their next attempt by lease, so concurrent callers don't get the same deliveries.
+// The caller is expected to store the outcome of the attempt before the lease runs out;
+// if it doesn't (e.g. the process is killed), the deliveries are attempted again after the lease.
+func (r *Repo) ClaimDueWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) (
+	deliveries []ds.WebhookDelivery, err error) {
+	_, span := r.tracer.Start(ctx, "ClaimDueWebhookDeliveries")
+	defer span.End()
+
+	const query = `WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $3
+		FROM due
+		WHERE d.id = due.id
+		RETURNING d.*`
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &deliveries, query, ds.WebhookDeliveryPending, limit, time.Now().Add(lease))
+	return
+}
+
+// FilterWebhookDeliveries returns webhook deliveries matching the filter, newest first.
+func (r *Repo) FilterWebhookDeliveries(ctx context.Context, f ds.WebhookDeliveriesFilter) (
+	deliveries []ds.WebhookDelivery, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterWebhookDeliveries")
+	defer span.End()
+
+	count, err = r.filter("webhook_deliveries").
+		paginate(f.Page, f.PerPage).
+		whereIf(f.WebhookID != nil, "webhook_id", f.WebhookID).
+		whereIf(f.Status != "", "status", f.Status).
+		order("created_at", "desc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &deliveries)
+
+	return
+}
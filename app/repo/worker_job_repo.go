@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+var (
+	// ErrWorkerJobNotFound is returned when a worker job is not found.
+	ErrWorkerJobNotFound = app.ErrNotFound("worker job not found")
+)
+
+// CreateWorkerJob inserts the worker job unless it already exists.
+func (r *Repo) CreateWorkerJob(ctx context.Context, j *ds.WorkerJob) error {
+	_, span := r.tracer.Start(ctx, "CreateWorkerJob")
+	defer span.End()
+
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = time.Now()
+	}
+
+	return r.exec(ctx, `INSERT INTO worker_jobs (name, paused, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO NOTHING`, j.Name, j.Paused, j.CreatedAt)
+}
+
+// GetWorkerJobs returns all worker jobs ordered by name.
+func (r *Repo) GetWorkerJobs(ctx context.Context) (jobs []ds.WorkerJob, err error) {
+	_, span := r.tracer.Start(ctx, "GetWorkerJobs")
+	defer span.End()
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &jobs, `SELECT * FROM worker_jobs ORDER BY name`)
+	return
+}
+
+// GetWorkerJobByName retrieves a worker job by its name.
+func (r *Repo) GetWorkerJobByName(ctx context.Context, name string) (*ds.WorkerJob, error) {
+	_, span := r.tracer.Start(ctx, "GetWorkerJobByName")
+	defer span.End()
+
+	j := new(ds.WorkerJob)
+	err := pgxscan.Get(ctx, r.getDB(ctx), j, `SELECT * FROM worker_jobs WHERE name = $1`, name)
+	if noRows(err) {
+		return nil, ErrWorkerJobNotFound
+	}
+
+	return j, err
+}
+
+// SetWorkerJobPaused pauses or resumes the worker job.
+func (r *Repo) SetWorkerJobPaused(ctx context.Context, name string, paused bool) error {
+	_, span := r.tracer.Start(ctx, "SetWorkerJobPaused")
+	defer span.End()
+
+	return r.exec(ctx, `UPDATE worker_jobs SET paused = $2, updated_at = NOW() WHERE name = $1`, name, paused)
+}
+
+// RequestWorkerJobRun marks the worker job to be run as soon as the worker picks it up.
+func (r *Repo) RequestWorkerJobRun(ctx context.Context, name string) error {
+	_, span := r.tracer.Start(ctx, "RequestWorkerJobRun")
+	defer span.End()
+
+	return r.exec(ctx, `UPDATE worker_jobs SET run_requested_at = NOW() WHERE name = $1`, name)
+}
+
+// TakeRequestedWorkerJobs clears run requests and returns names of the jobs they were made for.
+func (r *Repo) TakeRequestedWorkerJobs(ctx context.Context) (names []string, err error) {
+	_, span := r.tracer.Start(ctx, "TakeRequestedWorkerJobs")
+	defer span.End()
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &names, `UPDATE worker_jobs
+		SET run_requested_at = NULL
+		WHERE run_requested_at IS NOT NULL
+		RETURNING name`)
+	return
+}
+
+// CreateWorkerJobRun inserts a new job run into the database.
+func (r *Repo) CreateWorkerJobRun(ctx context.Context, run *ds.WorkerJobRun) error {
+	_, span := r.tracer.Start(ctx, "CreateWorkerJobRun")
+	defer span.End()
+
+	if run.ID.IsNil() {
+		run.ID = ds.NewID()
+	}
+
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now()
+	}
+
+	return r.insert(ctx, "worker_job_runs", data{
+		"id":              run.ID,
+		"job_name":        run.JobName,
+		"trigger":         run.Trigger,
+		"status":          run.Status,
+		"items_processed": run.ItemsProcessed,
+		"error":           run.Error,
+		"duration_ms":     run.DurationMS,
+		"started_at":      run.StartedAt,
+		"finished_at":     run.FinishedAt,
+	})
+}
+
+// UpdateWorkerJobRun stores the outcome of the job run.
+func (r *Repo) UpdateWorkerJobRun(ctx context.Context, run *ds.WorkerJobRun) error {
+	_, span := r.tracer.Start(ctx, "UpdateWorkerJobRun")
+	defer span.End()
+
+	return r.update(ctx, run.ID, "worker_job_runs", data{
+		"status":          run.Status,
+		"items_processed": run.ItemsProcessed,
+		"error":           run.Error,
+		"duration_ms":     run.DurationMS,
+		"finished_at":     run.FinishedAt,
+	})
+}
+
+// GetLastWorkerJobRuns returns the latest run of every job that has been run at least once.
+func (r *Repo) GetLastWorkerJobRuns(ctx context.Context) (runs []ds.WorkerJobRun, err error) {
+	_, span := r.tracer.Start(ctx, "GetLastWorkerJobRuns")
+	defer span.End()
+
+	err = pgxscan.Select(ctx, r.getDB(ctx), &runs, `SELECT DISTINCT ON (job_name) *
+		FROM worker_job_runs
+		ORDER BY job_name, started_at DESC`)
+	return
+}
+
+// FilterWorkerJobRuns returns job runs matching the filter, newest first.
+func (r *Repo) FilterWorkerJobRuns(ctx context.Context, f ds.WorkerJobRunsFilter) (runs []ds.WorkerJobRun, count int, err error) {
+	_, span := r.tracer.Start(ctx, "FilterWorkerJobRuns")
+	defer span.End()
+
+	count, err = r.filter("worker_job_runs").
+		paginate(f.Page, f.PerPage).
+		whereIf(f.JobName != "", "job_name", f.JobName).
+		whereIf(f.Status != "", "status", f.Status).
+		order("started_at", "desc").
+		withCount(f.WithCount).
+		withoutSoftDelete().
+		scan(ctx, &runs)
+
+	return
+}
+
+// DeleteWorkerJobRunsBefore deletes finished job runs started before t and returns how many were deleted.
+func (r *Repo) DeleteWorkerJobRunsBefore(ctx context.Context, t time.Time) (int64, error) {
+	_, span := r.tracer.Start(ctx, "DeleteWorkerJobRunsBefore")
+	defer span.End()
+
+	tag, err := r.getDB(ctx).Exec(ctx,
+		`DELETE FROM worker_job_runs WHERE started_at < $1 AND status <> $2`, t, ds.WorkerJobRunRunning)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
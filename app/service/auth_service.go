@@ -46,21 +46,30 @@ func (s *Service) AuthenticateUser(ctx context.Context, email, password string)
 		return
 	}
 
-	user, err = s.db.GetUserByEmail(ctx, in.Email)
+	err = s.checkAccountLock(ctx, in.Email)
 	if err != nil {
-		if errors.Is(err, repo.ErrUserNotFound) {
-			err = ErrInvalidEmailOrPassword
-		}
 		return
 	}
-	if user.Deleted() {
-		err = ErrInvalidEmailOrPassword
+
+	user, err = s.db.GetUserByEmail(ctx, in.Email)
+	if err != nil && !errors.Is(err, repo.ErrUserNotFound) {
 		return
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(in.Password))
+	// Failed attempts are counted for unknown emails as well,
+	// so the response doesn't tell whether the account exists.
+	if user == nil || user.Deleted() ||
+		bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(in.Password)) != nil {
+		err = s.registerFailedSignIn(ctx, in.Email, user)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return nil, "", ErrInvalidEmailOrPassword
+	}
+
+	err = s.resetFailedSignIns(ctx, in.Email)
 	if err != nil {
-		err = ErrInvalidEmailOrPassword
 		return
 	}
 
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	z "github.com/Oudwins/zog"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/repo"
+)
+
+const (
+	authorNameMaxLen  = 255
+	authorBioMaxLen   = 10000
+	authorLinksMaxLen = 10
+)
+
+var (
+	// ErrAuthorMergedIntoItself is returned when merging an author into itself.
+	ErrAuthorMergedIntoItself = app.ErrUnprocessable("an author can't be merged into itself")
+
+	// ErrAuthorAlreadyMerged is returned when merging an author that is already merged (or into one).
+	ErrAuthorAlreadyMerged = app.ErrUnprocessable("the author is already merged into another one")
+)
+
+var updateAuthorInputRules = z.Shape{
+	"Name": z.String().Required(z.Message("Name is required")).
+		Max(authorNameMaxLen, z.Message("Name must be at most 255 characters")),
+	"Bio": z.String().Max(authorBioMaxLen, z.Message("Bio must be at most 10000 characters")),
+	"Links": z.Slice(z.Struct(z.Shape{
+		"Title": z.String().Trim().Required(z.Message("Title is required")),
+		"URL":   z.String().Trim().URL(z.Message("Invalid URL")).Required(z.Message("URL is required")),
+	})).Max(authorLinksMaxLen, z.Message("At most 10 links are allowed")),
+}
+
+// UpdateAuthorInput defines the input for editing an author.
+type UpdateAuthorInput struct {
+	Name string
+	// Bio is the biography in Markdown.
+	Bio   string
+	Links []ds.AuthorLink
+}
+
+// Sanitize trims whitespace.
+func (in *UpdateAuthorInput) Sanitize() {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Bio = strings.TrimSpace(in.Bio)
+}
+
+// Validate validates the author input against defined rules.
+func (in *UpdateAuthorInput) Validate() error {
+	return validateInput(updateAuthorInputRules, in)
+}
+
+// FilterAuthors returns authors matching the filter.
+// Users other than admins only find the authors of approved public books.
+func (s *Service) FilterAuthors(ctx context.Context, f ds.AuthorsFilter) (data []ds.Author, count int, err error) {
+	ctx, span := s.tracer.Start(ctx, "FilterAuthors")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	f.OnlyPublished = user == nil || !user.IsAdmin
+
+	return s.db.FilterAuthors(ctx, f)
+}
+
+// GetAuthorByID returns the author by its ID.
+// Users other than admins only find the authors of approved public books.
+func (s *Service) GetAuthorByID(ctx context.Context, id ds.ID) (*ds.Author, error) {
+	ctx, span := s.tracer.Start(ctx, "GetAuthorByID")
+	defer span.End()
+
+	a, err := s.db.GetAuthorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkAuthorPublished(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// GetAuthorBySlug returns the author by its slug.
+// Users other than admins only find the authors of approved public books.
+func (s *Service) GetAuthorBySlug(ctx context.Context, slug string) (*ds.Author, error) {
+	ctx, span := s.tracer.Start(ctx, "GetAuthorBySlug")
+	defer span.End()
+
+	a, err := s.db.GetAuthorBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkAuthorPublished(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// checkAuthorPublished returns repo.ErrAuthorNotFound if the author has no approved public books
+// and the user is not an admin. Merged authors are kept, since they resolve to the authors they're merged into.
+func checkAuthorPublished(ctx context.Context, a *ds.Author) error {
+	user := ds.UserFromContext(ctx)
+	if a.BookCount == 0 && !a.Merged() && (user == nil || !user.IsAdmin) {
+		return repo.ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// UpdateAuthor updates the name, bio and links of the author. The slug is kept, so links to the author page stay valid.
+// Only admins can edit authors.
+func (s *Service) UpdateAuthor(ctx context.Context, id ds.ID, in UpdateAuthorInput) (*ds.Author, error) {
+	ctx, span := s.tracer.Start(ctx, "UpdateAuthor")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil || !user.IsAdmin {
+		return nil, app.ErrUnauthorized()
+	}
+
+	err := Normalize(&in)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.db.GetAuthorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	bio, err := app.MarkdownToHTML(in.Bio)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Name = in.Name
+	a.BioRaw = in.Bio
+	a.Bio = bio
+	a.Links = in.Links
+
+	err = s.db.UpdateAuthor(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// MergeAuthor merges the duplicate source author into the target one: the books of the source
+// are moved to the target, and its links (and bio, if the target has none) are added to the target.
+// The source is kept as merged, so its page redirects to the target.
+// Only admins can merge authors.
+func (s *Service) MergeAuthor(ctx context.Context, sourceID, targetID ds.ID) (target *ds.Author, err error) {
+	ctx, span := s.tracer.Start(ctx, "MergeAuthor")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil || !user.IsAdmin {
+		return nil, app.ErrUnauthorized()
+	}
+
+	if sourceID == targetID {
+		return nil, ErrAuthorMergedIntoItself
+	}
+
+	source, err := s.db.GetAuthorByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err = s.db.GetAuthorByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Merged() || target.Merged() {
+		return nil, ErrAuthorAlreadyMerged
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.db.MergeAuthor(ctx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+
+		target.Links = mergeAuthorLinks(target.Links, source.Links)
+		if target.BioRaw == "" {
+			target.BioRaw = source.BioRaw
+			target.Bio = source.Bio
+		}
+
+		return s.db.UpdateAuthor(ctx, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.db.GetAuthorByID(ctx, target.ID)
+}
+
+// mergeAuthorLinks appends the links not in the list yet (by URL) to the list.
+func mergeAuthorLinks(links, more []ds.AuthorLink) []ds.AuthorLink {
+	for _, l := range more {
+		found := false
+		for _, ex := range links {
+			if strings.EqualFold(ex.URL, l.URL) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			links = append(links, l)
+		}
+	}
+
+	return links
+}
+
+// resolveBookAuthors resolves the authors listed on a book into author records.
+// Authors are matched by name (or the slug of the name), and authors merged into other ones
+// resolve to the target of the merge. Names matching no author create a new one,
+// with the link given along the name as the homepage.
+// Authors resolving to the same record are listed once.
+// It must be called in a transaction.
+func (s *Service) resolveBookAuthors(ctx context.Context, authors []ds.BookAuthor) ([]ds.BookAuthor, error) {
+	resolved := make([]ds.BookAuthor, 0, len(authors))
+	seen := make(map[ds.ID]bool, len(authors))
+
+	for _, ba := range authors {
+		name := strings.TrimSpace(ba.Name)
+		if name == "" {
+			continue
+		}
+
+		a, err := s.db.FindAuthorByName(ctx, name, ds.AuthorSlug(name))
+		if errors.Is(err, repo.ErrAuthorNotFound) {
+			a, err = s.createAuthor(ctx, name, strings.TrimSpace(ba.Link))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for a.Merged() {
+			a, err = s.db.GetAuthorByID(ctx, *a.MergedIntoID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if seen[a.ID] {
+			continue
+		}
+		seen[a.ID] = true
+
+		resolved = append(resolved, ds.BookAuthor{
+			ID:   a.ID,
+			Slug: a.Slug,
+			Name: a.Name,
+		})
+	}
+
+	return resolved, nil
+}
+
+// createAuthor creates an author with a unique slug made of the name.
+func (s *Service) createAuthor(ctx context.Context, name, homepage string) (*ds.Author, error) {
+	base := ds.AuthorSlug(name)
+	slug := base
+
+	for i := 2; ; i++ {
+		exists, err := s.db.AuthorSlugExists(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			break
+		}
+
+		slug = base + "-" + strconv.Itoa(i)
+	}
+
+	a := &ds.Author{
+		Slug:  slug,
+		Name:  name,
+		Links: make([]ds.AuthorLink, 0),
+	}
+	if homepage != "" {
+		a.Links = append(a.Links, ds.AuthorLink{Title: "Homepage", URL: homepage})
+	}
+
+	err := s.db.CreateAuthor(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// bookAuthorsFromAny converts the authors of a change request into []ds.BookAuthor.
+// The authors are []ds.BookAuthor if the changes are applied right away,
+// or a decoded JSON if the change request was loaded from the database.
+func bookAuthorsFromAny(v any) (authors []ds.BookAuthor, err error) {
+	if a, ok := v.([]ds.BookAuthor); ok {
+		return a, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	authors = make([]ds.BookAuthor, 0)
+	err = json.Unmarshal(b, &authors)
+	if err != nil {
+		return nil, fmt.Errorf("book authors: %w", err)
+	}
+
+	return authors, nil
+}
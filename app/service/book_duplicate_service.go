@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/email"
+)
+
+var (
+	// ErrBookMergedIntoItself is returned when merging a book into itself.
+	ErrBookMergedIntoItself = app.ErrUnprocessable("a book can't be merged into itself")
+
+	// ErrBookMergedIntoRejected is returned when merging a book into a rejected one.
+	ErrBookMergedIntoRejected = app.ErrUnprocessable("a book can't be merged into a rejected book")
+)
+
+// FindBookDuplicates returns the existing books that are likely the same book as the given one,
+// judging by the similarity of the titles, the authors in common and the ISBNs.
+// The book may be a new one, not created yet.
+// Users other than admins only find the approved public books, without their status.
+func (s *Service) FindBookDuplicates(ctx context.Context, book *ds.Book) ([]ds.BookDuplicate, error) {
+	ctx, span := s.tracer.Start(ctx, "FindBookDuplicates")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	isAdmin := user != nil && user.IsAdmin
+
+	q := ds.NewBookDuplicatesQuery(book)
+	q.OnlyPublic = !isAdmin
+	if q.Title == "" && len(q.ISBNs) == 0 {
+		return []ds.BookDuplicate{}, nil
+	}
+
+	candidates, err := s.db.FindBookDuplicates(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]ds.BookDuplicate, 0, len(candidates))
+	for _, d := range candidates {
+		if !d.Likely() {
+			continue
+		}
+		if !isAdmin {
+			d.Status = ""
+		}
+		duplicates = append(duplicates, d)
+	}
+
+	return duplicates, nil
+}
+
+// MergeBook merges the newly submitted book into an existing duplicate of it:
+// the topics and the links (homepage, ISBNs and the links of the editions) of the book
+// the existing one lacks are carried over, and the new book is rejected as a duplicate.
+// Only admins can merge books.
+func (s *Service) MergeBook(ctx context.Context, book *ds.Book, targetID ds.ID) (target *ds.Book, err error) {
+	ctx, span := s.tracer.Start(ctx, "MergeBook")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil || !user.IsAdmin {
+		return nil, app.ErrUnauthorized()
+	}
+
+	if book.Status.Not(ds.EntityStatusUnderReview) {
+		return nil, ErrBookIsNotUnderReview
+	}
+
+	if book.ID == targetID {
+		return nil, ErrBookMergedIntoItself
+	}
+
+	target, err = s.GetBookByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Status == ds.EntityStatusRejected {
+		return nil, ErrBookMergedIntoRejected
+	}
+
+	note := "Duplicate of " + target.Title + ": " + app.ServerURL(target.ViewURL()) + "/"
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		topics := make([]ds.Topic, 0)
+		for _, t := range book.Topics {
+			if !hasTopic(target.Topics, t.ID) {
+				topics = append(topics, t)
+			}
+		}
+
+		err := s.AttachTopics(ctx, target.ID, topics)
+		if err != nil {
+			return err
+		}
+		target.Topics = append(target.Topics, topics...)
+
+		changes := make(map[string]any)
+		if target.Homepage == "" && book.Homepage != "" {
+			changes["homepage"] = book.Homepage
+		}
+		if target.ISBN10 == "" && book.ISBN10 != "" {
+			changes["isbn10"] = book.ISBN10
+		}
+		if target.ISBN13 == "" && book.ISBN13 != "" {
+			changes["isbn13"] = book.ISBN13
+		}
+
+		if len(changes) > 0 {
+			err = s.db.ApplyChangesToBook(ctx, target.ID, changes)
+			if err != nil {
+				return err
+			}
+		}
+
+		editions, changed := mergeBookEditions(target.Editions, book.Editions)
+		if changed {
+			err = s.db.ReplaceBookEditions(ctx, target.ID, editions)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.ChangeEntityStatus(ctx, book.ID, ds.EntityStatusRejected)
+		if err != nil {
+			return err
+		}
+
+		return s.LogBookRejected(ctx, user.ID, note, book)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.GetUserByID(ctx, book.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = email.Send(ctx, owner.Email, email.BookRejected{
+		Note:     note,
+		BookName: book.Title,
+		Username: owner.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBookByID(ctx, target.ID)
+}
+
+// hasTopic reports whether the topic is among the topics.
+func hasTopic(topics []ds.Topic, id ds.ID) bool {
+	for _, t := range topics {
+		if t.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeBookEditions adds the editions (by number) and the edition links (by URL) missing from the editions.
+func mergeBookEditions(editions, more []ds.BookEdition) (merged []ds.BookEdition, changed bool) {
+	merged = make([]ds.BookEdition, len(editions))
+	for i := range editions {
+		merged[i] = editions[i].Data()
+	}
+
+	for _, m := range more {
+		i := -1
+		for j := range merged {
+			if merged[j].Number == m.Number {
+				i = j
+				break
+			}
+		}
+
+		if i < 0 {
+			merged = append(merged, m.Data())
+			changed = true
+			continue
+		}
+
+		for _, l := range m.Links {
+			found := false
+			for _, ex := range merged[i].Links {
+				if strings.EqualFold(ex.URL, l.URL) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				merged[i].Links = append(merged[i].Links, l)
+				changed = true
+			}
+		}
+	}
+
+	return merged, changed
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/bookmeta"
+)
+
+var (
+	// ErrInvalidISBN is returned when the ISBN is neither a valid ISBN-10 nor ISBN-13.
+	ErrInvalidISBN = app.ErrUnprocessable("invalid ISBN")
+
+	// ErrBookMetadataNotFound is returned when the metadata provider doesn't know the book.
+	ErrBookMetadataNotFound = app.ErrNotFound("no book found by the ISBN")
+
+	// ErrBookMetadataDisabled is returned when the lookup of book metadata is disabled in config.
+	ErrBookMetadataDisabled = app.ErrNotFound("book metadata lookup is disabled")
+)
+
+// BookMetadata is the metadata of a book found by ISBN, used to prefill a new book.
+type BookMetadata struct {
+	bookmeta.Metadata
+
+	// CoverFileID is the ID of the downloaded cover, a temporary file of the current user.
+	// It's empty if the book has no cover, or it couldn't be downloaded.
+	CoverFileID ds.ID `json:"cover_file_id,omitzero"`
+}
+
+// LookupBookMetadata returns the metadata of the book with the ISBN from the configured provider.
+// The cover is downloaded, so it can be attached to the new book as if the user uploaded it.
+func (s *Service) LookupBookMetadata(ctx context.Context, isbn string) (*BookMetadata, error) {
+	ctx, span := s.tracer.Start(ctx, "LookupBookMetadata")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		return nil, app.ErrUnauthorized()
+	}
+
+	err := s.throttleAccount(ctx, RateLimitBookMetadata, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.lookupBookMetadata(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &BookMetadata{Metadata: *m}
+	if m.CoverURL == "" {
+		return meta, nil
+	}
+
+	f, err := s.uploadBookCover(ctx, m.CoverURL, user.ID, false)
+	if err != nil {
+		// the rest of the metadata is still of use
+		slog.WarnContext(ctx, "download book cover", "isbn", isbn, "url", m.CoverURL, "error", err)
+		return meta, nil
+	}
+
+	meta.CoverFileID = f.ID
+
+	return meta, nil
+}
+
+// lookupBookMetadata returns the metadata of the book with the ISBN (normalized here),
+// converting provider errors to app errors.
+func (s *Service) lookupBookMetadata(ctx context.Context, isbn string) (*bookmeta.Metadata, error) {
+	isbn = ds.NormalizeISBN(isbn)
+	if !ds.ValidISBN(isbn) {
+		return nil, ErrInvalidISBN
+	}
+
+	m, err := s.bookMeta.Lookup(ctx, isbn)
+	switch {
+	case errors.Is(err, bookmeta.ErrNotFound):
+		return nil, ErrBookMetadataNotFound
+	case errors.Is(err, bookmeta.ErrDisabled):
+		return nil, ErrBookMetadataDisabled
+	case err != nil:
+		return nil, err
+	}
+
+	return m, nil
+}
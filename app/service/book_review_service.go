@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	z "github.com/Oudwins/zog"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/repo"
+)
+
+const (
+	bookReviewBodyMaxLen   = 5000
+	bookReviewReasonMaxLen = 500
+)
+
+var (
+	// ErrBookNotReviewable is returned when reviewing a book that is not approved yet.
+	ErrBookNotReviewable = app.ErrUnprocessable("only approved books can be reviewed")
+
+	// ErrCannotReportOwnReview is returned when a user reports their own review.
+	ErrCannotReportOwnReview = app.ErrUnprocessable("you can't report your own review")
+)
+
+var bookReviewInputRules = z.Shape{
+	"Rating": z.Int().Required(z.Message("Rating is required")).
+		GTE(ds.BookReviewMinRating, z.Message("Rating must be from 1 to 5")).
+		LTE(ds.BookReviewMaxRating, z.Message("Rating must be from 1 to 5")),
+	"Body": z.String().Required(z.Message("Review is required")).
+		Max(bookReviewBodyMaxLen, z.Message("Review must be at most 5000 characters")),
+}
+
+// BookReviewInput defines the input for writing a book review.
+type BookReviewInput struct {
+	Rating int
+	// Body is the review in Markdown.
+	Body string
+}
+
+// Sanitize trims whitespace.
+func (in *BookReviewInput) Sanitize() {
+	in.Body = strings.TrimSpace(in.Body)
+}
+
+// Validate validates the review input against defined rules.
+func (in *BookReviewInput) Validate() error {
+	return validateInput(bookReviewInputRules, in)
+}
+
+var bookReviewReportInputRules = z.Shape{
+	"Reason": z.String().Max(bookReviewReasonMaxLen, z.Message("Reason must be at most 500 characters")),
+}
+
+// BookReviewReportInput defines the input for reporting a book review.
+type BookReviewReportInput struct {
+	Reason string
+}
+
+// Sanitize trims whitespace.
+func (in *BookReviewReportInput) Sanitize() {
+	in.Reason = strings.TrimSpace(in.Reason)
+}
+
+// Validate validates the report input against defined rules.
+func (in *BookReviewReportInput) Validate() error {
+	return validateInput(bookReviewReportInputRules, in)
+}
+
+// SaveBookReview writes the review of the book by the current user,
+// or updates it if the user has already reviewed the book.
+func (s *Service) SaveBookReview(ctx context.Context, book *ds.Book, in BookReviewInput) (rev *ds.BookReview, err error) {
+	ctx, span := s.tracer.Start(ctx, "SaveBookReview")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		return nil, app.ErrUnauthorized()
+	}
+
+	if book.Status.Not(ds.EntityStatusApproved) {
+		return nil, ErrBookNotReviewable
+	}
+
+	err = Normalize(&in)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.throttleAccount(ctx, RateLimitBookReview, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := app.MarkdownToHTML(in.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) (err error) {
+		rev, err = s.db.GetBookReviewByUser(ctx, book.ID, user.ID)
+		if errors.Is(err, repo.ErrBookReviewNotFound) {
+			rev = &ds.BookReview{
+				BookID:   book.ID,
+				UserID:   new(user.ID),
+				Username: user.Username,
+				Rating:   in.Rating,
+				BodyRaw:  in.Body,
+				Body:     body,
+			}
+
+			err = s.db.CreateBookReview(ctx, rev)
+			if err != nil {
+				return
+			}
+
+			err = s.LogBookReviewed(ctx, rev)
+		} else if err == nil {
+			rev.Rating = in.Rating
+			rev.BodyRaw = in.Body
+			rev.Body = body
+
+			err = s.db.UpdateBookReview(ctx, rev)
+		}
+		if err != nil {
+			return
+		}
+
+		return s.db.RefreshBookRating(ctx, book.ID)
+	})
+
+	return rev, err
+}
+
+// GetUserBookReview returns the review of the book by the current user,
+// or nil if there is no user or the user hasn't reviewed the book.
+func (s *Service) GetUserBookReview(ctx context.Context, bookID ds.ID) (*ds.BookReview, error) {
+	ctx, span := s.tracer.Start(ctx, "GetUserBookReview")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	rev, err := s.db.GetBookReviewByUser(ctx, bookID, user.ID)
+	if errors.Is(err, repo.ErrBookReviewNotFound) {
+		return nil, nil //nolint:nilnil
+	}
+
+	return rev, err
+}
+
+// DeleteBookReview deletes the review of the book by the current user.
+func (s *Service) DeleteBookReview(ctx context.Context, book *ds.Book) error {
+	ctx, span := s.tracer.Start(ctx, "DeleteBookReview")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		return app.ErrUnauthorized()
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		rev, err := s.db.GetBookReviewByUser(ctx, book.ID, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.db.DeleteBookReview(ctx, rev.ID)
+		if err != nil {
+			return err
+		}
+
+		return s.db.RefreshBookRating(ctx, book.ID)
+	})
+}
+
+// FilterBookReviews retrieves a paginated list of book reviews matching the given filter.
+func (s *Service) FilterBookReviews(ctx context.Context, f ds.BookReviewsFilter) (
+	reviews []ds.BookReview, count int, err error) {
+	ctx, span := s.tracer.Start(ctx, "FilterBookReviews")
+	defer span.End()
+
+	return s.db.FilterBookReviews(ctx, f)
+}
+
+// GetBookRating returns the aggregate rating of the book along with the distribution of ratings.
+func (s *Service) GetBookRating(ctx context.Context, bookID ds.ID) (ds.BookRating, error) {
+	ctx, span := s.tracer.Start(ctx, "GetBookRating")
+	defer span.End()
+
+	counts, err := s.db.BookRatingCounts(ctx, bookID)
+	if err != nil {
+		return ds.BookRating{}, err
+	}
+
+	return ds.NewBookRating(counts), nil
+}
+
+// ReportBookReview reports the review to moderators on behalf of the current user.
+// Reporting the same review again has no effect.
+func (s *Service) ReportBookReview(ctx context.Context, reviewID ds.ID, in BookReviewReportInput) error {
+	ctx, span := s.tracer.Start(ctx, "ReportBookReview")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		return app.ErrUnauthorized()
+	}
+
+	err := Normalize(&in)
+	if err != nil {
+		return err
+	}
+
+	rev, err := s.db.GetBookReviewByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if rev.Hidden {
+		return repo.ErrBookReviewNotFound
+	}
+
+	if rev.UserID != nil && *rev.UserID == user.ID {
+		return ErrCannotReportOwnReview
+	}
+
+	err = s.throttleAccount(ctx, RateLimitBookReview, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		reported, err := s.db.CreateBookReviewReport(ctx, &ds.BookReviewReport{
+			ReviewID: rev.ID,
+			UserID:   new(user.ID),
+			Reason:   in.Reason,
+		})
+		if err != nil || !reported {
+			return err
+		}
+
+		return s.LogBookReviewReported(ctx, user.ID, rev, in.Reason)
+	})
+}
+
+// SetBookReviewHidden hides the review from the book page and its rating (or makes it visible again).
+// Only admins can moderate reviews.
+func (s *Service) SetBookReviewHidden(ctx context.Context, reviewID ds.ID, hidden bool) (*ds.BookReview, error) {
+	ctx, span := s.tracer.Start(ctx, "SetBookReviewHidden")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil || !user.IsAdmin {
+		return nil, app.ErrUnauthorized()
+	}
+
+	rev, err := s.db.GetBookReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rev.Hidden == hidden {
+		return rev, nil
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.db.SetBookReviewHidden(ctx, rev, hidden, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.db.RefreshBookRating(ctx, rev.BookID)
+		if err != nil {
+			return err
+		}
+
+		return s.LogBookReviewHidden(ctx, user.ID, rev)
+	})
+
+	return rev, err
+}
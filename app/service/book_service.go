@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -12,6 +13,7 @@ import (
 	"github.com/gopl-dev/server/app/ds/prop"
 	"github.com/gopl-dev/server/app/repo"
 	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/metrics"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -47,6 +49,35 @@ func (s *Service) CreateBook(ctx context.Context, book *ds.Book) (err error) {
 	ctx, span := s.tracer.Start(ctx, "CreateBook")
 	defer span.End()
 
+	err = prepareNewBook(book)
+	if err != nil {
+		return err
+	}
+
+	err = s.resolveBookCover(ctx, book, false)
+	if err != nil {
+		return err
+	}
+
+	book.Topics, err = s.normalizeTopics(ctx, book.Topics, ds.EntityTypeBook, 1)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) (err error) {
+		return s.insertBook(ctx, book)
+	})
+	if err != nil {
+		return err
+	}
+
+	metrics.BooksSubmitted.Inc()
+
+	return nil
+}
+
+// prepareNewBook renders the markdown fields of the new book, sets its public ID and validates it.
+func prepareNewBook(book *ds.Book) (err error) {
 	book.Summary, err = app.MarkdownToHTML(book.SummaryRaw)
 	if err != nil {
 		return
@@ -57,46 +88,52 @@ func (s *Service) CreateBook(ctx context.Context, book *ds.Book) (err error) {
 	}
 	book.PublicID = app.Slug(book.Title)
 
-	err = ValidateCreate(book)
+	return ValidateCreate(book)
+}
+
+// insertBook creates the entity of the prepared book (logging the event), the book, its authors, topics,
+// editions, and commits its cover. It must be called in a transaction.
+func (s *Service) insertBook(ctx context.Context, book *ds.Book) (err error) {
+	err = s.CreateEntity(ctx, book.Entity)
 	if err != nil {
-		return err
+		return
 	}
 
-	err = s.resolveBookCover(ctx, book, false)
+	err = s.db.CreateBook(ctx, book)
 	if err != nil {
-		return err
+		return
 	}
 
-	book.Topics, err = s.normalizeTopics(ctx, book.Topics, ds.EntityTypeBook, 1)
+	book.Authors, err = s.resolveBookAuthors(ctx, book.Authors)
 	if err != nil {
-		return err
+		return
 	}
 
-	return s.db.WithTx(ctx, func(ctx context.Context) (err error) {
-		err = s.CreateEntity(ctx, book.Entity)
-		if err != nil {
-			return
-		}
+	err = s.db.ReplaceBookAuthors(ctx, book.ID, book.Authors)
+	if err != nil {
+		return
+	}
+
+	err = s.AttachTopics(ctx, book.ID, book.Topics)
+	if err != nil {
+		return
+	}
 
-		err = s.db.CreateBook(ctx, book)
+	if len(book.Editions) > 0 {
+		err = s.db.ReplaceBookEditions(ctx, book.ID, book.Editions)
 		if err != nil {
 			return
 		}
+	}
 
-		err = s.AttachTopics(ctx, book.ID, book.Topics)
+	if !book.CoverFileID.IsNil() {
+		err = s.db.CommitFile(ctx, book.CoverFileID)
 		if err != nil {
 			return
 		}
+	}
 
-		if !book.CoverFileID.IsNil() {
-			err = s.db.CommitFile(ctx, book.CoverFileID)
-			if err != nil {
-				return
-			}
-		}
-
-		return nil
-	})
+	return nil
 }
 
 // ApproveNewBook approves a newly submitted book.
@@ -137,7 +174,7 @@ func (s *Service) ApproveNewBook(ctx context.Context, book *ds.Book) (err error)
 		return
 	}
 
-	return email.Send(owner.Email, email.BookApproved{
+	return email.Send(ctx, owner.Email, email.BookApproved{
 		BookName: book.Title,
 		Username: owner.Username,
 		PublicID: book.PublicID,
@@ -182,7 +219,7 @@ func (s *Service) RejectNewBook(ctx context.Context, note string, book *ds.Book)
 		return
 	}
 
-	return email.Send(owner.Email, email.BookRejected{
+	return email.Send(ctx, owner.Email, email.BookRejected{
 		Note:     note,
 		BookName: book.Title,
 		Username: owner.Username,
@@ -259,6 +296,11 @@ func (s *Service) UpdateBook(ctx context.Context, id ds.ID, newBook *ds.Book) (r
 		return
 	}
 
+	// editions left out of the request are kept, an empty list removes them
+	if newBook.Editions == nil {
+		newBook.Editions = book.Editions
+	}
+
 	newBook.ID = book.ID
 	newBook.OwnerID = book.OwnerID
 	newBook.PublicID = book.PublicID
@@ -435,6 +477,56 @@ func (s *Service) ApplyChangesToBook(ctx context.Context, changes []ChangeDiff,
 			return
 		}
 
+		if authorsAny, ok := data["authors"]; ok {
+			authors, err := bookAuthorsFromAny(authorsAny)
+			if err != nil {
+				return err
+			}
+
+			authors, err = s.resolveBookAuthors(ctx, authors)
+			if err != nil {
+				return err
+			}
+
+			err = s.db.ReplaceBookAuthors(ctx, book.ID, authors)
+			if err != nil {
+				return err
+			}
+
+			// authors is an external model
+			delete(data, "authors")
+		}
+
+		if editionsAny, ok := data["editions"]; ok {
+			editions, err := bookEditionsFromAny(editionsAny)
+			if err != nil {
+				return err
+			}
+
+			err = s.db.ReplaceBookEditions(ctx, book.ID, editions)
+			if err != nil {
+				return err
+			}
+
+			// editions is an external model
+			delete(data, "editions")
+		}
+
+		// numbers of a change request loaded from the database are decoded as float64
+		if pos, ok := data["series_position"].(float64); ok {
+			data["series_position"] = int(pos)
+		}
+
+		// keep the sort keys in sync with the values they are derived from
+		if title, ok := entityData["title"]; ok {
+			data["title_sort"] = ds.TitleSortKey(app.String(title))
+		}
+		if releaseDate, ok := data["release_date"]; ok {
+			if t, ok := ds.ParseReleaseDate(app.String(releaseDate)); ok {
+				data["release_date_sort"] = t
+			}
+		}
+
 		if len(data) > 0 {
 			err = s.db.ApplyChangesToBook(ctx, req.EntityID, data)
 			if err != nil {
@@ -463,7 +555,7 @@ func (s *Service) ApplyChangesToBook(ctx context.Context, changes []ChangeDiff,
 	}
 
 	if sendNotification {
-		err = email.Send(author.Email, email.ChangesApproved{
+		err = email.Send(ctx, author.Email, email.ChangesApproved{
 			Username:    author.Username,
 			EntityTitle: book.Title,
 			ViewURL:     book.ViewURL(),
@@ -476,6 +568,28 @@ func (s *Service) ApplyChangesToBook(ctx context.Context, changes []ChangeDiff,
 	return
 }
 
+// bookEditionsFromAny converts the editions of a change request into []ds.BookEdition.
+// The editions are []ds.BookEdition if the changes are applied right away,
+// or a decoded JSON if the change request was loaded from the database.
+func bookEditionsFromAny(v any) (editions []ds.BookEdition, err error) {
+	if e, ok := v.([]ds.BookEdition); ok {
+		return e, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	editions = make([]ds.BookEdition, 0)
+	err = json.Unmarshal(b, &editions)
+	if err != nil {
+		return nil, fmt.Errorf("book editions: %w", err)
+	}
+
+	return editions, nil
+}
+
 // makeDiff compares two DataProvider states and returns a diff map that contains
 // only fields whose values changed in newData compared to oldData.
 //
@@ -620,7 +734,7 @@ func (s *Service) SearchBooks(ctx context.Context, query string) ([]SearchBooksR
 
 	var (
 		books   []ds.Book
-		authors []ds.BookAuthor
+		authors []ds.Author
 		topics  []ds.Topic
 	)
 
@@ -670,7 +784,7 @@ func (s *Service) SearchBooks(ctx context.Context, query string) ([]SearchBooksR
 		results = append(results, SearchBooksResult{
 			Type: SearchBookTypeAuthor,
 			Name: r.Name,
-			URL:  "/books/?author=" + r.Name,
+			URL:  r.ViewURL(),
 		})
 	}
 
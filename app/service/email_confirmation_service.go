@@ -110,7 +110,7 @@ func (s *Service) ResendConfirmationEmailCode(ctx context.Context) (retryAfter t
 		return
 	}
 
-	err = email.Send(user.Email, email.ConfirmEmail{
+	err = email.Send(ctx, user.Email, email.ConfirmEmail{
 		Username: user.Username,
 		Email:    user.Email,
 		Code:     emailConfirmCode,
@@ -98,7 +98,12 @@ func (s *Service) UpdateEntityChangeRequest(ctx context.Context, m *ds.EntityCha
 	req, err := s.db.GetPendingChangeRequest(ctx, m.EntityID, m.UserID)
 	if errors.Is(err, repo.ErrEntityChangeRequestNotFound) {
 		m.Revision = 1
-		return s.db.CreateChangeRequest(ctx, m)
+		err = s.db.CreateChangeRequest(ctx, m)
+		if err != nil {
+			return err
+		}
+
+		return s.LogChangeRequestSubmitted(ctx, m)
 	}
 	if err != nil {
 		return err
@@ -115,7 +120,11 @@ func (s *Service) UpdateEntityChangeRequest(ctx context.Context, m *ds.EntityCha
 	m.UpdatedAt = new(time.Now())
 
 	err = s.db.UpdateChangeRequest(ctx, m)
-	return
+	if err != nil {
+		return err
+	}
+
+	return s.LogChangeRequestSubmitted(ctx, m)
 }
 
 // FilterChangeRequests retrieves a paginated list of change requests matching the given filter.
@@ -126,6 +135,14 @@ func (s *Service) FilterChangeRequests(ctx context.Context, f ds.ChangeRequestsF
 	return s.db.FilterChangeRequests(ctx, f)
 }
 
+// CountPendingChangeRequests returns the number of change requests awaiting review.
+func (s *Service) CountPendingChangeRequests(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "CountPendingChangeRequests")
+	defer span.End()
+
+	return s.db.CountPendingChangeRequests(ctx)
+}
+
 // ChangeDiff represents the difference between current and proposed values in an entity change request.
 // For type "diff" Diff property should be set, for other types Current and Proposed should be set.
 type ChangeDiff struct {
@@ -217,7 +234,7 @@ func (s *Service) RejectChangeRequest(ctx context.Context, id, reviewerID ds.ID,
 		return nil
 	}
 
-	return email.Send(author.Email, email.ChangesRejected{
+	return email.Send(ctx, author.Email, email.ChangesRejected{
 		Username:    author.Username,
 		EntityTitle: entity.Title,
 		Note:        note,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/ds"
@@ -51,6 +52,7 @@ resolvePublicID:
 	// for admins and private entities set status to approved
 	if user.IsAdmin || e.Visibility.Is(ds.EntityVisibilityPrivate) {
 		e.Status = ds.EntityStatusApproved
+		e.PublishedAt = new(time.Now())
 	}
 
 	err = ValidateCreate(e)
@@ -114,3 +116,11 @@ func (s *Service) GetEntityByID(ctx context.Context, id ds.ID) (*ds.Entity, erro
 
 	return s.db.GetEntityByID(ctx, id)
 }
+
+// FilterEntities retrieves a paginated list of entities matching the given filter.
+func (s *Service) FilterEntities(ctx context.Context, f ds.EntitiesFilter) (data []ds.Entity, count int, err error) {
+	ctx, span := s.tracer.Start(ctx, "FilterEntities")
+	defer span.End()
+
+	return s.db.FilterEntities(ctx, f)
+}
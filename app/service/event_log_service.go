@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/oauth/provider"
 )
 
 // createEventLog persists a prepared EventLog entry using the database layer.
@@ -11,7 +12,12 @@ func (s *Service) createEventLog(ctx context.Context, log *ds.EventLog) error {
 	ctx, span := s.tracer.Start(ctx, "createEventLog")
 	defer span.End()
 
-	return s.db.CreateEventLog(ctx, log)
+	err := s.db.CreateEventLog(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	return s.enqueueWebhookDeliveries(ctx, log)
 }
 
 // FilterEventLogs retrieves a paginated list of event logs matching the given filter.
@@ -101,6 +107,25 @@ func (s *Service) LogEntityRenamed(ctx context.Context, userID, entityID ds.ID,
 	return s.createEventLog(ctx, log)
 }
 
+// LogChangeRequestSubmitted records a private event that changes to the entity are waiting for review.
+func (s *Service) LogChangeRequestSubmitted(ctx context.Context, req *ds.EntityChangeRequest) error {
+	ctx, span := s.tracer.Start(ctx, "LogChangeRequestSubmitted")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:         new(req.UserID),
+		Type:           ds.EventLogChangeRequestSubmitted,
+		EntityID:       new(req.EntityID),
+		EntityChangeID: new(req.ID),
+		Meta: map[string]any{
+			"revision": req.Revision,
+		},
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
 // LogUserRegistered records the creation of a user account.
 func (s *Service) LogUserRegistered(ctx context.Context, userID ds.ID) error {
 	ctx, span := s.tracer.Start(ctx, "LogUserRegistered")
@@ -244,6 +269,85 @@ func (s *Service) LogUsernameChanged(ctx context.Context, userID ds.ID, oldName,
 	return s.createEventLog(ctx, log)
 }
 
+// LogUserProfileUpdated records an update of the user's public profile.
+func (s *Service) LogUserProfileUpdated(ctx context.Context, userID ds.ID) error {
+	ctx, span := s.tracer.Start(ctx, "LogUserProfileUpdated")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:   new(userID),
+		Type:     ds.EventLogUserProfileUpdated,
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogUserDataExportRequested records that a user requested a copy of their personal data.
+func (s *Service) LogUserDataExportRequested(ctx context.Context, userID ds.ID) error {
+	ctx, span := s.tracer.Start(ctx, "LogUserDataExportRequested")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:   new(userID),
+		Type:     ds.EventLogUserDataExportRequested,
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogOAuthAccountLinked records that a user linked an OAuth provider to their account.
+func (s *Service) LogOAuthAccountLinked(ctx context.Context, userID ds.ID, prov provider.Type) error {
+	ctx, span := s.tracer.Start(ctx, "LogOAuthAccountLinked")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID: new(userID),
+		Type:   ds.EventLogUserOAuthAccountLinked,
+		Meta: map[string]any{
+			"provider": prov,
+		},
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogOAuthAccountUnlinked records that a user unlinked an OAuth provider from their account.
+func (s *Service) LogOAuthAccountUnlinked(ctx context.Context, userID ds.ID, prov provider.Type) error {
+	ctx, span := s.tracer.Start(ctx, "LogOAuthAccountUnlinked")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID: new(userID),
+		Type:   ds.EventLogUserOAuthAccountUnlinked,
+		Meta: map[string]any{
+			"provider": prov,
+		},
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogAccountLocked records that a user account was locked after repeated failed sign-in attempts.
+func (s *Service) LogAccountLocked(ctx context.Context, userID ds.ID, attempts int) error {
+	ctx, span := s.tracer.Start(ctx, "LogAccountLocked")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID: new(userID),
+		Type:   ds.EventLogUserAccountLocked,
+		Meta: map[string]any{
+			"attempts": attempts,
+		},
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
 // LogBookApproved writes event logs for a successfully approved book.
 //
 // It creates two event log records:
@@ -290,3 +394,63 @@ func (s *Service) LogBookRejected(ctx context.Context, rejectedBy ds.ID, note st
 
 	return s.createEventLog(ctx, log)
 }
+
+// LogBookReviewed records a public event that the user reviewed the book.
+func (s *Service) LogBookReviewed(ctx context.Context, rev *ds.BookReview) error {
+	ctx, span := s.tracer.Start(ctx, "LogBookReviewed")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:   rev.UserID,
+		Type:     ds.EventLogBookReviewed,
+		EntityID: new(rev.BookID),
+		Meta: map[string]any{
+			"review_id": rev.ID,
+			"rating":    rev.Rating,
+		},
+		IsPublic: true,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogBookReviewReported records a private event that the user reported the review.
+func (s *Service) LogBookReviewReported(ctx context.Context, userID ds.ID, rev *ds.BookReview, reason string) error {
+	ctx, span := s.tracer.Start(ctx, "LogBookReviewReported")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:   new(userID),
+		Type:     ds.EventLogBookReviewReported,
+		EntityID: new(rev.BookID),
+		Meta: map[string]any{
+			"review_id": rev.ID,
+			"reason":    reason,
+		},
+		IsPublic: false,
+	}
+
+	return s.createEventLog(ctx, log)
+}
+
+// LogBookReviewHidden records a private event that the moderator hid (or unhid) the review.
+func (s *Service) LogBookReviewHidden(ctx context.Context, moderatorID ds.ID, rev *ds.BookReview) error {
+	ctx, span := s.tracer.Start(ctx, "LogBookReviewHidden")
+	defer span.End()
+
+	log := &ds.EventLog{
+		UserID:   new(moderatorID),
+		Type:     ds.EventLogBookReviewHidden,
+		EntityID: new(rev.BookID),
+		Meta: map[string]any{
+			"review_id": rev.ID,
+		},
+		IsPublic: false,
+	}
+
+	if !rev.Hidden {
+		log.Type = ds.EventLogBookReviewUnhidden
+	}
+
+	return s.createEventLog(ctx, log)
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+const (
+	// ExternalLinkRobotsAgent is the name the checker of external links goes by in robots.txt.
+	ExternalLinkRobotsAgent = "gopl.dev-linkchecker"
+
+	// ExternalLinkUserAgent is sent with the checks of external links.
+	ExternalLinkUserAgent = ExternalLinkRobotsAgent + " (+https://gopl.dev)"
+
+	// externalLinksBatchSize is how many due links are checked per worker run.
+	externalLinksBatchSize = 300
+
+	// externalLinkMaxCrawlDelay caps Crawl-delay of robots.txt, so a single site can't stall the run.
+	externalLinkMaxCrawlDelay = 30 * time.Second
+
+	// externalLinkBodyMaxLen is how much of the body of a GET response is read before closing it.
+	externalLinkBodyMaxLen = 64 << 10
+
+	// robotsMaxLen is how much of robots.txt is parsed.
+	robotsMaxLen = 512 << 10
+
+	externalLinkTimeout = 15 * time.Second
+)
+
+// Delays before the next check of a link, by its status.
+var externalLinkRecheckAfter = map[ds.ExternalLinkStatus]time.Duration{
+	ds.ExternalLinkOK:      7 * 24 * time.Hour,
+	ds.ExternalLinkSkipped: 7 * 24 * time.Hour,
+	ds.ExternalLinkFailing: 6 * time.Hour,
+	ds.ExternalLinkBroken:  24 * time.Hour,
+	// the least a rate limited link waits, unless the site asks for longer with Retry-After
+	ds.ExternalLinkRateLimited: time.Hour,
+}
+
+var (
+	// ExternalLinkHTTPClient is the client external links are checked with.
+	// It doesn't connect to private, loopback and link-local addresses, see externalLinkDialControl.
+	ExternalLinkHTTPClient = newExternalLinkHTTPClient()
+
+	// ExternalLinkHostInterval is the least time between two requests to the same host.
+	// Crawl-delay of robots.txt of the host makes it longer.
+	ExternalLinkHostInterval = time.Second
+)
+
+var (
+	// ErrExternalLinkRateLimited is the error of a check the site responded to with 429 Too Many Requests.
+	ErrExternalLinkRateLimited = errors.New("rate limited by the site")
+
+	// ErrExternalLinkAddressNotAllowed is the error of a check of a link that resolves
+	// (or redirects) to an address of a private network, which the checker doesn't connect to.
+	ErrExternalLinkAddressNotAllowed = errors.New("address not allowed")
+)
+
+// externalLinkBlockedPrefixes are the ranges, not covered by the netip.Addr methods,
+// that are not reachable on the internet or may lead into a private network.
+var externalLinkBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// newExternalLinkHTTPClient returns the client that checks the address of every connection it makes,
+// after DNS resolution and for every redirect, so links can't be used to probe the private network of the server.
+func newExternalLinkHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: externalLinkTimeout,
+		Control: externalLinkDialControl,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	// a proxy would connect to the site instead, out of reach of the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   externalLinkTimeout,
+		Transport: transport,
+	}
+}
+
+// externalLinkDialControl refuses to connect to the addresses that are not public unicast ones.
+func externalLinkDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("%w: %s", ErrExternalLinkAddressNotAllowed, ip)
+	}
+	for _, p := range externalLinkBlockedPrefixes {
+		if p.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrExternalLinkAddressNotAllowed, ip)
+		}
+	}
+
+	return nil
+}
+
+// CheckDueExternalLinks refreshes the list of external links found in books, authors and pages,
+// then checks the links whose next check is due, recording the result in their history.
+// Links of a site that rate limited us are left for the next run.
+func (s *Service) CheckDueExternalLinks(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "CheckDueExternalLinks")
+	defer span.End()
+
+	err := s.SyncExternalLinks(ctx)
+	if err != nil {
+		return fmt.Errorf("sync external links: %w", err)
+	}
+
+	links, err := s.db.GetDueExternalLinks(ctx, externalLinksBatchSize)
+	if err != nil {
+		return err
+	}
+
+	c := newExternalLinkChecker()
+	for _, l := range links {
+		if c.rateLimited(l.Host()) {
+			continue
+		}
+
+		check, retryAfter, err := c.check(ctx, l.URL)
+		if err != nil {
+			// the run is interrupted
+			return err
+		}
+
+		check.LinkID = l.ID
+		applyExternalLinkCheck(&l, check, retryAfter)
+
+		err = s.db.WithTx(ctx, func(ctx context.Context) error {
+			err := s.db.UpdateExternalLink(ctx, &l)
+			if err != nil {
+				return err
+			}
+
+			return s.db.CreateExternalLinkCheck(ctx, check)
+		})
+		if err != nil {
+			return err
+		}
+
+		ds.AddProcessedItems(ctx, 1)
+	}
+
+	return nil
+}
+
+// SyncExternalLinks stores the URLs found in books (homepage, links of editions),
+// authors (links) and pages (links in the content) as external links to check.
+// Links no longer found anywhere are deleted.
+func (s *Service) SyncExternalLinks(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "SyncExternalLinks")
+	defer span.End()
+
+	found, err := s.db.ExternalLinkRefs(ctx)
+	if err != nil {
+		return err
+	}
+
+	pages, err := s.db.PagesContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	for id, content := range pages {
+		for _, link := range app.MarkdownLinks(content) {
+			found = append(found, ds.ExternalLinkRef{
+				URL:        link,
+				SourceType: ds.ExternalLinkSourcePage,
+				SourceID:   id,
+				Field:      "content",
+			})
+		}
+	}
+
+	seen := make(map[ds.ExternalLinkRef]bool, len(found))
+	refs := make([]ds.ExternalLinkRef, 0, len(found))
+	for _, ref := range found {
+		var ok bool
+		ref.URL, ok = ds.ExternalLinkURL(ref.URL)
+		if !ok || seen[ref] {
+			continue
+		}
+
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		return s.db.SyncExternalLinks(ctx, refs)
+	})
+}
+
+// FilterExternalLinks returns external links along with the records they are found in.
+func (s *Service) FilterExternalLinks(ctx context.Context, f ds.ExternalLinksFilter) ([]ds.ExternalLink, int, error) {
+	ctx, span := s.tracer.Start(ctx, "FilterExternalLinks")
+	defer span.End()
+
+	links, count, err := s.db.FilterExternalLinks(ctx, f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]ds.ID, len(links))
+	for i, l := range links {
+		ids[i] = l.ID
+	}
+
+	sources, err := s.db.ExternalLinksSources(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range links {
+		links[i].Sources = sources[links[i].ID]
+	}
+
+	return links, count, nil
+}
+
+// FilterExternalLinkChecks returns the history of checks of the external link.
+func (s *Service) FilterExternalLinkChecks(ctx context.Context, f ds.ExternalLinkChecksFilter) (
+	[]ds.ExternalLinkCheck, int, error) {
+	ctx, span := s.tracer.Start(ctx, "FilterExternalLinkChecks")
+	defer span.End()
+
+	_, err := s.db.GetExternalLinkByID(ctx, f.LinkID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.db.FilterExternalLinkChecks(ctx, f)
+}
+
+// GetBrokenExternalLinks returns the broken links found in the record, for editors to fix.
+func (s *Service) GetBrokenExternalLinks(ctx context.Context, sourceType ds.ExternalLinkSourceType, sourceID ds.ID) (
+	[]ds.ExternalLink, error) {
+	ctx, span := s.tracer.Start(ctx, "GetBrokenExternalLinks")
+	defer span.End()
+
+	return s.db.GetExternalLinksOfSource(ctx, sourceType, sourceID, ds.ExternalLinkBroken)
+}
+
+// applyExternalLinkCheck records the outcome of the check on the link and schedules the next check.
+// A failed check makes the link failing, and broken once it failed ds.ExternalLinkBrokenAfter times in a row.
+// A rate limited check leaves the status of the link as is.
+func applyExternalLinkCheck(l *ds.ExternalLink, c *ds.ExternalLinkCheck, retryAfter time.Duration) {
+	now := c.CheckedAt
+	l.CheckedAt = new(now)
+
+	switch c.Status {
+	case ds.ExternalLinkRateLimited:
+		l.NextCheckAt = now.Add(max(retryAfter, externalLinkRecheckAfter[ds.ExternalLinkRateLimited]))
+		return
+	case ds.ExternalLinkFailing:
+		l.FailCount++
+		if l.FailCount >= ds.ExternalLinkBrokenAfter {
+			c.Status = ds.ExternalLinkBroken
+		}
+	default:
+		l.FailCount = 0
+	}
+
+	l.Status = c.Status
+	l.StatusCode = c.StatusCode
+	l.Error = c.Error
+	l.NextCheckAt = now.Add(externalLinkRecheckAfter[l.Status])
+}
+
+// externalLinkChecker checks external links, keeping the robots.txt rules and the time of the latest request
+// of every host for the length of a worker run.
+type externalLinkChecker struct {
+	robots      map[string]*ds.RobotsRules
+	nextRequest map[string]time.Time
+	limited     map[string]bool
+}
+
+func newExternalLinkChecker() *externalLinkChecker {
+	return &externalLinkChecker{
+		robots:      map[string]*ds.RobotsRules{},
+		nextRequest: map[string]time.Time{},
+		limited:     map[string]bool{},
+	}
+}
+
+// rateLimited reports whether the host responded with 429 Too Many Requests during the run.
+func (c *externalLinkChecker) rateLimited(host string) bool {
+	return c.limited[host]
+}
+
+// check checks the link with HEAD, falling back to GET, as some sites don't support HEAD.
+// The status of the returned check is ok, failing, skipped or rate_limited; for the latter
+// the delay the site asked for with Retry-After is returned too.
+// A link is failing if it can't be reached, or the site responds with 404, 410 or 5xx:
+// other responses, e.g. 403 of a site that blocks bots, don't tell that the page is gone.
+// The error is returned only when the context is done.
+func (c *externalLinkChecker) check(ctx context.Context, rawURL string) (*ds.ExternalLinkCheck, time.Duration, error) {
+	check := &ds.ExternalLinkCheck{Status: ds.ExternalLinkFailing}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		check.Error = err.Error()
+		check.CheckedAt = time.Now()
+		return check, 0, nil
+	}
+
+	rules, err := c.robotsRules(ctx, u)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !rules.Allowed(u.RequestURI()) {
+		check.Status = ds.ExternalLinkSkipped
+		check.Error = "disallowed by robots.txt"
+		check.CheckedAt = time.Now()
+		return check, 0, nil
+	}
+
+	var retryAfter time.Duration
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		err = c.wait(ctx, u.Host, rules)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		start := time.Now()
+		check.Method = method
+		check.StatusCode, retryAfter, err = c.request(ctx, method, rawURL)
+		check.CheckedAt = time.Now()
+		check.DurationMS += int(check.CheckedAt.Sub(start).Milliseconds())
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+
+		check.Error = ""
+		if err != nil {
+			check.Error = err.Error()
+		}
+
+		if err == nil && check.StatusCode < http.StatusBadRequest {
+			check.Status = ds.ExternalLinkOK
+			return check, 0, nil
+		}
+
+		if check.StatusCode == http.StatusTooManyRequests {
+			c.limited[u.Host] = true
+			check.Status = ds.ExternalLinkRateLimited
+			check.Error = ErrExternalLinkRateLimited.Error()
+			return check, retryAfter, nil
+		}
+	}
+
+	switch {
+	case check.Error != "":
+		check.Status = ds.ExternalLinkFailing
+	case check.StatusCode == http.StatusNotFound,
+		check.StatusCode == http.StatusGone,
+		check.StatusCode >= http.StatusInternalServerError:
+		check.Status = ds.ExternalLinkFailing
+		check.Error = "unexpected response status " + strconv.Itoa(check.StatusCode)
+	default:
+		check.Status = ds.ExternalLinkOK
+	}
+
+	return check, 0, nil
+}
+
+// wait blocks until the next request to the host is allowed.
+func (c *externalLinkChecker) wait(ctx context.Context, host string, rules *ds.RobotsRules) error {
+	interval := ExternalLinkHostInterval
+	if rules != nil {
+		interval = max(interval, min(rules.CrawlDelay, externalLinkMaxCrawlDelay))
+	}
+
+	defer func() {
+		c.nextRequest[host] = time.Now().Add(interval)
+	}()
+
+	d := time.Until(c.nextRequest[host])
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// request sends the request and returns the status of the response,
+// and the delay of Retry-After, if any.
+func (c *externalLinkChecker) request(ctx context.Context, method, rawURL string) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	req.Header.Set("User-Agent", ExternalLinkUserAgent)
+
+	resp, err := ExternalLinkHTTPClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, externalLinkBodyMaxLen))
+
+	return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+// robotsRules returns the rules of robots.txt of the site of the URL, loading it on first use.
+// A site that has no robots.txt, or fails to serve it, allows everything:
+// if the site is down, the check of the link tells that anyway.
+func (c *externalLinkChecker) robotsRules(ctx context.Context, u *url.URL) (*ds.RobotsRules, error) {
+	if rules, ok := c.robots[u.Host]; ok {
+		return rules, nil
+	}
+
+	robotsURL := u.Scheme + "://" + u.Host + "/robots.txt"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", ExternalLinkUserAgent)
+
+	var rules *ds.RobotsRules
+	resp, err := ExternalLinkHTTPClient.Do(req)
+	if err == nil {
+		defer resp.Body.Close() //nolint:errcheck
+
+		if resp.StatusCode == http.StatusOK {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, robotsMaxLen))
+			if err == nil {
+				rules = ds.ParseRobots(string(body), ExternalLinkRobotsAgent)
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	c.robots[u.Host] = rules
+	c.nextRequest[u.Host] = time.Now().Add(ExternalLinkHostInterval)
+
+	return rules, nil
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, given in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(sec) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
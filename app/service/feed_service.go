@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// FeedItemsLimit is how many of the latest items a syndication feed includes.
+const FeedItemsLimit = 50
+
+var (
+	// ErrTopicNotFound is returned when there is no topic with the requested public ID.
+	ErrTopicNotFound = app.ErrNotFound("topic not found")
+)
+
+// LatestPublishedBooks returns the public books in order they were approved, newest first.
+// If topic is not empty, only books of that topic are returned.
+func (s *Service) LatestPublishedBooks(ctx context.Context, topic string) ([]ds.Book, error) {
+	ctx, span := s.tracer.Start(ctx, "LatestPublishedBooks")
+	defer span.End()
+
+	f := ds.BooksFilter{
+		EntitiesFilter: ds.EntitiesFilter{
+			PerPage:        FeedItemsLimit,
+			Status:         []ds.EntityStatus{ds.EntityStatusApproved},
+			Visibility:     []ds.EntityVisibility{ds.EntityVisibilityPublic},
+			OrderBy:        "COALESCE(e.published_at, e.created_at)",
+			OrderDirection: "desc",
+		},
+	}
+	if topic != "" {
+		f.Topics = []string{topic}
+	}
+
+	books, _, err := s.db.FilterBooks(ctx, f)
+	return books, err
+}
+
+// LatestUpdatedPages returns the public pages in order they were last changed, newest first.
+func (s *Service) LatestUpdatedPages(ctx context.Context) ([]ds.Page, error) {
+	ctx, span := s.tracer.Start(ctx, "LatestUpdatedPages")
+	defer span.End()
+
+	entities, _, err := s.db.FilterEntities(ctx, ds.EntitiesFilter{
+		PerPage:        FeedItemsLimit,
+		Type:           ds.EntityTypePage,
+		Status:         []ds.EntityStatus{ds.EntityStatusApproved},
+		Visibility:     []ds.EntityVisibility{ds.EntityVisibilityPublic},
+		OrderBy:        "COALESCE(e.updated_at, e.created_at)",
+		OrderDirection: "desc",
+	})
+	if err != nil || len(entities) == 0 {
+		return nil, err
+	}
+
+	publicIDs := make([]string, len(entities))
+	for i, e := range entities {
+		publicIDs[i] = e.PublicID
+	}
+
+	found, err := s.db.GetPagesByPublicID(ctx, publicIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the order of the entities
+	byID := make(map[ds.ID]ds.Page, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	pages := make([]ds.Page, 0, len(found))
+	for _, e := range entities {
+		if p, ok := byID[e.ID]; ok {
+			pages = append(pages, p)
+		}
+	}
+
+	return pages, nil
+}
+
+// GetTopicByPublicID returns the topic of the given entity type by its public ID.
+func (s *Service) GetTopicByPublicID(ctx context.Context, t ds.EntityType, publicID string) (*ds.Topic, error) {
+	ctx, span := s.tracer.Start(ctx, "GetTopicByPublicID")
+	defer span.End()
+
+	topics, _, err := s.db.FilterTopics(ctx, ds.TopicsFilter{
+		PerPage:   1,
+		Type:      t,
+		PublicIDs: []string{publicID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return nil, ErrTopicNotFound
+	}
+
+	return &topics[0], nil
+}
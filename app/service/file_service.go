@@ -8,7 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -190,6 +190,11 @@ func handleFilePurpose(f *ds.File) error {
 			return app.InputError{"purpose": fmt.Sprintf("invalid file type for book cover, only %v types is accepted", file.ResizableImages)}
 		}
 		subDir = "book-covers"
+	case ds.FilePurposeUserAvatar:
+		if !file.IsResizableImage(f.Path) {
+			return app.InputError{"purpose": fmt.Sprintf("invalid file type for avatar, only %v types is accepted", file.ResizableImages)}
+		}
+		subDir = "user-avatars"
 	default:
 		return app.InputError{"purpose": "invalid purpose"}
 	}
@@ -308,12 +313,12 @@ func (s *Service) HardDeleteFileUnsafe(ctx context.Context, f *ds.File) error {
 	if errors.Is(err, repo.ErrFileNotFound) {
 		err = file.Delete(ctx, f.Path)
 		if err != nil {
-			log.Println("[ERROR] DELETE FILE: " + err.Error())
+			slog.ErrorContext(ctx, "delete file", "path", f.Path, "error", err)
 		}
 		if f.PreviewPath != "" {
 			err = file.Delete(ctx, f.PreviewPath)
 			if err != nil {
-				log.Println("[ERROR] DELETE PREVIEW FILE: " + err.Error())
+				slog.ErrorContext(ctx, "delete preview file", "path", f.PreviewPath, "error", err)
 			}
 		}
 	}
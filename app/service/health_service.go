@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/health"
+)
+
+// storageCheckTTL is how long the outcome of the storage check is reused.
+// The check writes to the storage, which is billed and too slow to do on every probe.
+const storageCheckTTL = time.Minute
+
+var storageCheck = sync.OnceValue(func() health.Check {
+	return health.Cached(health.Storage(file.Storage()), storageCheckTTL)
+})
+
+// CheckReadiness probes the dependencies the server needs to handle requests:
+// the database, its migration state, the file storage (checked once per storageCheckTTL)
+// and the email configuration.
+// The report fails as soon as the server starts shutting down.
+func (s *Service) CheckReadiness(ctx context.Context) health.Report {
+	ctx, span := s.tracer.Start(ctx, "CheckReadiness")
+	defer span.End()
+
+	return health.Run(ctx,
+		health.Shutdown(),
+		health.Check{Name: "db", Fn: s.db.Ping},
+		health.Migrations(s.db.PendingMigrations),
+		storageCheck(),
+		health.Check{Name: "email", Fn: func(context.Context) error {
+			return email.CheckConfig()
+		}},
+	)
+}
@@ -213,7 +213,7 @@ func (s *Service) ApplyChangesToPage(ctx context.Context, changes []ChangeDiff,
 	}
 
 	if sendNotification {
-		err = email.Send(author.Email, email.ChangesApproved{
+		err = email.Send(ctx, author.Email, email.ChangesApproved{
 			Username:    author.Username,
 			EntityTitle: page.Title,
 			ViewURL:     page.PublicID,
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/ratelimit"
+)
+
+// RateLimitAction identifies a throttled operation.
+type RateLimitAction string
+
+// Throttled operations.
+const (
+	RateLimitSignIn        RateLimitAction = "sign_in"
+	RateLimitSignUp        RateLimitAction = "sign_up"
+	RateLimitPasswordReset RateLimitAction = "password_reset"
+	RateLimitEmailChange   RateLimitAction = "email_change"
+	RateLimitDataExport    RateLimitAction = "data_export"
+	RateLimitBookMetadata  RateLimitAction = "book_metadata"
+	RateLimitBookReview    RateLimitAction = "book_review"
+)
+
+const (
+	defaultLockoutAttempts = 5
+	defaultLockoutMinutes  = 15
+)
+
+// ipRateLimitRules define how many requests of each action are allowed from a single IP.
+var ipRateLimitRules = map[RateLimitAction]ratelimit.Rule{
+	RateLimitSignIn:        {Limit: 20, Window: 15 * time.Minute},
+	RateLimitSignUp:        {Limit: 5, Window: time.Hour},
+	RateLimitPasswordReset: {Limit: 5, Window: time.Hour},
+	RateLimitEmailChange:   {Limit: 10, Window: time.Hour},
+}
+
+// accountRateLimitRules define how many requests of each action are allowed for a single account,
+// regardless of where they come from.
+var accountRateLimitRules = map[RateLimitAction]ratelimit.Rule{
+	RateLimitPasswordReset: {Limit: 3, Window: time.Hour},
+	RateLimitEmailChange:   {Limit: 3, Window: time.Hour},
+	RateLimitDataExport:    {Limit: 2, Window: 24 * time.Hour},
+	RateLimitBookMetadata:  {Limit: 60, Window: time.Hour},
+	RateLimitBookReview:    {Limit: 20, Window: time.Hour},
+}
+
+var (
+	// ErrTooManyRequests is returned when a rate limit is exceeded.
+	ErrTooManyRequests = app.ErrTooManyRequests("too many requests, try again later")
+
+	// ErrAccountLocked is returned when the account is temporarily locked after repeated failed sign-in attempts.
+	ErrAccountLocked = app.ErrTooManyRequests("too many failed sign-in attempts, account is temporarily locked")
+)
+
+// ThrottleIP records a request of the action from the given IP and returns
+// ErrTooManyRequests (with Retry-After) if the limit is exceeded.
+func (s *Service) ThrottleIP(ctx context.Context, action RateLimitAction, ip string) error {
+	ctx, span := s.tracer.Start(ctx, "ThrottleIP")
+	defer span.End()
+
+	rule, ok := ipRateLimitRules[action]
+	if !ok || ip == "" {
+		return nil
+	}
+
+	return s.throttle(ctx, rateLimitKey("ip", action, ip), rule)
+}
+
+// throttleAccount records a request of the action for the given account (user ID or email)
+// and returns ErrTooManyRequests (with Retry-After) if the limit is exceeded.
+func (s *Service) throttleAccount(ctx context.Context, action RateLimitAction, account string) error {
+	rule, ok := accountRateLimitRules[action]
+	if !ok {
+		return nil
+	}
+
+	return s.throttle(ctx, rateLimitKey("account", action, account), rule)
+}
+
+func (s *Service) throttle(ctx context.Context, key string, rule ratelimit.Rule) error {
+	retryAfter, err := s.limiter.Allow(ctx, key, rule)
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return withRetryAfter(ErrTooManyRequests, retryAfter)
+	}
+
+	return nil
+}
+
+// checkAccountLock returns ErrAccountLocked (with Retry-After) if there were too many
+// failed sign-in attempts for the email recently.
+func (s *Service) checkAccountLock(ctx context.Context, emailAddr string) error {
+	retryAfter, err := s.limiter.Check(ctx, lockoutKey(emailAddr), lockoutRule())
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return withRetryAfter(ErrAccountLocked, retryAfter)
+	}
+
+	return nil
+}
+
+// registerFailedSignIn records a failed sign-in attempt for the email.
+// When the attempt locks the account, the owner (if there is one) is notified by email.
+// Errors of the notification are only logged: the result must not differ
+// between existing and unknown accounts.
+func (s *Service) registerFailedSignIn(ctx context.Context, emailAddr string, user *ds.User) error {
+	rule := lockoutRule()
+
+	count, err := s.limiter.Hit(ctx, lockoutKey(emailAddr), rule.Window)
+	if err != nil {
+		return err
+	}
+
+	if count != rule.Limit || user == nil || user.Deleted() {
+		return nil
+	}
+
+	err = s.LogAccountLocked(ctx, user.ID, count)
+	if err != nil {
+		slog.ErrorContext(ctx, "log account locked", "user_id", user.ID, "error", err)
+	}
+
+	err = email.Send(ctx, user.Email, email.AccountLocked{
+		Username: user.Username,
+		Attempts: count,
+		Minutes:  int(rule.Window.Minutes()),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "send account locked email", "user_id", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// resetFailedSignIns forgets failed sign-in attempts for the email.
+func (s *Service) resetFailedSignIns(ctx context.Context, emailAddr string) error {
+	return s.limiter.Reset(ctx, lockoutKey(emailAddr))
+}
+
+// CleanupRateLimitHits removes rate limit hits that are too old to affect any rule.
+func (s *Service) CleanupRateLimitHits(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "CleanupRateLimitHits")
+	defer span.End()
+
+	return s.db.DeleteRateLimitHitsBefore(ctx, time.Now().Add(-ratelimit.MaxWindow))
+}
+
+func lockoutRule() ratelimit.Rule {
+	conf := app.Config().RateLimit
+
+	rule := ratelimit.Rule{
+		Limit:  conf.LockoutAttempts,
+		Window: time.Duration(conf.LockoutMinutes) * time.Minute,
+	}
+	if rule.Limit <= 0 {
+		rule.Limit = defaultLockoutAttempts
+	}
+	if rule.Window <= 0 {
+		rule.Window = defaultLockoutMinutes * time.Minute
+	}
+
+	return rule
+}
+
+func lockoutKey(emailAddr string) string {
+	return rateLimitKey("lockout", RateLimitSignIn, emailAddr)
+}
+
+func rateLimitKey(scope string, action RateLimitAction, id string) string {
+	return scope + ":" + string(action) + ":" + strings.ToLower(id)
+}
+
+// withRetryAfter returns a copy of the app.Error with RetryAfter set.
+func withRetryAfter(err error, retryAfter time.Duration) error {
+	appErr, ok := err.(app.Error) //nolint:errorlint
+	if !ok {
+		return err
+	}
+
+	appErr.RetryAfter = retryAfter
+	return appErr
+}
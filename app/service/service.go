@@ -8,6 +8,8 @@ import (
 	z "github.com/Oudwins/zog"
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/repo"
+	"github.com/gopl-dev/server/bookmeta"
+	"github.com/gopl-dev/server/ratelimit"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -31,16 +33,33 @@ var (
 
 // Service holds dependencies required for the application's business logic layer.
 type Service struct {
-	db     *repo.Repo
-	tracer trace.Tracer
+	db       *repo.Repo
+	tracer   trace.Tracer
+	limiter  *ratelimit.Limiter
+	bookMeta bookmeta.Provider
 }
 
 // New is a factory function that creates and returns a new Service instance.
-func New(db *app.DB, t trace.Tracer) *Service {
-	return &Service{
-		db:     repo.New(db, t),
-		tracer: t,
+// It returns an error if the rate limiter or the book metadata provider can't be created from config.
+func New(db *app.DB, t trace.Tracer) (*Service, error) {
+	r := repo.New(db, t)
+
+	limiter, err := ratelimit.FromConfig(r)
+	if err != nil {
+		return nil, fmt.Errorf("init rate limiter: %w", err)
+	}
+
+	bookMeta, err := bookmeta.FromConfig()
+	if err != nil {
+		return nil, fmt.Errorf("init book metadata provider: %w", err)
 	}
+
+	return &Service{
+		db:       r,
+		tracer:   t,
+		limiter:  limiter,
+		bookMeta: bookMeta,
+	}, nil
 }
 
 // Validatable indicates that the struct can be validated.
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/sitemap"
+)
+
+const (
+	// SitemapDir is the file storage directory the sitemap files are written to.
+	SitemapDir = "sitemap"
+
+	// SitemapIndexFile is the entry point of the sitemap: either the single sitemap
+	// or the sitemap index, when the URLs don't fit into one file.
+	SitemapIndexFile = "sitemap.xml"
+
+	sitemapBatchSize = 500
+)
+
+// SitemapURLsPerFile is the maximum number of URLs written into a single sitemap file.
+var SitemapURLsPerFile = sitemap.MaxURLs
+
+// sitemapStaticPaths lists public routes that are not backed by an entity.
+var sitemapStaticPaths = []string{
+	"/",
+	"/books/",
+	"/activity-log/",
+}
+
+// GenerateSitemap writes the sitemap of the public content into the file storage.
+//
+// If all URLs fit into a single file, SitemapIndexFile is the sitemap itself.
+// Otherwise, URLs are split into numbered sitemaps referenced from the sitemap index.
+// Entities are listed in order of creation, so new content is appended to the last file,
+// and files which content didn't change are not rewritten.
+func (s *Service) GenerateSitemap(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "GenerateSitemap")
+	defer span.End()
+
+	urls, err := s.sitemapURLs(ctx)
+	if err != nil {
+		return err
+	}
+	ds.AddProcessedItems(ctx, len(urls))
+
+	chunks := sitemap.Split(urls, SitemapURLsPerFile)
+	if len(chunks) == 1 {
+		body, err := sitemap.Encode(chunks[0])
+		if err != nil {
+			return err
+		}
+
+		err = storeSitemapFile(ctx, SitemapIndexFile, body)
+		if err != nil {
+			return err
+		}
+
+		return deleteSitemapFiles(ctx, 1)
+	}
+
+	index := make([]sitemap.Sitemap, len(chunks))
+	for i, chunk := range chunks {
+		name := SitemapFileName(i + 1)
+
+		body, err := sitemap.Encode(chunk)
+		if err != nil {
+			return err
+		}
+
+		err = storeSitemapFile(ctx, name, body)
+		if err != nil {
+			return err
+		}
+
+		index[i] = sitemap.Sitemap{
+			Loc:     app.ServerURL("/sitemaps/" + name),
+			LastMod: sitemap.LastMod(chunk),
+		}
+	}
+
+	body, err := sitemap.EncodeIndex(index)
+	if err != nil {
+		return err
+	}
+
+	err = storeSitemapFile(ctx, SitemapIndexFile, body)
+	if err != nil {
+		return err
+	}
+
+	return deleteSitemapFiles(ctx, len(chunks)+1)
+}
+
+// ErrSitemapNotFound is returned when the requested sitemap file is not generated (yet).
+var ErrSitemapNotFound = app.ErrNotFound("sitemap not found")
+
+// OpenSitemapFile opens the sitemap file previously written by GenerateSitemap.
+func (s *Service) OpenSitemapFile(ctx context.Context, name string) (file.ReadSeekCloser, int64, error) {
+	fh, size, err := file.Open(ctx, path.Join(SitemapDir, path.Base(name)))
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, file.ErrFileNotFound) {
+		return nil, 0, ErrSitemapNotFound
+	}
+
+	return fh, size, err
+}
+
+// SitemapFileName returns the name of the n-th (1-based) sitemap referenced from the sitemap index.
+func SitemapFileName(n int) string {
+	return "sitemap-" + strconv.Itoa(n) + ".xml"
+}
+
+func (s *Service) sitemapURLs(ctx context.Context) (urls []sitemap.URL, err error) {
+	for _, p := range sitemapStaticPaths {
+		urls = append(urls, sitemap.URL{Loc: sitemapLoc(p)})
+	}
+
+	// topic listings
+	for page := 1; ; page++ {
+		topics, _, err := s.db.FilterTopics(ctx, ds.TopicsFilter{
+			Page:           page,
+			PerPage:        sitemapBatchSize,
+			Type:           ds.EntityTypeBook,
+			OrderBy:        "created_at",
+			OrderDirection: "asc",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sitemap topics: %w", err)
+		}
+
+		for _, t := range topics {
+			urls = append(urls, sitemap.URL{
+				Loc:     sitemapLoc("/books/") + "?topics=" + t.PublicID,
+				LastMod: app.Value(t.UpdatedAt),
+			})
+		}
+
+		if len(topics) < sitemapBatchSize {
+			break
+		}
+	}
+
+	// entities
+	for page := 1; ; page++ {
+		entities, _, err := s.db.FilterEntities(ctx, ds.EntitiesFilter{
+			Page:           page,
+			PerPage:        sitemapBatchSize,
+			Status:         []ds.EntityStatus{ds.EntityStatusApproved},
+			Visibility:     []ds.EntityVisibility{ds.EntityVisibilityPublic},
+			OrderBy:        "created_at",
+			OrderDirection: "asc",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sitemap entities: %w", err)
+		}
+
+		for _, e := range entities {
+			urls = append(urls, sitemap.URL{
+				Loc:     sitemapLoc(e.ViewURL() + "/"),
+				LastMod: entityLastMod(e),
+			})
+		}
+
+		if len(entities) < sitemapBatchSize {
+			break
+		}
+	}
+
+	return urls, nil
+}
+
+func entityLastMod(e ds.Entity) time.Time {
+	if e.UpdatedAt != nil {
+		return *e.UpdatedAt
+	}
+
+	if e.PublishedAt != nil {
+		return *e.PublishedAt
+	}
+
+	return e.CreatedAt
+}
+
+// sitemapLoc returns the absolute URL of the path, keeping its trailing slash.
+func sitemapLoc(p string) string {
+	loc := app.ServerURL(p)
+	if p != "/" && p[len(p)-1] == '/' {
+		loc += "/"
+	}
+
+	return loc
+}
+
+// storeSitemapFile writes the file unless it already has the same content.
+func storeSitemapFile(ctx context.Context, name string, body []byte) error {
+	key := path.Join(SitemapDir, name)
+
+	current, err := file.Load(ctx, key)
+	if err == nil && bytes.Equal(current, body) {
+		return nil
+	}
+
+	_, err = file.Store(ctx, bytes.NewReader(body), key)
+	if err != nil {
+		return fmt.Errorf("store %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// deleteSitemapFiles deletes numbered sitemaps starting from n,
+// left over from a previous run that produced more of them.
+func deleteSitemapFiles(ctx context.Context, n int) error {
+	for ; ; n++ {
+		key := path.Join(SitemapDir, SitemapFileName(n))
+
+		_, err := file.Load(ctx, key)
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		err = file.Delete(ctx, key)
+		if err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/file"
+)
+
+const socialPreviewsDir = "social-previews"
+
+// GetBookSocialPreview returns the image shown when a link to the book is shared,
+// along with its version. The image is composed from the cover and the title on first request.
+//
+// The version is derived from the content of the image, so after the book is changed,
+// a new image is generated under a new key, and the image of the previous version is deleted.
+func (s *Service) GetBookSocialPreview(ctx context.Context, book *ds.Book) (fh file.ReadSeekCloser, size int64, version string, err error) {
+	ctx, span := s.tracer.Start(ctx, "GetBookSocialPreview")
+	defer span.End()
+
+	authors := make([]string, len(book.Authors))
+	for i, a := range book.Authors {
+		authors[i] = a.Name
+	}
+
+	p := file.SocialPreview{
+		Title:    book.Title,
+		Subtitle: strings.Join(authors, ", "),
+		Caption:  "gopl.dev",
+	}
+
+	if !book.CoverFileID.IsNil() {
+		cover, err := s.db.GetFileByID(ctx, book.CoverFileID)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		if file.IsResizableImage(cover.Path) {
+			p.Cover = cover.Path
+		}
+	}
+
+	sum := sha256.Sum256([]byte(p.Cover + "\n" + p.Title + "\n" + p.Subtitle))
+	version = hex.EncodeToString(sum[:8])
+	key := path.Join(socialPreviewsDir, "books", book.ID.String()+"-"+version+".jpg")
+
+	fh, size, err = file.Open(ctx, key)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, file.ErrFileNotFound) {
+		err = file.CreateSocialPreview(ctx, p, key)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		err = s.deletePreviousSocialPreview(ctx, book.ID, key)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		fh, size, err = file.Open(ctx, key)
+	}
+
+	return fh, size, version, err
+}
+
+// deletePreviousSocialPreview records key as the current social preview of the entity
+// and deletes the image of the previous version.
+// If the image can't be deleted, the error is only logged.
+func (s *Service) deletePreviousSocialPreview(ctx context.Context, entityID ds.ID, key string) error {
+	previous, err := s.db.SwapSocialPreviewPath(ctx, entityID, key)
+	if err != nil || previous == "" || previous == key {
+		return err
+	}
+
+	err = file.Delete(ctx, previous)
+	if err != nil {
+		slog.ErrorContext(ctx, "delete social preview", "path", previous, "error", err)
+	}
+
+	return nil
+}
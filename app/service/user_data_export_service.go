@@ -0,0 +1,527 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	z "github.com/Oudwins/zog"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/repo"
+	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/file"
+)
+
+const (
+	userDataExportTokenLength = 32
+
+	// userDataExportsDir is the storage directory of the export archives.
+	userDataExportsDir = "user-data-exports"
+)
+
+var getUserDataExportInputRules = z.Shape{
+	"UserID": ds.IDInputRules,
+	"Token":  z.String().Required(z.Message("Token is required")),
+}
+
+var (
+	// ErrUserDataExportInProgress is returned when the user requests an export
+	// while the previous one is not built yet.
+	ErrUserDataExportInProgress = app.ErrUnprocessable("data export is already in progress")
+
+	// ErrUserDataExportNotFound is returned when the export does not exist, belongs to another user,
+	// is not built yet or has expired.
+	ErrUserDataExportNotFound = app.ErrNotFound("data export not found or expired")
+)
+
+// RequestUserDataExport queues an export of the user's personal data.
+// The archive is built by the worker and the download link is sent by email.
+func (s *Service) RequestUserDataExport(ctx context.Context, userID ds.ID) (exp *ds.UserDataExport, err error) {
+	ctx, span := s.tracer.Start(ctx, "RequestUserDataExport")
+	defer span.End()
+
+	pending, _, err := s.db.FilterUserDataExports(ctx, ds.UserDataExportsFilter{
+		PerPage: 1,
+		UserID:  &userID,
+		Status:  ds.UserDataExportPending,
+	})
+	if err != nil {
+		return
+	}
+	if len(pending) > 0 {
+		err = ErrUserDataExportInProgress
+		return
+	}
+
+	err = s.throttleAccount(ctx, RateLimitDataExport, userID.String())
+	if err != nil {
+		return
+	}
+
+	token, err := app.Token(userDataExportTokenLength)
+	if err != nil {
+		return
+	}
+
+	exp = &ds.UserDataExport{
+		UserID: userID,
+		Status: ds.UserDataExportPending,
+		Token:  token,
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.db.CreateUserDataExport(ctx, exp)
+		if err != nil {
+			return err
+		}
+
+		return s.LogUserDataExportRequested(ctx, userID)
+	})
+
+	return
+}
+
+// ProcessPendingUserDataExports builds all pending exports, oldest first.
+// A failed export is marked as such and does not stop the others.
+func (s *Service) ProcessPendingUserDataExports(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "ProcessPendingUserDataExports")
+	defer span.End()
+
+	exports, _, err := s.db.FilterUserDataExports(ctx, ds.UserDataExportsFilter{
+		PerPage: ds.PerPageNoLimit,
+		Status:  ds.UserDataExportPending,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, exp := range exports {
+		err = s.BuildUserDataExport(ctx, &exp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("export %s: %w", exp.ID, err))
+			continue
+		}
+
+		ds.AddProcessedItems(ctx, 1)
+	}
+
+	return errors.Join(errs...)
+}
+
+// BuildUserDataExport writes the archive of the user's data to the storage,
+// marks the export as ready and emails the download link to the user.
+// If the archive can't be built, the export is marked as failed.
+func (s *Service) BuildUserDataExport(ctx context.Context, exp *ds.UserDataExport) (err error) {
+	ctx, span := s.tracer.Start(ctx, "BuildUserDataExport")
+	defer span.End()
+
+	user, err := s.db.GetUserByID(ctx, exp.UserID)
+	if err != nil {
+		return
+	}
+
+	exp.Path, exp.Size, err = s.storeUserDataArchive(ctx, exp)
+	exp.CompletedAt = new(time.Now())
+	exp.ExpiresAt = new(exp.CompletedAt.Add(ds.UserDataExportTTL))
+	exp.Status = ds.UserDataExportReady
+
+	if err != nil {
+		exp.Status = ds.UserDataExportFailed
+		exp.Error = err.Error()
+
+		return errors.Join(err, s.db.UpdateUserDataExport(ctx, exp))
+	}
+
+	err = s.db.UpdateUserDataExport(ctx, exp)
+	if err != nil {
+		return
+	}
+
+	return email.Send(ctx, user.Email, email.UserDataExportReady{
+		Username:  user.Username,
+		Token:     exp.Token,
+		ExpiresAt: *exp.ExpiresAt,
+	})
+}
+
+// storeUserDataArchive writes the archive to a temporary file first,
+// so large uploads of the user are not held in memory, and then moves it to the storage.
+func (s *Service) storeUserDataArchive(ctx context.Context, exp *ds.UserDataExport) (key string, size int64, err error) {
+	tmp, err := os.CreateTemp("", "user-data-export-*.zip")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	err = s.WriteUserDataArchive(ctx, exp.UserID, tmp)
+	if err != nil {
+		return
+	}
+
+	size, err = tmp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return
+	}
+
+	key, err = file.Store(ctx, tmp, path.Join(userDataExportsDir, exp.ID.String()+".zip"))
+	return
+}
+
+// WriteUserDataArchive writes a ZIP archive with everything tied to the user to w:
+// account data, sessions, linked OAuth accounts, uploaded files (metadata and originals),
+// owned entities, change requests and event logs.
+// Originals that can't be opened are skipped and marked with an error in files.json.
+func (s *Service) WriteUserDataArchive(ctx context.Context, userID ds.ID, w io.Writer) (err error) {
+	ctx, span := s.tracer.Start(ctx, "WriteUserDataArchive")
+	defer span.End()
+
+	user, err := s.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	sessions, err := s.db.GetUserSessionsByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	oauthAccounts, err := s.db.GetOAuthUserAccountsByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	files, _, err := s.db.FilterFiles(ctx, ds.FilesFilter{
+		PerPage:        ds.PerPageNoLimit,
+		OwnerID:        &userID,
+		Committed:      true,
+		OrderBy:        "created_at",
+		OrderDirection: "asc",
+	})
+	if err != nil {
+		return
+	}
+
+	entities, _, err := s.db.FilterEntities(ctx, ds.EntitiesFilter{
+		PerPage:        ds.PerPageNoLimit,
+		OwnerID:        &userID,
+		OrderBy:        "created_at",
+		OrderDirection: "asc",
+	})
+	if err != nil {
+		return
+	}
+
+	changeRequests, err := s.db.GetChangeRequestsByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	eventLogs, _, err := s.db.FilterEventLogs(ctx, ds.EventLogsFilter{
+		PerPage: ds.PerPageNoLimit,
+		UserID:  &userID,
+	})
+	if err != nil {
+		return
+	}
+
+	zw := zip.NewWriter(w)
+
+	archive := []struct {
+		name string
+		data any
+	}{
+		{"account.json", newUserDataExportAccount(user)},
+		{"sessions.json", newUserDataExportSessions(sessions)},
+		{"oauth_accounts.json", oauthAccounts},
+		{"entities.json", newUserDataExportEntities(entities)},
+		{"change_requests.json", changeRequests},
+		{"event_logs.json", eventLogs},
+	}
+
+	for _, a := range archive {
+		err = writeZipJSON(zw, a.name, a.data)
+		if err != nil {
+			return
+		}
+	}
+
+	exportFiles := newUserDataExportFiles(files)
+	for i, f := range files {
+		src, _, openErr := file.Open(ctx, f.Path)
+		if openErr != nil {
+			slog.WarnContext(ctx, "open user data export file", "file_id", f.ID, "path", f.Path, "error", openErr)
+			exportFiles[i].ArchivePath = ""
+			exportFiles[i].Error = userDataExportFileUnavailable
+			continue
+		}
+
+		err = writeZipFile(zw, exportFiles[i].ArchivePath, src)
+		_ = src.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	err = writeZipJSON(zw, "files.json", exportFiles)
+	if err != nil {
+		return
+	}
+
+	return zw.Close()
+}
+
+// GetUserDataExportByToken returns the ready export of the user by its download token.
+func (s *Service) GetUserDataExportByToken(ctx context.Context, userID ds.ID, token string) (
+	exp *ds.UserDataExport, err error) {
+	ctx, span := s.tracer.Start(ctx, "GetUserDataExportByToken")
+	defer span.End()
+
+	in := &GetUserDataExportInput{UserID: userID, Token: token}
+	err = Normalize(in)
+	if err != nil {
+		return
+	}
+
+	exp, err = s.db.GetUserDataExportByToken(ctx, in.Token)
+	if errors.Is(err, repo.ErrUserDataExportNotFound) {
+		return nil, ErrUserDataExportNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	if exp.UserID != in.UserID || !exp.Downloadable() {
+		return nil, ErrUserDataExportNotFound
+	}
+
+	return
+}
+
+// OpenUserDataExport opens the archive of the export for reading.
+func (s *Service) OpenUserDataExport(ctx context.Context, exp *ds.UserDataExport) (file.ReadSeekCloser, int64, error) {
+	ctx, span := s.tracer.Start(ctx, "OpenUserDataExport")
+	defer span.End()
+
+	return file.Open(ctx, exp.Path)
+}
+
+// CleanupExpiredUserDataExports deletes expired exports along with their archives.
+func (s *Service) CleanupExpiredUserDataExports(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "CleanupExpiredUserDataExports")
+	defer span.End()
+
+	exports, _, err := s.db.FilterUserDataExports(ctx, ds.UserDataExportsFilter{
+		PerPage:   ds.PerPageNoLimit,
+		ExpiresAt: ds.DtBefore(time.Now()),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, exp := range exports {
+		err = s.deleteUserDataExport(ctx, &exp)
+		if err != nil {
+			return err
+		}
+
+		ds.AddProcessedItems(ctx, 1)
+	}
+
+	return nil
+}
+
+// deleteUserDataExports deletes all exports of the user along with their archives.
+func (s *Service) deleteUserDataExports(ctx context.Context, userID ds.ID) error {
+	exports, _, err := s.db.FilterUserDataExports(ctx, ds.UserDataExportsFilter{
+		PerPage: ds.PerPageNoLimit,
+		UserID:  &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, exp := range exports {
+		err = s.deleteUserDataExport(ctx, &exp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteUserDataExport(ctx context.Context, exp *ds.UserDataExport) error {
+	if exp.Path != "" {
+		err := file.Delete(ctx, exp.Path)
+		if err != nil {
+			slog.ErrorContext(ctx, "delete user data export file", "path", exp.Path, "error", err)
+		}
+	}
+
+	return s.db.DeleteUserDataExport(ctx, exp.ID)
+}
+
+// GetUserDataExportInput defines the input for getting a user data export by its token.
+type GetUserDataExportInput struct {
+	UserID ds.ID
+	Token  string
+}
+
+// Sanitize trims whitespace from the token.
+func (in *GetUserDataExportInput) Sanitize() {
+	in.Token = strings.TrimSpace(in.Token)
+}
+
+// Validate validates the get user data export input against defined rules.
+func (in *GetUserDataExportInput) Validate() error {
+	return validateInput(getUserDataExportInputRules, in)
+}
+
+// userDataExportAccount is the account data of the export.
+// Unlike ds.User, it includes all stored fields except the password hash.
+type userDataExportAccount struct {
+	ID                 ds.ID         `json:"id"`
+	Username           string        `json:"username"`
+	Email              string        `json:"email"`
+	EmailConfirmed     bool          `json:"email_confirmed"`
+	Bio                string        `json:"bio"`
+	AvatarFileID       ds.ID         `json:"avatar_file_id,omitzero"`
+	Links              []ds.UserLink `json:"links"`
+	ShowBooks          bool          `json:"show_books"`
+	ShowPages          bool          `json:"show_pages"`
+	ShowChangeRequests bool          `json:"show_change_requests"`
+	ShowActivity       bool          `json:"show_activity"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          *time.Time    `json:"updated_at"`
+}
+
+func newUserDataExportAccount(u *ds.User) userDataExportAccount {
+	return userDataExportAccount{
+		ID:                 u.ID,
+		Username:           u.Username,
+		Email:              u.Email,
+		EmailConfirmed:     u.EmailConfirmed,
+		Bio:                u.Bio,
+		AvatarFileID:       u.AvatarFileID,
+		Links:              u.Links,
+		ShowBooks:          u.ShowBooks,
+		ShowPages:          u.ShowPages,
+		ShowChangeRequests: u.ShowChangeRequests,
+		ShowActivity:       u.ShowActivity,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+}
+
+type userDataExportSession struct {
+	ID        ds.ID      `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+func newUserDataExportSessions(sessions []ds.UserSession) []userDataExportSession {
+	out := make([]userDataExportSession, len(sessions))
+	for i, sess := range sessions {
+		out[i] = userDataExportSession{
+			ID:        sess.ID,
+			CreatedAt: sess.CreatedAt,
+			UpdatedAt: sess.UpdatedAt,
+			ExpiresAt: sess.ExpiresAt,
+		}
+	}
+
+	return out
+}
+
+// userDataExportFileUnavailable is the error of the file whose original can't be opened.
+const userDataExportFileUnavailable = "the original file is not available"
+
+// userDataExportFile is the file metadata of the export, with the name of the original in the archive,
+// or the error if the original is not included.
+type userDataExportFile struct {
+	ds.File
+
+	ArchivePath string `json:"archive_path,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func newUserDataExportFiles(files []ds.File) []userDataExportFile {
+	out := make([]userDataExportFile, len(files))
+	for i, f := range files {
+		out[i] = userDataExportFile{
+			File:        f,
+			ArchivePath: userDataExportFileName(f),
+		}
+	}
+
+	return out
+}
+
+func userDataExportFileName(f ds.File) string {
+	return path.Join("files", f.ID.String()+"-"+filepath.Base(f.Name))
+}
+
+// userDataExportEntity is the entity of the export, with the fields ds.Entity keeps internal.
+type userDataExportEntity struct {
+	ds.Entity
+
+	Type       ds.EntityType `json:"type"`
+	SummaryRaw string        `json:"summary_raw"`
+	DeletedAt  *time.Time    `json:"deleted_at,omitempty"`
+}
+
+func newUserDataExportEntities(entities []ds.Entity) []userDataExportEntity {
+	out := make([]userDataExportEntity, len(entities))
+	for i, e := range entities {
+		out[i] = userDataExportEntity{
+			Entity:     e,
+			Type:       e.Type,
+			SummaryRaw: e.SummaryRaw,
+			DeletedAt:  e.DeletedAt,
+		}
+	}
+
+	return out
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func writeZipFile(zw *zip.Writer, name string, src io.Reader) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, src)
+	return err
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	z "github.com/Oudwins/zog"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/repo"
+)
+
+const (
+	// UserBioMaxLen is the maximum length of the bio shown on the user profile.
+	UserBioMaxLen = 500
+
+	// UserLinksMax is the maximum number of links shown on the user profile.
+	UserLinksMax = 5
+
+	// UserLinkTitleMaxLen is the maximum length of a profile link title.
+	UserLinkTitleMaxLen = 50
+
+	// userProfileListLimit is how many books, pages and change requests are shown on the profile.
+	userProfileListLimit = 10
+
+	// userProfileActivityLimit is how many event logs are shown on the profile.
+	userProfileActivityLimit = 25
+)
+
+var httpURLRegex = regexp.MustCompile(`^https?://`)
+
+var updateUserProfileInputRules = z.Shape{
+	"UserID": ds.IDInputRules,
+	"Bio": z.String().
+		Max(UserBioMaxLen, z.Message("Bio must be at most 500 characters")),
+	"Links": z.Slice(z.Struct(z.Shape{
+		"Title": z.String().Required(z.Message("Title is required")).
+			Max(UserLinkTitleMaxLen, z.Message("Title must be at most 50 characters")),
+		"URL": z.String().Required(z.Message("URL is required")).
+			URL(z.Message("Invalid URL")).
+			Match(httpURLRegex, z.Message("URL must start with http:// or https://")),
+	})).Max(UserLinksMax, z.Message("At most 5 links are allowed")),
+}
+
+var (
+	// ErrUserProfileNotFound is returned when there is no user with the requested username.
+	ErrUserProfileNotFound = app.ErrNotFound("user not found")
+
+	// ErrAvatarIsNotAUserAvatar is returned when the file was not uploaded as a user avatar.
+	ErrAvatarIsNotAUserAvatar = app.ErrUnprocessable("avatar: not a user avatar")
+
+	// ErrAvatarBelongsToAnotherUser is returned when the avatar file was uploaded by another user.
+	ErrAvatarBelongsToAnotherUser = app.ErrUnprocessable("avatar: not owner")
+)
+
+// GetUserProfile returns the public profile of the user with the given username,
+// along with the sections the user has chosen to show.
+//
+// Deleted users get an empty profile that should be rendered as a tombstone.
+func (s *Service) GetUserProfile(ctx context.Context, username string) (p *ds.UserProfile, err error) {
+	ctx, span := s.tracer.Start(ctx, "GetUserProfile")
+	defer span.End()
+
+	p = new(ds.UserProfile)
+	if username == ds.DeletedUsername {
+		return p, nil
+	}
+
+	p.User, err = s.db.GetUserByUsername(ctx, username)
+	if errors.Is(err, repo.ErrUserNotFound) {
+		return nil, ErrUserProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Deleted() {
+		return p, nil
+	}
+
+	userID := p.User.ID
+
+	if p.User.ShowBooks {
+		p.Books, err = s.userProfileEntities(ctx, userID, ds.EntityTypeBook)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.User.ShowPages {
+		p.Pages, err = s.userProfileEntities(ctx, userID, ds.EntityTypePage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.User.ShowChangeRequests {
+		p.ChangeRequests, _, err = s.FilterChangeRequests(ctx, ds.ChangeRequestsFilter{
+			PerPage:          userProfileListLimit,
+			Status:           ds.EntityChangeCommitted,
+			UserID:           &userID,
+			EntityStatus:     []ds.EntityStatus{ds.EntityStatusApproved},
+			EntityVisibility: []ds.EntityVisibility{ds.EntityVisibilityPublic},
+			OrderDirection:   "desc",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.User.ShowActivity {
+		p.EventLogs, _, err = s.FilterEventLogs(ctx, ds.EventLogsFilter{
+			PerPage:    userProfileActivityLimit,
+			OnlyPublic: true,
+			UserID:     &userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// userProfileEntities returns the latest public entities of the given type created by the user.
+func (s *Service) userProfileEntities(ctx context.Context, userID ds.ID, t ds.EntityType) ([]ds.Entity, error) {
+	entities, _, err := s.FilterEntities(ctx, ds.EntitiesFilter{
+		PerPage:        userProfileListLimit,
+		OwnerID:        &userID,
+		Type:           t,
+		Status:         []ds.EntityStatus{ds.EntityStatusApproved},
+		Visibility:     []ds.EntityVisibility{ds.EntityVisibilityPublic},
+		OrderBy:        "created_at",
+		OrderDirection: "desc",
+	})
+
+	return entities, err
+}
+
+// UpdateUserProfile updates the public profile of the user.
+// A new avatar is committed and the previous one is deleted.
+func (s *Service) UpdateUserProfile(ctx context.Context, in UpdateUserProfileInput) (err error) {
+	ctx, span := s.tracer.Start(ctx, "UpdateUserProfile")
+	defer span.End()
+
+	err = Normalize(&in)
+	if err != nil {
+		return
+	}
+
+	user, err := s.db.GetUserByID(ctx, in.UserID)
+	if err != nil {
+		return
+	}
+
+	oldAvatarID := user.AvatarFileID
+	avatarChanged := in.AvatarFileID != oldAvatarID
+
+	if avatarChanged && !in.AvatarFileID.IsNil() {
+		err = s.checkUserAvatar(ctx, user.ID, in.AvatarFileID)
+		if err != nil {
+			return
+		}
+	}
+
+	user.Bio = in.Bio
+	user.AvatarFileID = in.AvatarFileID
+	user.Links = in.Links
+	user.ShowBooks = in.ShowBooks
+	user.ShowPages = in.ShowPages
+	user.ShowChangeRequests = in.ShowChangeRequests
+	user.ShowActivity = in.ShowActivity
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.db.UpdateUserProfile(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		if avatarChanged {
+			if !user.AvatarFileID.IsNil() {
+				err = s.db.CommitFile(ctx, user.AvatarFileID)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !oldAvatarID.IsNil() {
+				err = s.db.DeleteFile(ctx, oldAvatarID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.LogUserProfileUpdated(ctx, user.ID)
+	})
+}
+
+// checkUserAvatar ensures the file exists, belongs to the user and was uploaded as an avatar.
+func (s *Service) checkUserAvatar(ctx context.Context, userID, fileID ds.ID) error {
+	avatar, err := s.db.GetFileByID(ctx, fileID)
+	if errors.Is(err, repo.ErrFileNotFound) {
+		return app.InputError{"avatar_file_id": "File not found"}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !avatar.IsOwner(userID) {
+		return ErrAvatarBelongsToAnotherUser
+	}
+
+	if !avatar.IsUserAvatar() {
+		return ErrAvatarIsNotAUserAvatar
+	}
+
+	return nil
+}
+
+// UpdateUserProfileInput defines the input for updating a user's public profile.
+type UpdateUserProfileInput struct {
+	UserID             ds.ID
+	Bio                string
+	AvatarFileID       ds.ID
+	Links              []ds.UserLink
+	ShowBooks          bool
+	ShowPages          bool
+	ShowChangeRequests bool
+	ShowActivity       bool
+}
+
+// Sanitize trims whitespace and drops empty links.
+func (in *UpdateUserProfileInput) Sanitize() {
+	in.Bio = strings.TrimSpace(in.Bio)
+
+	links := make([]ds.UserLink, 0, len(in.Links))
+	for _, l := range in.Links {
+		l.Title = strings.TrimSpace(l.Title)
+		l.URL = strings.TrimSpace(l.URL)
+		if l.Title == "" && l.URL == "" {
+			continue
+		}
+
+		links = append(links, l)
+	}
+	in.Links = links
+}
+
+// Validate validates the update user profile input against defined rules.
+func (in *UpdateUserProfileInput) Validate() error {
+	return validateInput(updateUserProfileInputRules, in)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/gopl-dev/server/app/repo"
 	"github.com/gopl-dev/server/app/session"
 	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/metrics"
 	"github.com/gopl-dev/server/oauth/provider"
 	"github.com/gopl-dev/server/test/factory/random"
 	"github.com/markbates/goth"
@@ -77,6 +79,21 @@ var getOAuthUserAccountInputRules = z.Shape{
 	"ProviderUserID": z.String().Required(z.Message("provider_user_id is required")),
 }
 
+var getUserOAuthAccountsInputRules = z.Shape{
+	"UserID": ds.IDInputRules,
+}
+
+var linkOAuthAccountInputRules = z.Shape{
+	"UserID":         ds.IDInputRules,
+	"Provider":       provider.TypeInputRules,
+	"ProviderUserID": z.String().Required(z.Message("provider_user_id is required")),
+}
+
+var unlinkOAuthAccountInputRules = z.Shape{
+	"UserID":   ds.IDInputRules,
+	"Provider": provider.KnownTypeInputRules,
+}
+
 var hardDeleteUserInputRules = z.Shape{
 	"UserID": ds.IDInputRules,
 }
@@ -96,7 +113,10 @@ var usernameInputRules = z.String().Required().
 	Match(UsernameBasicRegex,
 		z.Message("Username can only contain letters, numbers, dots, underscores, and dashes")).
 	Match(UsernameSpecialCharsRegex,
-		z.Message("Username cannot contain more than two dots, underscores, or dashes"))
+		z.Message("Username cannot contain more than two dots, underscores, or dashes")).
+	TestFunc(func(val *string, _ z.Ctx) bool {
+		return !IsReservedUsername(*val)
+	}, z.Message("Username is reserved"))
 
 var registerUserInputRules = z.Shape{
 	"Username": usernameInputRules,
@@ -115,8 +135,22 @@ var (
 
 	// UsernameSpecialCharsRegex enforces a limit on the maximum number of special characters (dot, underscore, dash).
 	UsernameSpecialCharsRegex = regexp.MustCompile(`^[^._-]*([._-][^._-]*){0,2}$`)
+
+	// ReservedUsernames can't be taken by users, as the profile URLs (/users/{username}/)
+	// would collide with other routes under /users/, now or in the future.
+	ReservedUsernames = []string{
+		"sign-up", "sign-in", "sign-out", "confirm-email", "email-confirmation-code",
+		"password-reset", "password-reset-request", "password", "email", "username",
+		"profile", "settings", "data-export", "oauth-accounts", "connected-accounts",
+		"admin", "api", "me", "new", "edit", "delete",
+	}
 )
 
+// IsReservedUsername reports whether the username is one of ReservedUsernames, ignoring case.
+func IsReservedUsername(username string) bool {
+	return slices.Contains(ReservedUsernames, strings.ToLower(username))
+}
+
 const (
 	// UserWithThisEmailAlreadyExists is the specific error message for email validation failure during registration.
 	UserWithThisEmailAlreadyExists = "User with this email already exists."
@@ -142,6 +176,22 @@ var (
 	// ErrChangeEmailToSameEmail ...
 	ErrChangeEmailToSameEmail = app.ErrUnprocessable("you already use this email, no change needed")
 
+	// ErrOAuthAccountLinkedToAnotherUser is returned when a user tries to link an OAuth account
+	// that already belongs to a different user.
+	ErrOAuthAccountLinkedToAnotherUser = app.ErrUnprocessable("this account is already linked to another user")
+
+	// ErrOAuthProviderAlreadyLinked is returned when a user tries to link a second account
+	// of the same provider.
+	ErrOAuthProviderAlreadyLinked = app.ErrUnprocessable("an account of this provider is already linked")
+
+	// ErrOAuthAccountNotLinked is returned when a user tries to unlink a provider that is not linked.
+	ErrOAuthAccountNotLinked = app.ErrNotFound("account of this provider is not linked")
+
+	// ErrCannotUnlinkLastSignInMethod is returned when unlinking would leave the user
+	// without a password or any other provider to sign in with.
+	ErrCannotUnlinkLastSignInMethod = app.ErrUnprocessable(
+		"cannot unlink the only sign-in method, set a password or link another account first")
+
 	// ErrInvalidJWT is returned when an authentication token is malformed,
 	// invalidly signed, or contains unexpected claims.
 	ErrInvalidJWT = app.ErrForbidden("invalid token")
@@ -350,6 +400,11 @@ func (s *Service) CreateChangeEmailRequest(ctx context.Context, userID ds.ID, ne
 		return ErrChangeEmailToSameEmail
 	}
 
+	err = s.throttleAccount(ctx, RateLimitEmailChange, user.ID.String())
+	if err != nil {
+		return
+	}
+
 	// Check if the new email is already taken by another user.
 	existingUser, err := s.db.GetUserByEmail(ctx, in.NewEmail)
 	if errors.Is(err, repo.ErrUserNotFound) {
@@ -385,7 +440,7 @@ func (s *Service) CreateChangeEmailRequest(ctx context.Context, userID ds.ID, ne
 		return
 	}
 
-	return email.Send(in.NewEmail, email.ConfirmEmailChange{
+	return email.Send(ctx, in.NewEmail, email.ConfirmEmailChange{
 		Username: user.Username,
 		Token:    token,
 	})
@@ -447,6 +502,12 @@ func (s *Service) CreatePasswordResetRequest(ctx context.Context, emailAddr stri
 		return
 	}
 
+	// Throttled before the lookup, so limits don't reveal whether the email is registered.
+	err = s.throttleAccount(ctx, RateLimitPasswordReset, in.Email)
+	if err != nil {
+		return
+	}
+
 	user, err := s.db.GetUserByEmail(ctx, in.Email)
 	if err != nil {
 		// If the user is not found, we don't return an error to prevent email enumeration attacks.
@@ -479,7 +540,7 @@ func (s *Service) CreatePasswordResetRequest(ctx context.Context, emailAddr stri
 		return
 	}
 
-	return email.Send(user.Email, email.PasswordResetRequest{
+	return email.Send(ctx, user.Email, email.PasswordResetRequest{
 		Username: user.Username,
 		Token:    resetToken,
 	})
@@ -778,6 +839,175 @@ func (in *GetOAuthUserAccountInput) Validate() error {
 	return validateInput(getOAuthUserAccountInputRules, in)
 }
 
+// GetUserOAuthAccounts returns all OAuth accounts linked to the given user.
+func (s *Service) GetUserOAuthAccounts(ctx context.Context, userID ds.ID) (accounts []ds.OAuthUserAccount, err error) {
+	ctx, span := s.tracer.Start(ctx, "GetUserOAuthAccounts")
+	defer span.End()
+
+	in := &GetUserOAuthAccountsInput{UserID: userID}
+	err = Normalize(in)
+	if err != nil {
+		return
+	}
+
+	return s.db.GetOAuthUserAccountsByUserID(ctx, in.UserID)
+}
+
+// GetUserOAuthAccountsInput defines the input for listing user's OAuth accounts.
+type GetUserOAuthAccountsInput struct {
+	UserID ds.ID
+}
+
+// Sanitize performs no sanitization for this input.
+func (in *GetUserOAuthAccountsInput) Sanitize() {}
+
+// Validate validates the input against defined rules.
+func (in *GetUserOAuthAccountsInput) Validate() error {
+	return validateInput(getUserOAuthAccountsInputRules, in)
+}
+
+// LinkOAuthAccount links the OAuth account to an existing (signed-in) user.
+// Unlike ResolveUserFromOAuthAccount, it never creates or looks up a user by email.
+func (s *Service) LinkOAuthAccount(ctx context.Context, userID ds.ID, authAcc goth.User) (err error) {
+	ctx, span := s.tracer.Start(ctx, "LinkOAuthAccount")
+	defer span.End()
+
+	in := &LinkOAuthAccountInput{
+		UserID:         userID,
+		Provider:       provider.New(authAcc.Provider),
+		ProviderUserID: authAcc.UserID,
+	}
+	err = Normalize(in)
+	if err != nil {
+		return
+	}
+
+	acc, err := s.db.GetOAuthUserAccount(ctx, in.Provider, in.ProviderUserID)
+	if err == nil {
+		if acc.UserID == in.UserID {
+			return nil
+		}
+
+		return ErrOAuthAccountLinkedToAnotherUser
+	}
+	if !errors.Is(err, repo.ErrOAuthUserAccountNotFound) {
+		return
+	}
+
+	accounts, err := s.db.GetOAuthUserAccountsByUserID(ctx, in.UserID)
+	if err != nil {
+		return
+	}
+	for _, a := range accounts {
+		if a.Provider == in.Provider {
+			return ErrOAuthProviderAlreadyLinked
+		}
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.CreateOAuthUserAccount(ctx, &ds.OAuthUserAccount{
+			UserID:         in.UserID,
+			Provider:       in.Provider,
+			ProviderUserID: in.ProviderUserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.LogOAuthAccountLinked(ctx, in.UserID, in.Provider)
+	})
+}
+
+// LinkOAuthAccountInput defines the input for linking an OAuth account to a user.
+type LinkOAuthAccountInput struct {
+	UserID         ds.ID
+	Provider       provider.Type
+	ProviderUserID string
+}
+
+// Sanitize trims whitespace from the provider fields.
+func (in *LinkOAuthAccountInput) Sanitize() {
+	in.Provider = provider.New(strings.TrimSpace(in.Provider.String()))
+	in.ProviderUserID = strings.TrimSpace(in.ProviderUserID)
+}
+
+// Validate validates the link OAuth account input against defined rules.
+func (in *LinkOAuthAccountInput) Validate() error {
+	return validateInput(linkOAuthAccountInputRules, in)
+}
+
+// UnlinkOAuthAccount removes the link between the user and the OAuth provider.
+// The provider may be disabled since the account was linked.
+// It refuses to unlink the last enabled provider of a user who has no password set,
+// since that would leave the account without any way to sign in.
+func (s *Service) UnlinkOAuthAccount(ctx context.Context, userID ds.ID, prov provider.Type) (err error) {
+	ctx, span := s.tracer.Start(ctx, "UnlinkOAuthAccount")
+	defer span.End()
+
+	in := &UnlinkOAuthAccountInput{
+		UserID:   userID,
+		Provider: prov,
+	}
+	err = Normalize(in)
+	if err != nil {
+		return
+	}
+
+	user, err := s.db.GetUserByID(ctx, in.UserID)
+	if err != nil {
+		return
+	}
+
+	accounts, err := s.db.GetOAuthUserAccountsByUserID(ctx, in.UserID)
+	if err != nil {
+		return
+	}
+
+	var acc *ds.OAuthUserAccount
+	signInMethods := 0
+	for i := range accounts {
+		if accounts[i].Provider == in.Provider {
+			acc = &accounts[i]
+		}
+		// accounts of disabled providers can't be used to sign in
+		if accounts[i].Provider.Valid() {
+			signInMethods++
+		}
+	}
+	if acc == nil {
+		return ErrOAuthAccountNotLinked
+	}
+
+	if !user.HasPassword() && acc.Provider.Valid() && signInMethods == 1 {
+		return ErrCannotUnlinkLastSignInMethod
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.db.DeleteOAuthUserAccount(ctx, acc.ID)
+		if err != nil {
+			return err
+		}
+
+		return s.LogOAuthAccountUnlinked(ctx, in.UserID, in.Provider)
+	})
+}
+
+// UnlinkOAuthAccountInput defines the input for unlinking an OAuth account from a user.
+type UnlinkOAuthAccountInput struct {
+	UserID   ds.ID
+	Provider provider.Type
+}
+
+// Sanitize trims whitespace from the provider.
+func (in *UnlinkOAuthAccountInput) Sanitize() {
+	in.Provider = provider.New(strings.TrimSpace(in.Provider.String()))
+}
+
+// Validate validates the unlink OAuth account input against defined rules.
+func (in *UnlinkOAuthAccountInput) Validate() error {
+	return validateInput(unlinkOAuthAccountInputRules, in)
+}
+
 // GetUserAndSessionFromJWT checks the associated session's validity and retrieves the corresponding user record.
 func (s *Service) GetUserAndSessionFromJWT(ctx context.Context, token string) (
 	user *ds.User, sess *ds.UserSession, err error) {
@@ -867,10 +1097,33 @@ func (s *Service) CleanupDeletedUser(ctx context.Context, userID ds.ID) (err err
 		return
 	}
 
+	// data exports
+	err = s.deleteUserDataExports(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	// book reviews stay, but no longer point to the user
+	err = s.db.AnonymizeBookReviewsByUser(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	// avatar
+	if !user.AvatarFileID.IsNil() {
+		err = s.db.DeleteFile(ctx, user.AvatarFileID)
+		if err != nil {
+			return
+		}
+	}
+
 	user.Email = "deleted-" + random.String(16) + "-" + uuid.NewString()    //nolint:mnd
 	user.Username = "deleted-" + random.String(16) + "-" + uuid.NewString() //nolint:mnd
 	user.Password = "deleted-" + random.String(16)                          //nolint:mnd
 	user.CleanedAt = new(time.Now())
+	user.Bio = ""
+	user.AvatarFileID = ds.NilID
+	user.Links = []ds.UserLink{}
 
 	return s.db.UpdateUser(ctx, user)
 }
@@ -966,12 +1219,14 @@ func (s *Service) RegisterUser(ctx context.Context, username, emailAddr, passwor
 		return
 	}
 
+	metrics.UserSignups.WithLabelValues("password").Inc()
+
 	emailConfirmCode, err := s.CreateEmailConfirmation(ctx, user.ID)
 	if err != nil {
 		return
 	}
 
-	err = email.Send(user.Email, email.ConfirmEmail{
+	err = email.Send(ctx, user.Email, email.ConfirmEmail{
 		Username: user.Username,
 		Email:    in.Email,
 		Code:     emailConfirmCode,
@@ -1093,6 +1348,7 @@ func (s *Service) ResolveUserFromOAuthAccount(ctx context.Context, authAcc goth.
 		return
 	}
 
+	signedUp := false
 	err = s.db.WithTx(ctx, func(ctx context.Context) error {
 		// create new oauth account
 		user, err = s.GetUserByEmail(ctx, in.Email)
@@ -1123,6 +1379,8 @@ func (s *Service) ResolveUserFromOAuthAccount(ctx context.Context, authAcc goth.
 				return err
 			}
 
+			signedUp = true
+
 			err = s.LogAccountActivated(ctx, user.ID)
 			if err != nil {
 				return err
@@ -1148,6 +1406,10 @@ func (s *Service) ResolveUserFromOAuthAccount(ctx context.Context, authAcc goth.
 		return nil, err
 	}
 
+	if signedUp {
+		metrics.UserSignups.WithLabelValues(in.Provider).Inc()
+	}
+
 	return user, nil
 }
 
@@ -1183,6 +1445,9 @@ func (s *Service) selectUsernameForOAuthUser(ctx context.Context, maybeNames ...
 		if ii := strings.Index(n, atSign); ii > -1 {
 			n = n[:ii]
 		}
+		if IsReservedUsername(n) {
+			continue
+		}
 		names = append(names, n)
 	}
 
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	z "github.com/Oudwins/zog"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/repo"
+)
+
+const (
+	// WebhookMaxAttempts is how many times a delivery is attempted before it is marked as failed.
+	WebhookMaxAttempts = 6
+
+	// WebhookSignatureHeader carries "sha256=" followed by hex-encoded HMAC-SHA256
+	// of "{timestamp}.{body}", keyed with the secret of the webhook.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	// WebhookTimestampHeader carries the Unix time the delivery attempt was signed at.
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+
+	// WebhookEventHeader carries the event type of the delivery.
+	WebhookEventHeader = "X-Webhook-Event"
+
+	// WebhookDeliveryHeader carries the ID of the delivery.
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+
+	// webhookDeliveriesBatchSize is how many due deliveries are sent per worker run.
+	webhookDeliveriesBatchSize = 100
+
+	// webhookResponseBodyMaxLen is how much of the response body is kept in the delivery log.
+	webhookResponseBodyMaxLen = 2048
+
+	webhookTimeout = 10 * time.Second
+
+	// webhookDeliveryLease is how long the claimed deliveries are not given to another worker run.
+	// It's longer than a batch may take when every receiver times out.
+	webhookDeliveryLease = webhookDeliveriesBatchSize*webhookTimeout + 5*time.Minute
+)
+
+// webhookBackoff defines the delay before the next attempt, by number of failed attempts.
+var webhookBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+
+// WebhookHTTPClient is the client deliveries are sent with.
+var WebhookHTTPClient = &http.Client{Timeout: webhookTimeout}
+
+var webhookEventTypes = func() []string {
+	types := make([]string, len(ds.EventLogTypes))
+	for i, t := range ds.EventLogTypes {
+		types[i] = string(t)
+	}
+
+	return types
+}()
+
+var webhookInputRules = z.Shape{
+	"URL": z.String().Required(z.Message("URL is required")).
+		URL(z.Message("Invalid URL")).
+		Match(httpURLRegex, z.Message("URL must start with http:// or https://")),
+	"Secret": z.String().Required(z.Message("Secret is required")),
+	"EventTypes": z.Slice(z.String().
+		OneOf(webhookEventTypes, z.Message("Unknown event type"))),
+	"Description": z.String().Max(255, z.Message("Description must be at most 255 characters")), //nolint:mnd
+}
+
+// WebhookInput defines the input for creating or updating a webhook.
+type WebhookInput struct {
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Description string
+	Active      bool
+}
+
+// Sanitize trims whitespace and removes duplicate event types.
+func (in *WebhookInput) Sanitize() {
+	in.URL = strings.TrimSpace(in.URL)
+	in.Secret = strings.TrimSpace(in.Secret)
+	in.Description = strings.TrimSpace(in.Description)
+
+	slices.Sort(in.EventTypes)
+	in.EventTypes = slices.Compact(in.EventTypes)
+}
+
+// Validate validates the webhook input against defined rules.
+func (in *WebhookInput) Validate() error {
+	return validateInput(webhookInputRules, in)
+}
+
+// CreateWebhook creates a webhook subscription.
+// If no secret is given, a random one is generated.
+func (s *Service) CreateWebhook(ctx context.Context, in WebhookInput) (w *ds.Webhook, err error) {
+	ctx, span := s.tracer.Start(ctx, "CreateWebhook")
+	defer span.End()
+
+	if strings.TrimSpace(in.Secret) == "" {
+		in.Secret, err = app.Token()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = Normalize(&in)
+	if err != nil {
+		return nil, err
+	}
+
+	w = &ds.Webhook{
+		URL:         in.URL,
+		Secret:      in.Secret,
+		EventTypes:  in.EventTypes,
+		Description: in.Description,
+		Active:      in.Active,
+	}
+	if user := ds.UserFromContext(ctx); user != nil {
+		w.CreatedBy = new(user.ID)
+	}
+
+	err = s.db.CreateWebhook(ctx, w)
+	return w, err
+}
+
+// UpdateWebhook updates the webhook subscription.
+// If no secret is given, the current one is kept.
+func (s *Service) UpdateWebhook(ctx context.Context, id ds.ID, in WebhookInput) (*ds.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "UpdateWebhook")
+	defer span.End()
+
+	w, err := s.db.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.Secret) == "" {
+		in.Secret = w.Secret
+	}
+
+	err = Normalize(&in)
+	if err != nil {
+		return nil, err
+	}
+
+	w.URL = in.URL
+	w.Secret = in.Secret
+	w.EventTypes = in.EventTypes
+	w.Description = in.Description
+	w.Active = in.Active
+
+	err = s.db.UpdateWebhook(ctx, w)
+	return w, err
+}
+
+// GetWebhooks returns all webhook subscriptions.
+func (s *Service) GetWebhooks(ctx context.Context) ([]ds.Webhook, error) {
+	ctx, span := s.tracer.Start(ctx, "GetWebhooks")
+	defer span.End()
+
+	return s.db.GetWebhooks(ctx)
+}
+
+// DeleteWebhook deletes the webhook subscription; pending deliveries are not sent anymore.
+func (s *Service) DeleteWebhook(ctx context.Context, id ds.ID) error {
+	ctx, span := s.tracer.Start(ctx, "DeleteWebhook")
+	defer span.End()
+
+	_, err := s.db.GetWebhookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.DeleteWebhook(ctx, id)
+}
+
+// FilterWebhookDeliveries returns the delivery log of webhooks.
+func (s *Service) FilterWebhookDeliveries(ctx context.Context, f ds.WebhookDeliveriesFilter) ([]ds.WebhookDelivery, int, error) {
+	ctx, span := s.tracer.Start(ctx, "FilterWebhookDeliveries")
+	defer span.End()
+
+	return s.db.FilterWebhookDeliveries(ctx, f)
+}
+
+// PingWebhook enqueues a delivery with a sample payload to test the webhook.
+func (s *Service) PingWebhook(ctx context.Context, id ds.ID) (*ds.WebhookDelivery, error) {
+	ctx, span := s.tracer.Start(ctx, "PingWebhook")
+	defer span.End()
+
+	w, err := s.db.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &ds.EventLog{
+		ID:        ds.NewID(),
+		Type:      ds.WebhookEventPing,
+		Message:   "Hello from gopl.dev!",
+		CreatedAt: time.Now(),
+	}
+	if user := ds.UserFromContext(ctx); user != nil {
+		log.UserID = new(user.ID)
+	}
+
+	d, err := newWebhookDelivery(w, log)
+	if err != nil {
+		return nil, err
+	}
+	d.EventLogID = nil // sample event is not stored
+
+	err = s.db.CreateWebhookDelivery(ctx, d)
+	return d, err
+}
+
+// RedeliverWebhookDelivery enqueues a new delivery with the payload of the given one.
+func (s *Service) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID ds.ID) (*ds.WebhookDelivery, error) {
+	ctx, span := s.tracer.Start(ctx, "RedeliverWebhookDelivery")
+	defer span.End()
+
+	_, err := s.db.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	orig, err := s.db.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if orig.WebhookID != webhookID {
+		return nil, repo.ErrWebhookDeliveryNotFound
+	}
+
+	d := &ds.WebhookDelivery{
+		WebhookID:     orig.WebhookID,
+		EventLogID:    orig.EventLogID,
+		EventType:     orig.EventType,
+		Payload:       orig.Payload,
+		Status:        ds.WebhookDeliveryPending,
+		NextAttemptAt: new(time.Now()),
+	}
+
+	err = s.db.CreateWebhookDelivery(ctx, d)
+	return d, err
+}
+
+// enqueueWebhookDeliveries creates pending deliveries of the event log
+// for every active webhook subscribed to its type.
+// Only public events are delivered: private ones carry personal data (e.g. emails) in their meta.
+func (s *Service) enqueueWebhookDeliveries(ctx context.Context, log *ds.EventLog) error {
+	ctx, span := s.tracer.Start(ctx, "enqueueWebhookDeliveries")
+	defer span.End()
+
+	if !log.IsPublic {
+		return nil
+	}
+
+	webhooks, err := s.db.GetActiveWebhooksByEventType(ctx, log.Type)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range webhooks {
+		d, err := newWebhookDelivery(&w, log)
+		if err != nil {
+			return err
+		}
+
+		err = s.db.CreateWebhookDelivery(ctx, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newWebhookDelivery(w *ds.Webhook, log *ds.EventLog) (*ds.WebhookDelivery, error) {
+	payload, err := json.Marshal(ds.WebhookPayload{
+		ID:        log.ID,
+		Event:     log.Type,
+		CreatedAt: log.CreatedAt,
+		Data:      log,
+		Meta:      log.Meta,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	return &ds.WebhookDelivery{
+		ID:            ds.NewID(),
+		WebhookID:     w.ID,
+		EventLogID:    new(log.ID),
+		EventType:     log.Type,
+		Payload:       payload,
+		Status:        ds.WebhookDeliveryPending,
+		NextAttemptAt: new(time.Now()),
+	}, nil
+}
+
+// ProcessDueWebhookDeliveries sends pending deliveries whose attempt is due.
+// Failed attempts are rescheduled with increasing delay until WebhookMaxAttempts is reached.
+func (s *Service) ProcessDueWebhookDeliveries(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "ProcessDueWebhookDeliveries")
+	defer span.End()
+
+	deliveries, err := s.db.ClaimDueWebhookDeliveries(ctx, webhookDeliveriesBatchSize, webhookDeliveryLease)
+	if err != nil {
+		return err
+	}
+
+	webhooks := map[ds.ID]*ds.Webhook{}
+	for _, d := range deliveries {
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			w, err = s.db.GetWebhookByID(ctx, d.WebhookID)
+			if err != nil && !errors.Is(err, repo.ErrWebhookNotFound) {
+				return err
+			}
+
+			webhooks[d.WebhookID] = w
+		}
+
+		if w == nil {
+			// webhook is deleted
+			d.Status = ds.WebhookDeliveryFailed
+			d.NextAttemptAt = nil
+			d.Error = "webhook deleted"
+		} else {
+			s.attemptWebhookDelivery(ctx, w, &d)
+		}
+
+		err = s.db.UpdateWebhookDelivery(ctx, &d)
+		if err != nil {
+			return err
+		}
+
+		ds.AddProcessedItems(ctx, 1)
+	}
+
+	return nil
+}
+
+// attemptWebhookDelivery sends the delivery and records the outcome on it.
+func (s *Service) attemptWebhookDelivery(ctx context.Context, w *ds.Webhook, d *ds.WebhookDelivery) {
+	d.Attempts++
+	d.ResponseStatus = 0
+	d.ResponseBody = ""
+	d.Error = ""
+
+	start := time.Now()
+	status, body, err := sendWebhook(ctx, w, d)
+	d.DurationMS = int(time.Since(start).Milliseconds())
+	d.ResponseStatus = status
+	d.ResponseBody = body
+
+	if err == nil && status >= 200 && status < 300 {
+		d.Status = ds.WebhookDeliverySucceeded
+		d.NextAttemptAt = nil
+		d.DeliveredAt = new(time.Now())
+		return
+	}
+
+	if err != nil {
+		d.Error = err.Error()
+	} else {
+		d.Error = "unexpected response status " + strconv.Itoa(status)
+	}
+
+	if d.Attempts >= WebhookMaxAttempts {
+		d.Status = ds.WebhookDeliveryFailed
+		d.NextAttemptAt = nil
+		return
+	}
+
+	delay := webhookBackoff[min(d.Attempts, len(webhookBackoff))-1]
+	d.NextAttemptAt = new(time.Now().Add(delay))
+}
+
+func sendWebhook(ctx context.Context, w *ds.Webhook, d *ds.WebhookDelivery) (status int, body string, err error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gopl.dev-webhooks")
+	req.Header.Set(WebhookEventHeader, string(d.EventType))
+	req.Header.Set(WebhookDeliveryHeader, d.ID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(w.Secret, timestamp, d.Payload))
+
+	resp, err := WebhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyMaxLen))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+
+	return resp.StatusCode, string(b), nil
+}
+
+// WebhookSignature returns the value of WebhookSignatureHeader for the payload.
+// Receivers compute the same value to verify the delivery comes from us.
+func WebhookSignature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// RegisterWorkerJobs makes sure every job known to the worker has its state stored,
+// so it can be listed, paused and triggered before it runs for the first time.
+func (s *Service) RegisterWorkerJobs(ctx context.Context, names []string) error {
+	ctx, span := s.tracer.Start(ctx, "RegisterWorkerJobs")
+	defer span.End()
+
+	for _, name := range names {
+		err := s.db.CreateWorkerJob(ctx, &ds.WorkerJob{Name: name})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetWorkerJobs returns all worker jobs along with their latest runs.
+func (s *Service) GetWorkerJobs(ctx context.Context) ([]ds.WorkerJob, error) {
+	ctx, span := s.tracer.Start(ctx, "GetWorkerJobs")
+	defer span.End()
+
+	jobs, err := s.db.GetWorkerJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.db.GetLastWorkerJobRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lastRuns := make(map[string]*ds.WorkerJobRun, len(runs))
+	for i := range runs {
+		lastRuns[runs[i].JobName] = &runs[i]
+	}
+
+	for i := range jobs {
+		jobs[i].LastRun = lastRuns[jobs[i].Name]
+	}
+
+	return jobs, nil
+}
+
+// GetWorkerJob returns the worker job by its name.
+func (s *Service) GetWorkerJob(ctx context.Context, name string) (*ds.WorkerJob, error) {
+	ctx, span := s.tracer.Start(ctx, "GetWorkerJob")
+	defer span.End()
+
+	return s.db.GetWorkerJobByName(ctx, name)
+}
+
+// PauseWorkerJob stops scheduled runs of the job until it is resumed.
+// Manual runs are still possible.
+func (s *Service) PauseWorkerJob(ctx context.Context, name string) error {
+	ctx, span := s.tracer.Start(ctx, "PauseWorkerJob")
+	defer span.End()
+
+	return s.setWorkerJobPaused(ctx, name, true)
+}
+
+// ResumeWorkerJob resumes scheduled runs of the paused job.
+func (s *Service) ResumeWorkerJob(ctx context.Context, name string) error {
+	ctx, span := s.tracer.Start(ctx, "ResumeWorkerJob")
+	defer span.End()
+
+	return s.setWorkerJobPaused(ctx, name, false)
+}
+
+func (s *Service) setWorkerJobPaused(ctx context.Context, name string, paused bool) error {
+	_, err := s.db.GetWorkerJobByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return s.db.SetWorkerJobPaused(ctx, name, paused)
+}
+
+// RequestWorkerJobRun asks the worker to run the job as soon as possible.
+func (s *Service) RequestWorkerJobRun(ctx context.Context, name string) error {
+	ctx, span := s.tracer.Start(ctx, "RequestWorkerJobRun")
+	defer span.End()
+
+	_, err := s.db.GetWorkerJobByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return s.db.RequestWorkerJobRun(ctx, name)
+}
+
+// TakeRequestedWorkerJobs returns names of the jobs requested to run, clearing the requests.
+func (s *Service) TakeRequestedWorkerJobs(ctx context.Context) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "TakeRequestedWorkerJobs")
+	defer span.End()
+
+	return s.db.TakeRequestedWorkerJobs(ctx)
+}
+
+// StartWorkerJobRun records the beginning of the job run.
+func (s *Service) StartWorkerJobRun(ctx context.Context, name string, trigger ds.WorkerJobTrigger) (*ds.WorkerJobRun, error) {
+	ctx, span := s.tracer.Start(ctx, "StartWorkerJobRun")
+	defer span.End()
+
+	run := &ds.WorkerJobRun{
+		JobName:   name,
+		Trigger:   trigger,
+		Status:    ds.WorkerJobRunRunning,
+		StartedAt: time.Now(),
+	}
+
+	err := s.db.CreateWorkerJobRun(ctx, run)
+	return run, err
+}
+
+// FinishWorkerJobRun records the outcome of the job run; runErr is the error the job returned.
+func (s *Service) FinishWorkerJobRun(ctx context.Context, run *ds.WorkerJobRun, runErr error) error {
+	ctx, span := s.tracer.Start(ctx, "FinishWorkerJobRun")
+	defer span.End()
+
+	run.Status = ds.WorkerJobRunSucceeded
+	if runErr != nil {
+		run.Status = ds.WorkerJobRunFailed
+		run.Error = runErr.Error()
+	}
+
+	run.FinishedAt = new(time.Now())
+	run.DurationMS = int(run.FinishedAt.Sub(run.StartedAt).Milliseconds())
+
+	return s.db.UpdateWorkerJobRun(ctx, run)
+}
+
+// SkipWorkerJobRun records the run that didn't start for the reason given,
+// so the one who requested it can see what happened.
+func (s *Service) SkipWorkerJobRun(ctx context.Context, name string, trigger ds.WorkerJobTrigger, reason error) error {
+	ctx, span := s.tracer.Start(ctx, "SkipWorkerJobRun")
+	defer span.End()
+
+	now := time.Now()
+	return s.db.CreateWorkerJobRun(ctx, &ds.WorkerJobRun{
+		JobName:    name,
+		Trigger:    trigger,
+		Status:     ds.WorkerJobRunSkipped,
+		Error:      reason.Error(),
+		StartedAt:  now,
+		FinishedAt: &now,
+	})
+}
+
+// FilterWorkerJobRuns returns the run history of worker jobs.
+func (s *Service) FilterWorkerJobRuns(ctx context.Context, f ds.WorkerJobRunsFilter) ([]ds.WorkerJobRun, int, error) {
+	ctx, span := s.tracer.Start(ctx, "FilterWorkerJobRuns")
+	defer span.End()
+
+	return s.db.FilterWorkerJobRuns(ctx, f)
+}
+
+// CleanupWorkerJobRuns deletes job runs older than ds.CleanupWorkerJobRunsAfterDays.
+func (s *Service) CleanupWorkerJobRuns(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "CleanupWorkerJobRuns")
+	defer span.End()
+
+	n, err := s.db.DeleteWorkerJobRunsBefore(ctx, time.Now().AddDate(0, 0, -ds.CleanupWorkerJobRunsAfterDays))
+	ds.AddProcessedItems(ctx, int(n))
+
+	return err
+}
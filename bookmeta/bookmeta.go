@@ -0,0 +1,136 @@
+// Package bookmeta looks up metadata of books (title, authors, cover, etc.) by ISBN with pluggable providers.
+package bookmeta
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+const (
+	// OpenLibraryDriver looks books up with the Open Library API.
+	OpenLibraryDriver = "openlibrary"
+
+	// FixtureDriver looks books up in a local JSON file. Intended for tests and offline development.
+	FixtureDriver = "fixture"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrInvalidDriver indicates that the configured book metadata driver is not recognized.
+	ErrInvalidDriver = errors.New("invalid book metadata driver")
+
+	// ErrDisabled is returned by the provider of the disabled lookup.
+	ErrDisabled = errors.New("book metadata lookup is disabled")
+
+	// ErrNotFound is returned when the provider has no metadata of the book.
+	ErrNotFound = errors.New("book metadata not found")
+)
+
+// Metadata describes a book as known to a provider.
+type Metadata struct {
+	ISBN10    string          `json:"isbn10"`
+	ISBN13    string          `json:"isbn13"`
+	Title     string          `json:"title"`
+	Authors   []ds.BookAuthor `json:"authors"`
+	Publisher string          `json:"publisher"`
+	// Description is plain text, or empty if the provider has none.
+	Description string `json:"description"`
+	// ReleaseDate is in one of ds.ReleaseDateLayouts, or empty if unknown.
+	ReleaseDate string `json:"release_date"`
+	PageCount   int    `json:"page_count"`
+	// CoverURL is the URL of the cover image, or empty if there is none.
+	CoverURL string `json:"cover_url"`
+}
+
+// Provider looks up metadata of books.
+type Provider interface {
+	// Lookup returns the metadata of the book with the ISBN (valid ISBN-10 or ISBN-13, see ds.NormalizeISBN).
+	// It returns ErrNotFound if the provider doesn't know the book.
+	Lookup(ctx context.Context, isbn string) (*Metadata, error)
+}
+
+// FromConfig creates the provider using the driver set in config.
+// If the lookup is disabled, the provider returns ErrDisabled.
+func FromConfig() (Provider, error) {
+	conf := app.Config().BookMetadata
+	if !conf.Enabled {
+		return disabledProvider{}, nil
+	}
+
+	client := &http.Client{
+		Timeout: cmp.Or(time.Duration(conf.TimeoutSeconds)*time.Second, defaultTimeout),
+	}
+
+	switch conf.Driver {
+	case OpenLibraryDriver:
+		return NewOpenLibraryProvider(conf.OpenLibrary.BaseURL, client), nil
+	case FixtureDriver:
+		return NewFixtureProvider(conf.Fixture.Path)
+	default:
+		return nil, fmt.Errorf("driver '%s': %w", conf.Driver, ErrInvalidDriver)
+	}
+}
+
+type disabledProvider struct{}
+
+// Lookup implements Provider.
+func (disabledProvider) Lookup(context.Context, string) (*Metadata, error) {
+	return nil, ErrDisabled
+}
+
+// releaseDateLayouts maps the date layouts used by providers to the closest one of ds.ReleaseDateLayouts.
+var releaseDateLayouts = []struct {
+	in, out string
+}{
+	{"2006", "2006"},
+	{"January 2006", "January 2006"},
+	{"Jan 2006", "January 2006"},
+	{"2006-01", "January 2006"},
+	{"January 2, 2006", "January 2, 2006"},
+	{"Jan 2, 2006", "January 2, 2006"},
+	{"2 January 2006", "January 2, 2006"},
+	{"2 Jan 2006", "January 2, 2006"},
+	{"2006-01-02", "January 2, 2006"},
+}
+
+// NormalizeReleaseDate converts the release date to one of ds.ReleaseDateLayouts.
+// It returns an empty string if the date is in an unknown layout.
+func NormalizeReleaseDate(date string) string {
+	date = strings.TrimSpace(date)
+	for _, l := range releaseDateLayouts {
+		t, err := time.Parse(l.in, date)
+		if err == nil {
+			return t.Format(l.out)
+		}
+	}
+
+	return ""
+}
+
+// complete fills in the data the provider may miss: the other form of the ISBN,
+// and the release date in the layout the books use.
+func complete(m *Metadata, isbn string) {
+	switch len(isbn) {
+	case ds.ISBN10Len:
+		m.ISBN10 = cmp.Or(m.ISBN10, isbn)
+	case ds.ISBN13Len:
+		m.ISBN13 = cmp.Or(m.ISBN13, isbn)
+	}
+
+	m.ISBN10 = cmp.Or(m.ISBN10, ds.ISBN13To10(m.ISBN13))
+	m.ISBN13 = cmp.Or(m.ISBN13, ds.ISBN10To13(m.ISBN10))
+	m.ReleaseDate = NormalizeReleaseDate(m.ReleaseDate)
+
+	if m.Authors == nil {
+		m.Authors = make([]ds.BookAuthor, 0)
+	}
+}
@@ -0,0 +1,178 @@
+package bookmeta
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// OpenLibraryBaseURL is the address of the Open Library API used if no other is configured.
+const OpenLibraryBaseURL = "https://openlibrary.org"
+
+// OpenLibraryProvider looks books up with the Open Library Books API,
+// see https://openlibrary.org/dev/docs/api/books.
+type OpenLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenLibraryProvider creates a new OpenLibraryProvider.
+// If baseURL is empty, OpenLibraryBaseURL is used.
+func NewOpenLibraryProvider(baseURL string, client *http.Client) *OpenLibraryProvider {
+	return &OpenLibraryProvider{
+		baseURL: strings.TrimSuffix(cmp.Or(baseURL, OpenLibraryBaseURL), "/"),
+		client:  client,
+	}
+}
+
+// openLibraryBook is the book in the response of the Books API with jscmd=data.
+type openLibraryBook struct {
+	// Key is the path of the edition, e.g. "/books/OL26974419M".
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Publishers []struct {
+		Name string `json:"name"`
+	} `json:"publishers"`
+	PublishDate   string `json:"publish_date"`
+	NumberOfPages int    `json:"number_of_pages"`
+	Identifiers   struct {
+		ISBN10 []string `json:"isbn_10"`
+		ISBN13 []string `json:"isbn_13"`
+	} `json:"identifiers"`
+	Cover struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// Lookup implements Provider.
+func (p *OpenLibraryProvider) Lookup(ctx context.Context, isbn string) (*Metadata, error) {
+	key := "ISBN:" + isbn
+	q := url.Values{
+		"bibkeys": {key},
+		"format":  {"json"},
+		"jscmd":   {"data"},
+	}
+
+	books := make(map[string]openLibraryBook)
+	err := p.get(ctx, "/api/books?"+q.Encode(), &books)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := books[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	m := &Metadata{
+		Title:       b.Title,
+		Authors:     make([]ds.BookAuthor, 0, len(b.Authors)),
+		ReleaseDate: b.PublishDate,
+		PageCount:   b.NumberOfPages,
+		CoverURL:    cmp.Or(b.Cover.Large, b.Cover.Medium),
+	}
+
+	for _, a := range b.Authors {
+		m.Authors = append(m.Authors, ds.BookAuthor{Name: a.Name})
+	}
+
+	if len(b.Publishers) > 0 {
+		m.Publisher = b.Publishers[0].Name
+	}
+
+	if len(b.Identifiers.ISBN10) > 0 {
+		m.ISBN10 = ds.NormalizeISBN(b.Identifiers.ISBN10[0])
+	}
+
+	if len(b.Identifiers.ISBN13) > 0 {
+		m.ISBN13 = ds.NormalizeISBN(b.Identifiers.ISBN13[0])
+	}
+
+	m.Description = p.description(ctx, b.Key)
+
+	complete(m, isbn)
+
+	return m, nil
+}
+
+// openLibraryText is a text field that is either a string or {"type": "/type/text", "value": "..."}.
+type openLibraryText string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *openLibraryText) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*t = openLibraryText(s)
+		return nil
+	}
+
+	var v struct {
+		Value string `json:"value"`
+	}
+	err := json.Unmarshal(data, &v)
+	*t = openLibraryText(v.Value)
+
+	return err
+}
+
+// description returns the description of the edition, or of its work if the edition has none.
+// The description is optional, so errors are ignored.
+func (p *OpenLibraryProvider) description(ctx context.Context, editionKey string) string {
+	if !strings.HasPrefix(editionKey, "/books/") {
+		return ""
+	}
+
+	var edition struct {
+		Description openLibraryText `json:"description"`
+		Works       []struct {
+			Key string `json:"key"`
+		} `json:"works"`
+	}
+	err := p.get(ctx, editionKey+".json", &edition)
+	if err != nil || edition.Description != "" || len(edition.Works) == 0 ||
+		!strings.HasPrefix(edition.Works[0].Key, "/works/") {
+		return strings.TrimSpace(string(edition.Description))
+	}
+
+	var work struct {
+		Description openLibraryText `json:"description"`
+	}
+	_ = p.get(ctx, edition.Works[0].Key+".json", &work)
+
+	return strings.TrimSpace(string(work.Description))
+}
+
+// get requests the path of the API and decodes the JSON response into v.
+func (p *OpenLibraryProvider) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("open library: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("open library: unexpected status %s", resp.Status) //nolint:err113
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("open library: decode response: %w", err)
+	}
+
+	return nil
+}
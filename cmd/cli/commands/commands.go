@@ -3,19 +3,26 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/repo"
 	"github.com/gopl-dev/server/app/service"
 	"github.com/gopl-dev/server/tracing"
 )
 
+var (
+	errUserNotFound = errors.New("user not found")
+)
+
 var (
 	onceServices     sync.Once
 	onceDB           sync.Once
 	onceRepo         sync.Once
 	servicesInstance *service.Service
+	servicesErr      error
 	dbInstance       *app.DB
 	repoInstance     *repo.Repo
 )
@@ -32,12 +39,12 @@ func db() *app.DB {
 	return dbInstance
 }
 
-func services() *service.Service {
+func services() (*service.Service, error) {
 	onceServices.Do(func() {
-		servicesInstance = service.New(db(), tracing.NewNoOpTracer())
+		servicesInstance, servicesErr = service.New(db(), tracing.NewNoOpTracer())
 	})
 
-	return servicesInstance
+	return servicesInstance, servicesErr
 }
 
 func repos() *repo.Repo {
@@ -54,3 +61,18 @@ func CloseDB() {
 		dbInstance.Close()
 	}
 }
+
+// findUser returns the user by ID or email.
+func findUser(ctx context.Context, idOrEmail string) (user *ds.User, err error) {
+	id, idErr := ds.ParseID(idOrEmail)
+	if idErr == nil {
+		user, err = repos().GetUserByID(ctx, id)
+	} else {
+		user, err = repos().GetUserByEmail(ctx, idOrEmail)
+	}
+	if errors.Is(err, repo.ErrUserNotFound) {
+		return nil, errUserNotFound
+	}
+
+	return user, err
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"time"
+
+	"github.com/gopl-dev/server/cli"
+)
+
+// NewExportUserDataCmd returns a CLI command to export all personal data of a user into a ZIP archive.
+func NewExportUserDataCmd() cli.Command {
+	return cli.Command{
+		Name:  "export_user_data",
+		Alias: "eud",
+		Help: []string{
+			"Exports all personal data of a user into a ZIP archive",
+			"user: ID or email of the user",
+			"-o: Output file (default: <username>-data-export-<date>.zip)",
+		},
+		Handler: &exportUserDataCmd{},
+	}
+}
+
+type exportUserDataCmd struct {
+	User   string  `arg:"user"`
+	Output *string `arg:"-o"`
+}
+
+func (cmd *exportUserDataCmd) Handle(ctx context.Context) (err error) {
+	user, err := findUser(ctx, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	output := user.Username + "-data-export-" + time.Now().Format(time.DateOnly) + ".zip"
+	if cmd.Output != nil {
+		output = cmp.Or(*cmd.Output, output)
+	}
+
+	s, err := services()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+
+	err = s.WriteUserDataArchive(ctx, user.ID, f)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(output)
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	cli.OK("Data of %s exported to %s", user.Username, output)
+	return nil
+}
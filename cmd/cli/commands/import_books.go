@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/cli"
+)
+
+var (
+	errNoAdmins          = errors.New("no admins configured, set the user with -u")
+	errUserNotAdmin      = errors.New("user is not an admin")
+	errBookImportFailed  = errors.New("some books failed to import")
+	errUnknownFileFormat = errors.New("can't detect the format by the file extension, set it with -f")
+)
+
+// NewImportBooksCmd returns a CLI command to create books in bulk from a CSV or JSON file.
+func NewImportBooksCmd() cli.Command {
+	return cli.Command{
+		Name:  "import_books",
+		Alias: "ib",
+		Help: []string{
+			"Creates books in bulk from a CSV or JSON file, or a list of ISBNs (.txt, an ISBN per line)",
+			"file: Path of the file. The CSV file starts with the header row naming the columns: " +
+				strings.Join(service.BookImportColumns, ", ") + ". " +
+				"Authors and topics are separated with '" + service.BookImportListSeparator + "', " +
+				"an author may have a link: 'Name <https://...>'. Cover is a URL or a path of a local file. " +
+				"The fields left empty are filled from the metadata of the book found by ISBN",
+			"-u: ID or email of the admin the books are created by (default: the first configured admin)",
+			"-f: Format of the file, csv, json or isbn (default: by the file extension)",
+			"-t: Topics of the books that have none, separated with '" + service.BookImportListSeparator + "'",
+			"--dry-run: Validate the books and show what would be created, without creating anything",
+		},
+		Handler: &importBooksCmd{},
+	}
+}
+
+type importBooksCmd struct {
+	File   string  `arg:"file"`
+	User   *string `arg:"-u"`
+	Format *string `arg:"-f"`
+	Topics *string `arg:"-t"`
+	DryRun bool    `arg:"--dry-run"`
+}
+
+func (cmd *importBooksCmd) Handle(ctx context.Context) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(cmd.File)), ".")
+	if format == "txt" {
+		format = service.BookImportISBNList
+	}
+	if cmd.Format != nil && *cmd.Format != "" {
+		format = *cmd.Format
+	}
+	if !slices.Contains([]string{service.BookImportCSV, service.BookImportJSON, service.BookImportISBNList}, format) {
+		return errUnknownFileFormat
+	}
+
+	user, err := cmd.findAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(cmd.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := service.ParseBookImport(f, format)
+	if err != nil {
+		return err
+	}
+
+	// relative paths of local covers are relative to the file
+	for i, row := range rows {
+		if row.Cover != "" && !strings.Contains(row.Cover, "://") && !filepath.IsAbs(row.Cover) {
+			rows[i].Cover = filepath.Join(filepath.Dir(cmd.File), row.Cover)
+		}
+	}
+
+	var topics []string
+	if cmd.Topics != nil {
+		for t := range strings.SplitSeq(*cmd.Topics, service.BookImportListSeparator) {
+			if t = strings.TrimSpace(t); t != "" {
+				topics = append(topics, t)
+			}
+		}
+	}
+
+	s, err := services()
+	if err != nil {
+		return err
+	}
+
+	report, err := s.ImportBooks(user.ToContext(ctx), service.ImportBooksArgs{
+		Rows:             rows,
+		DryRun:           cmd.DryRun,
+		AllowLocalCovers: true,
+		Topics:           topics,
+	})
+	if err != nil {
+		return err
+	}
+
+	printBookImportReport(report)
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%w: %d of %d", errBookImportFailed, report.Failed, len(report.Rows))
+	}
+
+	if report.DryRun {
+		cli.OK("%d book(s) would be created, %d duplicate(s) skipped", report.Valid, report.Duplicates)
+	} else {
+		cli.OK("%d book(s) created, %d duplicate(s) skipped", report.Created, report.Duplicates)
+	}
+
+	return nil
+}
+
+// findAdmin returns the user the books are created by.
+func (cmd *importBooksCmd) findAdmin(ctx context.Context) (*ds.User, error) {
+	admins := app.Config().Admins
+
+	idOrEmail := ""
+	if cmd.User != nil {
+		idOrEmail = *cmd.User
+	}
+	if idOrEmail == "" {
+		if len(admins) == 0 {
+			return nil, errNoAdmins
+		}
+
+		idOrEmail = admins[0]
+	}
+
+	user, err := findUser(ctx, idOrEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	user.IsAdmin = slices.Contains(admins, user.ID.String())
+	if !user.IsAdmin {
+		return nil, fmt.Errorf("%w: %s", errUserNotAdmin, user.Username)
+	}
+
+	return user, nil
+}
+
+func printBookImportReport(report *ds.BookImportReport) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	_, _ = fmt.Fprintln(tw, "ROW\tSTATUS\tTITLE\tDETAILS")
+
+	for _, r := range report.Rows {
+		details := r.PublicID
+		if r.Error != "" {
+			details = r.Error
+		}
+
+		keys := make([]string, 0, len(r.InputErrors))
+		for k := range r.InputErrors {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			details += "; " + k + ": " + r.InputErrors[k]
+		}
+
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", r.Row, r.Status, r.Title, strings.TrimPrefix(details, "; "))
+	}
+
+	_ = tw.Flush()
+}
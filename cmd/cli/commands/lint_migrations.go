@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/cli"
+)
+
+var errRiskyMigrations = errors.New("risky migrations found")
+
+// NewLintMigrationsCmd returns a CLI command to check migrations for operations unsafe on a live database.
+func NewLintMigrationsCmd() cli.Command {
+	return cli.Command{
+		Name:  "lint_migrations",
+		Alias: "lmg",
+		Help: []string{
+			"Checks migrations for operations that lock tables for long: " +
+				"non-concurrent indexes, SET NOT NULL, table rewrites, constraint validation, backfills in DDL transactions",
+			"file: Path of a migration file to check (omit to check pending migrations)",
+			"--all: Check all migrations, including applied ones (no database needed)",
+		},
+		Handler: &lintMigrationsCmd{},
+	}
+}
+
+type lintMigrationsCmd struct {
+	File *string `arg:"file"`
+	All  bool    `arg:"--all"`
+}
+
+func (cmd *lintMigrationsCmd) Handle(ctx context.Context) error {
+	var (
+		issues []app.MigrationIssue
+		err    error
+	)
+
+	switch {
+	case cmd.File != nil && *cmd.File != "":
+		var src []byte
+		src, err = os.ReadFile(*cmd.File)
+		if err != nil {
+			return err
+		}
+
+		issues, err = app.LintMigrationFile(*cmd.File, string(src))
+	case cmd.All:
+		issues, err = app.LintAllMigrations()
+	default:
+		issues, err = app.LintMigrations(ctx, db())
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == 0 {
+		cli.OK("No issues found")
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	return fmt.Errorf("%w: %d issue(s)", errRiskyMigrations, len(issues))
+}
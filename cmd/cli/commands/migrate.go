@@ -2,38 +2,108 @@ package commands
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/cli"
 )
 
+var errUnknownMigrateMode = errors.New("unknown mode, expected one of: up, status, down, redo")
+
 // NewMigrateCmd ...
 func NewMigrateCmd() cli.Command {
 	return cli.Command{
 		Name:  "migrate",
 		Alias: "mg",
 		Help: []string{
-			"Migrate database to latest version (if new migrations available)",
+			"Migrate database to latest version (if new migrations available), show status or roll back migrations",
+			"mode: up (apply pending migrations), status (list applied and pending migrations), " +
+				"down (roll back the latest migrations), redo (roll back the latest migration and apply it again)",
+			"n: Number of migrations to roll back in 'down' mode",
+			"--dry-run: Print the SQL instead of executing it",
 		},
-		Handler: migrateCmd{},
+		Handler: &migrateCmd{},
 	}
 }
 
-type migrateCmd struct{}
+type migrateCmd struct {
+	Mode   *string `arg:"mode" default:"up"`
+	N      *int    `arg:"n" default:"1"`
+	DryRun bool    `arg:"--dry-run"`
+}
 
-func (migrateCmd) Handle(ctx context.Context) (err error) {
+func (cmd *migrateCmd) Handle(ctx context.Context) (err error) {
 	db, err := app.NewDB(ctx)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 	defer db.Close()
 
-	err = app.MigrateDB(ctx, db)
+	m := app.NewMigrator(db)
+	m.DryRun = cmd.DryRun
+
+	switch *cmd.Mode {
+	case "up":
+		return m.Up(ctx)
+	case "status":
+		return printMigrationStatus(ctx, m)
+	case "down":
+		return m.Down(ctx, *cmd.N)
+	case "redo":
+		return m.Redo(ctx)
+	default:
+		return fmt.Errorf("%w, got '%s'", errUnknownMigrateMode, *cmd.Mode)
+	}
+}
+
+func printMigrationStatus(ctx context.Context, m *app.Migrator) error {
+	statuses, err := m.Status(ctx)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tDOWN")
+
+	pending := 0
+	for _, s := range statuses {
+		status := "pending"
+		appliedAt := ""
+		if s.AppliedAt != nil {
+			status = "applied"
+			appliedAt = s.AppliedAt.Local().Format(time.DateTime)
+		}
+
+		switch {
+		case s.Missing:
+			status += ", file missing"
+		case s.Modified:
+			status += ", modified"
+		case s.AppliedAt == nil:
+			pending++
+		}
+
+		down := "no"
+		if s.HasDown {
+			down = "yes"
+		}
+
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt, down)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if pending > 0 {
+		cli.Info("%d pending migration(s)", pending)
+	} else {
+		cli.OK("Database is up to date")
 	}
 
 	return nil
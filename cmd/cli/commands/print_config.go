@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// NewPrintConfigCmd returns a CLI command to print the effective config.
+func NewPrintConfigCmd() cli.Command {
+	return cli.Command{
+		Name:  "config",
+		Alias: "cfg",
+		Help: []string{
+			"Prints the effective config (the config file with environment overrides applied), secrets are redacted",
+		},
+		Handler: printConfigCmd{},
+	}
+}
+
+type printConfigCmd struct{}
+
+func (printConfigCmd) Handle(_ context.Context) error {
+	out, err := yaml.Marshal(app.Config().Redacted())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(out))
+	return nil
+}
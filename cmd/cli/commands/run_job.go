@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/cli"
+	"github.com/gopl-dev/server/worker"
+)
+
+// NewRunJobCmd returns a CLI command to run a background job once.
+func NewRunJobCmd() cli.Command {
+	return cli.Command{
+		Name:  "run_job",
+		Alias: "rj",
+		Help: []string{
+			"Runs a background job once; the run is recorded in the job history",
+			"Fails if the job is being run by a scheduler at the moment",
+			"name: Name of the job (omit to list jobs)",
+		},
+		Handler: &runJobCmd{},
+	}
+}
+
+type runJobCmd struct {
+	Name *string `arg:"name"`
+}
+
+func (cmd *runJobCmd) Handle(ctx context.Context) error {
+	if cmd.Name == nil || *cmd.Name == "" {
+		names := make([]string, 0, len(worker.Jobs()))
+		for _, j := range worker.Jobs() {
+			names = append(names, j.Name())
+		}
+
+		cli.Info("Jobs:\n  %s", strings.Join(names, "\n  "))
+		return nil
+	}
+
+	j, err := worker.FindJob(strings.ToUpper(*cmd.Name))
+	if err != nil {
+		return err
+	}
+
+	s, err := services()
+	if err != nil {
+		return err
+	}
+
+	err = s.RegisterWorkerJobs(ctx, []string{j.Name()})
+	if err != nil {
+		return err
+	}
+
+	// the job must not run concurrently with the run of a scheduler
+	locker := worker.NewLocker(db())
+	defer locker.Close()
+
+	_, err = locker.Lock(ctx, j.Name())
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = worker.Run(locker.Context(ctx, j.Name()), s, db(), j, ds.WorkerJobTriggerCLI)
+	if err != nil {
+		return err
+	}
+
+	cli.OK("%s done in %s", j.Name(), time.Since(start).Round(time.Millisecond))
+	return nil
+}
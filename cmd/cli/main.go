@@ -7,15 +7,26 @@ import (
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/cli"
 	"github.com/gopl-dev/server/cmd/cli/commands"
+	"github.com/gopl-dev/server/logging"
 )
 
 func main() {
 	conf := app.Config()
+	err := logging.Setup(conf.Log.Level, conf.Log.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	cliApp := cli.NewApp(conf.App.Name, conf.App.Env)
 
 	// Register core commands available in all environments
-	err := cliApp.Register(
+	err = cliApp.Register(
 		commands.NewMigrateCmd(),
+		commands.NewLintMigrationsCmd(),
+		commands.NewExportUserDataCmd(),
+		commands.NewImportBooksCmd(),
+		commands.NewRunJobCmd(),
+		commands.NewPrintConfigCmd(),
 
 		// Uncomment to play with this demo commands
 		// cli.NewSampleCommandWithSignatureCmd(),
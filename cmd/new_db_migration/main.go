@@ -9,6 +9,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/gopl-dev/server/app"
 )
 
 const privateFileMode os.FileMode = 0600
@@ -34,7 +36,8 @@ func newMigration(args []string) (err error) {
 	}
 
 	name = time.Now().UTC().Format("20060102150405") + "_" + name + ".sql"
-	mg := resolveSampleMg(name)
+	up, down := resolveSampleMg(name)
+	mg := up + "\n\n" + app.MigrationDownMarker + "\n" + down
 
 	err = os.WriteFile("app/db_migrations/"+name, []byte(mg+"\n"), privateFileMode) //nolint:gosec
 	if err != nil {
@@ -75,31 +78,40 @@ const sampleCreateTableMg = `-- CREATE TABLE my_table
 -- CREATE INDEX  my_table_project_id_idx ON my_table (project_id);
 `
 
+const sampleDropTableMg = `-- DROP TABLE my_table;`
+
 const sampleAddColMg = `-- ALTER TABLE ? ADD COLUMN ? TYPE;`
 const sampleDropColMg = `-- ALTER TABLE ? DROP COLUMN ?;`
-const sampleIndexMg = `-- CREATE INDEX  ?_idx ON ? (?);`
+
+// Indexes on existing tables are built concurrently to not block writes,
+// which requires the migration to run outside a transaction.
+const sampleIndexMg = app.MigrationNoTxDirective + `
+-- CREATE INDEX CONCURRENTLY IF NOT EXISTS ?_idx ON ? (?);`
+const sampleDropIndexMg = `-- DROP INDEX CONCURRENTLY IF EXISTS ?_idx;`
 const sampleRenameMg = `-- ALTER TABLE ? RENAME COLUMN ? TO ?;
 -- ALTER TABLE ? RENAME TO ?;`
 
-func resolveSampleMg(name string) string {
+// resolveSampleMg returns the commented samples of the up and down sections
+// of the migration, guessed by its name.
+func resolveSampleMg(name string) (up, down string) {
 	if strings.Contains(name, "create_table") {
-		return sampleCreateTableMg
+		return sampleCreateTableMg, sampleDropTableMg
 	}
 
 	if strings.Contains(name, "rename") {
-		return sampleRenameMg
+		return sampleRenameMg, sampleRenameMg
 	}
 
 	if strings.Contains(name, "drop") {
-		return sampleDropColMg
+		return sampleDropColMg, sampleAddColMg
 	}
 
 	if strings.Contains(name, "add") {
-		return sampleAddColMg
+		return sampleAddColMg, sampleDropColMg
 	}
 
 	if strings.Contains(name, "index") {
-		return sampleIndexMg
+		return sampleIndexMg, sampleDropIndexMg
 	}
 
 	all := []string{
@@ -109,5 +121,12 @@ func resolveSampleMg(name string) string {
 		sampleRenameMg,
 	}
 
-	return strings.Join(all, "\n\n")
+	allDown := []string{
+		sampleDropTableMg,
+		sampleDropColMg,
+		sampleAddColMg,
+		sampleRenameMg,
+	}
+
+	return strings.Join(all, "\n\n"), strings.Join(allDown, "\n\n")
 }
@@ -5,22 +5,32 @@ import (
 	"context"
 	"errors"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/service"
 	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/health"
+	"github.com/gopl-dev/server/logging"
+	"github.com/gopl-dev/server/metrics"
 	"github.com/gopl-dev/server/server"
 	"github.com/gopl-dev/server/tracing"
 	"github.com/gopl-dev/server/worker"
 )
 
 func main() {
-	_ = file.Storage()
 	conf := app.Config()
+	err := logging.Setup(conf.Log.Level, conf.Log.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = file.Storage()
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	tracer, err := tracing.New(ctx)
 	if err != nil {
@@ -53,19 +63,58 @@ func main() {
 
 	defer db.Close() // log.Fatal will exit, and `defer db.Close()` will not run (gocritic)
 
-	services := service.New(db, tracer)
+	services, err := service.New(db, tracer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	srv := server.New(services, tracer)
 
+	err = metrics.RegisterDB(db, services)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	metricsSrv := metrics.NewServer()
+	if metricsSrv != nil {
+		go func() {
+			slog.Info("metrics serving", "addr", metricsSrv.Addr, "path", metrics.Path)
+
+			err := metricsSrv.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics ListenAndServe", "error", err)
+			}
+		}()
+	}
+
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		<-quit
-		cancelCtx()
-		err := srv.Close()
+
+		// fail readiness first, so the load balancer stops sending traffic
+		// before the server stops accepting connections
+		health.StartDraining()
+		drain := time.Duration(conf.Server.ShutdownDrainSeconds) * time.Second
+		slog.Info("draining", "duration", drain)
+		time.Sleep(drain)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout)
+		defer cancel()
+
+		err := srv.Shutdown(shutdownCtx)
 		if err != nil {
-			log.Fatal(err)
+			slog.Error("shutdown", "error", err)
+		}
+
+		if metricsSrv != nil {
+			_ = metricsSrv.Close()
 		}
+
+		cancelCtx()
 	}()
 
-	log.Println(conf.App.Name + " (" + conf.App.Version + ") serving at " + conf.Server.Host + ":" + conf.Server.Port)
+	slog.Info("serving", "app", conf.App.Name, "version", conf.App.Version, "host", conf.Server.Host, "port", conf.Server.Port)
 
 	if conf.Server.AutocertHosts != "" {
 		err = srv.ListenAndServeTLS("", "")
@@ -73,9 +122,12 @@ func main() {
 		err = srv.ListenAndServe()
 	}
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Println(err.Error())
+		slog.Error("serve", "error", err)
 		return
 	}
 
-	log.Println(conf.App.Name + " (" + conf.App.Version + ") server closed")
+	// ListenAndServe returns as soon as Shutdown is called, wait for in-flight requests
+	<-shutdownDone
+
+	slog.Info("server closed", "app", conf.App.Name, "version", conf.App.Version)
 }
@@ -738,13 +738,14 @@ func writeTestConfigs(m swModel, src []byte) error {
 	}
 
 	dbVals := stepDBCredentials.Values(m.states[stepIdxDBCreds])
-	vals := make([]yamlValue, 0, len(dbVals)+4) //nolint:mnd
+	vals := make([]yamlValue, 0, len(dbVals)+5) //nolint:mnd
 	vals = append(vals, dbVals...)
 	vals = append(vals,
 		yv("db", "name", testDBName),
 		yv("email", "driver", "test"),
 		yv("tracing", "enabled", false),
 		yv("files", "storage_driver", "in-memory-fs"),
+		yv("book_metadata", "driver", "fixture"),
 	)
 
 	err = applyValues(&doc, vals)
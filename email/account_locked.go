@@ -0,0 +1,34 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/gopl-dev/server/app"
+)
+
+// AccountLocked ...
+type AccountLocked struct {
+	Username string
+	Attempts int
+	Minutes  int
+}
+
+// Subject ...
+func (p AccountLocked) Subject() string {
+	return "Your Account Was Temporarily Locked"
+}
+
+// TemplateName ...
+func (p AccountLocked) TemplateName() string {
+	return "account_locked"
+}
+
+// Variables ...
+func (p AccountLocked) Variables() map[string]any {
+	return map[string]any{
+		"Username":      p.Username,
+		"Attempts":      p.Attempts,
+		"Minutes":       p.Minutes,
+		"PasswordReset": fmt.Sprintf("%spassword-reset/", app.Config().Server.Addr),
+	}
+}
@@ -3,10 +3,12 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"embed"
 	"errors"
 	"fmt"
 	"html/template"
+	"log/slog"
 	"sync"
 
 	"github.com/gopl-dev/server/app"
@@ -15,6 +17,9 @@ import (
 var (
 	// ErrInvalidDriver indicates that the configured email driver name is not recognized or supported.
 	ErrInvalidDriver = errors.New("invalid email driver")
+
+	// ErrMissingConfig indicates that a setting required by the configured email driver is empty.
+	ErrMissingConfig = errors.New("missing email config")
 )
 
 var initDriverOnce sync.Once
@@ -34,7 +39,7 @@ var templates = template.Must(template.ParseFS(templateFiles, "*.html"))
 
 // Sender is the interface defining the capability to send an email message.
 type Sender interface {
-	Send(to string, c Composer) error
+	Send(ctx context.Context, to string, c Composer) error
 }
 
 // Composer is the interface defining the components required to compose a full email message.
@@ -45,7 +50,7 @@ type Composer interface {
 }
 
 // Send initializes the appropriate email driver (if not already done) and dispatches the email.
-func Send(to string, c Composer) (err error) {
+func Send(ctx context.Context, to string, c Composer) (err error) {
 	initDriverOnce.Do(func() {
 		conf := app.Config().Email
 		switch conf.Driver {
@@ -62,7 +67,35 @@ func Send(to string, c Composer) (err error) {
 		return
 	}
 
-	return driver.Send(to, c)
+	err = driver.Send(ctx, to, c)
+	if err != nil {
+		slog.ErrorContext(ctx, "send email", "template", c.TemplateName(), "error", err)
+	}
+
+	return err
+}
+
+// CheckConfig verifies that the configured driver is known and has the settings it requires.
+// It doesn't connect to the mail server.
+func CheckConfig() error {
+	conf := app.Config().Email
+
+	switch conf.Driver {
+	case SMTPDriver:
+		switch {
+		case conf.From == "":
+			return fmt.Errorf("from: %w", ErrMissingConfig)
+		case conf.SMTP.Host == "":
+			return fmt.Errorf("smtp.host: %w", ErrMissingConfig)
+		case conf.SMTP.Port == 0:
+			return fmt.Errorf("smtp.port: %w", ErrMissingConfig)
+		}
+	case TestDriver:
+	default:
+		return fmt.Errorf("driver '%s': %w", conf.Driver, ErrInvalidDriver)
+	}
+
+	return nil
 }
 
 // TemplateData represents the data that passed to the base email layout template.
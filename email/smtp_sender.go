@@ -1,10 +1,12 @@
 package email
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/metrics"
 	"github.com/wneessen/go-mail"
 )
 
@@ -39,12 +41,13 @@ func NewSMTPSender() (*SMTPSender, error) {
 //
 // In production environment, the email is sent asynchronously in a goroutine
 // and any send error is logged but not returned to the caller.
-func (s *SMTPSender) Send(to string, c Composer) (err error) {
+func (s *SMTPSender) Send(ctx context.Context, to string, c Composer) (err error) {
 	if app.Config().IsProductionEnv() {
+		ctx = context.WithoutCancel(ctx)
 		go func() {
-			err = s.send(to, c)
+			err := s.send(to, c)
 			if err != nil {
-				log.Println("SMTP send error:", err)
+				slog.ErrorContext(ctx, "send email", "template", c.TemplateName(), "error", err)
 			}
 		}()
 		return nil
@@ -55,6 +58,10 @@ func (s *SMTPSender) Send(to string, c Composer) (err error) {
 
 // send composes and dispatches an email message to the specified recipient using the configured SMTP server.
 func (s *SMTPSender) send(to string, c Composer) (err error) {
+	defer func() {
+		metrics.EmailSent(c.TemplateName(), err)
+	}()
+
 	body, err := renderTemplate(c)
 	if err != nil {
 		return err
@@ -78,9 +85,7 @@ func (s *SMTPSender) send(to string, c Composer) (err error) {
 
 	err = s.client.DialAndSend(message)
 	if err != nil {
-		log.Printf("failed to deliver mail: %s", err)
-
-		return err
+		return fmt.Errorf("deliver email: %w", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package email
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -30,7 +31,7 @@ type TestSender struct {
 
 // Send records the email and its recipient into the in-memory store.
 // In a test environment, this method returns nil to simulate a successful send operation.
-func (t *TestSender) Send(to string, c Composer) (err error) {
+func (t *TestSender) Send(_ context.Context, to string, c Composer) (err error) {
 	t.emails.Store(to, c)
 
 	return nil
@@ -0,0 +1,37 @@
+package email
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+)
+
+// UserDataExportReady ...
+type UserDataExportReady struct {
+	Username  string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Subject ...
+func (p UserDataExportReady) Subject() string {
+	return "Your Data Export Is Ready"
+}
+
+// TemplateName ...
+func (p UserDataExportReady) TemplateName() string {
+	return "user_data_export_ready"
+}
+
+// Variables ...
+func (p UserDataExportReady) Variables() map[string]any {
+	return map[string]any{
+		"Username":  p.Username,
+		"Link":      fmt.Sprintf("%sdata-export/%s/", app.Config().Server.Addr, p.Token),
+		"ExpiresAt": p.ExpiresAt.Format("January 2, 2006 15:04 MST"),
+
+		// Token var is not used in email template, it is here to make testing easier.
+		"token": p.Token,
+	}
+}
@@ -0,0 +1,342 @@
+// Package feed renders syndication feeds in Atom, RSS 2.0 and JSON Feed formats
+// from a single format-agnostic model.
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// Format identifies the syndication format of a feed.
+type Format string
+
+const (
+	// Atom is the Atom 1.0 format (RFC 4287).
+	Atom Format = "atom"
+
+	// RSS is the RSS 2.0 format.
+	RSS Format = "rss"
+
+	// JSON is the JSON Feed 1.1 format.
+	JSON Format = "json"
+)
+
+// Formats lists all supported formats, in order of preference.
+var Formats = []Format{Atom, RSS, JSON}
+
+// ErrInvalidFormat is returned when the feed format is not supported.
+var ErrInvalidFormat = errors.New("invalid feed format")
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case Atom:
+		return "application/atom+xml"
+	case RSS:
+		return "application/rss+xml"
+	case JSON:
+		return "application/feed+json"
+	}
+
+	return ""
+}
+
+// Ext returns the file extension of the format, without the dot.
+func (f Format) Ext() string {
+	return string(f)
+}
+
+// Valid reports whether the format is supported.
+func (f Format) Valid() bool {
+	return f.ContentType() != ""
+}
+
+// Feed is a format-agnostic feed.
+// All URLs must be absolute.
+type Feed struct {
+	Title       string
+	Description string
+
+	// Link is the URL of the HTML page the feed is about.
+	Link string
+
+	// FeedURL is the URL of the feed itself.
+	FeedURL string
+
+	// Updated is the last time any item of the feed was changed.
+	Updated time.Time
+
+	Items []Item
+}
+
+// Item is a single entry of the feed.
+type Item struct {
+	// ID is a permanent, unique identifier of the item.
+	ID    string
+	Title string
+	Link  string
+
+	// Content is the HTML body of the item.
+	Content string
+
+	Author     string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// Encode renders the feed in the given format.
+func (f *Feed) Encode(format Format) ([]byte, error) {
+	switch format {
+	case Atom:
+		return f.atom()
+	case RSS:
+		return f.rss()
+	case JSON:
+		return f.json()
+	}
+
+	return nil, ErrInvalidFormat
+}
+
+// LastUpdated returns the latest update time of the items,
+// to be used as Updated of the feed.
+func LastUpdated(items []Item) (t time.Time) {
+	for _, it := range items {
+		if it.updated().After(t) {
+			t = it.updated()
+		}
+	}
+
+	return
+}
+
+func (it Item) updated() time.Time {
+	if it.Updated.IsZero() {
+		return it.Published
+	}
+
+	return it.Updated
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Summary string      `xml:"subtitle,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomContent   `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (f *Feed) atom() ([]byte, error) {
+	feed := atomFeed{
+		Title:   f.Title,
+		ID:      f.FeedURL,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Summary: f.Description,
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedURL, Rel: "self", Type: Atom.ContentType()},
+		},
+		Entries: make([]atomEntry, len(f.Items)),
+	}
+
+	for i, it := range f.Items {
+		e := atomEntry{
+			Title:   it.Title,
+			ID:      it.ID,
+			Link:    atomLink{Href: it.Link, Rel: "alternate", Type: "text/html"},
+			Updated: it.updated().UTC().Format(time.RFC3339),
+		}
+
+		if !it.Published.IsZero() {
+			e.Published = it.Published.UTC().Format(time.RFC3339)
+		}
+
+		if it.Author != "" {
+			e.Author = &atomAuthor{Name: it.Author}
+		}
+
+		for _, c := range it.Categories {
+			e.Categories = append(e.Categories, atomCategory{Term: c})
+		}
+
+		if it.Content != "" {
+			e.Content = &atomContent{Type: "html", Body: it.Content}
+		}
+
+		feed.Entries[i] = e
+	}
+
+	return marshalXML(feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Author      string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (f *Feed) rss() ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			AtomLink:      atomLink{Href: f.FeedURL, Rel: "self", Type: RSS.ContentType()},
+			Items:         make([]rssItem, len(f.Items)),
+		},
+	}
+
+	for i, it := range f.Items {
+		pubDate := it.Published
+		if pubDate.IsZero() {
+			pubDate = it.Updated
+		}
+
+		feed.Channel.Items[i] = rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			GUID:        rssGUID{IsPermaLink: it.ID == it.Link, Value: it.ID},
+			PubDate:     pubDate.UTC().Format(time.RFC1123Z),
+			Author:      it.Author,
+			Categories:  it.Categories,
+			Description: it.Content,
+		}
+	}
+
+	return marshalXML(feed)
+}
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func (f *Feed) json() ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       make([]jsonItem, len(f.Items)),
+	}
+
+	for i, it := range f.Items {
+		item := jsonItem{
+			ID:          it.ID,
+			URL:         it.Link,
+			Title:       it.Title,
+			ContentHTML: it.Content,
+			Tags:        it.Categories,
+		}
+
+		if !it.Published.IsZero() {
+			item.DatePublished = it.Published.UTC().Format(time.RFC3339)
+		}
+
+		if !it.Updated.IsZero() {
+			item.DateModified = it.Updated.UTC().Format(time.RFC3339)
+		}
+
+		if it.Author != "" {
+			item.Authors = []jsonAuthor{{Name: it.Author}}
+		}
+
+		feed.Items[i] = item
+	}
+
+	return json.MarshalIndent(feed, "", "  ")
+}
+
+func marshalXML(v any) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
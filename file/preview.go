@@ -9,6 +9,7 @@ import (
 	"image/jpeg"
 	_ "image/png" // register PNG decoder for image.Decode
 	"io"
+	"log/slog"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -101,7 +102,7 @@ func CreatePreviewCustom(ctx context.Context, srcKey, dstKey string, maxW, maxH
 	defer func() {
 		closeErr := rc.Close()
 		if closeErr != nil {
-			fmt.Println("CLOSE FILE ERROR:", closeErr)
+			slog.ErrorContext(ctx, "close file", "error", closeErr)
 		}
 	}()
 
@@ -117,16 +118,21 @@ func CreatePreviewCustom(ctx context.Context, srcKey, dstKey string, maxW, maxH
 	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
 	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
 
+	return storeJPEG(ctx, dst, dstKey, quality)
+}
+
+// storeJPEG encodes the image as JPEG and stores it under dstKey.
+func storeJPEG(ctx context.Context, img image.Image, dstKey string, quality int) error {
 	pr, pw := io.Pipe()
 
 	encodeErrCh := make(chan error, 1)
 	go func() {
-		errEncode := jpeg.Encode(pw, dst, &jpeg.Options{Quality: quality})
+		errEncode := jpeg.Encode(pw, img, &jpeg.Options{Quality: quality})
 		_ = pw.CloseWithError(errEncode)
 		encodeErrCh <- errEncode
 	}()
 
-	_, err = Store(ctx, pr, dstKey)
+	_, err := Store(ctx, pr, dstKey)
 	if err != nil {
 		_ = pr.Close()
 		<-encodeErrCh
@@ -0,0 +1,193 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// SocialPreviewWidth is the width of the social preview image,
+	// as recommended for OpenGraph and Twitter cards.
+	SocialPreviewWidth = 1200
+
+	// SocialPreviewHeight is the height of the social preview image.
+	SocialPreviewHeight = 630
+
+	socialPreviewPadding     = 60
+	socialPreviewTitleMax    = 4 // max lines of the title
+	socialPreviewSubtitleMax = 2 // max lines of the subtitle
+)
+
+var (
+	socialPreviewBg      = color.RGBA{R: 0x4b, G: 0x55, B: 0x63, A: 0xff}
+	socialPreviewFg      = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	socialPreviewMutedFg = color.RGBA{R: 0xd1, G: 0xd5, B: 0xdb, A: 0xff}
+)
+
+// SocialPreview describes the content of the social preview image.
+type SocialPreview struct {
+	// Cover is the storage key of an image drawn on the left side. Optional.
+	Cover string
+
+	Title    string
+	Subtitle string
+
+	// Caption is printed in the bottom right corner, e.g. the name of the site.
+	Caption string
+}
+
+// CreateSocialPreview composes the image shown when a link is shared (OpenGraph, Twitter card)
+// and stores it as JPEG under dstKey.
+func CreateSocialPreview(ctx context.Context, p SocialPreview, dstKey string) error {
+	dst := image.NewRGBA(image.Rect(0, 0, SocialPreviewWidth, SocialPreviewHeight))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(socialPreviewBg), image.Point{}, draw.Src)
+
+	textX := socialPreviewPadding
+	if p.Cover != "" {
+		coverW, err := drawSocialPreviewCover(ctx, dst, p.Cover)
+		if err != nil {
+			return fmt.Errorf("draw cover: %w", err)
+		}
+
+		textX += coverW + socialPreviewPadding
+	}
+
+	titleFace, err := newFace(gobold.TTF, 56) //nolint:mnd
+	if err != nil {
+		return err
+	}
+	defer titleFace.Close()
+
+	textFace, err := newFace(goregular.TTF, 32) //nolint:mnd
+	if err != nil {
+		return err
+	}
+	defer textFace.Close()
+
+	maxW := SocialPreviewWidth - socialPreviewPadding - textX
+	y := socialPreviewPadding
+
+	lines := wrapText(titleFace, p.Title, maxW, socialPreviewTitleMax)
+	for _, line := range lines {
+		y += titleFace.Metrics().Height.Ceil()
+		drawText(dst, titleFace, socialPreviewFg, textX, y, line)
+	}
+
+	if p.Subtitle != "" {
+		y += socialPreviewPadding / 2 //nolint:mnd
+		for _, line := range wrapText(textFace, p.Subtitle, maxW, socialPreviewSubtitleMax) {
+			y += textFace.Metrics().Height.Ceil()
+			drawText(dst, textFace, socialPreviewMutedFg, textX, y, line)
+		}
+	}
+
+	if p.Caption != "" {
+		w := font.MeasureString(textFace, p.Caption).Ceil()
+		drawText(dst, textFace, socialPreviewMutedFg,
+			SocialPreviewWidth-socialPreviewPadding-w, SocialPreviewHeight-socialPreviewPadding, p.Caption)
+	}
+
+	return storeJPEG(ctx, dst, dstKey, 85) //nolint:mnd
+}
+
+// drawSocialPreviewCover draws the cover, vertically centered, on the left side of dst
+// and returns its width.
+func drawSocialPreviewCover(ctx context.Context, dst *image.RGBA, cover string) (int, error) {
+	rc, _, err := Open(ctx, cover)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		closeErr := rc.Close()
+		if closeErr != nil {
+			slog.ErrorContext(ctx, "close file", "error", closeErr)
+		}
+	}()
+
+	img, _, err := image.Decode(rc)
+	if err != nil {
+		return 0, err
+	}
+
+	maxH := SocialPreviewHeight - 2*socialPreviewPadding //nolint:mnd
+	b := img.Bounds()
+	w, h := fit(b.Dx(), b.Dy(), SocialPreviewWidth/3, maxH) //nolint:mnd
+
+	top := (SocialPreviewHeight - h) / 2 //nolint:mnd
+	rect := image.Rect(socialPreviewPadding, top, socialPreviewPadding+w, top+h)
+	draw.CatmullRom.Scale(dst, rect, img, b, draw.Over, nil)
+
+	return w, nil
+}
+
+func newFace(ttf []byte, size float64) (font.Face, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72, //nolint:mnd
+		Hinting: font.HintingFull,
+	})
+}
+
+func drawText(dst *image.RGBA, face font.Face, c color.Color, x, y int, text string) {
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// wrapText breaks the text into lines no wider than maxW.
+// If the text doesn't fit into maxLines, the last line is cut with an ellipsis.
+func wrapText(face font.Face, text string, maxW, maxLines int) (lines []string) {
+	var line string
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+
+		if line != "" && font.MeasureString(face, candidate).Ceil() > maxW {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+
+		line = candidate
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) <= maxLines {
+		return lines
+	}
+
+	lines = lines[:maxLines]
+	last := lines[maxLines-1]
+	for last != "" && font.MeasureString(face, last+"…").Ceil() > maxW {
+		r := []rune(last)
+		last = strings.TrimRight(string(r[:len(r)-1]), " ")
+	}
+	lines[maxLines-1] = last + "…"
+
+	return lines
+}
@@ -0,0 +1,90 @@
+window.dashboardComponents['broken-links'] = function brokenLinksComponent() {
+    return {
+        loading: false,
+        error: null,
+        status: 'broken',
+        links: { data: [], count: 0 },
+        page: 1,
+        perPage: 50,
+
+        // checks modal state
+        selectedLink: null,
+        checksLoading: false,
+        checks: [],
+
+        get totalPages() {
+            return Math.ceil((this.links.count ?? 0) / this.perPage);
+        },
+
+        async init() {
+            await this.loadLinks();
+        },
+
+        async loadLinks() {
+            this.loading = true;
+            this.error = null;
+
+            try {
+                const params = new URLSearchParams({
+                    page: this.page,
+                    per_page: this.perPage,
+                });
+                if (this.status) params.set('status', this.status);
+
+                const resp = await fetch(`/api/external-links/?${params}`);
+                if (!resp.ok) throw new Error(`HTTP error! status: ${resp.status}`);
+
+                const payload = await resp.json();
+                this.links.data = Array.isArray(payload.data) ? payload.data : [];
+                this.links.count = payload.count ?? 0;
+            } catch (e) {
+                console.error('Error loading links:', e);
+                this.error = 'Failed to load links. Please try again.';
+            } finally {
+                this.loading = false;
+            }
+        },
+
+        async changePage(page, force = false) {
+            if (!force && (page < 1 || page > this.totalPages)) return;
+            this.page = page;
+            await this.loadLinks();
+        },
+
+        async openChecks(link) {
+            this.selectedLink = link;
+            this.checks = [];
+            this.$refs.checksModal.showModal();
+            this.checksLoading = true;
+
+            try {
+                const resp = await fetch(`/api/external-links/${link.id}/checks/?per_page=50`);
+                if (!resp.ok) throw new Error(`HTTP error! status: ${resp.status}`);
+
+                const payload = await resp.json();
+                this.checks = Array.isArray(payload.data) ? payload.data : [];
+            } catch (e) {
+                console.error('Error loading checks:', e);
+            } finally {
+                this.checksLoading = false;
+            }
+        },
+
+        sourceURL(s) {
+            return {
+                'book': `/books/${s.public_id}/`,
+                'page': `/${s.public_id}/`,
+                'author': `/authors/${s.public_id}/`,
+            }[s.source_type] || '/';
+        },
+
+        statusClass(status) {
+            return {
+                'ok': 'badge-success',
+                'failing': 'badge-warning',
+                'broken': 'badge-error',
+                'rate_limited': 'badge-info',
+            }[status] || '';
+        },
+    };
+};
@@ -68,7 +68,7 @@ window.dashboardComponents['change-requests'] = function changeRequestsComponent
                                 if (typeof item === 'object' && item !== null) {
                                     return Object.entries(item)
                                         .filter(([key, val]) => val !== null && val !== undefined && val !== '')
-                                        .map(([key, val]) => `${key}: ${val}`)
+                                        .map(([key, val]) => `${key}: ${typeof val === 'object' ? JSON.stringify(val) : val}`)
                                         .join(', ');
                                 }
                                 return String(item);
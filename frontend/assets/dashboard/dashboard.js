@@ -4,7 +4,9 @@ window.dashboardComponents = {
     'new-books': null,
     'book-edits': null,
     'page-edits': null,
-    'log': null
+    'log': null,
+    'workers': null,
+    'broken-links': null
 };
 
 window.formatDate = function (dateString) {
@@ -131,6 +133,8 @@ function dashboardApp() {
                 'home': 'Dashboard',
                 'new-books': 'New Books',
                 'change-requests': 'Change requests',
+                'workers': 'Workers',
+                'broken-links': 'Broken links',
             };
             return titles[route] || 'Dashboard';
         }
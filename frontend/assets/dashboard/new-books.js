@@ -16,6 +16,10 @@ window.dashboardComponents['new-books'] = function newBooksComponent() {
             limit: 20
         },
 
+        // likely duplicates of the books, by book ID
+        duplicates: {},
+        mergeError: null,
+
         async init() {
             await this.loadData();
         },
@@ -38,6 +42,7 @@ window.dashboardComponents['new-books'] = function newBooksComponent() {
                 }
 
                 this.data = await response.json();
+                await this.loadDuplicates();
 
             } catch (error) {
                 console.error('Error loading data:', error);
@@ -47,6 +52,38 @@ window.dashboardComponents['new-books'] = function newBooksComponent() {
             }
         },
 
+        async loadDuplicates() {
+            const books = this.data.data ?? [];
+            const results = await Promise.all(books.map(async (book) => {
+                const response = await fetch(`/api/books/${book.id}/duplicates/`);
+                return response.ok ? response.json() : [];
+            }));
+
+            this.duplicates = Object.fromEntries(books.map((book, i) => [book.id, results[i]]));
+        },
+
+        async merge(book, dup) {
+            if (!confirm(`Merge "${book.title}" into "${dup.title}"? Its topics and links are carried over and it is rejected as a duplicate.`)) {
+                return;
+            }
+
+            this.mergeError = null;
+
+            try {
+                const resp = await fetch(`/api/books/${book.id}/merge/`, {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ into_id: dup.id }),
+                });
+                if (resp.status !== 200) throw new Error(`HTTP error! status: ${resp.status}`);
+
+                await this.loadData();
+            } catch (error) {
+                console.error('Error merging book:', error);
+                this.mergeError = 'Failed to merge the book. Please try again.';
+            }
+        },
+
         async changePage(page) {
             if (page < 1 || page > this.data.total_pages) return;
             this.pagination.page = page;
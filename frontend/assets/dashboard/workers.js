@@ -0,0 +1,121 @@
+window.dashboardComponents['workers'] = function workersComponent() {
+    return {
+        loading: false,
+        error: null,
+        jobs: [],
+        busy: {},
+
+        // runs modal state
+        selectedJob: null,
+        runsLoading: false,
+        runs: { data: [], count: 0 },
+        runsPage: 1,
+        runsPerPage: 20,
+
+        get runsTotalPages() {
+            return Math.ceil((this.runs.count ?? 0) / this.runsPerPage);
+        },
+
+        async init() {
+            await this.loadJobs();
+        },
+
+        async loadJobs() {
+            this.loading = true;
+            this.error = null;
+
+            try {
+                const response = await fetch('/api/worker-jobs/');
+                if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
+
+                const payload = await response.json();
+                this.jobs = Array.isArray(payload) ? payload : [];
+            } catch (error) {
+                console.error('Error loading jobs:', error);
+                this.error = 'Failed to load jobs. Please try again.';
+            } finally {
+                this.loading = false;
+            }
+        },
+
+        jobURL(job, action) {
+            return `/api/worker-jobs/${encodeURIComponent(job.name)}/${action}/`;
+        },
+
+        async runNow(job) {
+            await this.action(job, 'run', 'POST');
+        },
+
+        async togglePause(job) {
+            await this.action(job, job.paused ? 'resume' : 'pause', 'PUT');
+        },
+
+        async action(job, action, method) {
+            this.busy[job.name] = true;
+            this.error = null;
+
+            try {
+                const resp = await fetch(this.jobURL(job, action), { method });
+                if (resp.status !== 200) throw new Error(`HTTP error! status: ${resp.status}`);
+
+                await this.loadJobs();
+            } catch (e) {
+                console.error(`Error on ${action}:`, e);
+                this.error = 'Action failed. Please try again.';
+            } finally {
+                this.busy[job.name] = false;
+            }
+        },
+
+        async openRuns(job) {
+            this.selectedJob = job;
+            this.runsPage = 1;
+            this.$refs.runsModal.showModal();
+
+            await this.loadRuns();
+        },
+
+        async loadRuns() {
+            this.runsLoading = true;
+
+            try {
+                const params = new URLSearchParams({
+                    page: this.runsPage,
+                    per_page: this.runsPerPage,
+                });
+
+                const resp = await fetch(`${this.jobURL(this.selectedJob, 'runs')}?${params}`);
+                if (!resp.ok) throw new Error(`HTTP error! status: ${resp.status}`);
+
+                const payload = await resp.json();
+                this.runs.data = Array.isArray(payload.data) ? payload.data : [];
+                this.runs.count = payload.count ?? 0;
+            } catch (e) {
+                console.error('Error loading runs:', e);
+                this.runs = { data: [], count: 0 };
+            } finally {
+                this.runsLoading = false;
+            }
+        },
+
+        async changeRunsPage(page) {
+            if (page < 1 || page > this.runsTotalPages) return;
+            this.runsPage = page;
+            await this.loadRuns();
+        },
+
+        statusClass(status) {
+            return {
+                'running': 'badge-info',
+                'succeeded': 'badge-success',
+                'failed': 'badge-error',
+                'skipped': 'badge-warning',
+            }[status] || '';
+        },
+
+        formatDuration(ms) {
+            if (ms < 1000) return `${ms} ms`;
+            return `${(ms / 1000).toFixed(1)} s`;
+        },
+    };
+};
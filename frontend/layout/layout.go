@@ -14,4 +14,34 @@ type Data struct {
 	MetaKeywords    string
 	Body            templ.Component
 	User            *frontend.User
+
+	// Feeds are advertised to feed readers via <link rel="alternate">.
+	Feeds []FeedLink
+
+	// Share describes the page for link previews and search engines.
+	Share *Share
+}
+
+// Share holds OpenGraph / Twitter card properties of the page and its schema.org structured data.
+// Title and description of the card are taken from Data.Title and Data.MetaDescription.
+type Share struct {
+	// Type is the OpenGraph type of the object: "website", "book", "article", "profile".
+	Type string
+
+	// URL is the canonical absolute URL of the page.
+	URL string
+
+	// Image is the absolute URL of the preview image. Optional.
+	Image    string
+	ImageAlt string
+
+	// StructuredData is rendered as JSON-LD. Optional.
+	StructuredData any
+}
+
+// FeedLink describes a syndication feed of the page.
+type FeedLink struct {
+	Title string
+	URL   string
+	Type  string
 }
@@ -34,7 +34,7 @@ func CreateBookForm() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/file_upload_helpers.js\"></script><script src=\"/assets/form_helpers.js\"></script><script src=\"/assets/topic_picker.js\"></script><script>\r\n    const BOOK_FORM_DEFAULTS = {\r\n        title: '',\r\n        summary: '',\r\n        description: '',\r\n        authors: [{ name: '', link: '' }],\r\n        homepage: '',\r\n        release_date: '',\r\n        cover_file_id: '',\r\n        topics: []\r\n    }\r\n\r\n    function createBookForm() {\r\n        return {\r\n            ...FormHelpers.makeForm({\r\n                defaults: BOOK_FORM_DEFAULTS,\r\n                submit: async function () {\r\n                    const {resp, data} = await HTTP.postJSON('/api/books/', this.form)\r\n\r\n                    if (resp.status === 201) {\r\n                        this.createdBook = data\r\n                        this.success = true\r\n                        return\r\n                    }\r\n\r\n                    if (data?.error) this.error = data.error\r\n                    FormHelpers.applyInputErrors(this.errors, data?.input_errors)\r\n                },\r\n            }),\r\n\r\n            topics: [],\r\n            ...TopicPicker.make(),\r\n\r\n            createdBook: null,\r\n            upload: null,\r\n            loading: false,\r\n            loadError: '',\r\n\r\n            async init() {\r\n                this.upload = FileUpload.makeFileUpload({\r\n                    purpose: 'book-cover',\r\n                    onUploaded: (id) => {\r\n                        this.form.cover_file_id = id\r\n                    },\r\n                    onRemoved: () => {\r\n                        this.form.cover_file_id = ''\r\n                    },\r\n                })\r\n\r\n                this.loading = true\r\n                this.loadError = ''\r\n\r\n                try {\r\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\r\n                    if (resp.status !== 200) {\r\n                        this.loadError = data?.error || 'Failed to load topics'\r\n                        return\r\n                    }\r\n\r\n                    this.topics = data?.data ?? []\r\n                } catch (err) {\r\n                    console.error(err)\r\n                    this.loadError = 'Failed to load topics'\r\n                } finally {\r\n                    this.loading = false\r\n                }\r\n            },\r\n\r\n            get createdBookURL() {\r\n                const pid = this.createdBook?.public_id\r\n                return pid ? `/books/${pid}/` : ''\r\n            },\r\n\r\n            addAuthorRow() {\r\n                this.form.authors.push({ name: '', link: '' })\r\n            },\r\n\r\n            removeAuthorRow(i) {\r\n                if (this.form.authors.length <= 1) return\r\n                this.form.authors.splice(i, 1)\r\n            },\r\n\r\n\r\n            dragIndex: null,\r\n            dragOverIndex: null,\r\n\r\n            onAuthorDragStart(i) {\r\n                this.dragIndex = i\r\n            },\r\n\r\n            onAuthorDragOver(e, i) {\r\n                e.preventDefault()\r\n                this.dragOverIndex = i\r\n            },\r\n\r\n            onAuthorDrop(i) {\r\n                if (this.dragIndex === null || this.dragIndex === i) {\r\n                    this.dragOverIndex = null\r\n                    return\r\n                }\r\n\r\n                const moved = this.form.authors.splice(this.dragIndex, 1)[0]\r\n                this.form.authors.splice(i, 0, moved)\r\n\r\n                this.dragIndex = null\r\n                this.dragOverIndex = null\r\n            },\r\n\r\n            onAuthorDragLeave(i) {\r\n                if (this.dragOverIndex === i) this.dragOverIndex = null\r\n            },\r\n\r\n            onAuthorDragEnd() {\r\n                this.dragIndex = null\r\n                this.dragOverIndex = null\r\n            },\r\n        }\r\n    }\r\n\r\n\r\n</script><div class=\"min-w-2xl\"><h1 class=\"text-3xl pb-4\">Add Book</h1><div class=\"bg-base-100 shadow-md card-body\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/file_upload_helpers.js\"></script><script src=\"/assets/form_helpers.js\"></script><script src=\"/assets/topic_picker.js\"></script><script>\n    const BOOK_FORM_DEFAULTS = {\n        title: '',\n        subtitle: '',\n        series: '',\n        series_position: 0,\n        summary: '',\n        description: '',\n        authors: [{ name: '', link: '' }],\n        homepage: '',\n        release_date: '',\n        cover_file_id: '',\n        isbn10: '',\n        isbn13: '',\n        topics: []\n    }\n\n    function createBookForm() {\n        return {\n            ...FormHelpers.makeForm({\n                defaults: BOOK_FORM_DEFAULTS,\n                submit: async function () {\n                    const body = {\n                        ...this.form,\n                        series_position: Number(this.form.series_position) || 0,\n                    }\n\n                    // warn about the books that likely exist already, once\n                    if (!this.duplicatesChecked) {\n                        const dup = await HTTP.postJSON('/api/books/duplicates/', body)\n                        this.duplicatesChecked = true\n                        if (dup.resp.status === 200 && dup.data?.length) {\n                            this.duplicates = dup.data\n                            return\n                        }\n                    }\n\n                    const {resp, data} = await HTTP.postJSON('/api/books/', body)\n\n                    if (resp.status === 201) {\n                        this.createdBook = data\n                        this.success = true\n                        return\n                    }\n\n                    if (data?.error) this.error = data.error\n                    FormHelpers.applyInputErrors(this.errors, data?.input_errors)\n                },\n            }),\n\n            topics: [],\n            ...TopicPicker.make(),\n\n            createdBook: null,\n            duplicates: [],\n            duplicatesChecked: false,\n            upload: null,\n            loading: false,\n            loadError: '',\n\n            isbn: '',\n            isbnLookingUp: false,\n            isbnError: '',\n            isbnInfo: '',\n\n            async init() {\n                this.upload = FileUpload.makeFileUpload({\n                    purpose: 'book-cover',\n                    onUploaded: (id) => {\n                        this.form.cover_file_id = id\n                    },\n                    onRemoved: () => {\n                        this.form.cover_file_id = ''\n                    },\n                })\n\n                this.loading = true\n                this.loadError = ''\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\n                    if (resp.status !== 200) {\n                        this.loadError = data?.error || 'Failed to load topics'\n                        return\n                    }\n\n                    this.topics = data?.data ?? []\n                } catch (err) {\n                    console.error(err)\n                    this.loadError = 'Failed to load topics'\n                } finally {\n                    this.loading = false\n                }\n            },\n\n            get createdBookURL() {\n                const pid = this.createdBook?.public_id\n                return pid ? `/books/${pid}/` : ''\n            },\n\n            // lookupISBN fills the empty fields of the form with the metadata of the book found by ISBN\n            async lookupISBN() {\n                const isbn = this.isbn.trim()\n                if (isbn === '') return\n\n                this.isbnLookingUp = true\n                this.isbnError = ''\n                this.isbnInfo = ''\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON(`/api/books/isbn/${encodeURIComponent(isbn)}/`, { method: 'GET' })\n                    if (resp.status !== 200) {\n                        this.isbnError = data?.error || 'Failed to look up the book'\n                        return\n                    }\n\n                    for (const k of ['title', 'description', 'release_date', 'isbn10', 'isbn13', 'cover_file_id']) {\n                        if (!this.form[k] && data[k]) this.form[k] = data[k]\n                    }\n\n                    const noAuthors = this.form.authors.every(a => a.name.trim() === '')\n                    if (noAuthors && data.authors?.length) {\n                        this.form.authors = data.authors.map(a => ({ name: a.name, link: a.link ?? '' }))\n                    }\n\n                    this.isbnInfo = [data.publisher, data.page_count ? `${data.page_count} pages` : '']\n                        .filter(Boolean)\n                        .join(', ')\n                } catch (err) {\n                    console.error(err)\n                    this.isbnError = 'Failed to look up the book'\n                } finally {\n                    this.isbnLookingUp = false\n                }\n            },\n\n            addAuthorRow() {\n                this.form.authors.push({ name: '', link: '' })\n            },\n\n            removeAuthorRow(i) {\n                if (this.form.authors.length <= 1) return\n                this.form.authors.splice(i, 1)\n            },\n\n\n            dragIndex: null,\n            dragOverIndex: null,\n\n            onAuthorDragStart(i) {\n                this.dragIndex = i\n            },\n\n            onAuthorDragOver(e, i) {\n                e.preventDefault()\n                this.dragOverIndex = i\n            },\n\n            onAuthorDrop(i) {\n                if (this.dragIndex === null || this.dragIndex === i) {\n                    this.dragOverIndex = null\n                    return\n                }\n\n                const moved = this.form.authors.splice(this.dragIndex, 1)[0]\n                this.form.authors.splice(i, 0, moved)\n\n                this.dragIndex = null\n                this.dragOverIndex = null\n            },\n\n            onAuthorDragLeave(i) {\n                if (this.dragOverIndex === i) this.dragOverIndex = null\n            },\n\n            onAuthorDragEnd() {\n                this.dragIndex = null\n                this.dragOverIndex = null\n            },\n        }\n    }\n\n\n</script><div class=\"min-w-2xl\"><h1 class=\"text-3xl pb-4\">Add Book</h1><div class=\"bg-base-100 shadow-md card-body\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -50,7 +50,7 @@ func CreateBookForm() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div x-init=\"init()\"><p class=\"text-red-500\" x-text=\"error\" x-show=\"error !== ''\"></p><div role=\"alert\" class=\"alert alert-success\" x-show=\"success\" x-cloak><svg xmlns=\"http://www.w3.org/2000/svg\" class=\"h-6 w-6 shrink-0 stroke-current\" fill=\"none\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg><div>Book created successfully!</div><a class=\"link\" :href=\"createdBookURL\" x-show=\"createdBookURL !== ''\">View book page</a> | <a href=\"/add-book/\" class=\"link\">Add another one</a></div><div x-show=\"!success\"><fieldset class=\"fieldset\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div x-init=\"init()\"><p class=\"text-red-500\" x-text=\"error\" x-show=\"error !== ''\"></p><div role=\"alert\" class=\"alert alert-success\" x-show=\"success\" x-cloak><svg xmlns=\"http://www.w3.org/2000/svg\" class=\"h-6 w-6 shrink-0 stroke-current\" fill=\"none\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg><div>Book created successfully!</div><a class=\"link\" :href=\"createdBookURL\" x-show=\"createdBookURL !== ''\">View book page</a> | <a href=\"/add-book/\" class=\"link\">Add another one</a></div><div x-show=\"!success\"><fieldset class=\"fieldset\"><div class=\"p-2\"><label class=\"label\" for=\"isbn_lookup\"><span class=\"label-text\">Fill from ISBN</span></label><div class=\"flex gap-2\"><input id=\"isbn_lookup\" type=\"text\" class=\"input input-bordered w-full\" placeholder=\"ISBN-10 or ISBN-13\" x-model=\"isbn\" x-on:keydown.enter.prevent=\"lookupISBN()\"> <button type=\"button\" class=\"btn btn-outline\" x-on:click=\"lookupISBN()\" :disabled=\"isbnLookingUp || isbn.trim() === ''\"><span class=\"loading loading-spinner\" x-show=\"isbnLookingUp\"></span> <span x-show=\"!isbnLookingUp\">Look up</span></button></div><div class=\"text-xs text-gray-400 mt-1\">Empty fields are filled with the data of the book found by ISBN</div><p class=\"text-error text-sm\" x-show=\"isbnError !== ''\" x-text=\"isbnError\"></p><p class=\"text-sm opacity-70\" x-show=\"isbnInfo !== ''\" x-text=\"isbnInfo\"></p></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -79,6 +79,36 @@ func CreateBookForm() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "subtitle",
+				Label:      "Subtitle",
+				Model:      "form.subtitle",
+				ErrorModel: "errors.subtitle",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:          "series",
+				Label:       "Series",
+				Model:       "form.series",
+				ErrorModel:  "errors.series",
+				Description: "Name of the series the book is part of, if any",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:          "series_position",
+				Type:        "number",
+				Label:       "Position in series",
+				Model:       "form.series_position",
+				ErrorModel:  "errors.series_position",
+				Description: "Number of the book in the series, 0 if unknown",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			templ_7745c5c3_Err = Textarea(InputParams{
 				ID:          "summary",
 				Label:       "Summary",
@@ -122,7 +152,25 @@ func CreateBookForm() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Authors:</span></label><div class=\"flex flex-col gap-2\"><template x-for=\"(a, i) in form.authors\" :key=\"i\"><div class=\"relative\"><div class=\"absolute -top-1 left-0 right-0 h-1 border-t-2 border-dashed border-info\" x-show=\"dragOverIndex === i && dragIndex !== null && dragIndex !== i\" x-cloak></div><div class=\"flex gap-2 items-start p-2 rounded\" :class=\"[\r\n                dragIndex === i ? 'opacity-50' : '',\r\n                dragOverIndex === i && dragIndex !== null && dragIndex !== i ? 'bg-base-200' : ''\r\n            ].join(' ')\" draggable=\"true\" x-on:dragstart=\"onAuthorDragStart(i)\" x-on:dragover=\"onAuthorDragOver($event, i)\" x-on:dragleave=\"onAuthorDragLeave(i)\" x-on:drop=\"onAuthorDrop(i)\" x-on:dragend=\"onAuthorDragEnd()\"><div class=\"flex gap-2 flex-1\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Author name\" x-model=\"a.name\"> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"Author link (optional)\" x-model=\"a.link\"></div><div><button type=\"button\" class=\"btn btn-ghost btn-success px-2\" x-on:click=\"addAuthorRow()\" aria-label=\"Add author\" title=\"Add author\">")
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "isbn13",
+				Label:      "ISBN-13",
+				Model:      "form.isbn13",
+				ErrorModel: "errors.isbn13",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "isbn10",
+				Label:      "ISBN-10",
+				Model:      "form.isbn10",
+				ErrorModel: "errors.isbn10",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Authors:</span></label><div class=\"flex flex-col gap-2\"><template x-for=\"(a, i) in form.authors\" :key=\"i\"><div class=\"relative\"><div class=\"absolute -top-1 left-0 right-0 h-1 border-t-2 border-dashed border-info\" x-show=\"dragOverIndex === i && dragIndex !== null && dragIndex !== i\" x-cloak></div><div class=\"flex gap-2 items-start p-2 rounded\" :class=\"[\n                dragIndex === i ? 'opacity-50' : '',\n                dragOverIndex === i && dragIndex !== null && dragIndex !== i ? 'bg-base-200' : ''\n            ].join(' ')\" draggable=\"true\" x-on:dragstart=\"onAuthorDragStart(i)\" x-on:dragover=\"onAuthorDragOver($event, i)\" x-on:dragleave=\"onAuthorDragLeave(i)\" x-on:drop=\"onAuthorDrop(i)\" x-on:dragend=\"onAuthorDragEnd()\"><div class=\"flex gap-2 flex-1\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Author name\" x-model=\"a.name\"> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"Homepage of a new author (optional)\" x-model=\"a.link\"></div><div><button type=\"button\" class=\"btn btn-ghost btn-success px-2\" x-on:click=\"addAuthorRow()\" aria-label=\"Add author\" title=\"Add author\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -138,7 +186,7 @@ func CreateBookForm() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</button><div class=\"btn  btn-ghost cursor-move select-none\" title=\"Drag to reorder\">≡</div></div></div></div></template><p class=\"text-error text-sm\" x-show=\"errors.authors\" x-text=\"errors.authors\"></p></div></div><div class=\"p-2\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</button><div class=\"btn  btn-ghost cursor-move select-none\" title=\"Drag to reorder\">≡</div></div></div></div></template><p class=\"text-error text-sm\" x-show=\"errors.authors\" x-text=\"errors.authors\"></p></div></div><div role=\"alert\" class=\"alert alert-warning m-2\" x-show=\"duplicates.length > 0\" x-cloak><div><div>This book may already exist:</div><ul class=\"list-disc pl-5\"><template x-for=\"d in duplicates\" :key=\"d.id\"><li><a class=\"link\" :href=\"`/books/${d.public_id}/`\" target=\"_blank\" x-text=\"d.title\"></a></li></template></ul><div>If it's a different book, submit it anyway.</div></div></div><div class=\"p-2\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
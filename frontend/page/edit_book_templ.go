@@ -35,19 +35,19 @@ func EditBookForm(bookID string) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/file_upload_helpers.js\"></script><script src=\"/assets/form_helpers.js\"></script><script src=\"/assets/topic_picker.js\"></script><script>\r\n    const BOOK_FORM_DEFAULTS = {\r\n        title: '',\r\n        summary: '',\r\n        description: '',\r\n        authors: [{ name: '', link: '' }],\r\n        homepage: '',\r\n        release_date: '',\r\n        cover_file_id: '',\r\n        topics: [],\r\n    }\r\n\r\n    const BOOK_ID = \"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/file_upload_helpers.js\"></script><script src=\"/assets/form_helpers.js\"></script><script src=\"/assets/topic_picker.js\"></script><script>\n    const BOOK_FORM_DEFAULTS = {\n        title: '',\n        subtitle: '',\n        series: '',\n        series_position: 0,\n        summary: '',\n        description: '',\n        authors: [{ name: '', link: '' }],\n        homepage: '',\n        release_date: '',\n        cover_file_id: '',\n        isbn10: '',\n        isbn13: '',\n        editions: [],\n        topics: [],\n    }\n\n    const BOOK_FORMATS = ['print', 'ebook', 'online']\n\n    const BOOK_ID = \"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Var2, templ_7745c5c3_Err := templruntime.ScriptContentInsideStringLiteral(bookID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/edit_book.templ`, Line: 26, Col: 30}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/edit_book.templ`, Line: 34, Col: 30}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var2)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"\r\n\r\n    function editBookForm() {\r\n        return {\r\n            ...FormHelpers.makeForm({\r\n                defaults: BOOK_FORM_DEFAULTS,\r\n                submit: async function () {\r\n                    const { resp, data } = await HTTP.putJSON(`/api/books/${BOOK_ID}/`, this.form)\r\n\r\n                    if (resp.status === 200) {\r\n                        this.saveRevision = data?.revision ?? 0\r\n                        this.needReview = data?.status === `pending` ?? false\r\n                        this.success = true\r\n                        return\r\n                    }\r\n\r\n                    if (data?.error) this.error = data.error\r\n                    FormHelpers.applyInputErrors(this.errors, data?.input_errors)\r\n                },\r\n            }),\r\n\r\n            topics: [],\r\n            ...TopicPicker.make(),\r\n\r\n            revision: null,\r\n            revision_date: null,\r\n            saveRevision: null,\r\n            needReview: true,\r\n\r\n            // page state\r\n            loading: true,\r\n            loadError: '',\r\n            book: null,\r\n            updatedBook: null,\r\n\r\n            // uploader (init() to bind callbacks to Alpine proxy)\r\n            upload: null,\r\n\r\n            get bookURL() {\r\n                return  `/books/${BOOK_ID}/`\r\n            },\r\n\r\n            get revisionDateFormatted() {\r\n                if (!this.revision_date) return ''\r\n\r\n                return new Date(this.revision_date).toLocaleString('en-US', {\r\n                    hour: '2-digit',\r\n                    minute: '2-digit',\r\n                    month: 'short',\r\n                    hour12: false,\r\n                    day: '2-digit'\r\n                })\r\n\r\n            },\r\n\r\n            async init() {\r\n                // init uploader\r\n                this.upload = FileUpload.makeFileUpload({\r\n                    purpose: 'book-cover',\r\n                    onUploaded: (id) => { this.form.cover_file_id = id },\r\n                    onRemoved: () => { this.form.cover_file_id = '' },\r\n                })\r\n\r\n                this.loading = true\r\n                this.loadError = ''\r\n\r\n                try {\r\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\r\n                    if (resp.status !== 200) {\r\n                        this.loadError = data?.error || 'Failed to load topics'\r\n                        return\r\n                    }\r\n\r\n                    this.topics = data?.data ?? []\r\n                } catch (err) {\r\n                    console.error(err)\r\n                    this.loadError = 'Failed to load topics'\r\n                } finally {\r\n                    this.loading = false\r\n                }\r\n\r\n                this.loading = true\r\n                this.loadError = ''\r\n\r\n                try {\r\n                    const { resp, data } = await HTTP.requestJSON(`/api/books/${BOOK_ID}/edit/`, { method: 'GET' })\r\n                    if (resp.status !== 200) {\r\n                        this.loadError = data?.error || 'Failed to load book'\r\n                        return\r\n                    }\r\n\r\n                    this.book = data.data || null\r\n                    this.revision = data?.revision ?? null\r\n                    this.revision_date = data?.revision_date ?? null\r\n\r\n                    for (const k of Object.keys(BOOK_FORM_DEFAULTS)) {\r\n                        if (k in (data.data || {})) this.form[k] = data.data[k] ?? BOOK_FORM_DEFAULTS[k]\r\n                    }\r\n\r\n                    const topicByPublicID = new Map((this.topics ?? []).map(t => [t.public_id, t.id]))\r\n                    const bookTopicPublicIDs = data.data?.topics ?? []\r\n                    this.form.topics = bookTopicPublicIDs\r\n                        .map(pid => topicByPublicID.get(pid))\r\n                        .filter(Boolean)\r\n                } catch (err) {\r\n                    console.error(err)\r\n                    this.loadError = 'Failed to load book'\r\n                } finally {\r\n                    this.loading = false\r\n                }\r\n            },\r\n\r\n            addAuthorRow() {\r\n                this.form.authors.push({ name: '', link: '' })\r\n            },\r\n\r\n            removeAuthorRow(i) {\r\n                if (this.form.authors.length <= 1) return\r\n                this.form.authors.splice(i, 1)\r\n            },\r\n\r\n\r\n            dragIndex: null,\r\n            dragOverIndex: null,\r\n\r\n            onAuthorDragStart(i) {\r\n                this.dragIndex = i\r\n            },\r\n\r\n            onAuthorDragOver(e, i) {\r\n                e.preventDefault()\r\n                this.dragOverIndex = i\r\n            },\r\n\r\n            onAuthorDrop(i) {\r\n                if (this.dragIndex === null || this.dragIndex === i) {\r\n                    this.dragOverIndex = null\r\n                    return\r\n                }\r\n\r\n                const moved = this.form.authors.splice(this.dragIndex, 1)[0]\r\n                this.form.authors.splice(i, 0, moved)\r\n\r\n                this.dragIndex = null\r\n                this.dragOverIndex = null\r\n            },\r\n\r\n            onAuthorDragLeave(i) {\r\n                if (this.dragOverIndex === i) this.dragOverIndex = null\r\n            },\r\n\r\n            onAuthorDragEnd() {\r\n                this.dragIndex = null\r\n                this.dragOverIndex = null\r\n            },\r\n        }\r\n    }\r\n</script><div class=\"min-w-2xl\"><h1 class=\"text-3xl pb-4\">Edit Book</h1><div class=\"bg-base-100 w-full shadow-md\"><div class=\"card-body\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"\n\n    function editBookForm() {\n        return {\n            ...FormHelpers.makeForm({\n                defaults: BOOK_FORM_DEFAULTS,\n                submit: async function () {\n                    const { resp, data } = await HTTP.putJSON(`/api/books/${BOOK_ID}/`, {\n                        ...this.form,\n                        series_position: Number(this.form.series_position) || 0,\n                    })\n\n                    if (resp.status === 200) {\n                        this.saveRevision = data?.revision ?? 0\n                        this.needReview = data?.status === `pending` ?? false\n                        this.success = true\n                        return\n                    }\n\n                    if (data?.error) this.error = data.error\n                    FormHelpers.applyInputErrors(this.errors, data?.input_errors)\n                    this.editionErrors = Object.entries(data?.input_errors ?? {})\n                        .filter(([k]) => k.startsWith('editions'))\n                        .map(([k, v]) => `${k}: ${v}`)\n                },\n            }),\n\n            topics: [],\n            ...TopicPicker.make(),\n\n            bookFormats: BOOK_FORMATS,\n            editionErrors: [],\n\n            revision: null,\n            revision_date: null,\n            saveRevision: null,\n            needReview: true,\n\n            // page state\n            loading: true,\n            loadError: '',\n            book: null,\n            updatedBook: null,\n\n            // uploader (init() to bind callbacks to Alpine proxy)\n            upload: null,\n\n            get bookURL() {\n                return  `/books/${BOOK_ID}/`\n            },\n\n            get revisionDateFormatted() {\n                if (!this.revision_date) return ''\n\n                return new Date(this.revision_date).toLocaleString('en-US', {\n                    hour: '2-digit',\n                    minute: '2-digit',\n                    month: 'short',\n                    hour12: false,\n                    day: '2-digit'\n                })\n\n            },\n\n            async init() {\n                // init uploader\n                this.upload = FileUpload.makeFileUpload({\n                    purpose: 'book-cover',\n                    onUploaded: (id) => { this.form.cover_file_id = id },\n                    onRemoved: () => { this.form.cover_file_id = '' },\n                })\n\n                this.loading = true\n                this.loadError = ''\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\n                    if (resp.status !== 200) {\n                        this.loadError = data?.error || 'Failed to load topics'\n                        return\n                    }\n\n                    this.topics = data?.data ?? []\n                } catch (err) {\n                    console.error(err)\n                    this.loadError = 'Failed to load topics'\n                } finally {\n                    this.loading = false\n                }\n\n                this.loading = true\n                this.loadError = ''\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON(`/api/books/${BOOK_ID}/edit/`, { method: 'GET' })\n                    if (resp.status !== 200) {\n                        this.loadError = data?.error || 'Failed to load book'\n                        return\n                    }\n\n                    this.book = data.data || null\n                    this.revision = data?.revision ?? null\n                    this.revision_date = data?.revision_date ?? null\n\n                    for (const k of Object.keys(BOOK_FORM_DEFAULTS)) {\n                        if (k in (data.data || {})) this.form[k] = data.data[k] ?? BOOK_FORM_DEFAULTS[k]\n                    }\n\n                    const topicByPublicID = new Map((this.topics ?? []).map(t => [t.public_id, t.id]))\n                    const bookTopicPublicIDs = data.data?.topics ?? []\n                    this.form.topics = bookTopicPublicIDs\n                        .map(pid => topicByPublicID.get(pid))\n                        .filter(Boolean)\n                } catch (err) {\n                    console.error(err)\n                    this.loadError = 'Failed to load book'\n                } finally {\n                    this.loading = false\n                }\n            },\n\n            addAuthorRow() {\n                this.form.authors.push({ name: '', link: '' })\n            },\n\n            removeAuthorRow(i) {\n                if (this.form.authors.length <= 1) return\n                this.form.authors.splice(i, 1)\n            },\n\n\n            addEdition() {\n                const last = this.form.editions[this.form.editions.length - 1]\n                this.form.editions.push({\n                    number: (last?.number ?? 0) + 1,\n                    go_version: '',\n                    publisher: '',\n                    isbn10: '',\n                    isbn13: '',\n                    page_count: 0,\n                    release_date: '',\n                    links: [],\n                })\n            },\n\n            removeEdition(i) {\n                this.form.editions.splice(i, 1)\n            },\n\n            addEditionLink(e) {\n                e.links.push({ format: 'print', url: '', title: '' })\n            },\n\n            removeEditionLink(e, i) {\n                e.links.splice(i, 1)\n            },\n\n            dragIndex: null,\n            dragOverIndex: null,\n\n            onAuthorDragStart(i) {\n                this.dragIndex = i\n            },\n\n            onAuthorDragOver(e, i) {\n                e.preventDefault()\n                this.dragOverIndex = i\n            },\n\n            onAuthorDrop(i) {\n                if (this.dragIndex === null || this.dragIndex === i) {\n                    this.dragOverIndex = null\n                    return\n                }\n\n                const moved = this.form.authors.splice(this.dragIndex, 1)[0]\n                this.form.authors.splice(i, 0, moved)\n\n                this.dragIndex = null\n                this.dragOverIndex = null\n            },\n\n            onAuthorDragLeave(i) {\n                if (this.dragOverIndex === i) this.dragOverIndex = null\n            },\n\n            onAuthorDragEnd() {\n                this.dragIndex = null\n                this.dragOverIndex = null\n            },\n        }\n    }\n</script><div class=\"min-w-2xl\"><h1 class=\"text-3xl pb-4\">Edit Book</h1><div class=\"bg-base-100 w-full shadow-md\"><div class=\"card-body\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -95,6 +95,36 @@ func EditBookForm(bookID string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "subtitle",
+				Label:      "Subtitle",
+				Model:      "form.subtitle",
+				ErrorModel: "errors.subtitle",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:          "series",
+				Label:       "Series",
+				Model:       "form.series",
+				ErrorModel:  "errors.series",
+				Description: "Name of the series the book is part of, if any",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:          "series_position",
+				Type:        "number",
+				Label:       "Position in series",
+				Model:       "form.series_position",
+				ErrorModel:  "errors.series_position",
+				Description: "Number of the book in the series, 0 if unknown",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			templ_7745c5c3_Err = Textarea(InputParams{
 				ID:          "summary",
 				Label:       "Summary",
@@ -138,7 +168,25 @@ func EditBookForm(bookID string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Authors:</span></label><div class=\"flex flex-col gap-2\"><template x-for=\"(a, i) in form.authors\" :key=\"i\"><div class=\"relative\"><div class=\"absolute -top-1 left-0 right-0 h-1 border-t-2 border-dashed border-info\" x-show=\"dragOverIndex === i && dragIndex !== null && dragIndex !== i\" x-cloak></div><div class=\"flex gap-2 items-start p-2 rounded\" :class=\"[\r\n                dragIndex === i ? 'opacity-50' : '',\r\n                dragOverIndex === i && dragIndex !== null && dragIndex !== i ? 'bg-base-200' : ''\r\n            ].join(' ')\" draggable=\"true\" x-on:dragstart=\"onAuthorDragStart(i)\" x-on:dragover=\"onAuthorDragOver($event, i)\" x-on:dragleave=\"onAuthorDragLeave(i)\" x-on:drop=\"onAuthorDrop(i)\" x-on:dragend=\"onAuthorDragEnd()\"><div class=\"flex gap-2 flex-1\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Author name\" x-model=\"a.name\"> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"Author link (optional)\" x-model=\"a.link\"></div><div><button type=\"button\" class=\"btn btn-ghost btn-success px-2\" x-on:click=\"addAuthorRow()\" aria-label=\"Add author\" title=\"Add author\">")
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "isbn13",
+				Label:      "ISBN-13",
+				Model:      "form.isbn13",
+				ErrorModel: "errors.isbn13",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Input(InputParams{
+				ID:         "isbn10",
+				Label:      "ISBN-10",
+				Model:      "form.isbn10",
+				ErrorModel: "errors.isbn10",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Authors:</span></label><div class=\"flex flex-col gap-2\"><template x-for=\"(a, i) in form.authors\" :key=\"i\"><div class=\"relative\"><div class=\"absolute -top-1 left-0 right-0 h-1 border-t-2 border-dashed border-info\" x-show=\"dragOverIndex === i && dragIndex !== null && dragIndex !== i\" x-cloak></div><div class=\"flex gap-2 items-start p-2 rounded\" :class=\"[\n                dragIndex === i ? 'opacity-50' : '',\n                dragOverIndex === i && dragIndex !== null && dragIndex !== i ? 'bg-base-200' : ''\n            ].join(' ')\" draggable=\"true\" x-on:dragstart=\"onAuthorDragStart(i)\" x-on:dragover=\"onAuthorDragOver($event, i)\" x-on:dragleave=\"onAuthorDragLeave(i)\" x-on:drop=\"onAuthorDrop(i)\" x-on:dragend=\"onAuthorDragEnd()\"><div class=\"flex gap-2 flex-1\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Author name\" x-model=\"a.name\"> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"Homepage of a new author (optional)\" x-model=\"a.link\"></div><div><button type=\"button\" class=\"btn btn-ghost btn-success px-2\" x-on:click=\"addAuthorRow()\" aria-label=\"Add author\" title=\"Add author\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -154,7 +202,23 @@ func EditBookForm(bookID string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</button><div class=\"btn  btn-ghost cursor-move select-none\" title=\"Drag to reorder\">≡</div></div></div></div></template><p class=\"text-error text-sm\" x-show=\"errors.authors\" x-text=\"errors.authors\"></p></div></div><div class=\"p-2\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</button><div class=\"btn  btn-ghost cursor-move select-none\" title=\"Drag to reorder\">≡</div></div></div></div></template><p class=\"text-error text-sm\" x-show=\"errors.authors\" x-text=\"errors.authors\"></p></div></div><div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Editions:</span></label><div class=\"flex flex-col gap-4\"><template x-for=\"(e, i) in form.editions\" :key=\"i\"><div class=\"border border-base-300 rounded p-3 flex flex-col gap-2\"><div class=\"grid grid-cols-3 gap-2\"><input type=\"number\" min=\"1\" class=\"input input-bordered w-full\" placeholder=\"Edition number\" x-model.number=\"e.number\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Go version, e.g. 1.22\" x-model=\"e.go_version\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Publisher\" x-model=\"e.publisher\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"ISBN-13\" x-model=\"e.isbn13\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"ISBN-10\" x-model=\"e.isbn10\"> <input type=\"number\" min=\"0\" class=\"input input-bordered w-full\" placeholder=\"Pages\" x-model.number=\"e.page_count\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Release date\" x-model=\"e.release_date\"></div><template x-for=\"(l, j) in e.links\" :key=\"j\"><div class=\"flex gap-2\"><select class=\"select select-bordered\" x-model=\"l.format\"><template x-for=\"f in bookFormats\" :key=\"f\"><option :value=\"f\" x-text=\"f\" :selected=\"l.format === f\"></option></template></select> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"Link to buy or read\" x-model=\"l.url\"> <input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Store or site (optional)\" x-model=\"l.title\"> <button type=\"button\" class=\"btn btn-ghost btn-error px-2\" x-on:click=\"removeEditionLink(e, j)\" aria-label=\"Remove link\" title=\"Remove link\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = icon.SquareMinus().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</button></div></template><div class=\"flex gap-2\"><button type=\"button\" class=\"btn btn-sm btn-ghost\" x-on:click=\"addEditionLink(e)\">Add link</button> <button type=\"button\" class=\"btn btn-sm btn-ghost btn-error\" x-on:click=\"removeEdition(i)\">Remove edition</button></div></div></template><div><button type=\"button\" class=\"btn btn-ghost btn-success\" x-on:click=\"addEdition()\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = icon.SquarePlus().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "Add edition</button></div><template x-for=\"err in editionErrors\" :key=\"err\"><p class=\"text-error text-sm\" x-text=\"err\"></p></template></div></div><div class=\"p-2\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -162,7 +226,7 @@ func EditBookForm(bookID string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</div></fieldset></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</div></fieldset></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -172,7 +236,7 @@ func EditBookForm(bookID string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,142 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package page
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import (
+	"github.com/gopl-dev/server/app/ds"
+	. "github.com/gopl-dev/server/frontend/component"
+)
+
+// UserProfileFormData holds the current values of the profile edit form.
+type UserProfileFormData struct {
+	Bio                string        `json:"bio"`
+	AvatarFileID       ds.ID         `json:"avatar_file_id,omitzero"`
+	Links              []ds.UserLink `json:"links"`
+	ShowBooks          bool          `json:"show_books"`
+	ShowPages          bool          `json:"show_pages"`
+	ShowChangeRequests bool          `json:"show_change_requests"`
+	ShowActivity       bool          `json:"show_activity"`
+}
+
+// EditUserProfileForm renders the form to edit the user's public profile.
+func EditUserProfileForm(profileURL string, data UserProfileFormData) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/file_upload_helpers.js\"></script><script src=\"/assets/form_helpers.js\"></script><script>\n    const PROFILE_FORM_DEFAULTS = {\n        bio: '',\n        avatar_file_id: '',\n        links: [],\n        show_books: true,\n        show_pages: true,\n        show_change_requests: true,\n        show_activity: true,\n    }\n\n    function editUserProfileForm() {\n        return {\n            ...FormHelpers.makeForm({\n                defaults: { ...PROFILE_FORM_DEFAULTS, ...")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Var2, templ_7745c5c3_Err := templruntime.ScriptContentOutsideStringLiteral(data)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/edit_user_profile.templ`, Line: 38, Col: 64}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var2)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " },\n                submit: async function () {\n                    const { resp, data } = await HTTP.putJSON('/api/users/profile/', this.form)\n\n                    if (resp.status === 200) {\n                        this.success = true\n                        return\n                    }\n\n                    if (data?.error) this.error = data.error\n                    FormHelpers.applyInputErrors(this.errors, data?.input_errors)\n\n                    // errors of individual links come as \"links[i].field\"\n                    const linkErr = Object.entries(data?.input_errors ?? {}).find(([k]) => k.startsWith('links['))\n                    if (linkErr) this.errors.links = `Link #${parseInt(linkErr[0].slice(6)) + 1}: ${linkErr[1]}`\n                },\n            }),\n\n            upload: null,\n\n            init() {\n                this.upload = FileUpload.makeFileUpload({\n                    purpose: 'user-avatar',\n                    onUploaded: (id) => { this.form.avatar_file_id = id },\n                    onRemoved: () => { this.form.avatar_file_id = '' },\n                })\n            },\n\n            addLink() {\n                this.form.links.push({ title: '', url: '' })\n            },\n\n            removeLink(i) {\n                this.form.links.splice(i, 1)\n            },\n        }\n    }\n</script><div class=\"min-w-2xl\"><h1 class=\"text-3xl pb-4\">Edit profile</h1><div class=\"bg-base-100 shadow-md card-body\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Var3 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div x-init=\"init()\"><p class=\"text-red-500\" x-text=\"error\" x-show=\"error !== ''\"></p><div role=\"alert\" class=\"alert alert-success\" x-show=\"success\" x-cloak><svg xmlns=\"http://www.w3.org/2000/svg\" class=\"h-6 w-6 shrink-0 stroke-current\" fill=\"none\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg><div>Profile saved.</div><a href=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var4 templ.SafeURL
+			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinURLErrs(templ.URL(profileURL))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/edit_user_profile.templ`, Line: 90, Col: 47}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\" class=\"link\">View profile</a></div><div x-show=\"!success\"><fieldset class=\"fieldset\" :disabled=\"submitting\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = FileUploadInput(FileUploadInputParams{
+				Label:       "Avatar",
+				Purpose:     "user-avatar",
+				Name:        "avatar_file_id",
+				FileIDModel: "form.avatar_file_id",
+				Accept:      "image/*",
+				Preview:     true,
+				Description: "Only PNG | JPG. Max dimensions: 3000×3000px",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = Textarea(InputParams{
+				ID:          "bio",
+				Label:       "Bio",
+				Model:       "form.bio",
+				ErrorModel:  "errors.bio",
+				Description: "A few words about yourself, up to 500 characters.",
+			}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Links:</span></label><div class=\"flex flex-col gap-2\"><template x-for=\"(l, i) in form.links\" :key=\"i\"><div class=\"flex gap-2 items-start\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Title\" x-model=\"l.title\"> <input type=\"url\" class=\"input input-bordered w-full\" placeholder=\"https://\" x-model=\"l.url\"> <button type=\"button\" class=\"btn btn-sm btn-ghost\" @click=\"removeLink(i)\">Remove</button></div></template></div><p class=\"text-red-500 text-sm\" x-text=\"errors.links\" x-show=\"errors.links !== ''\"></p><button type=\"button\" class=\"btn btn-sm mt-2\" @click=\"addLink()\" x-show=\"form.links.length < 5\">Add link</button></div><div class=\"p-2\"><label class=\"label\"><span class=\"label-text text-lg\">Show on profile:</span></label><div class=\"flex flex-col gap-2\"><label class=\"label cursor-pointer\"><input type=\"checkbox\" class=\"checkbox\" x-model=\"form.show_books\"> <span>Books I added</span></label> <label class=\"label cursor-pointer\"><input type=\"checkbox\" class=\"checkbox\" x-model=\"form.show_pages\"> <span>Pages I added</span></label> <label class=\"label cursor-pointer\"><input type=\"checkbox\" class=\"checkbox\" x-model=\"form.show_change_requests\"> <span>My accepted contributions</span></label> <label class=\"label cursor-pointer\"><input type=\"checkbox\" class=\"checkbox\" x-model=\"form.show_activity\"> <span>My activity</span></label></div></div><div class=\"p-2\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = SubmitButton("Save profile").Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div></fieldset></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = Form("editUserProfileForm").Render(templ.WithChildren(ctx, templ_7745c5c3_Var3), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -31,7 +31,7 @@ func FilterBooksPage() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/helpers.js\"></script><script>\r\n    function booksPage() {\r\n        return {\r\n            books: [],\r\n            loading: false,\r\n            error: '',\r\n            total: 0,\r\n            topics: [],\r\n            loadedOnce: false,\r\n\r\n            search: '',\r\n            searchResults: [],\r\n            searchLoading: false,\r\n            searchOpen: false,\r\n            searchDebounce: null,\r\n\r\n            filters: {\r\n                page: 1,\r\n                per_page: 10,\r\n                topic_ids: [],\r\n                author: \"\",\r\n            },\r\n\r\n            coverURL(fileID) {\r\n                return '/files/' + fileID + '/?preview'\r\n            },\r\n\r\n            get totalPages() {\r\n                return Math.max(1, Math.ceil(this.total / this.filters.per_page))\r\n            },\r\n\r\n            readFromURL() {\r\n                const url = new URL(window.location.href)\r\n\r\n                const p = parseInt(url.searchParams.get('page') || '', 10)\r\n                if (Number.isFinite(p) && p > 0) this.filters.page = p\r\n                else this.filters.page = 1\r\n\r\n                const pp = parseInt(url.searchParams.get('per_page') || '', 10)\r\n                if (Number.isFinite(pp) && pp > 0) this.filters.per_page = pp\r\n\r\n                const topicIDs = url.searchParams.getAll('topics')\r\n                this.filters.topic_ids = topicIDs ?? []\r\n\r\n                const author = url.searchParams.get('author')\r\n                this.filters.author = author ?? \"\"\r\n            },\r\n\r\n            writeToURL() {\r\n                const url = new URL(window.location.href)\r\n\r\n                if (this.filters.page === 1) url.searchParams.delete('page')\r\n                else url.searchParams.set('page', String(this.filters.page))\r\n\r\n                if (this.filters.per_page === 10) url.searchParams.delete('per_page')\r\n                else url.searchParams.set('per_page', String(this.filters.per_page))\r\n\r\n                url.searchParams.delete('topics')\r\n                for (const id of (this.filters.topic_ids ?? [])) {\r\n                    url.searchParams.append('topics', id)\r\n                }\r\n\r\n                window.history.replaceState({}, '', url.toString())\r\n            },\r\n\r\n            onPopState() {\r\n                this.readFromURL()\r\n                this.load({ syncURL: false, scrollTop: true })\r\n            },\r\n\r\n            // ---------- search ----------\r\n\r\n            async onSearchInput() {\r\n                clearTimeout(this.searchDebounce)\r\n                if (this.search.length < 3) {\r\n                    this.searchResults = []\r\n                    this.searchOpen = false\r\n                    return\r\n                }\r\n                this.searchDebounce = setTimeout(async () => {\r\n                    this.searchLoading = true\r\n                    try {\r\n                        const { resp, data } = await HTTP.requestJSON(\r\n                            '/api/books/search/?search=' + encodeURIComponent(this.search)\r\n                        )\r\n                        if (resp.status === 200) {\r\n                            this.searchResults = data ?? []\r\n                            this.searchOpen = this.searchResults.length > 0\r\n                        }\r\n                    } catch (e) {\r\n                        console.error(e)\r\n                    } finally {\r\n                        this.searchLoading = false\r\n                    }\r\n                }, 300)\r\n            },\r\n\r\n            onSearchSelect(result) {\r\n                window.location.href = result.url\r\n            },\r\n\r\n            closeSearch() {\r\n                this.searchOpen = false\r\n            },\r\n\r\n            // ---------- pagination ui ----------\r\n\r\n            scrollToTop() {\r\n                if (window.scrollY > 80) {\r\n                    window.scrollTo({ top: 0, behavior: 'smooth' })\r\n                }\r\n            },\r\n\r\n            get pageButtons() {\r\n                const max = this.totalPages\r\n                const cur = this.filters.page\r\n\r\n                const start = Math.max(2, cur - 3)\r\n                const end = Math.min(max - 1, cur + 3)\r\n\r\n                const btns = []\r\n                for (let p = start; p <= end; p++) btns.push(p)\r\n                return btns\r\n            },\r\n\r\n            get showLeftDots() {\r\n                return this.pageButtons.length > 0 && this.pageButtons[0] > 2\r\n            },\r\n\r\n            get showRightDots() {\r\n                const btns = this.pageButtons\r\n                return btns.length > 0 && btns[btns.length - 1] < this.totalPages - 1\r\n            },\r\n\r\n            gotoPage(p) {\r\n                if (p < 1) p = 1\r\n                if (p > this.totalPages) p = this.totalPages\r\n                if (p === this.filters.page) return\r\n\r\n                this.filters.page = p\r\n                this.load({ syncURL: true, scrollTop: true })\r\n            },\r\n\r\n            buildBooksQS() {\r\n                const qs = new URLSearchParams({\r\n                    page: String(this.filters.page),\r\n                    per_page: String(this.filters.per_page),\r\n                    author: this.filters.author,\r\n                })\r\n\r\n                for (const id of (this.filters.topic_ids ?? [])) {\r\n                    qs.append('topics', id)\r\n                }\r\n\r\n                return qs.toString()\r\n            },\r\n\r\n            async loadTopicsOnce() {\r\n                if (this.topics.length) return\r\n\r\n                try {\r\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\r\n                    if (resp.status !== 200) {\r\n                        this.error = data?.error || 'Failed to load topics'\r\n                        return\r\n                    }\r\n                    this.topics = data?.data ?? []\r\n                } catch (err) {\r\n                    console.error(err)\r\n                    this.error = 'Failed to load topics'\r\n                }\r\n            },\r\n\r\n            async load(opt) {\r\n                const options = opt || { syncURL: true, scrollTop: true }\r\n                if (this.filters.page < 1) this.filters.page = 1\r\n                if (this.loadedOnce && this.filters.page > this.totalPages) {\r\n                    this.filters.page = this.totalPages\r\n                }\r\n\r\n                if (options.scrollTop) this.scrollToTop()\r\n\r\n                this.loading = true\r\n                this.error = ''\r\n\r\n                try {\r\n                    await this.loadTopicsOnce()\r\n\r\n                    const { resp, data } = await HTTP.requestJSON('/api/books/?' + this.buildBooksQS())\r\n                    if (resp.status !== 200) {\r\n                        this.error = data?.error || 'Failed to load books'\r\n                        return\r\n                    }\r\n\r\n                    this.books = data?.data ?? []\r\n                    this.total = data?.count ?? 0\r\n                    this.loadedOnce = true\r\n\r\n                    if (this.filters.page > this.totalPages) {\r\n                        this.filters.page = this.totalPages\r\n                        if (options.syncURL) this.writeToURL()\r\n                        return await this.load({ syncURL: false, scrollTop: false })\r\n                    }\r\n\r\n                    if (options.syncURL) this.writeToURL()\r\n                } catch (e) {\r\n                    this.error = e?.message ?? String(e)\r\n                } finally {\r\n                    this.loading = false\r\n                }\r\n            },\r\n\r\n            isTopicSelected(t) {\r\n                return (this.filters.topic_ids ?? []).includes(String(t.public_id))\r\n            },\r\n\r\n            init() {\r\n                this.readFromURL()\r\n                window.addEventListener('popstate', () => this.onPopState())\r\n                this.load({ syncURL: false, scrollTop: false })\r\n            },\r\n        }\r\n    }\r\n</script><div x-data=\"booksPage()\"><div class=\"flex items-center justify-between pb-4 gap-4\"><h1 class=\"text-3xl shrink-0\">Books</h1><div class=\"flex items-center gap-2 flex-1\"><div class=\"relative flex-1\" x-on:click.outside=\"closeSearch()\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Search by book title, author or topic\" x-model=\"search\" x-on:input=\"onSearchInput()\" x-on:focus=\"searchOpen = searchResults.length > 0\"><template x-if=\"searchLoading\"><div class=\"absolute right-3 top-3\"><span class=\"loading loading-spinner loading-sm\"></span></div></template><template x-if=\"searchOpen\"><ul class=\"absolute z-50 mt-1 w-full bg-base-100 border border-base-300 rounded-box shadow-lg max-h-80 overflow-y-auto\"><template x-for=\"(r, i) in searchResults\" :key=\"i\"><li class=\"flex items-center gap-3 px-4 py-2 cursor-pointer hover:bg-base-200\" x-on:click=\"onSearchSelect(r)\"><div class=\"shrink-0\"><template x-if=\"r.type === 'book'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-book-open-text\"><path d=\"M12 7v14\"></path><path d=\"M16 12h2\"></path><path d=\"M16 8h2\"></path><path d=\"M3 18a1 1 0 0 1-1-1V4a1 1 0 0 1 1-1h5a4 4 0 0 1 4 4 4 4 0 0 1 4-4h5a1 1 0 0 1 1 1v13a1 1 0 0 1-1 1h-6a3 3 0 0 0-3 3 3 3 0 0 0-3-3z\"></path><path d=\"M6 12h2\"></path><path d=\"M6 8h2\"></path></svg></template><template x-if=\"r.type === 'topic'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-hash\"><line x1=\"4\" x2=\"20\" y1=\"9\" y2=\"9\"></line><line x1=\"4\" x2=\"20\" y1=\"15\" y2=\"15\"></line><line x1=\"10\" x2=\"8\" y1=\"3\" y2=\"21\"></line><line x1=\"16\" x2=\"14\" y1=\"3\" y2=\"21\"></line></svg></template><template x-if=\"r.type === 'author'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-user\"><path d=\"M19 21v-2a4 4 0 0 0-4-4H9a4 4 0 0 0-4 4v2\"></path><circle cx=\"12\" cy=\"7\" r=\"4\"></circle></svg></template></div><span x-text=\"r.name\"></span></li></template></ul></template></div><a class=\"btn btn-info ml-2 shrink-0\" href=\"/add-book/\">Add book</a></div></div><div class=\"flex flex-wrap gap-2 mb-2\"><template x-for=\"t in topics\" :key=\"t.id\"><label class=\"badge badge-lg cursor-pointer select-none\" :class=\"filters.topic_ids.includes(t.public_id) ? 'badge-info' : 'badge-soft'\"><input type=\"checkbox\" class=\"hidden\" @change=\"\r\n  $event.target.checked\r\n    ? filters.topic_ids.push(t.public_id)\r\n    : filters.topic_ids = filters.topic_ids.filter(id => id !== t.public_id)\r\n  filters.page = 1\r\n  load({ syncURL: true, scrollTop: true })\r\n\" :checked=\"filters.topic_ids.includes(t.public_id)\"> <span x-text=\"t.name\"></span></label></template></div><div class=\"flex items-center justify-between mb-4\"><div class=\"text-sm text-gray-500\"><span x-text=\"'Total: ' + total\"></span> <span class=\"mx-2\">•</span> <span x-text=\"'Page ' + filters.page + ' of ' + totalPages\"></span></div><div class=\"flex items-center gap-2\"><button class=\"btn btn-sm\" :class=\"filters.page === 1 ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(1)\">1</button><template x-if=\"showLeftDots\"><span class=\"px-1 select-none\">...</span></template><template x-for=\"p in pageButtons\" :key=\"p\"><button class=\"btn btn-sm\" :class=\"p === filters.page ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(p)\" x-text=\"p\"></button></template><template x-if=\"showRightDots\"><span class=\"px-1 select-none\">...</span></template><template x-if=\"totalPages > 1\"><button class=\"btn btn-sm\" :class=\"filters.page === totalPages ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(totalPages)\" x-text=\"totalPages\"></button></template></div></div><template x-if=\"loading\"><div>Loading...</div></template><template x-if=\"error\"><div class=\"text-red-600\" x-text=\"error\"></div></template><template x-for=\"b in books\" :key=\"b.id\"><div class=\"card rounded-none card-side bg-base-100 mb-3\"><template x-if=\"b.cover_file_id\"><figure><img class=\"object-cover shrink-0 w-40\" :src=\"coverURL(b.cover_file_id)\" :alt=\"b.title\" width=\"300\" loading=\"lazy\"></figure></template><div class=\"card-body\"><div class=\"flex items-start justify-between gap-3\"><h2 class=\"card-title\"><a class=\"hover:underline link-info\" :href=\"'/books/' + b.public_id + '/'\" x-text=\"b.title\"></a> <span x-text=\"b.release_date\" class=\"italic font-normal text-gray-400\"></span></h2><a class=\"btn btn-ghost btn-sm btn-square\" title=\"Edit\" :href=\"'/edit-book/' + b.public_id + '/'\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/helpers.js\"></script><script>\n    const BOOK_SORTS = [\n        { value: 'release_date', label: 'Newest releases' },\n        { value: 'title', label: 'Title A–Z' },\n        { value: 'added', label: 'Recently added' },\n        { value: 'updated', label: 'Recently updated' },\n        { value: 'rating', label: 'Highest rated' },\n    ]\n    const BOOK_SORT_DEFAULT = 'release_date'\n\n    function booksPage() {\n        return {\n            books: [],\n            loading: false,\n            error: '',\n            total: 0,\n            topics: [],\n            loadedOnce: false,\n\n            search: '',\n            searchResults: [],\n            searchLoading: false,\n            searchOpen: false,\n            searchDebounce: null,\n\n            filters: {\n                page: 1,\n                per_page: 10,\n                topic_ids: [],\n                author: \"\",\n                sort: BOOK_SORT_DEFAULT,\n            },\n\n            sorts: BOOK_SORTS,\n\n            coverURL(fileID) {\n                return '/files/' + fileID + '/?preview'\n            },\n\n            get totalPages() {\n                return Math.max(1, Math.ceil(this.total / this.filters.per_page))\n            },\n\n            readFromURL() {\n                const url = new URL(window.location.href)\n\n                const p = parseInt(url.searchParams.get('page') || '', 10)\n                if (Number.isFinite(p) && p > 0) this.filters.page = p\n                else this.filters.page = 1\n\n                const pp = parseInt(url.searchParams.get('per_page') || '', 10)\n                if (Number.isFinite(pp) && pp > 0) this.filters.per_page = pp\n\n                const topicIDs = url.searchParams.getAll('topics')\n                this.filters.topic_ids = topicIDs ?? []\n\n                const author = url.searchParams.get('author')\n                this.filters.author = author ?? \"\"\n\n                const sort = url.searchParams.get('sort')\n                this.filters.sort = BOOK_SORTS.some(s => s.value === sort) ? sort : BOOK_SORT_DEFAULT\n            },\n\n            writeToURL() {\n                const url = new URL(window.location.href)\n\n                if (this.filters.page === 1) url.searchParams.delete('page')\n                else url.searchParams.set('page', String(this.filters.page))\n\n                if (this.filters.per_page === 10) url.searchParams.delete('per_page')\n                else url.searchParams.set('per_page', String(this.filters.per_page))\n\n                url.searchParams.delete('topics')\n                for (const id of (this.filters.topic_ids ?? [])) {\n                    url.searchParams.append('topics', id)\n                }\n\n                if (this.filters.sort === BOOK_SORT_DEFAULT) url.searchParams.delete('sort')\n                else url.searchParams.set('sort', this.filters.sort)\n\n                window.history.replaceState({}, '', url.toString())\n            },\n\n            onPopState() {\n                this.readFromURL()\n                this.load({ syncURL: false, scrollTop: true })\n            },\n\n            // ---------- search ----------\n\n            async onSearchInput() {\n                clearTimeout(this.searchDebounce)\n                if (this.search.length < 3) {\n                    this.searchResults = []\n                    this.searchOpen = false\n                    return\n                }\n                this.searchDebounce = setTimeout(async () => {\n                    this.searchLoading = true\n                    try {\n                        const { resp, data } = await HTTP.requestJSON(\n                            '/api/books/search/?search=' + encodeURIComponent(this.search)\n                        )\n                        if (resp.status === 200) {\n                            this.searchResults = data ?? []\n                            this.searchOpen = this.searchResults.length > 0\n                        }\n                    } catch (e) {\n                        console.error(e)\n                    } finally {\n                        this.searchLoading = false\n                    }\n                }, 300)\n            },\n\n            onSearchSelect(result) {\n                window.location.href = result.url\n            },\n\n            closeSearch() {\n                this.searchOpen = false\n            },\n\n            // ---------- pagination ui ----------\n\n            scrollToTop() {\n                if (window.scrollY > 80) {\n                    window.scrollTo({ top: 0, behavior: 'smooth' })\n                }\n            },\n\n            get pageButtons() {\n                const max = this.totalPages\n                const cur = this.filters.page\n\n                const start = Math.max(2, cur - 3)\n                const end = Math.min(max - 1, cur + 3)\n\n                const btns = []\n                for (let p = start; p <= end; p++) btns.push(p)\n                return btns\n            },\n\n            get showLeftDots() {\n                return this.pageButtons.length > 0 && this.pageButtons[0] > 2\n            },\n\n            get showRightDots() {\n                const btns = this.pageButtons\n                return btns.length > 0 && btns[btns.length - 1] < this.totalPages - 1\n            },\n\n            gotoPage(p) {\n                if (p < 1) p = 1\n                if (p > this.totalPages) p = this.totalPages\n                if (p === this.filters.page) return\n\n                this.filters.page = p\n                this.load({ syncURL: true, scrollTop: true })\n            },\n\n            buildBooksQS() {\n                const qs = new URLSearchParams({\n                    page: String(this.filters.page),\n                    per_page: String(this.filters.per_page),\n                    author: this.filters.author,\n                    sort: this.filters.sort,\n                })\n\n                for (const id of (this.filters.topic_ids ?? [])) {\n                    qs.append('topics', id)\n                }\n\n                return qs.toString()\n            },\n\n            async loadTopicsOnce() {\n                if (this.topics.length) return\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON(`/api/topics/?type=book&per_page=100`, { method: 'GET' })\n                    if (resp.status !== 200) {\n                        this.error = data?.error || 'Failed to load topics'\n                        return\n                    }\n                    this.topics = data?.data ?? []\n                } catch (err) {\n                    console.error(err)\n                    this.error = 'Failed to load topics'\n                }\n            },\n\n            async load(opt) {\n                const options = opt || { syncURL: true, scrollTop: true }\n                if (this.filters.page < 1) this.filters.page = 1\n                if (this.loadedOnce && this.filters.page > this.totalPages) {\n                    this.filters.page = this.totalPages\n                }\n\n                if (options.scrollTop) this.scrollToTop()\n\n                this.loading = true\n                this.error = ''\n\n                try {\n                    await this.loadTopicsOnce()\n\n                    const { resp, data } = await HTTP.requestJSON('/api/books/?' + this.buildBooksQS())\n                    if (resp.status !== 200) {\n                        this.error = data?.error || 'Failed to load books'\n                        return\n                    }\n\n                    this.books = data?.data ?? []\n                    this.total = data?.count ?? 0\n                    this.loadedOnce = true\n\n                    if (this.filters.page > this.totalPages) {\n                        this.filters.page = this.totalPages\n                        if (options.syncURL) this.writeToURL()\n                        return await this.load({ syncURL: false, scrollTop: false })\n                    }\n\n                    if (options.syncURL) this.writeToURL()\n                } catch (e) {\n                    this.error = e?.message ?? String(e)\n                } finally {\n                    this.loading = false\n                }\n            },\n\n            isTopicSelected(t) {\n                return (this.filters.topic_ids ?? []).includes(String(t.public_id))\n            },\n\n            init() {\n                this.readFromURL()\n                window.addEventListener('popstate', () => this.onPopState())\n                this.load({ syncURL: false, scrollTop: false })\n            },\n        }\n    }\n</script><div x-data=\"booksPage()\"><div class=\"flex items-center justify-between pb-4 gap-4\"><h1 class=\"text-3xl shrink-0\">Books</h1><div class=\"flex items-center gap-2 flex-1\"><div class=\"relative flex-1\" x-on:click.outside=\"closeSearch()\"><input type=\"text\" class=\"input input-bordered w-full\" placeholder=\"Search by book title, author or topic\" x-model=\"search\" x-on:input=\"onSearchInput()\" x-on:focus=\"searchOpen = searchResults.length > 0\"><template x-if=\"searchLoading\"><div class=\"absolute right-3 top-3\"><span class=\"loading loading-spinner loading-sm\"></span></div></template><template x-if=\"searchOpen\"><ul class=\"absolute z-50 mt-1 w-full bg-base-100 border border-base-300 rounded-box shadow-lg max-h-80 overflow-y-auto\"><template x-for=\"(r, i) in searchResults\" :key=\"i\"><li class=\"flex items-center gap-3 px-4 py-2 cursor-pointer hover:bg-base-200\" x-on:click=\"onSearchSelect(r)\"><div class=\"shrink-0\"><template x-if=\"r.type === 'book'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-book-open-text\"><path d=\"M12 7v14\"></path><path d=\"M16 12h2\"></path><path d=\"M16 8h2\"></path><path d=\"M3 18a1 1 0 0 1-1-1V4a1 1 0 0 1 1-1h5a4 4 0 0 1 4 4 4 4 0 0 1 4-4h5a1 1 0 0 1 1 1v13a1 1 0 0 1-1 1h-6a3 3 0 0 0-3 3 3 3 0 0 0-3-3z\"></path><path d=\"M6 12h2\"></path><path d=\"M6 8h2\"></path></svg></template><template x-if=\"r.type === 'topic'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-hash\"><line x1=\"4\" x2=\"20\" y1=\"9\" y2=\"9\"></line><line x1=\"4\" x2=\"20\" y1=\"15\" y2=\"15\"></line><line x1=\"10\" x2=\"8\" y1=\"3\" y2=\"21\"></line><line x1=\"16\" x2=\"14\" y1=\"3\" y2=\"21\"></line></svg></template><template x-if=\"r.type === 'author'\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"18\" height=\"18\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"currentColor\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\" class=\"lucide lucide-user\"><path d=\"M19 21v-2a4 4 0 0 0-4-4H9a4 4 0 0 0-4 4v2\"></path><circle cx=\"12\" cy=\"7\" r=\"4\"></circle></svg></template></div><span x-text=\"r.name\"></span></li></template></ul></template></div><a class=\"btn btn-info ml-2 shrink-0\" href=\"/add-book/\">Add book</a></div></div><div class=\"flex flex-wrap gap-2 mb-2\"><template x-for=\"t in topics\" :key=\"t.id\"><label class=\"badge badge-lg cursor-pointer select-none\" :class=\"filters.topic_ids.includes(t.public_id) ? 'badge-info' : 'badge-soft'\"><input type=\"checkbox\" class=\"hidden\" @change=\"\n  $event.target.checked\n    ? filters.topic_ids.push(t.public_id)\n    : filters.topic_ids = filters.topic_ids.filter(id => id !== t.public_id)\n  filters.page = 1\n  load({ syncURL: true, scrollTop: true })\n\" :checked=\"filters.topic_ids.includes(t.public_id)\"> <span x-text=\"t.name\"></span></label></template></div><div class=\"flex items-center justify-between mb-4\"><div class=\"text-sm text-gray-500 flex items-center\"><span x-text=\"'Total: ' + total\"></span> <span class=\"mx-2\">•</span> <span x-text=\"'Page ' + filters.page + ' of ' + totalPages\"></span> <span class=\"mx-2\">•</span> <select class=\"select select-sm select-ghost w-auto\" aria-label=\"Sort books\" x-model=\"filters.sort\" @change=\"filters.page = 1; load({ syncURL: true, scrollTop: true })\"><template x-for=\"s in sorts\" :key=\"s.value\"><option :value=\"s.value\" x-text=\"s.label\" :selected=\"filters.sort === s.value\"></option></template></select></div><div class=\"flex items-center gap-2\"><button class=\"btn btn-sm\" :class=\"filters.page === 1 ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(1)\">1</button><template x-if=\"showLeftDots\"><span class=\"px-1 select-none\">...</span></template><template x-for=\"p in pageButtons\" :key=\"p\"><button class=\"btn btn-sm\" :class=\"p === filters.page ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(p)\" x-text=\"p\"></button></template><template x-if=\"showRightDots\"><span class=\"px-1 select-none\">...</span></template><template x-if=\"totalPages > 1\"><button class=\"btn btn-sm\" :class=\"filters.page === totalPages ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(totalPages)\" x-text=\"totalPages\"></button></template></div></div><template x-if=\"loading\"><div>Loading...</div></template><template x-if=\"error\"><div class=\"text-red-600\" x-text=\"error\"></div></template><template x-for=\"b in books\" :key=\"b.id\"><div class=\"card rounded-none card-side bg-base-100 mb-3\"><template x-if=\"b.cover_file_id\"><figure><img class=\"object-cover shrink-0 w-40\" :src=\"coverURL(b.cover_file_id)\" :alt=\"b.title\" width=\"300\" loading=\"lazy\"></figure></template><div class=\"card-body\"><div class=\"flex items-start justify-between gap-3\"><h2 class=\"card-title\"><a class=\"hover:underline link-info\" :href=\"'/books/' + b.public_id + '/'\" x-text=\"b.title\"></a> <span x-text=\"b.release_date\" class=\"italic font-normal text-gray-400\"></span></h2><a class=\"btn btn-ghost btn-sm btn-square\" title=\"Edit\" :href=\"'/edit-book/' + b.public_id + '/'\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -39,7 +39,7 @@ func FilterBooksPage() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</a></div><span>by&nbsp;<template x-for=\"(a, i) in b.authors\" :key=\"a.name\"><span><template x-if=\"a.link\"><a :href=\"a.link\" class=\"link\" x-text=\"a.name\"></a></template><template x-if=\"!a.link\"><span x-text=\"a.name\"></span></template><template x-if=\"b.authors.length > 1 && i < b.authors.length - 2\"><span>, </span></template><template x-if=\"b.authors.length > 1 && i === b.authors.length - 2\"><span>&nbsp;and&nbsp;</span></template></span></template></span><div x-html=\"b.summary\"></div><div class=\"flex flex-wrap gap-2 mb-3\"><template x-for=\"t in (b.topics ?? [])\" :key=\"t.public_id\"><a :href=\"'/books/?topics=' + t.public_id\" class=\"badge\" :class=\"isTopicSelected(t) ? 'badge-outline badge-info' : 'badge-soft'\" x-text=\"t.name\"></a></template></div><p><a class=\"hover:underline link-info\" :href=\"'/books/' + b.public_id + '/'\">More...</a></p></div></div></template><template x-if=\"!loading && books.length === 0 && !error\"><div>No books found</div></template><div class=\"flex items-center justify-between mt-4\"><div class=\"text-sm text-gray-500\"><span x-text=\"'Total: ' + total\"></span> <span class=\"mx-2\">•</span> <span x-text=\"'Page ' + filters.page + ' of ' + totalPages\"></span></div><div class=\"flex items-center gap-2\"><button class=\"btn btn-sm\" :class=\"filters.page === 1 ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(1)\">1</button><template x-if=\"showLeftDots\"><span class=\"px-1 select-none\">...</span></template><template x-for=\"p in pageButtons\" :key=\"p\"><button class=\"btn btn-sm\" :class=\"p === filters.page ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(p)\" x-text=\"p\"></button></template><template x-if=\"showRightDots\"><span class=\"px-1 select-none\">...</span></template><template x-if=\"totalPages > 1\"><button class=\"btn btn-sm\" :class=\"filters.page === totalPages ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(totalPages)\" x-text=\"totalPages\"></button></template></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</a></div><template x-if=\"b.subtitle\"><div class=\"text-gray-500 -mt-2\" x-text=\"b.subtitle\"></div></template><template x-if=\"b.series\"><div class=\"text-sm text-gray-500\" x-text=\"b.series + (b.series_position ? ' #' + b.series_position : '')\"></div></template><template x-if=\"b.rating_count > 0\"><div class=\"text-sm\"><span class=\"text-warning\">★</span> <span x-text=\"b.rating.toFixed(1)\"></span> <span class=\"text-gray-500\" x-text=\"'(' + b.rating_count + ')'\"></span></div></template><span>by&nbsp;<template x-for=\"(a, i) in b.authors\" :key=\"a.name\"><span><a :href=\"'/authors/' + a.slug + '/'\" class=\"link\" x-text=\"a.name\"></a><template x-if=\"b.authors.length > 1 && i < b.authors.length - 2\"><span>, </span></template><template x-if=\"b.authors.length > 1 && i === b.authors.length - 2\"><span>&nbsp;and&nbsp;</span></template></span></template></span><div x-html=\"b.summary\"></div><div class=\"flex flex-wrap gap-2 mb-3\"><template x-for=\"t in (b.topics ?? [])\" :key=\"t.public_id\"><a :href=\"'/books/?topics=' + t.public_id\" class=\"badge\" :class=\"isTopicSelected(t) ? 'badge-outline badge-info' : 'badge-soft'\" x-text=\"t.name\"></a></template></div><p><a class=\"hover:underline link-info\" :href=\"'/books/' + b.public_id + '/'\">More...</a></p></div></div></template><template x-if=\"!loading && books.length === 0 && !error\"><div>No books found</div></template><div class=\"flex items-center justify-between mt-4\"><div class=\"text-sm text-gray-500\"><span x-text=\"'Total: ' + total\"></span> <span class=\"mx-2\">•</span> <span x-text=\"'Page ' + filters.page + ' of ' + totalPages\"></span></div><div class=\"flex items-center gap-2\"><button class=\"btn btn-sm\" :class=\"filters.page === 1 ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(1)\">1</button><template x-if=\"showLeftDots\"><span class=\"px-1 select-none\">...</span></template><template x-for=\"p in pageButtons\" :key=\"p\"><button class=\"btn btn-sm\" :class=\"p === filters.page ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(p)\" x-text=\"p\"></button></template><template x-if=\"showRightDots\"><span class=\"px-1 select-none\">...</span></template><template x-if=\"totalPages > 1\"><button class=\"btn btn-sm\" :class=\"filters.page === totalPages ? 'btn-active' : ''\" :disabled=\"loading\" @click=\"gotoPage(totalPages)\" x-text=\"totalPages\"></button></template></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
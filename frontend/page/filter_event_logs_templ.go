@@ -31,7 +31,7 @@ func FilterEventLogsPage() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/helpers.js\"></script><script>\r\n    function eventLogsPage() {\r\n        return {\r\n            logs: [],\r\n            loading: false,\r\n            error: '',\r\n            total: 0,\r\n            loadedOnce: false,\r\n\r\n            filters: {\r\n                page: 1,\r\n                per_page: 100,\r\n            },\r\n\r\n            // changes modal state\r\n            selectedLog: null,\r\n            diffLoading: false,\r\n            diffError: null,\r\n            diff: { changes: [] },\r\n\r\n            get diffRows() {\r\n                const fields = this.diff?.changes && Array.isArray(this.diff.changes) ? this.diff.changes : [];\r\n\r\n                return fields.map((field) => ({\r\n                    key: field.key,\r\n                    type: field.type,\r\n                    hasDiff: !!(field.diff && field.diff !== ''),\r\n                    current: this.renderValue(field, 'current'),\r\n                    proposed: this.renderValue(field, 'proposed'),\r\n                }));\r\n            },\r\n\r\n            renderValue(field, side) {\r\n                // If field has diff property and it's not empty, show diff\r\n                if (field.diff && field.diff !== '') {\r\n                    const html = (field.diff || '').replace(/\\n/g, '<br/>');\r\n                    return { kind: 'diff', html: html };\r\n                }\r\n\r\n                const value = field[side];\r\n                if (value === null || value === undefined || value === '') {\r\n                    return { kind: 'text', text: '' };\r\n                }\r\n\r\n                switch (field.type) {\r\n                    case 'image':\r\n                        return { kind: 'image', src: `/files/${value}/?preview`, alt: 'preview' };\r\n                    case 'list':\r\n                        const items = Array.isArray(value) ? value : [];\r\n                        const hasObjects = items.length > 0 && typeof items[0] === 'object' && items[0] !== null;\r\n\r\n                        if (hasObjects) {\r\n                            return {\r\n                                kind: 'list-objects',\r\n                                items: items.map(item => {\r\n                                    if (typeof item === 'object' && item !== null) {\r\n                                        return Object.entries(item)\r\n                                            .filter(([key, val]) => val !== null && val !== undefined && val !== '')\r\n                                            .map(([key, val]) => `${key}: ${val}`)\r\n                                            .join(', ');\r\n                                    }\r\n                                    return String(item);\r\n                                })\r\n                            };\r\n                        }\r\n\r\n                        return { kind: 'list', items: items };\r\n                    case 'text':\r\n                    default:\r\n                        return { kind: 'text', text: String(value) };\r\n                }\r\n            },\r\n\r\n            get totalPages() {\r\n                return Math.max(1, Math.ceil(this.total / this.filters.per_page))\r\n            },\r\n\r\n            readFromURL() {\r\n                const url = new URL(window.location.href)\r\n\r\n                const p = parseInt(url.searchParams.get('page') || '', 10)\r\n                if (Number.isFinite(p) && p > 0) this.filters.page = p\r\n                else this.filters.page = 1\r\n            },\r\n\r\n            writeToURL() {\r\n                const url = new URL(window.location.href)\r\n\r\n                if (this.filters.page === 1) url.searchParams.delete('page')\r\n                else url.searchParams.set('page', String(this.filters.page))\r\n\r\n                window.history.replaceState({}, '', url.toString())\r\n            },\r\n\r\n            onPopState() {\r\n                this.readFromURL()\r\n                this.load({ syncURL: false, scrollTop: true })\r\n            },\r\n\r\n            scrollToTop() {\r\n                if (window.scrollY > 80) {\r\n                    window.scrollTo({ top: 0, behavior: 'smooth' })\r\n                }\r\n            },\r\n\r\n            get pageButtons() {\r\n                const max = this.totalPages\r\n                const cur = this.filters.page\r\n\r\n                const start = Math.max(2, cur - 3)\r\n                const end = Math.min(max - 1, cur + 3)\r\n\r\n                const btns = []\r\n                for (let p = start; p <= end; p++) btns.push(p)\r\n                return btns\r\n            },\r\n\r\n            get showLeftDots() {\r\n                return this.pageButtons.length > 0 && this.pageButtons[0] > 2\r\n            },\r\n\r\n            get showRightDots() {\r\n                const btns = this.pageButtons\r\n                return btns.length > 0 && btns[btns.length - 1] < this.totalPages - 1\r\n            },\r\n\r\n            gotoPage(p) {\r\n                if (p < 1) p = 1\r\n                if (p > this.totalPages) p = this.totalPages\r\n                if (p === this.filters.page) return\r\n\r\n                this.filters.page = p\r\n                this.load({ syncURL: true, scrollTop: true })\r\n            },\r\n\r\n            buildLogsQS() {\r\n                const qs = new URLSearchParams({\r\n                    page: String(this.filters.page),\r\n                    per_page: String(this.filters.per_page),\r\n                })\r\n\r\n                for (const id of (this.filters.topic_ids ?? [])) {\r\n                    qs.append('topics', id)\r\n                }\r\n\r\n                return qs.toString()\r\n            },\r\n\r\n            async load(opt) {\r\n                const options = opt || { syncURL: true, scrollTop: true }\r\n                if (this.filters.page < 1) this.filters.page = 1\r\n                if (this.loadedOnce && this.filters.page > this.totalPages) {\r\n                    this.filters.page = this.totalPages\r\n                }\r\n\r\n                if (options.scrollTop) this.scrollToTop()\r\n\r\n                this.loading = true\r\n                this.error = ''\r\n\r\n                try {\r\n                    const { resp, data } = await HTTP.requestJSON('/api/event-logs/?' + this.buildLogsQS())\r\n                    if (resp.status !== 200) {\r\n                        this.error = data?.error || 'Failed to load log'\r\n                        return\r\n                    }\r\n\r\n                    this.logs = data?.data ?? []\r\n                    this.total = data?.count ?? 0\r\n                    this.loadedOnce = true\r\n\r\n                    if (this.filters.page > this.totalPages) {\r\n                        this.filters.page = this.totalPages\r\n                        if (options.syncURL) this.writeToURL()\r\n                        return await this.load({ syncURL: false, scrollTop: false })\r\n                    }\r\n\r\n                    if (options.syncURL) this.writeToURL()\r\n                } catch (e) {\r\n                    this.error = e?.message ?? String(e)\r\n                } finally {\r\n                    this.loading = false\r\n                }\r\n            },\r\n\r\n            resetChangesState() {\r\n                this.selectedLog = null;\r\n                this.diffLoading = false;\r\n                this.diffError = null;\r\n                this.diff = { diff: [] };\r\n            },\r\n\r\n            async openChanges(log) {\r\n                this.resetChangesState();\r\n                this.selectedLog = log;\r\n\r\n                // open modal\r\n                this.$refs.changesModal.showModal();\r\n\r\n                // load changes\r\n                await this.loadChanges(log.id);\r\n            },\r\n\r\n            async loadChanges(id) {\r\n                this.diffLoading = true;\r\n                this.diffError = null;\r\n                this.diff = { diff: [] };\r\n\r\n                try {\r\n                    const resp = await fetch(`/api/event-logs/${id}/changes/`);\r\n                    if (!resp.ok) throw new Error(`HTTP error! status: ${resp.status}`);\r\n\r\n                    const payload = await resp.json();\r\n                    this.diff.changes = Array.isArray(payload?.changes) ? payload.changes : [];\r\n                } catch (e) {\r\n                    console.error('Error loading changes:', e);\r\n                    this.diffError = 'Failed to load changes. Please try again.';\r\n                } finally {\r\n                    this.diffLoading = false;\r\n                }\r\n            },\r\n\r\n            closeChangesModal() {\r\n                this.$refs.changesModal.close();\r\n            },\r\n\r\n            init() {\r\n                this.readFromURL()\r\n                window.addEventListener('popstate', () => this.onPopState())\r\n                this.load({ syncURL: false, scrollTop: false })\r\n            },\r\n        }\r\n    }\r\n</script><div x-data=\"eventLogsPage()\"><h1 class=\"text-3xl mb-3\">Look what we did here:</h1><template x-if=\"loading\"><div>Loading…</div></template><template x-if=\"error\"><div class=\"text-red-600\" x-text=\"error\"></div></template><ul class=\"list bg-base-100 text-lg\"><template x-for=\"l in logs\" :key=\"l.id\"><li class=\"list-row flex items-start justify-between gap-4 w-full\"><div class=\"flex-1 min-w-0\"><div x-text=\"l.date\" class=\"text-gray-500 text-xs\"></div><div x-html=\"l.message\"></div></div><div class=\"flex-shrink-0\"><template x-if=\"l.has_changes\"><button class=\"btn btn-sm btn-circle btn-soft btn-info btn-ghost\" @click=\"openChanges(l)\" title=\"View changes\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script src=\"/assets/helpers.js\"></script><script>\n    function eventLogsPage() {\n        return {\n            logs: [],\n            loading: false,\n            error: '',\n            total: 0,\n            loadedOnce: false,\n\n            filters: {\n                page: 1,\n                per_page: 100,\n            },\n\n            // changes modal state\n            selectedLog: null,\n            diffLoading: false,\n            diffError: null,\n            diff: { changes: [] },\n\n            get diffRows() {\n                const fields = this.diff?.changes && Array.isArray(this.diff.changes) ? this.diff.changes : [];\n\n                return fields.map((field) => ({\n                    key: field.key,\n                    type: field.type,\n                    hasDiff: !!(field.diff && field.diff !== ''),\n                    current: this.renderValue(field, 'current'),\n                    proposed: this.renderValue(field, 'proposed'),\n                }));\n            },\n\n            renderValue(field, side) {\n                // If field has diff property and it's not empty, show diff\n                if (field.diff && field.diff !== '') {\n                    const html = (field.diff || '').replace(/\\n/g, '<br/>');\n                    return { kind: 'diff', html: html };\n                }\n\n                const value = field[side];\n                if (value === null || value === undefined || value === '') {\n                    return { kind: 'text', text: '' };\n                }\n\n                switch (field.type) {\n                    case 'image':\n                        return { kind: 'image', src: `/files/${value}/?preview`, alt: 'preview' };\n                    case 'list':\n                        const items = Array.isArray(value) ? value : [];\n                        const hasObjects = items.length > 0 && typeof items[0] === 'object' && items[0] !== null;\n\n                        if (hasObjects) {\n                            return {\n                                kind: 'list-objects',\n                                items: items.map(item => {\n                                    if (typeof item === 'object' && item !== null) {\n                                        return Object.entries(item)\n                                            .filter(([key, val]) => val !== null && val !== undefined && val !== '')\n                                            .map(([key, val]) => `${key}: ${typeof val === 'object' ? JSON.stringify(val) : val}`)\n                                            .join(', ');\n                                    }\n                                    return String(item);\n                                })\n                            };\n                        }\n\n                        return { kind: 'list', items: items };\n                    case 'text':\n                    default:\n                        return { kind: 'text', text: String(value) };\n                }\n            },\n\n            get totalPages() {\n                return Math.max(1, Math.ceil(this.total / this.filters.per_page))\n            },\n\n            readFromURL() {\n                const url = new URL(window.location.href)\n\n                const p = parseInt(url.searchParams.get('page') || '', 10)\n                if (Number.isFinite(p) && p > 0) this.filters.page = p\n                else this.filters.page = 1\n            },\n\n            writeToURL() {\n                const url = new URL(window.location.href)\n\n                if (this.filters.page === 1) url.searchParams.delete('page')\n                else url.searchParams.set('page', String(this.filters.page))\n\n                window.history.replaceState({}, '', url.toString())\n            },\n\n            onPopState() {\n                this.readFromURL()\n                this.load({ syncURL: false, scrollTop: true })\n            },\n\n            scrollToTop() {\n                if (window.scrollY > 80) {\n                    window.scrollTo({ top: 0, behavior: 'smooth' })\n                }\n            },\n\n            get pageButtons() {\n                const max = this.totalPages\n                const cur = this.filters.page\n\n                const start = Math.max(2, cur - 3)\n                const end = Math.min(max - 1, cur + 3)\n\n                const btns = []\n                for (let p = start; p <= end; p++) btns.push(p)\n                return btns\n            },\n\n            get showLeftDots() {\n                return this.pageButtons.length > 0 && this.pageButtons[0] > 2\n            },\n\n            get showRightDots() {\n                const btns = this.pageButtons\n                return btns.length > 0 && btns[btns.length - 1] < this.totalPages - 1\n            },\n\n            gotoPage(p) {\n                if (p < 1) p = 1\n                if (p > this.totalPages) p = this.totalPages\n                if (p === this.filters.page) return\n\n                this.filters.page = p\n                this.load({ syncURL: true, scrollTop: true })\n            },\n\n            buildLogsQS() {\n                const qs = new URLSearchParams({\n                    page: String(this.filters.page),\n                    per_page: String(this.filters.per_page),\n                })\n\n                for (const id of (this.filters.topic_ids ?? [])) {\n                    qs.append('topics', id)\n                }\n\n                return qs.toString()\n            },\n\n            async load(opt) {\n                const options = opt || { syncURL: true, scrollTop: true }\n                if (this.filters.page < 1) this.filters.page = 1\n                if (this.loadedOnce && this.filters.page > this.totalPages) {\n                    this.filters.page = this.totalPages\n                }\n\n                if (options.scrollTop) this.scrollToTop()\n\n                this.loading = true\n                this.error = ''\n\n                try {\n                    const { resp, data } = await HTTP.requestJSON('/api/event-logs/?' + this.buildLogsQS())\n                    if (resp.status !== 200) {\n                        this.error = data?.error || 'Failed to load log'\n                        return\n                    }\n\n                    this.logs = data?.data ?? []\n                    this.total = data?.count ?? 0\n                    this.loadedOnce = true\n\n                    if (this.filters.page > this.totalPages) {\n                        this.filters.page = this.totalPages\n                        if (options.syncURL) this.writeToURL()\n                        return await this.load({ syncURL: false, scrollTop: false })\n                    }\n\n                    if (options.syncURL) this.writeToURL()\n                } catch (e) {\n                    this.error = e?.message ?? String(e)\n                } finally {\n                    this.loading = false\n                }\n            },\n\n            resetChangesState() {\n                this.selectedLog = null;\n                this.diffLoading = false;\n                this.diffError = null;\n                this.diff = { diff: [] };\n            },\n\n            async openChanges(log) {\n                this.resetChangesState();\n                this.selectedLog = log;\n\n                // open modal\n                this.$refs.changesModal.showModal();\n\n                // load changes\n                await this.loadChanges(log.id);\n            },\n\n            async loadChanges(id) {\n                this.diffLoading = true;\n                this.diffError = null;\n                this.diff = { diff: [] };\n\n                try {\n                    const resp = await fetch(`/api/event-logs/${id}/changes/`);\n                    if (!resp.ok) throw new Error(`HTTP error! status: ${resp.status}`);\n\n                    const payload = await resp.json();\n                    this.diff.changes = Array.isArray(payload?.changes) ? payload.changes : [];\n                } catch (e) {\n                    console.error('Error loading changes:', e);\n                    this.diffError = 'Failed to load changes. Please try again.';\n                } finally {\n                    this.diffLoading = false;\n                }\n            },\n\n            closeChangesModal() {\n                this.$refs.changesModal.close();\n            },\n\n            init() {\n                this.readFromURL()\n                window.addEventListener('popstate', () => this.onPopState())\n                this.load({ syncURL: false, scrollTop: false })\n            },\n        }\n    }\n</script><div x-data=\"eventLogsPage()\"><h1 class=\"text-3xl mb-3\">Look what we did here:</h1><template x-if=\"loading\"><div>Loading…</div></template><template x-if=\"error\"><div class=\"text-red-600\" x-text=\"error\"></div></template><ul class=\"list bg-base-100 text-lg\"><template x-for=\"l in logs\" :key=\"l.id\"><li class=\"list-row flex items-start justify-between gap-4 w-full\"><div class=\"flex-1 min-w-0\"><div x-text=\"l.date\" class=\"text-gray-500 text-xs\"></div><div x-html=\"l.message\"></div></div><div class=\"flex-shrink-0\"><template x-if=\"l.has_changes\"><button class=\"btn btn-sm btn-circle btn-soft btn-info btn-ghost\" @click=\"openChanges(l)\" title=\"View changes\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -10,7 +10,13 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import "github.com/gopl-dev/server/frontend/component/icon"
 
-func UserSettings() templ.Component {
+type ConnectedAccount struct {
+	Provider string
+	Name     string
+	Linked   bool
+}
+
+func UserSettings(accounts []ConnectedAccount) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -31,7 +37,15 @@ func UserSettings() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div><h1 class=\"text-3xl pb-4\">Settings</h1><div class=\"bg-base-100 shadow-md card-body\"><ul class=\"menu bg-base-200 w-56\"><li><a href=\"/change-email/\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<script src=\"/assets/http_helpers.js\"></script><script>\n    function connectedAccounts() {\n        return {\n            error: '',\n\n            async unlink(provider) {\n                this.error = ''\n\n                const { resp, data } = await HTTP.deleteJSON(`/api/users/oauth-accounts/${provider}/`)\n                if (resp.status === 200) {\n                    window.location.reload()\n                    return\n                }\n\n                if (data && typeof data.error === 'string' && data.error.trim() !== '') {\n                    this.error = data.error\n                    return\n                }\n                this.error = `Request failed (HTTP ${resp.status})`\n            },\n        }\n    }\n\n    function userDataExport() {\n        return {\n            error: '',\n            requested: false,\n            submitting: false,\n\n            async request() {\n                this.error = ''\n                this.submitting = true\n\n                const { resp, data } = await HTTP.postJSON('/api/users/data-export/', {})\n                this.submitting = false\n                if (resp.status === 200) {\n                    this.requested = true\n                    return\n                }\n\n                if (data && typeof data.error === 'string' && data.error.trim() !== '') {\n                    this.error = data.error\n                    return\n                }\n                this.error = `Request failed (HTTP ${resp.status})`\n            },\n        }\n    }\n</script><div><h1 class=\"text-3xl pb-4\">Settings</h1><div class=\"bg-base-100 shadow-md card-body\"><ul class=\"menu bg-base-200 w-56\"><li><a href=\"/edit-profile/\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = icon.Pencil().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "Edit profile</a></li><li><a href=\"/change-email/\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -39,7 +53,7 @@ func UserSettings() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "Change email</a></li><li><a href=\"/change-username/\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "Change email</a></li><li><a href=\"/change-username/\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -47,7 +61,7 @@ func UserSettings() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "Change username</a></li><li><a href=\"/change-password/\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "Change username</a></li><li><a href=\"/change-password/\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -55,7 +69,7 @@ func UserSettings() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "Change password</a></li><li><a href=\"/delete-account/\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "Change password</a></li><li><a href=\"/delete-account/\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -63,7 +77,71 @@ func UserSettings() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "Delete account</a></li></ul></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "Delete account</a></li></ul></div><h2 class=\"text-2xl pt-6 pb-4\">Connected accounts</h2><div class=\"bg-base-100 shadow-md card-body\" x-data=\"connectedAccounts()\"><p class=\"text-red-500\" x-text=\"error\" x-show=\"error !== ''\" x-cloak></p><ul class=\"w-full max-w-sm\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, acc := range accounts {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<li class=\"flex items-center justify-between gap-3 py-2\"><span>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var2 string
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(acc.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/user_settings.templ`, Line: 90, Col: 32}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</span> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if acc.Linked {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<button type=\"button\" class=\"btn btn-sm btn-ghost\" @click=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var3 string
+				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("unlink('" + acc.Provider + "')")
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/user_settings.templ`, Line: 95, Col: 65}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\">Disconnect</button>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			} else {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<a class=\"btn btn-sm\" href=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var4 templ.SafeURL
+				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL("/users/connected-accounts/" + acc.Provider + "/link/"))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `frontend/page/user_settings.templ`, Line: 98, Col: 114}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\">Connect</a>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "</ul></div><h2 class=\"text-2xl pt-6 pb-4\">Your data</h2><div class=\"bg-base-100 shadow-md card-body\" x-data=\"userDataExport()\"><p>Get a copy of everything tied to your account: profile, sessions, connected accounts, uploaded files, books, pages, contributions and activity.</p><p class=\"text-red-500\" x-text=\"error\" x-show=\"error !== ''\" x-cloak></p><p class=\"text-gray-500\" x-show=\"requested\" x-cloak>We are preparing your data. You will get an email with a download link shortly.</p><div x-show=\"!requested\"><button type=\"button\" class=\"btn btn-sm\" :disabled=\"submitting\" @click=\"request()\">Request data export</button></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
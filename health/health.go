@@ -0,0 +1,200 @@
+// Package health provides the liveness and readiness checks of the server.
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopl-dev/server/file"
+)
+
+var (
+	// ErrDraining is reported by the readiness check while the server is shutting down.
+	ErrDraining = errors.New("server is shutting down")
+
+	// ErrPendingMigrations indicates that the database schema is behind the application.
+	ErrPendingMigrations = errors.New("pending migrations")
+)
+
+// CheckTimeout limits the duration of a single check.
+const CheckTimeout = 3 * time.Second
+
+// Status is the result of a check or of the whole report.
+type Status string
+
+const (
+	// StatusOK means the check passed.
+	StatusOK Status = "ok"
+
+	// StatusFailed means the check failed.
+	StatusFailed Status = "failed"
+)
+
+// Check is a named dependency probe.
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     Status  `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks; it's failed if any check failed.
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// OK reports whether all checks passed.
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+var draining atomic.Bool
+
+// StartDraining makes the readiness check fail, so the load balancer stops
+// sending new traffic before the server is shut down.
+func StartDraining() {
+	draining.Store(true)
+}
+
+// Draining reports whether the server is shutting down.
+func Draining() bool {
+	return draining.Load()
+}
+
+// Run executes the checks concurrently, each limited by CheckTimeout.
+// Results are in the order of the given checks.
+func Run(ctx context.Context, checks ...Check) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make([]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() {
+			report.Checks[i] = run(ctx, c)
+		})
+	}
+	wg.Wait()
+
+	for _, c := range report.Checks {
+		if c.Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Fn(ctx)
+
+	res := CheckResult{
+		Name:       c.Name,
+		Status:     StatusOK,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000, //nolint:mnd
+	}
+	if err != nil {
+		res.Status = StatusFailed
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// Shutdown is the check that fails once draining has started.
+func Shutdown() Check {
+	return Check{
+		Name: "shutdown",
+		Fn: func(context.Context) error {
+			if Draining() {
+				return ErrDraining
+			}
+
+			return nil
+		},
+	}
+}
+
+// Migrations is the check that fails if any of migrations returned by pending is not applied.
+func Migrations(pending func(ctx context.Context) ([]string, error)) Check {
+	return Check{
+		Name: "migrations",
+		Fn: func(ctx context.Context) error {
+			names, err := pending(ctx)
+			if err != nil {
+				return err
+			}
+			if len(names) > 0 {
+				return fmt.Errorf("%w: %s", ErrPendingMigrations, strings.Join(names, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+// Cached returns the check that runs c at most once per ttl, reporting the cached outcome in between.
+// It's meant for the checks that are costly (e.g. billed writes) to run on every probe.
+// Concurrent calls wait for the running check and share its outcome.
+func Cached(c Check, ttl time.Duration) Check {
+	var (
+		mu        sync.Mutex
+		err       error
+		checkedAt time.Time
+	)
+
+	return Check{
+		Name: c.Name,
+		Fn: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+				return err
+			}
+
+			err = c.Fn(ctx)
+			checkedAt = time.Now()
+
+			return err
+		},
+	}
+}
+
+// Storage is the check that writes and deletes a probe file to verify the storage is writable.
+func Storage(d file.Driver) Check {
+	return Check{
+		Name: "storage",
+		Fn: func(ctx context.Context) error {
+			name, err := d.Store(ctx, bytes.NewReader([]byte("ok")), ".health/probe")
+			if err != nil {
+				return fmt.Errorf("store: %w", err)
+			}
+
+			err = d.Delete(ctx, name)
+			if err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,151 @@
+// Package logging configures structured, leveled logging (log/slog)
+// and carries request-scoped values (request ID, trace ID) into log records.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// ErrInvalidLevel indicates that the configured log level is not recognized.
+	ErrInvalidLevel = errors.New("invalid log level")
+
+	// ErrInvalidFormat indicates that the configured log format is not recognized.
+	ErrInvalidFormat = errors.New("invalid log format")
+)
+
+const (
+	// TextFormat writes records as key=value pairs, handy for local development.
+	TextFormat = "text"
+
+	// JSONFormat writes records as JSON objects, one per line, for log collectors.
+	JSONFormat = "json"
+)
+
+// Setup makes the logger of the given level ("debug", "info", "warn", "error") and format
+// ("text", "json") the default one, including for the standard "log" package.
+// Empty values mean "info" and "text".
+func Setup(level, format string) error {
+	h, err := NewHandler(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(h))
+
+	return nil
+}
+
+// NewHandler returns the handler that writes records of the given level and format to w.
+// Records are enriched with the request and trace IDs found in the context.
+func NewHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	var lvl slog.Level
+	if level != "" {
+		err := lvl.UnmarshalText([]byte(level))
+		if err != nil {
+			return nil, fmt.Errorf("level '%s': %w", level, ErrInvalidLevel)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", TextFormat:
+		h = slog.NewTextHandler(w, opts)
+	case JSONFormat:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("format '%s': %w", format, ErrInvalidFormat)
+	}
+
+	return contextHandler{h}, nil
+}
+
+// contextHandler adds request_id, trace_id, span_id and the attributes set with WithAttrs from the context.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx that carries the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx that adds the attributes (as key-value pairs or slog.Attr)
+// to every record logged with it, e.g. the job name in worker runs.
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	parent, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	r := slog.Record{}
+	r.Add(args...)
+
+	attrs := make([]slog.Attr, 0, len(parent)+r.NumAttrs())
+	attrs = append(attrs, parent...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// NewRequestID returns a random request ID.
+func NewRequestID() string {
+	b := make([]byte, 16) //nolint:mnd
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queryTimeout limits the queries made while metrics are collected.
+const queryTimeout = 5 * time.Second
+
+// DBCollector exports the statistics of the database connection pool.
+type DBCollector struct {
+	db *app.DB
+
+	acquiredConns *prometheus.Desc
+	idleConns     *prometheus.Desc
+	totalConns    *prometheus.Desc
+	maxConns      *prometheus.Desc
+	acquireCount  *prometheus.Desc
+	acquireWait   *prometheus.Desc
+	emptyAcquire  *prometheus.Desc
+	canceledCount *prometheus.Desc
+}
+
+// NewDBCollector returns a collector of the pool statistics of the given database.
+func NewDBCollector(db *app.DB) *DBCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("db_pool_"+name, help, nil, nil)
+	}
+
+	return &DBCollector{
+		db:            db,
+		acquiredConns: desc("acquired_conns", "Number of currently acquired connections."),
+		idleConns:     desc("idle_conns", "Number of currently idle connections."),
+		totalConns:    desc("total_conns", "Total number of connections in the pool."),
+		maxConns:      desc("max_conns", "Maximum size of the pool."),
+		acquireCount:  desc("acquires_total", "Number of successful acquires from the pool."),
+		acquireWait:   desc("acquire_wait_seconds_total", "Total time spent waiting for a connection."),
+		emptyAcquire:  desc("empty_acquires_total", "Number of acquires that waited for a connection."),
+		canceledCount: desc("canceled_acquires_total", "Number of acquires canceled by the context."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *DBCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *DBCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.db.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireWait, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledCount, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
+
+// PendingChangeRequestsCounter is implemented by the service.
+type PendingChangeRequestsCounter interface {
+	CountPendingChangeRequests(ctx context.Context) (int, error)
+}
+
+// NewPendingChangeRequestsGauge returns the gauge of change requests awaiting review.
+// The number is queried on every scrape.
+func NewPendingChangeRequestsGauge(c PendingChangeRequestsCounter) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "app_change_requests_pending",
+		Help: "Number of change requests awaiting review.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+		defer cancel()
+
+		n, err := c.CountPendingChangeRequests(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "metrics: count pending change requests", "error", err)
+			return math.NaN()
+		}
+
+		return float64(n)
+	})
+}
+
+// RegisterDB registers the collectors that read the given database.
+func RegisterDB(db *app.DB, c PendingChangeRequestsCounter) error {
+	err := Registry.Register(NewDBCollector(db))
+	if err != nil {
+		return err
+	}
+
+	return Registry.Register(NewPendingChangeRequestsGauge(c))
+}
@@ -0,0 +1,104 @@
+// Package metrics collects application metrics and exposes them in the Prometheus format.
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the path metrics are served at.
+const Path = "/metrics"
+
+// Registry holds all metrics of the application.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	// UserSignups counts created user accounts, by the sign-up method ("password" or OAuth provider).
+	UserSignups = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "app_user_signups_total",
+		Help: "Number of created user accounts.",
+	}, []string{"method"})
+
+	// BooksSubmitted counts books submitted for review.
+	BooksSubmitted = factory.NewCounter(prometheus.CounterOpts{
+		Name: "app_books_submitted_total",
+		Help: "Number of books submitted for review.",
+	})
+
+	// EmailsSent counts sent emails by template and status ("sent" or "failed").
+	EmailsSent = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "app_emails_total",
+		Help: "Number of emails sent, by template and status.",
+	}, []string{"template", "status"})
+
+	workerJobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_job_runs_total",
+		Help: "Number of worker job runs, by job and status.",
+	}, []string{"job", "status"})
+
+	workerJobDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "worker_job_duration_seconds",
+		Help:    "Duration of worker job runs.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900, 1800, 3600},
+	}, []string{"job"})
+)
+
+// EmailSent records the result of sending the email of the given template.
+func EmailSent(template string, err error) {
+	status := "sent"
+	if err != nil {
+		status = "failed"
+	}
+
+	EmailsSent.WithLabelValues(template, status).Inc()
+}
+
+// WorkerJobRun records the duration and the result of a worker job run.
+func WorkerJobRun(job string, dur time.Duration, err error) {
+	status := "succeeded"
+	if err != nil {
+		status = "failed"
+	}
+
+	workerJobRuns.WithLabelValues(job, status).Inc()
+	workerJobDuration.WithLabelValues(job).Observe(dur.Seconds())
+}
+
+// Handler returns the HTTP handler that serves the collected metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// NewServer returns the server of the separate metrics listener, so metrics are not exposed
+// on the public address. It returns nil if metrics are disabled.
+func NewServer() *http.Server {
+	conf := app.Config().Metrics
+	if !conf.Enabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+Path, Handler())
+
+	return &http.Server{
+		Addr:              net.JoinHostPort(conf.Host, conf.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second, //nolint:mnd
+	}
+}
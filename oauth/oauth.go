@@ -0,0 +1,88 @@
+// Package oauth registers OAuth identity providers enabled in config.
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/oauth/provider"
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/bitbucket"
+	"github.com/markbates/goth/providers/github"
+	"github.com/markbates/goth/providers/gitlab"
+	"github.com/markbates/goth/providers/google"
+	"github.com/markbates/goth/providers/openidConnect"
+)
+
+// ErrDiscoveryURLRequired is returned when the OpenID Connect provider is configured without a discovery URL.
+var ErrDiscoveryURLRequired = errors.New("discovery_url is required")
+
+// factory builds a goth provider from config.
+type factory func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error)
+
+var factories = map[provider.Type]factory{
+	provider.Google: func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+		return google.New(c.ClientID, c.ClientSecret, callbackURL, c.Scopes...), nil
+	},
+	provider.GitHub: func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+		return github.New(c.ClientID, c.ClientSecret, callbackURL, c.Scopes...), nil
+	},
+	provider.GitLab: func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+		return gitlab.New(c.ClientID, c.ClientSecret, callbackURL, c.Scopes...), nil
+	},
+	provider.Bitbucket: func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+		return bitbucket.New(c.ClientID, c.ClientSecret, callbackURL, c.Scopes...), nil
+	},
+	provider.OIDC: func(c app.OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+		if c.DiscoveryURL == "" {
+			return nil, ErrDiscoveryURLRequired
+		}
+
+		p, err := openidConnect.New(c.ClientID, c.ClientSecret, callbackURL, c.DiscoveryURL, c.Scopes...)
+		if err != nil {
+			return nil, err
+		}
+
+		p.SetName(provider.OIDC.String())
+		return p, nil
+	},
+}
+
+// Setup registers every provider enabled in config, both in the provider registry
+// (so it passes validation and shows up on sign-in page) and in goth.
+// Providers that fail to initialize are logged and skipped,
+// so an unreachable SSO doesn't prevent the server from starting.
+func Setup(c *app.ConfigT) {
+	providers := make([]goth.Provider, 0, len(c.OAuth))
+
+	for _, t := range provider.Supported {
+		pc, ok := c.OAuth[t.String()]
+		if !ok || !pc.Enabled() {
+			continue
+		}
+
+		p, err := factories[t](pc, CallbackURL(c, t))
+		if err != nil {
+			slog.Error("oauth: setup provider", "provider", t, "error", err)
+			continue
+		}
+
+		provider.Register(t, pc.Name)
+		providers = append(providers, p)
+	}
+
+	for name := range c.OAuth {
+		if _, ok := factories[provider.New(name)]; !ok {
+			slog.Warn("oauth: unsupported provider", "provider", name)
+		}
+	}
+
+	goth.UseProviders(providers...)
+}
+
+// CallbackURL returns the absolute URL the provider redirects back to after authentication.
+func CallbackURL(c *app.ConfigT, t provider.Type) string {
+	return fmt.Sprintf("%sauth/%s/callback/", c.Server.Addr, t)
+}
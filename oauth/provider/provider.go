@@ -1,31 +1,51 @@
 // Package provider manages OAuth identity provider types and their validation.
 package provider
 
-import z "github.com/Oudwins/zog"
+import (
+	"slices"
+
+	z "github.com/Oudwins/zog"
+)
 
 // Type defines the supported OAuth provider strings.
 type Type string
 
 // Supported OAuth providers.
 const (
-	Google Type = "google"
-	GitHub Type = "github"
+	Google    Type = "google"
+	GitHub    Type = "github"
+	GitLab    Type = "gitlab"
+	Bitbucket Type = "bitbucket"
+	OIDC      Type = "oidc"
 )
 
-// Types is a collection of all valid OAuth provider types.
-var Types = []Type{
+// Supported is a collection of all provider types the server knows how to talk to.
+// Only those enabled in config end up in Types.
+var Supported = []Type{
 	Google,
 	GitHub,
+	GitLab,
+	Bitbucket,
+	OIDC,
 }
 
+// Types is a collection of enabled provider types, in registration order.
+// It is populated by Register at startup and must not be modified afterward.
+var Types []Type
+
 // InvalidType is the fallback string returned when a provider is unrecognized.
 var InvalidType = "invalid_oauth_provider"
 
-var nameByType = map[Type]string{
-	Google: "Google",
-	GitHub: "GitHub",
+var defaultNameByType = map[Type]string{
+	Google:    "Google",
+	GitHub:    "GitHub",
+	GitLab:    "GitLab",
+	Bitbucket: "Bitbucket",
+	OIDC:      "SSO",
 }
 
+var nameByType = map[Type]string{}
+
 // TypeInputRules defines the zog validation logic for provider types.
 var TypeInputRules = z.CustomFunc(func(val *Type, _ z.Ctx) bool {
 	if val == nil || !val.Valid() {
@@ -35,27 +55,64 @@ var TypeInputRules = z.CustomFunc(func(val *Type, _ z.Ctx) bool {
 	return true
 }, z.Message("Invalid provider type"))
 
+// KnownTypeInputRules accepts any supported provider type, enabled or not.
+// It's for the accounts that may have been linked before the provider was disabled.
+var KnownTypeInputRules = z.CustomFunc(func(val *Type, _ z.Ctx) bool {
+	return val != nil && val.Known()
+}, z.Message("Invalid provider type"))
+
+// Register enables the provider type under the given display name.
+// If name is empty, the default name of the provider is used.
+// Registering the same type again only updates its name.
+func Register(t Type, name string) {
+	if name == "" {
+		name = t.DefaultName()
+	}
+
+	if _, ok := nameByType[t]; !ok {
+		Types = append(Types, t)
+	}
+
+	nameByType[t] = name
+}
+
 // String converts the provider type to its raw string representation.
 func (p Type) String() string {
 	return string(p)
 }
 
-// Name returns the capitalized display name of the provider.
+// Name returns the display name of the enabled provider,
+// or the default one if the provider is not enabled.
 func (p Type) Name() string {
 	v, ok := nameByType[p]
 	if ok {
 		return v
 	}
 
+	return p.DefaultName()
+}
+
+// DefaultName returns the display name of the provider used when config doesn't set one.
+func (p Type) DefaultName() string {
+	v, ok := defaultNameByType[p]
+	if ok {
+		return v
+	}
+
 	return InvalidType
 }
 
-// Valid checks if the type exists within the allowed provider map.
+// Valid checks if the provider is enabled.
 func (p Type) Valid() bool {
 	_, ok := nameByType[p]
 	return ok
 }
 
+// Known checks if the provider is one of Supported, enabled or not.
+func (p Type) Known() bool {
+	return slices.Contains(Supported, p)
+}
+
 // New creates a new Type instance from a string.
 func New(s string) Type {
 	return Type(s)
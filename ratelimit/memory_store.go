@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps hits in process memory.
+// Hits outside the window are dropped on access, and keys that were not hit
+// within MaxWindow are swept periodically.
+type MemoryStore struct {
+	mu        sync.Mutex
+	hits      map[string][]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		hits: make(map[string][]time.Time),
+	}
+}
+
+// Hit implements Store.
+func (s *MemoryStore) Hit(_ context.Context, key string, window time.Duration) (
+	count int, oldest time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	hits := append(s.prune(key, window), time.Now())
+	s.hits[key] = hits
+
+	return len(hits), hits[0], nil
+}
+
+// Count implements Store.
+func (s *MemoryStore) Count(_ context.Context, key string, window time.Duration) (
+	count int, oldest time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hits := s.prune(key, window)
+	if len(hits) == 0 {
+		delete(s.hits, key)
+		return 0, time.Time{}, nil
+	}
+
+	s.hits[key] = hits
+	return len(hits), hits[0], nil
+}
+
+// Reset implements Store.
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.hits, key)
+	return nil
+}
+
+// prune returns hits of the key that are still within the window.
+// Hits are kept in chronological order, so expired ones are always at the front.
+func (s *MemoryStore) prune(key string, window time.Duration) []time.Time {
+	hits := s.hits[key]
+	since := time.Now().Add(-window)
+
+	i := 0
+	for i < len(hits) && !hits[i].After(since) {
+		i++
+	}
+
+	return hits[i:]
+}
+
+// sweep removes keys whose latest hit is older than MaxWindow.
+// It runs at most once per MaxWindow.
+func (s *MemoryStore) sweep() {
+	if time.Since(s.lastSweep) < MaxWindow {
+		return
+	}
+
+	since := time.Now().Add(-MaxWindow)
+	for key, hits := range s.hits {
+		if len(hits) == 0 || hits[len(hits)-1].Before(since) {
+			delete(s.hits, key)
+		}
+	}
+
+	s.lastSweep = time.Now()
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopl-dev/server/app/repo"
+)
+
+// PostgresStore keeps hits in the rate_limit_hits table.
+type PostgresStore struct {
+	repo *repo.Repo
+}
+
+// NewPostgresStore creates a new PostgresStore.
+func NewPostgresStore(r *repo.Repo) *PostgresStore {
+	return &PostgresStore{repo: r}
+}
+
+// Hit implements Store.
+func (s *PostgresStore) Hit(ctx context.Context, key string, window time.Duration) (
+	count int, oldest time.Time, err error) {
+	err = s.repo.CreateRateLimitHit(ctx, key, time.Now())
+	if err != nil {
+		return
+	}
+
+	return s.Count(ctx, key, window)
+}
+
+// Count implements Store.
+func (s *PostgresStore) Count(ctx context.Context, key string, window time.Duration) (
+	count int, oldest time.Time, err error) {
+	count, oldestPtr, err := s.repo.CountRateLimitHits(ctx, key, time.Now().Add(-window))
+	if err != nil {
+		return
+	}
+
+	if oldestPtr != nil {
+		oldest = *oldestPtr
+	}
+
+	return
+}
+
+// Reset implements Store.
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.repo.DeleteRateLimitHits(ctx, key)
+}
@@ -0,0 +1,137 @@
+// Package ratelimit implements sliding window rate limiting with pluggable hit stores.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/repo"
+)
+
+const (
+	// PostgresDriver keeps hits in the database, so limits are shared between instances.
+	PostgresDriver = "postgres"
+
+	// MemoryDriver keeps hits in process memory. Suitable for single-node deployments only.
+	MemoryDriver = "memory"
+)
+
+// MaxWindow is the longest window a rule may use.
+// Hits older than that are of no use to any rule and can be removed.
+const MaxWindow = 24 * time.Hour
+
+var (
+	// ErrInvalidDriver indicates that the configured rate limit driver is not recognized.
+	ErrInvalidDriver = errors.New("invalid rate limit driver")
+)
+
+// Rule defines how many hits are allowed within a sliding window.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Store keeps hits of sliding windows.
+type Store interface {
+	// Hit records a hit of the key and returns the number of hits within the window
+	// (including this one) and the time of the oldest of them.
+	Hit(ctx context.Context, key string, window time.Duration) (count int, oldest time.Time, err error)
+
+	// Count is the same as Hit, but without recording a new hit.
+	Count(ctx context.Context, key string, window time.Duration) (count int, oldest time.Time, err error)
+
+	// Reset removes all hits of the key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Limiter checks hits of keys against rules.
+// A nil or disabled Limiter allows everything.
+type Limiter struct {
+	store Store
+}
+
+// New creates a Limiter backed by the given store.
+func New(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// FromConfig creates a Limiter using the driver set in config.
+// If rate limiting is disabled, it returns a Limiter that allows everything.
+func FromConfig(r *repo.Repo) (*Limiter, error) {
+	conf := app.Config().RateLimit
+	if !conf.Enabled {
+		return new(Limiter), nil
+	}
+
+	switch conf.Driver {
+	case PostgresDriver, "":
+		return New(NewPostgresStore(r)), nil
+	case MemoryDriver:
+		return New(NewMemoryStore()), nil
+	default:
+		return nil, fmt.Errorf("driver '%s': %w", conf.Driver, ErrInvalidDriver)
+	}
+}
+
+// Enabled reports whether the limiter actually limits anything.
+func (l *Limiter) Enabled() bool {
+	return l != nil && l.store != nil
+}
+
+// Allow records a hit of the key, unless the key has already reached the rule's limit.
+// In that case it returns how long the caller has to wait before trying again, otherwise zero.
+// Denied hits are not recorded, so the window is freed as soon as the oldest allowed hit leaves it,
+// and the hits of a key never outnumber the limit by more than a few concurrent requests.
+func (l *Limiter) Allow(ctx context.Context, key string, rule Rule) (retryAfter time.Duration, err error) {
+	retryAfter, err = l.Check(ctx, key, rule)
+	if err != nil || retryAfter > 0 {
+		return
+	}
+
+	_, err = l.Hit(ctx, key, rule.Window)
+	return 0, err
+}
+
+// Check reports how long the caller has to wait if the key has already reached
+// the rule's limit, otherwise zero. Unlike Allow, it does not record a hit.
+func (l *Limiter) Check(ctx context.Context, key string, rule Rule) (retryAfter time.Duration, err error) {
+	if !l.Enabled() {
+		return 0, nil
+	}
+
+	count, oldest, err := l.store.Count(ctx, key, rule.Window)
+	if err != nil || count < rule.Limit {
+		return 0, err
+	}
+
+	return retryAfterFrom(oldest, rule.Window), nil
+}
+
+// Hit records a hit of the key without checking it against a rule,
+// and returns the number of hits within the window.
+func (l *Limiter) Hit(ctx context.Context, key string, window time.Duration) (count int, err error) {
+	if !l.Enabled() {
+		return 0, nil
+	}
+
+	count, _, err = l.store.Hit(ctx, key, window)
+	return
+}
+
+// Reset removes all hits of the key.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	return l.store.Reset(ctx, key)
+}
+
+// retryAfterFrom returns the time left until the oldest hit leaves the window.
+// It is never less than a second, so the client always gets a meaningful Retry-After.
+func retryAfterFrom(oldest time.Time, window time.Duration) time.Duration {
+	return max(time.Until(oldest.Add(window)), time.Second)
+}
@@ -1,5 +1,7 @@
 package endpoint
 
+import "github.com/gopl-dev/server/app/service"
+
 // ProtectedAPIEndpoints registers API routes that require authentication.
 func (r *Router) ProtectedAPIEndpoints() {
 	r.POST("/users/email-confirmation-code/", r.handler.SendEmailConfirmationCode)
@@ -8,19 +10,42 @@ func (r *Router) ProtectedAPIEndpoints() {
 
 	// users
 	r.PUT("/users/password/", r.handler.ChangePassword)
-	r.POST("/users/email/", r.handler.RequestEmailChange)
+	r.POST("/users/email/", r.mw.RateLimit(service.RateLimitEmailChange)(r.handler.RequestEmailChange))
 	r.PUT("/users/email/", r.handler.ConfirmEmailChange)
 	r.PUT("/users/username/", r.handler.ChangeUsername)
+	r.PUT("/users/profile/", r.handler.UpdateUserProfile)
+	r.POST("/users/data-export/", r.handler.RequestUserDataExport)
 	r.DELETE("/users/", r.handler.DeleteUser)
+	r.GET("/users/oauth-accounts/", r.handler.GetOAuthAccounts)
+	r.DELETE("/users/oauth-accounts/{provider}/", r.handler.UnlinkOAuthAccount)
 
 	// books
 	r.POST("/books/", r.handler.CreateBook)
+	r.POST("/books/import/", r.mw.AdminOnly(r.handler.ImportBooks))
+	r.GET("/books/isbn/{isbn}/", r.handler.LookupBookMetadata)
+	r.POST("/books/duplicates/", r.handler.FindNewBookDuplicates)
 	r.Group("/books/{id}/", r.mw.RequestBook).
 		PUT("/", r.handler.UpdateBook).
 		DELETE("/", r.handler.DeleteBook).
 		GET("/edit/", r.handler.GetBookEditState).
 		PUT("/approve/", r.handler.ApproveNewBook).
-		PUT("/reject/", r.handler.RejectNewBook)
+		PUT("/reject/", r.handler.RejectNewBook).
+		PUT("/review/", r.handler.SaveBookReview).
+		DELETE("/review/", r.handler.DeleteBookReview).
+		GET("/duplicates/", r.mw.AdminOnly(r.handler.FindBookDuplicates)).
+		POST("/merge/", r.mw.AdminOnly(r.handler.MergeBook))
+
+	// book reviews
+	r.POST("/book-reviews/{id}/report/", r.handler.ReportBookReview)
+	r.Group("/book-reviews/", r.mw.AdminOnly).
+		GET("/", r.handler.FilterModeratedBookReviews).
+		PUT("/{id}/hide/", r.handler.HideBookReview).
+		PUT("/{id}/unhide/", r.handler.UnhideBookReview)
+
+	// authors
+	r.Group("/authors/{id}/", r.mw.AdminOnly).
+		PUT("/", r.handler.UpdateAuthor).
+		POST("/merge/", r.handler.MergeAuthor)
 
 	// pages
 	r.POST("/pages/", r.handler.CreatePage)
@@ -37,4 +62,27 @@ func (r *Router) ProtectedAPIEndpoints() {
 		GET("/", r.handler.FilterChangeRequests).
 		PUT("/{id}/apply/", r.handler.ApplyChangeRequest).
 		PUT("/{id}/reject/", r.handler.RejectChangeRequest)
+
+	// webhooks
+	r.Group("/webhooks/", r.mw.AdminOnly).
+		GET("/", r.handler.GetWebhooks).
+		POST("/", r.handler.CreateWebhook).
+		PUT("/{id}/", r.handler.UpdateWebhook).
+		DELETE("/{id}/", r.handler.DeleteWebhook).
+		POST("/{id}/test/", r.handler.TestWebhook).
+		GET("/{id}/deliveries/", r.handler.FilterWebhookDeliveries).
+		POST("/{id}/deliveries/{delivery_id}/redeliver/", r.handler.RedeliverWebhookDelivery)
+
+	// external links
+	r.Group("/external-links/", r.mw.AdminOnly).
+		GET("/", r.handler.FilterExternalLinks).
+		GET("/{id}/checks/", r.handler.FilterExternalLinkChecks)
+
+	// worker jobs
+	r.Group("/worker-jobs/", r.mw.AdminOnly).
+		GET("/", r.handler.GetWorkerJobs).
+		GET("/{name}/runs/", r.handler.FilterWorkerJobRuns).
+		PUT("/{name}/pause/", r.handler.PauseWorkerJob).
+		PUT("/{name}/resume/", r.handler.ResumeWorkerJob).
+		POST("/{name}/run/", r.handler.RunWorkerJob)
 }
@@ -8,11 +8,14 @@ func (r *Router) ProtectedWebEndpoints() {
 	r.Use(r.mw.EmailMustBeConfirmed)
 	// users
 	r.GET("/users/settings/", r.handler.UserSettingsView)
+	r.GET("/edit-profile/", r.handler.EditUserProfileView)
 	r.GET("/change-password/", r.handler.ChangePasswordView)
 	r.GET("/change-email/", r.handler.RequestEmailChangeView)
 	r.GET("/change-email/{token}/", r.handler.ConfirmEmailChangeView)
 	r.GET("/change-username/", r.handler.ChangeUsernameView)
 	r.GET("/delete-account/", r.handler.DeleteUserView)
+	r.GET("/data-export/{token}/", r.handler.DownloadUserDataExport)
+	r.GET("/users/connected-accounts/{provider}/link/", r.handler.OAuthLinkStart)
 
 	// books
 	r.GET("/add-book/", r.handler.CreateBookView)
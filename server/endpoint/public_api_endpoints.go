@@ -1,23 +1,32 @@
 package endpoint
 
+import "github.com/gopl-dev/server/app/service"
+
 // PublicAPIEndpoints registers all publicly accessible API routes.
 func (r *Router) PublicAPIEndpoints() {
 	r.GET("status/", r.handler.ServerStatus)
 
 	// users
 	r.Group("users").
-		POST("sign-up/", r.handler.UserSignUp).
-		POST("sign-in/", r.handler.UserSignIn).
+		POST("sign-up/", r.mw.RateLimit(service.RateLimitSignUp)(r.handler.UserSignUp)).
+		POST("sign-in/", r.mw.RateLimit(service.RateLimitSignIn)(r.handler.UserSignIn)).
 		POST("confirm-email/", r.handler.ConfirmEmail).
-		POST("password-reset-request/", r.handler.PasswordResetRequest).
-		POST("password-reset/", r.handler.PasswordResetConfirm)
+		POST("password-reset-request/", r.mw.RateLimit(service.RateLimitPasswordReset)(r.handler.PasswordResetRequest)).
+		POST("password-reset/", r.handler.PasswordResetConfirm).
+		GET("{username}/", r.handler.GetUserProfile)
 
 	// books
 	r.Group("books").
 		GET("/", r.handler.FilterBooks).
 		GET("/search/", r.handler.SearchBooks).
 		Use(r.mw.RequestBook).
-		GET("{id}/", r.handler.GetBook)
+		GET("{id}/", r.handler.GetBook).
+		GET("{id}/reviews/", r.handler.FilterBookReviews)
+
+	// authors
+	r.Group("authors").
+		GET("/", r.handler.FilterAuthors).
+		GET("{slug}/", r.handler.GetAuthor)
 
 	// files
 	r.Group("files").
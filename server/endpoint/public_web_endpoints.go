@@ -4,9 +4,15 @@ package endpoint
 func (r *Router) PublicWebEndpoints() {
 	r.GET("/", r.handler.Home)
 
+	// crawlers
+	r.GET("/robots.txt", r.handler.Robots)
+	r.GET("/sitemap.xml", r.handler.Sitemap)
+	r.GET("/sitemaps/{name}", r.handler.SitemapPart)
+
 	// User authentication and registration
 	r.GET("/users/sign-up/", r.handler.UserSignUpView)
 	r.GET("/users/sign-in/", r.handler.UserSignInView)
+	r.GET("/users/{username}/", r.handler.UserProfileView)
 
 	r.GET("/password-reset/", r.handler.PasswordResetRequestView)
 	r.GET("/password-reset/{token}/", r.handler.PasswordResetConfirmView)
@@ -17,8 +23,14 @@ func (r *Router) PublicWebEndpoints() {
 
 	// books
 	r.GET("/books/", r.handler.FilterBooksView)
+	r.Feed("/books/feed", r.handler.BooksFeed)
+	r.Feed("/books/topics/{topic}/feed", r.handler.TopicBooksFeed)
 	r.Group("/books/{id}/", r.mw.RequestBook).
-		GET("/", r.handler.GetBookView)
+		GET("/", r.handler.GetBookView).
+		GET("/social.jpg", r.handler.BookSocialImage)
+
+	// authors
+	r.GET("/authors/{slug}/", r.handler.AuthorView)
 
 	// files
 	r.Group("files/{id}").
@@ -27,4 +39,8 @@ func (r *Router) PublicWebEndpoints() {
 
 	// activity log
 	r.GET("/activity-log/", r.handler.FilterEventLogsView)
+	r.Feed("/activity-log/feed", r.handler.ActivityLogFeed)
+
+	// pages
+	r.Feed("/pages/feed", r.handler.PagesFeed)
 }
@@ -3,14 +3,16 @@ package endpoint
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/feed"
 	"github.com/gopl-dev/server/frontend"
+	"github.com/gopl-dev/server/metrics"
 	"github.com/gopl-dev/server/server/docs"
 	"github.com/gopl-dev/server/server/handler"
 	"github.com/gopl-dev/server/server/middleware"
@@ -87,6 +89,16 @@ func (r *Router) DELETE(pattern string, handler handler.Fn) *Router {
 	return r
 }
 
+// Feed registers a GET handler for every syndication format of the feed at the specified pattern,
+// e.g. "/books/feed" is served as "/books/feed.atom", "/books/feed.rss" and "/books/feed.json".
+func (r *Router) Feed(pattern string, handler handler.Fn) *Router {
+	for _, f := range feed.Formats {
+		r.register(http.MethodGet, pattern+"."+f.Ext(), handler)
+	}
+
+	return r
+}
+
 // HandleAssets registers the handler for serving static assets.
 // It uses a different approach depending on the environment:
 //   - In development (`dev`), it serves files directly from the local disk
@@ -107,6 +119,15 @@ func (r *Router) HandleAssets() *Router {
 	return r
 }
 
+// HandleHealthChecks registers the liveness ("/healthz") and readiness ("/readyz") probes.
+// They are registered without middlewares, so frequent probing doesn't flood the logs.
+func (r *Router) HandleHealthChecks() *Router {
+	r.mux.HandleFunc("GET /healthz", r.handler.Liveness)
+	r.mux.HandleFunc("GET /readyz", r.handler.Readiness)
+
+	return r
+}
+
 // HandleOpenAPIDocs registers a file server handler to serve static swagger files.
 func (r *Router) HandleOpenAPIDocs() *Router {
 	conf := app.Config()
@@ -116,7 +137,7 @@ func (r *Router) HandleOpenAPIDocs() *Router {
 
 	serverURL, err := url.Parse(conf.Server.Addr)
 	if err != nil {
-		log.Printf("OpenAPIHandler: could not parse server address: %s\n", err)
+		slog.Error("OpenAPIHandler: could not parse server address", "error", err)
 		return r
 	}
 
@@ -144,8 +165,9 @@ func (r *Router) HandleNotFound() *Router {
 		pattern += "/"
 	}
 
+	// the route matches any method, so all of its requests share the same label
 	h := r.applyMWsToHandler(r.handler.RenderPageOrNotFound)
-	r.mux.HandleFunc(pattern, h)
+	r.mux.Handle(pattern, metrics.InstrumentHandler("ANY", pattern, h))
 	return r
 }
 
@@ -181,8 +203,10 @@ func (r *Router) register(method, pattern string, h handler.Fn) {
 		pattern += exactMatchSuffix
 	}
 
+	route := strings.TrimSuffix(pattern, exactMatchSuffix)
+
 	pattern = method + " " + pattern
-	fmt.Println(pattern)
+	slog.Debug("route registered", "pattern", pattern)
 
-	r.mux.Handle(pattern, http.HandlerFunc(h))
+	r.mux.Handle(pattern, metrics.InstrumentHandler(method, route, h))
 }
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/frontend/layout"
+	"github.com/gopl-dev/server/frontend/page"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// authorBooksPerPage is the max number of books listed on the author page.
+const authorBooksPerPage = 100
+
+// FilterAuthors handles API requests for retrieving authors.
+//
+//	@ID			FilterAuthors
+//	@Summary	Get authors
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		params	query		request.FilterAuthors	false	"Query parameters"
+//	@Success	200		{object}	response.FilterAuthors
+//	@Failure	500		{object}	Error
+//	@Router		/authors/ [get]
+func (h *Handler) FilterAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterAuthors")
+	defer span.End()
+
+	var req request.FilterAuthors
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterAuthors(ctx, req.ToFilter())
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterAuthors{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// GetAuthor handles API requests for retrieving an author along with their books.
+// An author merged into another one resolves to the latter.
+//
+//	@ID			GetAuthor
+//	@Summary	Get author
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		slug	path		string	true	"Author slug"
+//	@Success	200		{object}	response.Author
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/authors/{slug}/ [get]
+func (h *Handler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "GetAuthor")
+	defer span.End()
+
+	author, err := h.authorFromPath(ctx, r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	books, err := h.authorBooks(ctx, author)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.Author{
+		Author: *author,
+		Books:  books,
+	})
+}
+
+// UpdateAuthor handles API requests for editing an author.
+//
+//	@ID			UpdateAuthor
+//	@Summary	Update author
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Author ID"
+//	@Param		request	body		request.UpdateAuthor	true	"Request body"
+//	@Success	200		{object}	ds.Author
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/authors/{id}/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "UpdateAuthor")
+	defer span.End()
+
+	var req request.UpdateAuthor
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	id, err := idFromPath(r)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	author, err := h.service.UpdateAuthor(ctx, id, service.UpdateAuthorInput{
+		Name:  req.Name,
+		Bio:   req.Bio,
+		Links: req.Links,
+	})
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(author)
+}
+
+// MergeAuthor handles API requests for merging a duplicate author into another one.
+// The books of the author are moved to the other one, which is returned.
+//
+//	@ID			MergeAuthor
+//	@Summary	Merge author
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string				true	"Author ID"
+//	@Param		request	body		request.MergeAuthor	true	"Request body"
+//	@Success	200		{object}	ds.Author
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/authors/{id}/merge/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) MergeAuthor(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MergeAuthor")
+	defer span.End()
+
+	var req request.MergeAuthor
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	id, err := idFromPath(r)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	into, err := h.service.GetAuthorBySlug(ctx, req.Into)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	author, err := h.service.MergeAuthor(ctx, id, into.ID)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(author)
+}
+
+// AuthorView renders the page of the author listing their books.
+// The page of an author merged into another one redirects to the page of the latter.
+func (h *Handler) AuthorView(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AuthorView")
+	defer span.End()
+
+	author, err := h.authorFromPath(ctx, r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	if author.Slug != r.PathValue("slug") {
+		http.Redirect(w, r, author.ViewURL(), http.StatusMovedPermanently)
+		return
+	}
+
+	books, err := h.authorBooks(ctx, author)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	user := ds.UserFromContext(ctx)
+	broken, err := h.brokenExternalLinks(ctx, user != nil && user.IsAdmin, ds.ExternalLinkSourceAuthor, author.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	RenderDefaultLayout(ctx, w, layout.Data{
+		Title:           author.Name,
+		MetaDescription: shareDescription(author.Bio),
+		Body:            page.ViewAuthorPage(user, author, books, broken),
+	})
+}
+
+// authorFromPath returns the author of the slug given in the path,
+// or the author it's merged into.
+func (h *Handler) authorFromPath(ctx context.Context, r *http.Request) (*ds.Author, error) {
+	author, err := h.service.GetAuthorBySlug(ctx, r.PathValue("slug"))
+	if err != nil {
+		return nil, err
+	}
+
+	if author.Merged() {
+		return h.service.GetAuthorByID(ctx, *author.MergedIntoID)
+	}
+
+	return author, nil
+}
+
+// authorBooks returns the approved public books of the author.
+func (h *Handler) authorBooks(ctx context.Context, author *ds.Author) ([]ds.Book, error) {
+	books, _, err := h.service.FilterBooks(ctx, ds.BooksFilter{
+		EntitiesFilter: ds.EntitiesFilter{
+			PerPage:    authorBooksPerPage,
+			Status:     []ds.EntityStatus{ds.EntityStatusApproved},
+			Visibility: []ds.EntityVisibility{ds.EntityVisibilityPublic},
+		},
+		Author: author.Slug,
+		Sort:   ds.BookSortReleaseDate,
+	})
+
+	return books, err
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+)
+
+// FindNewBookDuplicates handles API requests for checking whether a book about to be submitted
+// already exists, so the submitter can be warned before creating it.
+//
+//	@ID			FindNewBookDuplicates
+//	@Summary	Find duplicates of new book
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		request.CreateBook	true	"Request body"
+//	@Success	200		{array}		ds.BookDuplicate
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/duplicates/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) FindNewBookDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FindNewBookDuplicates")
+	defer span.End()
+
+	var req request.CreateBook
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	duplicates, err := h.service.FindBookDuplicates(ctx, req.ToBook())
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(duplicates)
+}
+
+// FindBookDuplicates handles API requests for the likely duplicates of a book, for the reviewers of new books.
+//
+//	@ID			FindBookDuplicates
+//	@Summary	Find book duplicates
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Book ID"
+//	@Success	200	{array}		ds.BookDuplicate
+//	@Failure	400	{object}	Error
+//	@Failure	401	{object}	Error
+//	@Failure	500	{object}	Error
+//	@Router		/books/{id}/duplicates/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FindBookDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FindBookDuplicates")
+	defer span.End()
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		Abort(w, r, app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	duplicates, err := h.service.FindBookDuplicates(ctx, book)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, duplicates)
+}
+
+// MergeBook handles API requests for merging a new book into an existing duplicate of it.
+// The topics and links of the new book are carried over, and the new book is rejected.
+//
+//	@ID			MergeBook
+//	@Summary	Merge book into existing
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string				true	"Book ID"
+//	@Param		request	body		request.MergeBook	true	"Request body"
+//	@Success	200		{object}	ds.Book
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/{id}/merge/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) MergeBook(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MergeBook")
+	defer span.End()
+
+	var req request.MergeBook
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		res.Abort(app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	target, err := h.service.MergeBook(ctx, book, req.IntoID)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(target)
+}
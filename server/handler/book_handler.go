@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"mime"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
 	"github.com/gopl-dev/server/frontend/layout"
 	"github.com/gopl-dev/server/frontend/page"
 	"github.com/gopl-dev/server/server/request"
@@ -47,6 +51,105 @@ func (h *Handler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	res.jsonCreated(book)
 }
 
+// ImportBooks handles the API request for creating books in bulk from a CSV or JSON file, or a list of ISBNs.
+// The CSV file must start with a header row naming the columns (e.g. "title,authors,topics").
+//
+//	@ID			ImportBooks
+//	@Summary	Import books
+//	@Tags		books
+//	@Accept		json
+//	@Accept		text/csv
+//	@Accept		plain
+//	@Produce	json
+//	@Param		dry_run	query		bool					false	"Validate the books without creating them"
+//	@Param		format	query		string					false	"Format of the body, csv, json or isbn (default from Content-Type)"
+//	@Param		topics	query		[]string				false	"Topics of the books that have none"
+//	@Param		request	body		[]ds.BookImportRow		true	"Books"
+//	@Success	200		{object}	ds.BookImportReport
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	403		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/import/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ImportBooks")
+	defer span.End()
+
+	if ds.UserFromContext(ctx) == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	var req request.ImportBooks
+	bindQuery(r, &req)
+
+	if req.Format == "" {
+		req.Format = service.BookImportJSON
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			req.Format = service.BookImportCSV
+		case "text/plain":
+			req.Format = service.BookImportISBNList
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 10<<20) //nolint:mnd
+
+	rows, err := service.ParseBookImport(r.Body, req.Format)
+	if err != nil {
+		Abort(w, r, app.ErrBadRequest("Invalid import file: %v", err))
+		return
+	}
+
+	report, err := h.service.ImportBooks(ctx, service.ImportBooksArgs{
+		Rows:   rows,
+		DryRun: req.DryRun,
+		Topics: req.Topics,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, report)
+}
+
+// LookupBookMetadata handles the API request for the metadata of a book by ISBN, to prefill a new book.
+// The cover, if found, is downloaded as a temporary file of the user (cover_file_id).
+//
+//	@ID			LookupBookMetadata
+//	@Summary	Look up book metadata by ISBN
+//	@Tags		books
+//	@Produce	json
+//	@Param		isbn	path		string	true	"ISBN-10 or ISBN-13"
+//	@Success	200		{object}	service.BookMetadata
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	429		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/isbn/{isbn}/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) LookupBookMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "LookupBookMetadata")
+	defer span.End()
+
+	if ds.UserFromContext(ctx) == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	meta, err := h.service.LookupBookMetadata(ctx, r.PathValue("isbn"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, meta)
+}
+
 // UpdateBook handles the API request for updating book.
 //
 //	@ID			UpdateBook
@@ -182,14 +285,12 @@ func (h *Handler) FilterBooks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filter := ds.EntitiesFilter{
-		Page:           req.Page,
-		PerPage:        req.PerPage,
-		WithCount:      true,
-		Status:         req.Status,
-		Visibility:     req.Visibility,
-		Topics:         req.Topics,
-		OrderBy:        "b.release_date_sort",
-		OrderDirection: "desc",
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		WithCount:  true,
+		Status:     req.Status,
+		Visibility: req.Visibility,
+		Topics:     req.Topics,
 	}
 
 	if !isAdmin {
@@ -197,9 +298,14 @@ func (h *Handler) FilterBooks(w http.ResponseWriter, r *http.Request) {
 		filter.Visibility = []ds.EntityVisibility{ds.EntityVisibilityPublic}
 	}
 
+	if !req.Sort.Valid() {
+		req.Sort = ds.BookSortReleaseDate
+	}
+
 	books, count, err := h.service.FilterBooks(ctx, ds.BooksFilter{
 		EntitiesFilter: filter,
 		Author:         req.Author,
+		Sort:           req.Sort,
 	})
 	if err != nil {
 		Abort(w, r, err)
@@ -248,9 +354,15 @@ func (h *Handler) FilterBooksView(w http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "FilterBooksView")
 	defer span.End()
 
+	feeds := FeedLinks("New books", "/books/feed")
+	if topics := r.URL.Query()["topics"]; len(topics) == 1 {
+		feeds = append(feeds, FeedLinks("New books on "+topics[0], "/books/topics/"+url.PathEscape(topics[0])+"/feed")...)
+	}
+
 	RenderDefaultLayout(ctx, w, layout.Data{
 		Title: "Books",
 		Body:  page.FilterBooksPage(),
+		Feeds: feeds,
 	})
 }
 
@@ -265,12 +377,58 @@ func (h *Handler) GetBookView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	reviews, err := h.bookReviewsData(ctx, book)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	user := ds.UserFromContext(ctx)
+	canEdit := user != nil && (book.Status == ds.EntityStatusApproved || user.IsAdmin)
+	broken, err := h.brokenExternalLinks(ctx, canEdit, ds.ExternalLinkSourceBook, book.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
 	RenderDefaultLayout(ctx, w, layout.Data{
-		Title: book.Title,
-		Body:  page.ViewBookPage(ds.UserFromContext(ctx), book),
+		Title:           book.Title,
+		MetaDescription: shareDescription(book.Summary),
+		Body:            page.ViewBookPage(user, book, reviews, broken),
+		Share:           bookShare(book),
 	})
 }
 
+// BookSocialImage serves the image shown when a link to the book is shared.
+func (h *Handler) BookSocialImage(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "BookSocialImage")
+	defer span.End()
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		Abort(w, r, app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	fh, _, version, err := h.service.GetBookSocialPreview(ctx, book)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+	defer func() {
+		closeErr := fh.Close()
+		if closeErr != nil {
+			span.RecordError(closeErr)
+		}
+	}()
+
+	w.Header().Set("ETag", `"`+version+`"`)
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+
+	http.ServeContent(w, r, "", time.Time{}, fh)
+}
+
 // GetBookEditState return state of book changes for current user
 //
 //	@ID			GetBookEditState
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/frontend/page"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// FilterBookReviews handles API requests for retrieving the visible reviews of a book along with its rating.
+//
+//	@ID			FilterBookReviews
+//	@Summary	Get book reviews
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string						true	"Book ID"
+//	@Param		params	query		request.FilterBookReviews	false	"Query parameters"
+//	@Success	200		{object}	response.FilterBookReviews
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/{id}/reviews/ [get]
+func (h *Handler) FilterBookReviews(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterBookReviews")
+	defer span.End()
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		Abort(w, r, app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	var req request.FilterBookReviews
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterBookReviews(ctx, ds.BookReviewsFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		BookID:    new(book.ID),
+		Hidden:    new(false),
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	rating, err := h.service.GetBookRating(ctx, book.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterBookReviews{
+		Data:   data,
+		Count:  count,
+		Rating: rating,
+	})
+}
+
+// SaveBookReview handles API requests for reviewing a book.
+// A user has one review per book, so reviewing the book again updates the review.
+//
+//	@ID			SaveBookReview
+//	@Summary	Review book
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Book ID"
+//	@Param		request	body		request.SaveBookReview	true	"Request body"
+//	@Success	200		{object}	ds.BookReview
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	429		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/books/{id}/review/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) SaveBookReview(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SaveBookReview")
+	defer span.End()
+
+	var req request.SaveBookReview
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		res.Abort(app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	review, err := h.service.SaveBookReview(ctx, book, service.BookReviewInput{
+		Rating: req.Rating,
+		Body:   req.Body,
+	})
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(review)
+}
+
+// DeleteBookReview handles API requests for deleting the user's review of a book.
+//
+//	@ID			DeleteBookReview
+//	@Summary	Delete book review
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Book ID"
+//	@Success	200	{object}	response.Status
+//	@Failure	401	{object}	Error
+//	@Failure	404	{object}	Error
+//	@Failure	500	{object}	Error
+//	@Router		/books/{id}/review/ [delete]
+//	@Security	ApiKeyAuth
+func (h *Handler) DeleteBookReview(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "DeleteBookReview")
+	defer span.End()
+
+	book := ds.BookFromContext(ctx)
+	if book == nil {
+		Abort(w, r, app.ErrBadRequest("book is missing from context"))
+		return
+	}
+
+	err := h.service.DeleteBookReview(ctx, book)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.Success)
+}
+
+// ReportBookReview handles API requests for reporting a book review to moderators.
+//
+//	@ID			ReportBookReview
+//	@Summary	Report book review
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Review ID"
+//	@Param		request	body		request.ReportBookReview	true	"Request body"
+//	@Success	200		{object}	response.Status
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/book-reviews/{id}/report/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) ReportBookReview(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ReportBookReview")
+	defer span.End()
+
+	var req request.ReportBookReview
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	id, err := idFromPath(r)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	err = h.service.ReportBookReview(ctx, id, service.BookReviewReportInput{
+		Reason: req.Reason,
+	})
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonSuccess()
+}
+
+// FilterModeratedBookReviews handles API requests for retrieving book reviews to moderate.
+//
+//	@ID			FilterModeratedBookReviews
+//	@Summary	Get book reviews to moderate
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		params	query		request.FilterModeratedBookReviews	false	"Query parameters"
+//	@Success	200		{object}	response.FilterModeratedBookReviews
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/book-reviews/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FilterModeratedBookReviews(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterModeratedBookReviews")
+	defer span.End()
+
+	var req request.FilterModeratedBookReviews
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterBookReviews(ctx, ds.BookReviewsFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		Reported:  req.Reported,
+		Hidden:    req.Hidden,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterModeratedBookReviews{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// HideBookReview handles API requests for hiding a book review.
+// Hidden reviews are not listed and don't count towards the rating of the book.
+//
+//	@ID			HideBookReview
+//	@Summary	Hide book review
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Review ID"
+//	@Success	200	{object}	ds.BookReview
+//	@Failure	401	{object}	Error
+//	@Failure	404	{object}	Error
+//	@Failure	500	{object}	Error
+//	@Router		/book-reviews/{id}/hide/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) HideBookReview(w http.ResponseWriter, r *http.Request) {
+	h.setBookReviewHidden(w, r, true)
+}
+
+// UnhideBookReview handles API requests for making a hidden book review visible again.
+//
+//	@ID			UnhideBookReview
+//	@Summary	Unhide book review
+//	@Tags		books
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Review ID"
+//	@Success	200	{object}	ds.BookReview
+//	@Failure	401	{object}	Error
+//	@Failure	404	{object}	Error
+//	@Failure	500	{object}	Error
+//	@Router		/book-reviews/{id}/unhide/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) UnhideBookReview(w http.ResponseWriter, r *http.Request) {
+	h.setBookReviewHidden(w, r, false)
+}
+
+func (h *Handler) setBookReviewHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	ctx, span := h.tracer.Start(r.Context(), "SetBookReviewHidden")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	review, err := h.service.SetBookReviewHidden(ctx, id, hidden)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, review)
+}
+
+// bookReviewsData loads the rating, the first page of the visible reviews
+// and the review of the current user shown on the book page.
+func (h *Handler) bookReviewsData(ctx context.Context, book *ds.Book) (d page.BookReviewsData, err error) {
+	d.Reviews, d.Count, err = h.service.FilterBookReviews(ctx, ds.BookReviewsFilter{
+		WithCount: true,
+		BookID:    new(book.ID),
+		Hidden:    new(false),
+	})
+	if err != nil {
+		return
+	}
+
+	d.Rating, err = h.service.GetBookRating(ctx, book.ID)
+	if err != nil {
+		return
+	}
+
+	d.Own, err = h.service.GetUserBookReview(ctx, book.ID)
+	return
+}
@@ -18,6 +18,7 @@ func (h *Handler) FilterEventLogsView(w http.ResponseWriter, r *http.Request) {
 	RenderDefaultLayout(ctx, w, layout.Data{
 		Title: "Activity Log",
 		Body:  page.FilterEventLogsPage(),
+		Feeds: FeedLinks("Activity log", "/activity-log/feed"),
 	})
 }
 
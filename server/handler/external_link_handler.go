@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// FilterExternalLinks handles API requests for retrieving the links to other sites
+// found in books, authors and pages, along with the result of their latest check.
+//
+//	@ID			FilterExternalLinks
+//	@Summary	Get external links
+//	@Tags		external-links
+//	@Accept		json
+//	@Produce	json
+//	@Param		params	query		request.FilterExternalLinks	false	"Query parameters"
+//	@Success	200		{object}	response.FilterExternalLinks
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/external-links/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FilterExternalLinks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterExternalLinks")
+	defer span.End()
+
+	var req request.FilterExternalLinks
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterExternalLinks(ctx, ds.ExternalLinksFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		Status:    req.Status,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterExternalLinks{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// FilterExternalLinkChecks handles API requests for retrieving the history of checks of an external link.
+//
+//	@ID			FilterExternalLinkChecks
+//	@Summary	Get external link checks
+//	@Tags		external-links
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string							true	"External link ID"
+//	@Param		params	query		request.FilterExternalLinkChecks	false	"Query parameters"
+//	@Success	200		{object}	response.FilterExternalLinkChecks
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/external-links/{id}/checks/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FilterExternalLinkChecks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterExternalLinkChecks")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	var req request.FilterExternalLinkChecks
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterExternalLinkChecks(ctx, ds.ExternalLinkChecksFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		LinkID:    id,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterExternalLinkChecks{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// brokenExternalLinks returns the broken links found in the record for the users who can edit it
+// to fix them, or nothing for the others.
+func (h *Handler) brokenExternalLinks(ctx context.Context, canEdit bool, sourceType ds.ExternalLinkSourceType,
+	sourceID ds.ID) ([]ds.ExternalLink, error) {
+	if !canEdit {
+		return nil, nil
+	}
+
+	return h.service.GetBrokenExternalLinks(ctx, sourceType, sourceID)
+}
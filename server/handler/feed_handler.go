@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/feed"
+	"github.com/gopl-dev/server/frontend/layout"
+)
+
+// BooksFeed renders the feed of newly approved books.
+func (h *Handler) BooksFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "BooksFeed")
+	defer span.End()
+
+	books, err := h.service.LatestPublishedBooks(ctx, "")
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	renderFeed(w, r, &feed.Feed{
+		Title:       "New books | gopl.dev",
+		Description: "Newly added books about Go",
+		Link:        app.ServerURL("/books") + "/",
+		Items:       bookFeedItems(books),
+	})
+}
+
+// TopicBooksFeed renders the feed of newly approved books of a single topic.
+func (h *Handler) TopicBooksFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TopicBooksFeed")
+	defer span.End()
+
+	topic, err := h.service.GetTopicByPublicID(ctx, ds.EntityTypeBook, r.PathValue("topic"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	books, err := h.service.LatestPublishedBooks(ctx, topic.PublicID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	renderFeed(w, r, &feed.Feed{
+		Title:       "New books on " + topic.Name + " | gopl.dev",
+		Description: topic.Description,
+		Link:        app.ServerURL("/books") + "/?topics=" + topic.PublicID,
+		Items:       bookFeedItems(books),
+	})
+}
+
+// PagesFeed renders the feed of recently created or updated pages.
+func (h *Handler) PagesFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "PagesFeed")
+	defer span.End()
+
+	pages, err := h.service.LatestUpdatedPages(ctx)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	items := make([]feed.Item, len(pages))
+	for i, p := range pages {
+		items[i] = entityFeedItem(p.Entity, p.Content)
+	}
+
+	renderFeed(w, r, &feed.Feed{
+		Title:       "Pages | gopl.dev",
+		Description: "Recently updated pages",
+		Link:        app.ServerURL("/"),
+		Items:       items,
+	})
+}
+
+// ActivityLogFeed renders the feed of the public activity log.
+func (h *Handler) ActivityLogFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ActivityLogFeed")
+	defer span.End()
+
+	logs, _, err := h.service.FilterEventLogs(ctx, ds.EventLogsFilter{
+		PerPage:    service.FeedItemsLimit,
+		OnlyPublic: true,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	items := make([]feed.Item, len(logs))
+	for i, l := range logs {
+		link := app.ServerURL("/activity-log") + "/"
+		if l.EntityPublicID != nil && l.EntityType != nil {
+			link = app.ServerURL((&ds.Entity{Type: *l.EntityType, PublicID: *l.EntityPublicID}).ViewURL())
+		}
+
+		items[i] = feed.Item{
+			ID:        "urn:uuid:" + l.ID.String(),
+			Title:     l.RenderText(),
+			Link:      link,
+			Content:   absLinks(l.RenderMessage()),
+			Author:    app.Value(l.UserUsername),
+			Published: l.CreatedAt,
+		}
+	}
+
+	renderFeed(w, r, &feed.Feed{
+		Title:       "Activity log | gopl.dev",
+		Description: "Recent activity on gopl.dev",
+		Link:        app.ServerURL("/activity-log") + "/",
+		Items:       items,
+	})
+}
+
+// FeedLinks returns autodiscovery links for the feed at the given path (without extension).
+func FeedLinks(title, feedPath string) []layout.FeedLink {
+	links := make([]layout.FeedLink, len(feed.Formats))
+	for i, f := range feed.Formats {
+		links[i] = layout.FeedLink{
+			Title: title,
+			URL:   feedPath + "." + f.Ext(),
+			Type:  f.ContentType(),
+		}
+	}
+
+	return links
+}
+
+func bookFeedItems(books []ds.Book) []feed.Item {
+	items := make([]feed.Item, len(books))
+	for i, b := range books {
+		items[i] = entityFeedItem(b.Entity, b.Summary)
+
+		for _, t := range b.Topics {
+			items[i].Categories = append(items[i].Categories, t.Name)
+		}
+	}
+
+	return items
+}
+
+func entityFeedItem(e *ds.Entity, content string) feed.Item {
+	link := app.ServerURL(e.ViewURL()) + "/"
+
+	it := feed.Item{
+		ID:        link,
+		Title:     e.Title,
+		Link:      link,
+		Content:   absLinks(content),
+		Author:    app.Value(e.Owner),
+		Published: e.CreatedAt,
+	}
+
+	if e.PublishedAt != nil {
+		it.Published = *e.PublishedAt
+	}
+
+	if e.UpdatedAt != nil && e.UpdatedAt.After(it.Published) {
+		it.Updated = *e.UpdatedAt
+	}
+
+	return it
+}
+
+// absLinks rewrites root-relative links of the HTML to absolute ones,
+// as feed readers resolve them against the feed URL at best.
+func absLinks(html string) string {
+	base := strings.TrimSuffix(app.ServerURL("/"), "/")
+
+	return strings.NewReplacer(
+		`href="/`, `href="`+base+"/",
+		`src="/`, `src="`+base+"/",
+	).Replace(html)
+}
+
+// renderFeed writes the feed in the format requested by the extension of the path.
+// Responses are cacheable: ETag and Last-Modified are set, and conditional requests
+// are answered with 304 Not Modified.
+func renderFeed(w http.ResponseWriter, r *http.Request, f *feed.Feed) {
+	format := feed.Format(strings.TrimPrefix(path.Ext(r.URL.Path), "."))
+	if !format.Valid() {
+		Abort(w, r, app.ErrNotFound("feed not found"))
+		return
+	}
+
+	f.FeedURL = app.ServerURL(r.URL.Path)
+	f.Updated = feed.LastUpdated(f.Items)
+	if f.Updated.IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+
+	body, err := f.Encode(format)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Content-Type", format.ContentType()+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	http.ServeContent(w, r, "", f.Updated, bytes.NewReader(body))
+}
@@ -9,7 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -25,6 +26,7 @@ import (
 	"github.com/gopl-dev/server/frontend"
 	"github.com/gopl-dev/server/frontend/layout"
 	"github.com/gopl-dev/server/frontend/page"
+	"github.com/gopl-dev/server/oauth/provider"
 	"github.com/gopl-dev/server/server/response"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -235,7 +237,7 @@ func writeJSON(w http.ResponseWriter, body any, status int) (err error) {
 func jsonOK(w http.ResponseWriter, body any) {
 	err := writeJSON(w, body, http.StatusOK)
 	if err != nil {
-		log.Println(err)
+		slog.Error("write json response", "error", err)
 	}
 }
 
@@ -248,7 +250,7 @@ func jsonSuccess(w http.ResponseWriter) {
 func jsonCreated(w http.ResponseWriter, body any) {
 	err := writeJSON(w, body, http.StatusCreated)
 	if err != nil {
-		log.Println(err)
+		slog.Error("write json response", "error", err)
 	}
 }
 
@@ -260,11 +262,11 @@ func RenderTempl(ctx context.Context, w http.ResponseWriter, t templ.Component)
 
 	err := t.Render(ctx, w)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "render component", "error", err)
 
 		_, err = w.Write([]byte("templ: failed to render component"))
 		if err != nil {
-			log.Println(err)
+			slog.ErrorContext(ctx, "write response", "error", err)
 		}
 	}
 }
@@ -296,7 +298,7 @@ type Sanitizer interface {
 	Sanitize()
 }
 
-func idFromPath(r *http.Request, paramNameOpt ...string) (ds.ID, error) { //nolint:unparam
+func idFromPath(r *http.Request, paramNameOpt ...string) (ds.ID, error) {
 	name := "id"
 	if len(paramNameOpt) == 1 {
 		name = paramNameOpt[0]
@@ -315,6 +317,10 @@ func Abort(w http.ResponseWriter, r *http.Request, err error) {
 	if appErr, ok := errors.AsType[app.Error](err); ok {
 		resp.Code = appErr.Code
 		resp.Error = appErr.Error()
+
+		if appErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
 	}
 	if inputErr, ok := errors.AsType[app.InputError](err); ok {
 		resp.Code = app.CodeUnprocessable
@@ -323,16 +329,16 @@ func Abort(w http.ResponseWriter, r *http.Request, err error) {
 		resp.Error = err.Error()
 	}
 
-	log.Println(resp.Error)
-
 	if resp.Code >= app.CodeInternal {
-		log.Println(string(debug.Stack()))
+		slog.ErrorContext(r.Context(), "request failed", "status", resp.Code, "error", err, "stack", string(debug.Stack()))
+	} else {
+		slog.InfoContext(r.Context(), "request aborted", "status", resp.Code, "error", err)
 	}
 
 	if ShouldServeJSON(r) {
 		err = writeJSON(w, resp, resp.Code)
 		if err != nil {
-			log.Println(err)
+			slog.ErrorContext(r.Context(), "write json response", "error", err)
 		}
 
 		return
@@ -628,6 +634,48 @@ func GetSessionFromCookie(r *http.Request) string {
 	return ""
 }
 
+const (
+	oauthLinkCookieName   = "oauth_link"
+	oauthLinkCookieMaxAge = 10 * 60
+)
+
+// setOAuthLinkCookie marks the pending OAuth flow as linking the given provider
+// to the signed-in user instead of signing in.
+func setOAuthLinkCookie(w http.ResponseWriter, prov provider.Type) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthLinkCookieName,
+		Value:    prov.String(),
+		Path:     "/",
+		MaxAge:   oauthLinkCookieMaxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clearOAuthLinkCookie removes the OAuth link marker cookie.
+func clearOAuthLinkCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthLinkCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// getOAuthLinkFromCookie returns the provider being linked, if any.
+func getOAuthLinkFromCookie(r *http.Request) provider.Type {
+	cookie, _ := r.Cookie(oauthLinkCookieName)
+	if cookie != nil {
+		return provider.New(cookie.Value)
+	}
+
+	return ""
+}
+
 type ctxKey int
 
 const ctxServeJSON ctxKey = iota
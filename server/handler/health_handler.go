@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gopl-dev/server/health"
+)
+
+// Liveness reports that the process is up and able to handle requests.
+// It doesn't check any dependencies, so a failing database doesn't get the server restarted.
+func (h *Handler) Liveness(w http.ResponseWriter, _ *http.Request) {
+	jsonOK(w, health.Report{
+		Status: health.StatusOK,
+		Checks: []health.CheckResult{},
+	})
+}
+
+// Readiness reports whether the server is ready to receive traffic.
+// It responds with 503 if any dependency check fails or the server is shutting down.
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "Readiness")
+	defer span.End()
+
+	report := h.service.CheckReadiness(ctx)
+
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+
+	err := writeJSON(w, report, status)
+	if err != nil {
+		slog.ErrorContext(ctx, "write json response", "error", err)
+	}
+}
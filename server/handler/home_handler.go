@@ -54,6 +54,7 @@ func (h *Handler) Home(w http.ResponseWriter, r *http.Request) {
 	RenderTempl(ctx, w, layout.Default(layout.Data{
 		Title: "Welcome",
 		Body:  page.Home(data),
+		Feeds: append(FeedLinks("New books", "/books/feed"), FeedLinks("Pages", "/pages/feed")...),
 		User:  frontend.NewUser(ds.UserFromContext(r.Context())),
 	}))
 }
@@ -34,9 +34,17 @@ func (h *Handler) RenderPageOrNotFound(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	broken, err := h.brokenExternalLinks(ctx, ds.UserFromContext(ctx) != nil, ds.ExternalLinkSourcePage, p.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
 	RenderDefaultLayout(ctx, w, layout.Data{
-		Title: p.Title,
-		Body:  page.ViewPage(id, p.Title, p.Content),
+		Title:           p.Title,
+		MetaDescription: shareDescription(p.Content),
+		Body:            page.ViewPage(id, p.Title, p.Content, broken),
+		Share:           pageShare(p),
 	})
 }
 
@@ -31,6 +31,7 @@ func (h *Handler) PasswordResetRequestView(w http.ResponseWriter, r *http.Reques
 //	@Param		request	body		request.PasswordResetRequest	true	"Request body"
 //	@Success	200		{object}	response.Status
 //	@Failure	422		{object}	Error
+//	@Failure	429		{object}	Error "Too many requests"
 //	@Failure	500		{object}	Error
 //	@Router		/users/password-reset-request/ [post]
 //	@Security	ApiKeyAuth
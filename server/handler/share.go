@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/frontend/layout"
+)
+
+const (
+	schemaContext = "https://schema.org"
+
+	// shareDescriptionMaxLen is how much of the text is used for a description of a shared link.
+	shareDescriptionMaxLen = 200
+)
+
+// bookShare returns link preview data of the book: OpenGraph "book" and schema.org Book.
+func bookShare(book *ds.Book) *layout.Share {
+	u := app.ServerURL(book.ViewURL()) + "/"
+	image := app.ServerURL(book.ViewURL() + "/social.jpg")
+
+	authors := make([]map[string]any, len(book.Authors))
+	for i, a := range book.Authors {
+		authors[i] = map[string]any{
+			"@type": "Person",
+			"name":  a.Name,
+			"url":   app.ServerURL(a.ViewURL()),
+		}
+	}
+
+	data := map[string]any{
+		"@context": schemaContext,
+		"@type":    "Book",
+		"name":     book.Title,
+		"url":      u,
+		"image":    image,
+		"author":   authors,
+	}
+
+	if d := shareDescription(book.Summary); d != "" {
+		data["description"] = d
+	}
+
+	if book.ReleaseDate != "" {
+		data["datePublished"] = book.ReleaseDate
+	}
+
+	if book.Homepage != "" {
+		data["sameAs"] = book.Homepage
+	}
+
+	if len(book.Topics) > 0 {
+		topics := make([]string, len(book.Topics))
+		for i, t := range book.Topics {
+			topics[i] = t.Name
+		}
+
+		data["keywords"] = topics
+	}
+
+	return &layout.Share{
+		Type:           "book",
+		URL:            u,
+		Image:          image,
+		ImageAlt:       book.Title,
+		StructuredData: data,
+	}
+}
+
+// pageShare returns link preview data of the page: OpenGraph "article" and schema.org Article.
+func pageShare(p *ds.Page) *layout.Share {
+	u := app.ServerURL(p.ViewURL()) + "/"
+
+	data := map[string]any{
+		"@context":      schemaContext,
+		"@type":         "Article",
+		"headline":      p.Title,
+		"url":           u,
+		"datePublished": p.CreatedAt,
+	}
+
+	if p.UpdatedAt != nil {
+		data["dateModified"] = *p.UpdatedAt
+	}
+
+	if d := shareDescription(p.Content); d != "" {
+		data["description"] = d
+	}
+
+	return &layout.Share{
+		Type:           "article",
+		URL:            u,
+		StructuredData: data,
+	}
+}
+
+// profileShare returns link preview data of the user profile: OpenGraph "profile" and schema.org ProfilePage.
+func profileShare(profile *ds.UserProfile) *layout.Share {
+	if profile.Deleted() {
+		return nil
+	}
+
+	user := profile.User
+	u := app.ServerURL("/users/"+user.Username) + "/"
+
+	person := map[string]any{
+		"@type":         "Person",
+		"name":          user.Username,
+		"alternateName": "@" + user.Username,
+		"url":           u,
+	}
+
+	if user.Bio != "" {
+		person["description"] = user.Bio
+	}
+
+	if len(user.Links) > 0 {
+		links := make([]string, len(user.Links))
+		for i, l := range user.Links {
+			links[i] = l.URL
+		}
+
+		person["sameAs"] = links
+	}
+
+	share := &layout.Share{
+		Type: "profile",
+		URL:  u,
+		StructuredData: map[string]any{
+			"@context":    schemaContext,
+			"@type":       "ProfilePage",
+			"dateCreated": user.CreatedAt,
+			"mainEntity":  person,
+		},
+	}
+
+	if !user.AvatarFileID.IsNil() {
+		share.Image = app.ServerURL("/files/"+user.AvatarFileID.String()) + "/?preview"
+		share.ImageAlt = user.Username
+		person["image"] = share.Image
+	}
+
+	return share
+}
+
+// shareDescription returns the beginning of the HTML as plain text.
+func shareDescription(html string) string {
+	text := []rune(strings.Join(strings.Fields(app.PlainText(html)), " "))
+	if len(text) <= shareDescriptionMaxLen {
+		return string(text)
+	}
+
+	return string(text[:shareDescriptionMaxLen-1]) + "…"
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Sitemap serves the sitemap (or the sitemap index) generated by the worker.
+func (h *Handler) Sitemap(w http.ResponseWriter, r *http.Request) {
+	h.serveSitemapFile(w, r, service.SitemapIndexFile)
+}
+
+// SitemapPart serves a numbered sitemap referenced from the sitemap index.
+func (h *Handler) SitemapPart(w http.ResponseWriter, r *http.Request) {
+	h.serveSitemapFile(w, r, r.PathValue("name"))
+}
+
+// Robots serves robots.txt that points crawlers to the sitemap.
+func (h *Handler) Robots(w http.ResponseWriter, _ *http.Request) {
+	conf := app.Config()
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Disallow: /" + strings.Trim(conf.Server.APIBasePath, "/") + "/\n")
+	b.WriteString("Disallow: /dashboard/\n")
+	b.WriteString("Disallow: /users/settings/\n")
+	b.WriteString("\n")
+	b.WriteString("Sitemap: " + app.ServerURL(service.SitemapIndexFile) + "\n")
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func (h *Handler) serveSitemapFile(w http.ResponseWriter, r *http.Request, name string) {
+	ctx, span := h.tracer.Start(r.Context(), "serveSitemapFile")
+	defer span.End()
+
+	if path.Ext(name) != ".xml" {
+		Abort(w, r, service.ErrSitemapNotFound)
+		return
+	}
+
+	fh, _, err := h.service.OpenSitemapFile(ctx, name)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+	defer func() {
+		closeErr := fh.Close()
+		if closeErr != nil {
+			span.RecordError(closeErr)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+
+	http.ServeContent(w, r, name, time.Time{}, fh)
+}
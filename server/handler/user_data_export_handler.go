@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// RequestUserDataExport handles the API request for an authenticated user to get a copy of their personal data.
+// The archive is built in the background and the download link is sent by email.
+//
+//	@ID			RequestUserDataExport
+//	@Summary	Request personal data export
+//	@Tags		users
+//	@Produce	json
+//	@Success	200	{object}	response.Status
+//	@Failure	401	{object}	Error "Unauthorized"
+//	@Failure	422	{object}	Error
+//	@Failure	429	{object}	Error
+//	@Failure	500	{object}	Error
+//	@Router		/users/data-export/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) RequestUserDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RequestUserDataExport")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	_, err := h.service.RequestUserDataExport(ctx, user.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
+
+// DownloadUserDataExport serves the archive of the user data export given by the token in the path.
+// Only the owner of the export can download it.
+func (h *Handler) DownloadUserDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "DownloadUserDataExport")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	exp, err := h.service.GetUserDataExportByToken(ctx, user.ID, r.PathValue("token"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	fh, size, err := h.service.OpenUserDataExport(ctx, exp)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+	defer func() {
+		closeErr := fh.Close()
+		if closeErr != nil {
+			span.RecordError(closeErr)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, exp.Filename()))
+
+	http.ServeContent(w, r, exp.Filename(), *exp.CompletedAt, fh)
+}
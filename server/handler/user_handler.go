@@ -1,8 +1,9 @@
 package handler
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -11,8 +12,10 @@ import (
 	"github.com/gopl-dev/server/app/service"
 	"github.com/gopl-dev/server/frontend/layout"
 	"github.com/gopl-dev/server/frontend/page"
+	"github.com/gopl-dev/server/oauth/provider"
 	"github.com/gopl-dev/server/server/request"
 	"github.com/gopl-dev/server/server/response"
+	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 )
 
@@ -26,6 +29,7 @@ import (
 //	@Param		request	body		request.UserSignUp	true	"Request body"
 //	@Success	200		{object}	response.UserSignIn
 //	@Failure	422		{object}	Error
+//	@Failure	429		{object}	Error "Too many requests"
 //	@Failure	500		{object}	Error
 //	@Router		/users/sign-up/ [post]
 //	@Security	ApiKeyAuth
@@ -71,6 +75,7 @@ func (h *Handler) UserSignUp(w http.ResponseWriter, r *http.Request) {
 //	@Param		request	body		request.UserSignIn	true	"Request body"
 //	@Success	200		{object}	response.UserSignIn
 //	@Failure	422		{object}	Error
+//	@Failure	429		{object}	Error "Too many requests"
 //	@Failure	500		{object}	Error
 //	@Router		/users/sign-in/ [post]
 //	@Security	ApiKeyAuth
@@ -199,7 +204,7 @@ func (h *Handler) UserSignOut(w http.ResponseWriter, r *http.Request) {
 	if session != nil {
 		err := h.service.DeleteUserSession(ctx, session.ID)
 		if err != nil {
-			log.Println("delete user session: " + err.Error()) //nolint:gosec
+			slog.ErrorContext(ctx, "delete user session", "error", err)
 		}
 	}
 
@@ -268,15 +273,35 @@ func (h *Handler) ConfirmEmailView(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// UserSettingsView renders the static HTML page where a user can manually enter
-// an email confirmation code.
+// UserSettingsView renders the user settings page, including the list of connected accounts.
 func (h *Handler) UserSettingsView(w http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "UserSettingsView")
 	defer span.End()
 
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	accounts, err := h.service.GetUserOAuthAccounts(ctx, user.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	connected := make([]page.ConnectedAccount, 0, len(provider.Types))
+	for _, acc := range newOAuthAccountsResponse(accounts) {
+		connected = append(connected, page.ConnectedAccount{
+			Provider: acc.Provider.String(),
+			Name:     acc.Name,
+			Linked:   acc.Linked,
+		})
+	}
+
 	RenderDefaultLayout(ctx, w, layout.Data{
 		Title: "Settings",
-		Body:  page.UserSettings(),
+		Body:  page.UserSettings(connected),
 	})
 }
 
@@ -314,6 +339,7 @@ func (h *Handler) RequestEmailChangeView(w http.ResponseWriter, r *http.Request)
 //	@Success	200		{object}	response.Status
 //	@Failure	401		{object}	Error "Unauthorized"
 //	@Failure	422		{object}	Error "Validation error"
+//	@Failure	429		{object}	Error "Too many requests"
 //	@Failure	500		{object}	Error
 //	@Router		/users/email/ [post]
 //	@Security	ApiKeyAuth
@@ -465,29 +491,22 @@ func (h *Handler) DeleteUserView(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// OAuthStart ...
+// OAuthStart begins the OAuth flow for the provider given in the path.
+// If the provider session is already complete, the user is signed in right away.
 func (h *Handler) OAuthStart(w http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "OAuthStart")
 	defer span.End()
 
 	oauthUser, err := gothic.CompleteUserAuth(w, r)
 	if err == nil {
-		token, err := h.service.AuthenticateOAuthUser(ctx, oauthUser)
-		if err != nil {
-			Abort(w, r, err)
-			return
-		}
-
-		setSessionCookie(w, token)
-
-		http.Redirect(w, r, "/", http.StatusFound)
+		h.completeOAuth(ctx, w, r, oauthUser)
 		return
 	}
 
 	gothic.BeginAuthHandler(w, r)
 }
 
-// OAuthComplete ...
+// OAuthComplete handles the callback from the OAuth provider.
 func (h *Handler) OAuthComplete(w http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "OAuthComplete")
 	defer span.End()
@@ -498,6 +517,30 @@ func (h *Handler) OAuthComplete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.completeOAuth(ctx, w, r, oauthUser)
+}
+
+// completeOAuth finishes the OAuth flow. If the flow was started by a signed-in user
+// from the settings page, the provider account is linked to that user.
+// Otherwise, the user is resolved from the provider account and signed in.
+func (h *Handler) completeOAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, oauthUser goth.User) {
+	linkProvider := getOAuthLinkFromCookie(r)
+	if linkProvider != "" {
+		clearOAuthLinkCookie(w)
+	}
+
+	user := ds.UserFromContext(ctx)
+	if user != nil && linkProvider.String() == oauthUser.Provider {
+		err := h.service.LinkOAuthAccount(ctx, user.ID, oauthUser)
+		if err != nil {
+			Abort(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/users/settings/", http.StatusFound)
+		return
+	}
+
 	token, err := h.service.AuthenticateOAuthUser(ctx, oauthUser)
 	if err != nil {
 		Abort(w, r, err)
@@ -509,6 +552,124 @@ func (h *Handler) OAuthComplete(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
+// OAuthLinkStart begins the OAuth flow that links a new provider account
+// to the signed-in user instead of signing in.
+func (h *Handler) OAuthLinkStart(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "OAuthLinkStart")
+	defer span.End()
+
+	prov := provider.New(r.PathValue("provider"))
+	if !prov.Valid() {
+		Abort(w, r, app.ErrNotFound("unknown provider"))
+		return
+	}
+
+	setOAuthLinkCookie(w, prov)
+	gothic.BeginAuthHandler(w, r)
+}
+
+// GetOAuthAccounts returns the list of supported OAuth providers
+// and whether each of them is linked to the authenticated user.
+//
+//	@ID			GetOAuthAccounts
+//	@Summary	List connected accounts
+//	@Tags		users
+//	@Produce	json
+//	@Success	200		{object}	[]response.OAuthAccount
+//	@Failure	401		{object}	Error "Unauthorized"
+//	@Failure	500		{object}	Error
+//	@Router		/users/oauth-accounts/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) GetOAuthAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "GetOAuthAccounts")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	accounts, err := h.service.GetUserOAuthAccounts(ctx, user.ID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, newOAuthAccountsResponse(accounts))
+}
+
+// UnlinkOAuthAccount unlinks the OAuth provider given in the path from the authenticated user.
+//
+//	@ID			UnlinkOAuthAccount
+//	@Summary	Unlink connected account
+//	@Tags		users
+//	@Produce	json
+//	@Param		provider	path		string	true	"Provider"
+//	@Success	200			{object}	response.Status
+//	@Failure	401			{object}	Error "Unauthorized"
+//	@Failure	404			{object}	Error "Provider is not linked"
+//	@Failure	422			{object}	Error "Provider is the only sign-in method"
+//	@Failure	500			{object}	Error
+//	@Router		/users/oauth-accounts/{provider}/ [delete]
+//	@Security	ApiKeyAuth
+func (h *Handler) UnlinkOAuthAccount(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "UnlinkOAuthAccount")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	err := h.service.UnlinkOAuthAccount(ctx, user.ID, provider.New(r.PathValue("provider")))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
+
+// newOAuthAccountsResponse lists all enabled providers, marking the ones linked to the user,
+// followed by the linked accounts of providers disabled since, so they can still be unlinked.
+func newOAuthAccountsResponse(accounts []ds.OAuthUserAccount) []response.OAuthAccount {
+	resp := make([]response.OAuthAccount, 0, len(provider.Types))
+	for _, prov := range provider.Types {
+		item := response.OAuthAccount{
+			Provider: prov,
+			Name:     prov.Name(),
+			Enabled:  true,
+		}
+
+		for _, acc := range accounts {
+			if acc.Provider == prov {
+				item.Linked = true
+				item.LinkedAt = &acc.CreatedAt
+				break
+			}
+		}
+
+		resp = append(resp, item)
+	}
+
+	for _, acc := range accounts {
+		if acc.Provider.Valid() {
+			continue
+		}
+
+		resp = append(resp, response.OAuthAccount{
+			Provider: acc.Provider,
+			Name:     acc.Provider.Name(),
+			Linked:   true,
+			LinkedAt: &acc.CreatedAt,
+		})
+	}
+
+	return resp
+}
+
 // DeleteUser handles the API request for an authenticated user to delete their account.
 //
 //	@ID			DeleteUser
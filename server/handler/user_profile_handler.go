@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/frontend/layout"
+	"github.com/gopl-dev/server/frontend/page"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// UserProfileView renders the public profile of the user given in the path.
+// Deleted users are rendered as a tombstone.
+func (h *Handler) UserProfileView(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "UserProfileView")
+	defer span.End()
+
+	profile, err := h.service.GetUserProfile(ctx, r.PathValue("username"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	title := ds.DeletedUsername
+	if !profile.Deleted() {
+		title = profile.User.Username
+	}
+
+	var description string
+	if !profile.Deleted() {
+		description = shareDescription(profile.User.Bio)
+	}
+
+	RenderDefaultLayout(ctx, w, layout.Data{
+		Title:           title,
+		MetaDescription: description,
+		Body:            page.UserProfile(profile),
+		Share:           profileShare(profile),
+	})
+}
+
+// GetUserProfile returns the public profile of the user given in the path.
+//
+//	@ID			GetUserProfile
+//	@Summary	Get user profile
+//	@Tags		users
+//	@Produce	json
+//	@Param		username	path		string	true	"Username"
+//	@Success	200			{object}	response.UserProfile
+//	@Failure	404			{object}	Error
+//	@Failure	500			{object}	Error
+//	@Router		/users/{username}/ [get]
+func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "GetUserProfile")
+	defer span.End()
+
+	profile, err := h.service.GetUserProfile(ctx, r.PathValue("username"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.NewUserProfile(profile))
+}
+
+// EditUserProfileView renders the page with the form to edit the user's public profile.
+func (h *Handler) EditUserProfileView(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EditUserProfileView")
+	defer span.End()
+
+	user := ds.UserFromContext(ctx)
+	if user == nil {
+		Abort(w, r, app.ErrUnauthorized())
+		return
+	}
+
+	links := user.Links
+	if links == nil {
+		links = []ds.UserLink{}
+	}
+
+	RenderDefaultLayout(ctx, w, layout.Data{
+		Title: "Edit profile",
+		Body: page.EditUserProfileForm(user.ProfileURL(), page.UserProfileFormData{
+			Bio:                user.Bio,
+			AvatarFileID:       user.AvatarFileID,
+			Links:              links,
+			ShowBooks:          user.ShowBooks,
+			ShowPages:          user.ShowPages,
+			ShowChangeRequests: user.ShowChangeRequests,
+			ShowActivity:       user.ShowActivity,
+		}),
+	})
+}
+
+// UpdateUserProfile handles the API request for an authenticated user to update their public profile.
+//
+//	@ID			UpdateUserProfile
+//	@Summary	Update user profile
+//	@Tags		users
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		request.UpdateUserProfile	true	"Profile"
+//	@Success	200		{object}	response.Status
+//	@Failure	401		{object}	Error "Unauthorized"
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/users/profile/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) UpdateUserProfile(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "UpdateUserProfile")
+	defer span.End()
+
+	var req request.UpdateUserProfile
+	user, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	err := h.service.UpdateUserProfile(ctx, service.UpdateUserProfileInput{
+		UserID:             user.ID,
+		Bio:                req.Bio,
+		AvatarFileID:       req.AvatarFileID,
+		Links:              req.Links,
+		ShowBooks:          req.ShowBooks,
+		ShowPages:          req.ShowPages,
+		ShowChangeRequests: req.ShowChangeRequests,
+		ShowActivity:       req.ShowActivity,
+	})
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonSuccess()
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// GetWebhooks handles API requests for retrieving webhook subscriptions.
+//
+//	@ID			GetWebhooks
+//	@Summary	Get webhooks
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Success	200		{array}		ds.Webhook
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "GetWebhooks")
+	defer span.End()
+
+	webhooks, err := h.service.GetWebhooks(ctx)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, webhooks)
+}
+
+// CreateWebhook handles API requests for creating a webhook subscription.
+// The response includes the secret deliveries are signed with.
+//
+//	@ID			CreateWebhook
+//	@Summary	Create webhook
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		request.CreateWebhook	true	"Request body"
+//	@Success	201		{object}	response.CreateWebhook
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "CreateWebhook")
+	defer span.End()
+
+	var req request.CreateWebhook
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(ctx, webhookInput(req))
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonCreated(response.CreateWebhook{
+		Webhook: webhook,
+		Secret:  webhook.Secret,
+	})
+}
+
+// UpdateWebhook handles API requests for updating a webhook subscription.
+//
+//	@ID			UpdateWebhook
+//	@Summary	Update webhook
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Webhook ID"
+//	@Param		request	body		request.UpdateWebhook	true	"Request body"
+//	@Success	200		{object}	ds.Webhook
+//	@Failure	400		{object}	Error
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	422		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/{id}/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "UpdateWebhook")
+	defer span.End()
+
+	var req request.UpdateWebhook
+	_, res := handleAuthorizedJSON(w, r, &req)
+	if res.Aborted() {
+		return
+	}
+
+	id, err := idFromPath(r)
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(ctx, id, webhookInput(req.CreateWebhook))
+	if err != nil {
+		res.Abort(err)
+		return
+	}
+
+	res.jsonOK(webhook)
+}
+
+// DeleteWebhook handles API requests for deleting a webhook subscription.
+//
+//	@ID			DeleteWebhook
+//	@Summary	Delete webhook
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Webhook ID"
+//	@Success	200		{object}	response.Status
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/{id}/ [delete]
+//	@Security	ApiKeyAuth
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "DeleteWebhook")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteWebhook(ctx, id)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
+
+// TestWebhook handles API requests for sending a sample "ping" payload to the webhook.
+// The delivery is sent by the worker, its result is available in the delivery log.
+//
+//	@ID			TestWebhook
+//	@Summary	Send test payload to webhook
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"Webhook ID"
+//	@Success	201		{object}	ds.WebhookDelivery
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/{id}/test/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TestWebhook")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	delivery, err := h.service.PingWebhook(ctx, id)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonCreated(w, delivery)
+}
+
+// FilterWebhookDeliveries handles API requests for retrieving the delivery log of the webhook.
+//
+//	@ID			FilterWebhookDeliveries
+//	@Summary	Get webhook deliveries
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string							true	"Webhook ID"
+//	@Param		params	query		request.FilterWebhookDeliveries	false	"Query parameters"
+//	@Success	200		{object}	response.FilterWebhookDeliveries
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/{id}/deliveries/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FilterWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterWebhookDeliveries")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	var req request.FilterWebhookDeliveries
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterWebhookDeliveries(ctx, ds.WebhookDeliveriesFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		WebhookID: &id,
+		Status:    req.Status,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterWebhookDeliveries{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// RedeliverWebhookDelivery handles API requests for sending the delivery again.
+//
+//	@ID			RedeliverWebhookDelivery
+//	@Summary	Redeliver webhook delivery
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string	true	"Webhook ID"
+//	@Param		delivery_id	path		string	true	"Delivery ID"
+//	@Success	201		{object}	ds.WebhookDelivery
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/webhooks/{id}/deliveries/{delivery_id}/redeliver/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RedeliverWebhookDelivery")
+	defer span.End()
+
+	id, err := idFromPath(r)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	deliveryID, err := idFromPath(r, "delivery_id")
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	delivery, err := h.service.RedeliverWebhookDelivery(ctx, id, deliveryID)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonCreated(w, delivery)
+}
+
+func webhookInput(req request.CreateWebhook) service.WebhookInput {
+	return service.WebhookInput{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		Active:      req.Active,
+	}
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+)
+
+// GetWorkerJobs handles API requests for retrieving background jobs along with their latest runs.
+//
+//	@ID			GetWorkerJobs
+//	@Summary	Get worker jobs
+//	@Tags		worker-jobs
+//	@Accept		json
+//	@Produce	json
+//	@Success	200		{array}		ds.WorkerJob
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/worker-jobs/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) GetWorkerJobs(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "GetWorkerJobs")
+	defer span.End()
+
+	jobs, err := h.service.GetWorkerJobs(ctx)
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, jobs)
+}
+
+// FilterWorkerJobRuns handles API requests for retrieving the run history of the job.
+//
+//	@ID			FilterWorkerJobRuns
+//	@Summary	Get worker job runs
+//	@Tags		worker-jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		name	path		string						true	"Job name"
+//	@Param		params	query		request.FilterWorkerJobRuns	false	"Query parameters"
+//	@Success	200		{object}	response.FilterWorkerJobRuns
+//	@Failure	401		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/worker-jobs/{name}/runs/ [get]
+//	@Security	ApiKeyAuth
+func (h *Handler) FilterWorkerJobRuns(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FilterWorkerJobRuns")
+	defer span.End()
+
+	var req request.FilterWorkerJobRuns
+	bindQuery(r, &req)
+
+	data, count, err := h.service.FilterWorkerJobRuns(ctx, ds.WorkerJobRunsFilter{
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+		WithCount: true,
+		JobName:   r.PathValue("name"),
+		Status:    req.Status,
+	})
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonOK(w, response.FilterWorkerJobRuns{
+		Data:  data,
+		Count: count,
+	})
+}
+
+// PauseWorkerJob handles API requests for pausing scheduled runs of the job.
+//
+//	@ID			PauseWorkerJob
+//	@Summary	Pause worker job
+//	@Tags		worker-jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		name	path		string	true	"Job name"
+//	@Success	200		{object}	response.Status
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/worker-jobs/{name}/pause/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) PauseWorkerJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "PauseWorkerJob")
+	defer span.End()
+
+	err := h.service.PauseWorkerJob(ctx, r.PathValue("name"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
+
+// ResumeWorkerJob handles API requests for resuming scheduled runs of the paused job.
+//
+//	@ID			ResumeWorkerJob
+//	@Summary	Resume worker job
+//	@Tags		worker-jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		name	path		string	true	"Job name"
+//	@Success	200		{object}	response.Status
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/worker-jobs/{name}/resume/ [put]
+//	@Security	ApiKeyAuth
+func (h *Handler) ResumeWorkerJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ResumeWorkerJob")
+	defer span.End()
+
+	err := h.service.ResumeWorkerJob(ctx, r.PathValue("name"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
+
+// RunWorkerJob handles API requests for running the job now.
+// The run is picked up by the worker within a few seconds.
+//
+//	@ID			RunWorkerJob
+//	@Summary	Run worker job now
+//	@Tags		worker-jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		name	path		string	true	"Job name"
+//	@Success	200		{object}	response.Status
+//	@Failure	401		{object}	Error
+//	@Failure	404		{object}	Error
+//	@Failure	500		{object}	Error
+//	@Router		/worker-jobs/{name}/run/ [post]
+//	@Security	ApiKeyAuth
+func (h *Handler) RunWorkerJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RunWorkerJob")
+	defer span.End()
+
+	err := h.service.RequestWorkerJobRun(ctx, r.PathValue("name"))
+	if err != nil {
+		Abort(w, r, err)
+		return
+	}
+
+	jsonSuccess(w)
+}
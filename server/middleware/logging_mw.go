@@ -1,20 +1,58 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
+	"regexp"
 	"time"
 
+	"github.com/gopl-dev/server/logging"
 	"github.com/gopl-dev/server/server/handler"
 )
 
-// Logging is a middleware that logs the start time, HTTP method, and path of an incoming request,
-// and then logs the completion time and total duration after the next handler has executed.
+// RequestIDHeader is the header the request ID is read from and written to.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits request IDs accepted from clients (or a reverse proxy),
+// so arbitrary input doesn't end up in logs.
+var validRequestID = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// RequestID puts the request ID into the request's context, so every log record of the request has it.
+// The ID set by a reverse proxy is reused; otherwise a new one is generated.
+// The ID is returned to the client in the X-Request-ID header.
+func (mw *Middleware) RequestID(next handler.Fn) handler.Fn {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = logging.NewRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		next(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	}
+}
+
+// Logging is a middleware that logs the HTTP method, path, status and duration of a request
+// after the next handler has executed.
 func (mw *Middleware) Logging(next handler.Fn) handler.Fn {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		rec := &StatusRecorder{ResponseWriter: w}
+
+		next(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
 
-		next(w, r)
-		log.Printf("[%s] %s %s", time.Since(start), r.Method, r.URL.Path) //nolint:gosec
+		slog.InfoContext(r.Context(), "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", r.Pattern,
+			"status", status,
+			"duration", time.Since(start),
+		)
 	}
 }
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/server/handler"
+)
+
+// RateLimit returns a middleware that throttles requests of the given action by client IP.
+// When the limit is exceeded, it responds with 429 and a Retry-After header.
+func (mw *Middleware) RateLimit(action service.RateLimitAction) Fn {
+	return func(next handler.Fn) handler.Fn {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := mw.tracer.Start(r.Context(), "RateLimit")
+			err := mw.service.ThrottleIP(ctx, action, ClientIP(r, app.Config().RateLimit.ClientIPHeader))
+			span.End()
+
+			if err != nil {
+				handler.Abort(w, r, err)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// ClientIP returns the IP address of the client.
+// If the server is behind a reverse proxy, the given header set by the proxy is trusted instead of the remote address.
+// Only the rightmost address of the header is used: X-Forwarded-For may hold a chain of addresses,
+// where all but the last one (appended by the proxy itself) are sent by the client and can't be trusted.
+func ClientIP(r *http.Request, header string) string {
+	if header != "" {
+		values := r.Header.Values(header)
+		if len(values) > 0 {
+			hops := strings.Split(values[len(values)-1], ",")
+			ip := strings.TrimSpace(hops[len(hops)-1])
+			if net.ParseIP(ip) != nil {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 
@@ -15,8 +15,12 @@ func (mw *Middleware) Recovery(next handler.Fn) handler.Fn {
 		defer func() {
 			if err := recover(); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
-				log.Printf("[%s %s] Recovered from panic: %s", r.Method, r.URL.Path, err) //nolint:gosec
-				log.Println(string(debug.Stack()))
+				slog.ErrorContext(r.Context(), "recovered from panic",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"panic", err,
+					"stack", string(debug.Stack()),
+				)
 			}
 		}()
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/logging"
 	"github.com/gopl-dev/server/server/handler"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -54,6 +55,7 @@ func (mw *Middleware) Tracing(next handler.Fn) handler.Fn {
 		span.SetAttributes(
 			attribute.String("http.method", r.Method),
 			attribute.String("http.route", r.Pattern),
+			attribute.String("http.request_id", logging.RequestIDFromContext(ctx)),
 		)
 
 		rec := &StatusRecorder{ResponseWriter: w}
@@ -0,0 +1,38 @@
+package request
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterAuthors defines input parameters for filtering and paginating authors.
+type FilterAuthors struct {
+	Page    int    `json:"page" url:"page,omitempty"`
+	PerPage int    `json:"per_page" url:"per_page,omitempty"`
+	Name    string `json:"name" url:"name,omitempty"`
+}
+
+// ToFilter converts FilterAuthors into a ds.AuthorsFilter.
+func (f FilterAuthors) ToFilter() ds.AuthorsFilter {
+	filter := ds.AuthorsFilter{
+		Page:      f.Page,
+		PerPage:   f.PerPage,
+		WithCount: true,
+	}
+	if f.Name != "" {
+		filter.Name = &ds.FilterString{Contains: new(f.Name)}
+	}
+
+	return filter
+}
+
+// UpdateAuthor defines the request payload for editing an author.
+type UpdateAuthor struct {
+	Name string `json:"name"`
+	// Bio is the biography in Markdown.
+	Bio   string          `json:"bio"`
+	Links []ds.AuthorLink `json:"links"`
+}
+
+// MergeAuthor defines the request payload for merging a duplicate author into another one.
+type MergeAuthor struct {
+	// Into is the slug of the author to merge into.
+	Into string `json:"into"`
+}
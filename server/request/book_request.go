@@ -9,14 +9,21 @@ import (
 
 // CreateBook defines the request payload for creating a new book entity.
 type CreateBook struct {
-	Title       string          `json:"title"`
-	Summary     string          `json:"summary"`
-	Description string          `json:"description"`
-	ReleaseDate string          `json:"release_date"`
-	Homepage    string          `json:"homepage"`
-	CoverFileID ds.ID           `json:"cover_file_id,omitempty,omitzero"`
-	Authors     []ds.BookAuthor `json:"authors"`
-	Topics      []ds.ID         `json:"topics"`
+	Title          string          `json:"title"`
+	Subtitle       string          `json:"subtitle"`
+	Series         string          `json:"series"`
+	SeriesPosition int             `json:"series_position"`
+	Summary        string          `json:"summary"`
+	Description    string          `json:"description"`
+	ReleaseDate    string          `json:"release_date"`
+	Homepage       string          `json:"homepage"`
+	CoverFileID    ds.ID           `json:"cover_file_id,omitempty,omitzero"`
+	Authors        []ds.BookAuthor `json:"authors"`
+	Topics         []ds.ID         `json:"topics"`
+	ISBN10         string          `json:"isbn10"`
+	ISBN13         string          `json:"isbn13"`
+	// Editions of the book. If left out on update, the editions are kept as is.
+	Editions []ds.BookEdition `json:"editions"`
 }
 
 // Sanitize normalizes and validates CreateBook request.
@@ -34,6 +41,30 @@ func (r *CreateBook) Sanitize() {
 	}
 
 	r.Authors = authors
+	r.Subtitle = strings.TrimSpace(r.Subtitle)
+	r.Series = strings.TrimSpace(r.Series)
+	r.ISBN10 = ds.NormalizeISBN(r.ISBN10)
+	r.ISBN13 = ds.NormalizeISBN(r.ISBN13)
+
+	for i := range r.Editions {
+		e := &r.Editions[i]
+		e.GoVersion = strings.TrimPrefix(strings.TrimSpace(e.GoVersion), "go")
+		e.Publisher = strings.TrimSpace(e.Publisher)
+		e.ISBN10 = ds.NormalizeISBN(e.ISBN10)
+		e.ISBN13 = ds.NormalizeISBN(e.ISBN13)
+		e.ReleaseDate = strings.TrimSpace(e.ReleaseDate)
+
+		links := make([]ds.BookEditionLink, 0, len(e.Links))
+		for _, l := range e.Links {
+			l.URL = strings.TrimSpace(l.URL)
+			if l.URL == "" {
+				continue
+			}
+			l.Title = strings.TrimSpace(l.Title)
+			links = append(links, l)
+		}
+		e.Links = links
+	}
 }
 
 // ToBook converts the CreateBook request into a Book model.
@@ -58,11 +89,17 @@ func (r *CreateBook) ToBook() *ds.Book {
 			UpdatedAt:   nil,
 			DeletedAt:   nil,
 		},
+		Subtitle:       r.Subtitle,
+		Series:         r.Series,
+		SeriesPosition: r.SeriesPosition,
 		DescriptionRaw: r.Description,
 		Authors:        r.Authors,
 		Homepage:       r.Homepage,
 		ReleaseDate:    r.ReleaseDate,
 		CoverFileID:    r.CoverFileID,
+		ISBN10:         r.ISBN10,
+		ISBN13:         r.ISBN13,
+		Editions:       r.Editions,
 	}
 }
 
@@ -77,9 +114,28 @@ type FilterBooks struct {
 	FilterEntities
 
 	Author string `json:"author" url:"author,omitempty"`
+	// Sort is one of ds.BookSorts, the latest released books first by default.
+	Sort ds.BookSort `json:"sort" url:"sort,omitempty"`
 }
 
 // RejectBook represents a request payload for rejecting a book.
 type RejectBook struct {
 	Note string `json:"note"`
 }
+
+// MergeBook defines the request payload for merging a new book into an existing duplicate of it.
+type MergeBook struct {
+	// IntoID is the ID of the existing book.
+	IntoID ds.ID `json:"into_id"`
+}
+
+// ImportBooks defines the query parameters of the bulk book import.
+type ImportBooks struct {
+	// DryRun validates the books without creating them.
+	DryRun bool `url:"dry_run"`
+	// Format is the format of the body, "csv", "json" or "isbn" (a list of ISBNs).
+	// If empty, it's detected from the Content-Type header.
+	Format string `url:"format"`
+	// Topics are public IDs or names of the topics of the books that have none.
+	Topics []string `url:"topics"`
+}
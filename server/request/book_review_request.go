@@ -0,0 +1,30 @@
+package request
+
+// SaveBookReview defines the request payload for writing (or rewriting) a review of a book.
+type SaveBookReview struct {
+	// Rating from 1 to 5.
+	Rating int `json:"rating"`
+	// Body is the review in Markdown.
+	Body string `json:"body"`
+}
+
+// ReportBookReview defines the request payload for reporting a review to moderators.
+type ReportBookReview struct {
+	Reason string `json:"reason"`
+}
+
+// FilterBookReviews defines input parameters for paginating the reviews of a book.
+type FilterBookReviews struct {
+	Page    int `json:"page" url:"page,omitempty"`
+	PerPage int `json:"per_page" url:"per_page,omitempty"`
+}
+
+// FilterModeratedBookReviews defines input parameters for filtering book reviews by moderators.
+type FilterModeratedBookReviews struct {
+	Page    int `json:"page" url:"page,omitempty"`
+	PerPage int `json:"per_page" url:"per_page,omitempty"`
+	// Reported limits the result to reviews reported at least once.
+	Reported bool `json:"reported" url:"reported,omitempty"`
+	// Hidden filters reviews by hidden state, both if omitted.
+	Hidden *bool `json:"hidden" url:"hidden,omitempty"`
+}
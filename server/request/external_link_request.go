@@ -0,0 +1,16 @@
+package request
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterExternalLinks defines input parameters for filtering and paginating external links.
+type FilterExternalLinks struct {
+	Page    int                   `json:"page" url:"page,omitempty"`
+	PerPage int                   `json:"per_page" url:"per_page,omitempty"`
+	Status  ds.ExternalLinkStatus `json:"status" url:"status,omitempty"`
+}
+
+// FilterExternalLinkChecks defines input parameters for paginating the history of checks of an external link.
+type FilterExternalLinkChecks struct {
+	Page    int `json:"page" url:"page,omitempty"`
+	PerPage int `json:"per_page" url:"per_page,omitempty"`
+}
@@ -1,6 +1,8 @@
 //nolint:gosec
 package request
 
+import "github.com/gopl-dev/server/app/ds"
+
 // UserSignUp holds the data required to register a new user.
 type UserSignUp struct {
 	Username string `json:"username"`
@@ -29,3 +31,14 @@ type ChangeUsername struct {
 type DeleteUser struct {
 	Password string `json:"password"`
 }
+
+// UpdateUserProfile holds the editable fields of the user's public profile.
+type UpdateUserProfile struct {
+	Bio                string        `json:"bio"`
+	AvatarFileID       ds.ID         `json:"avatar_file_id,omitempty,omitzero"`
+	Links              []ds.UserLink `json:"links"`
+	ShowBooks          bool          `json:"show_books"`
+	ShowPages          bool          `json:"show_pages"`
+	ShowChangeRequests bool          `json:"show_change_requests"`
+	ShowActivity       bool          `json:"show_activity"`
+}
@@ -0,0 +1,27 @@
+package request
+
+import "github.com/gopl-dev/server/app/ds"
+
+// CreateWebhook defines the request payload for creating a webhook subscription.
+type CreateWebhook struct {
+	URL string `json:"url"`
+	// Secret is used to sign deliveries; generated when empty.
+	Secret string `json:"secret"`
+	// EventTypes the webhook is subscribed to; empty means all of them.
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+}
+
+// UpdateWebhook defines the request payload for updating a webhook subscription.
+// The secret is kept when empty.
+type UpdateWebhook struct {
+	CreateWebhook
+}
+
+// FilterWebhookDeliveries defines input parameters for filtering and paginating webhook deliveries.
+type FilterWebhookDeliveries struct {
+	Page    int                      `json:"page" url:"page,omitempty"`
+	PerPage int                      `json:"per_page" url:"per_page,omitempty"`
+	Status  ds.WebhookDeliveryStatus `json:"status" url:"status,omitempty"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterWorkerJobRuns defines input parameters for filtering and paginating job runs.
+type FilterWorkerJobRuns struct {
+	Page    int                   `json:"page" url:"page,omitempty"`
+	PerPage int                   `json:"per_page" url:"per_page,omitempty"`
+	Status  ds.WorkerJobRunStatus `json:"status" url:"status,omitempty"`
+}
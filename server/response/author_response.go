@@ -0,0 +1,16 @@
+package response
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterAuthors represents a paginated collection of authors.
+type FilterAuthors struct {
+	Data  []ds.Author `json:"data"`
+	Count int         `json:"count"`
+}
+
+// Author represents an author along with their books.
+type Author struct {
+	ds.Author
+
+	Books []ds.Book `json:"books"`
+}
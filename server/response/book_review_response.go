@@ -0,0 +1,16 @@
+package response
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterBookReviews represents a paginated collection of the reviews of a book along with its rating.
+type FilterBookReviews struct {
+	Data   []ds.BookReview `json:"data"`
+	Count  int             `json:"count"`
+	Rating ds.BookRating   `json:"rating"`
+}
+
+// FilterModeratedBookReviews represents a paginated collection of book reviews.
+type FilterModeratedBookReviews struct {
+	Data  []ds.BookReview `json:"data"`
+	Count int             `json:"count"`
+}
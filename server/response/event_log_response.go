@@ -21,13 +21,17 @@ type EventLog struct {
 
 // NewFilterEventLog converts domain event logs into a response model.
 func NewFilterEventLog(data []ds.EventLog, count int) FilterEventLogs {
-	r := FilterEventLogs{
+	return FilterEventLogs{
 		Count: count,
-		Data:  make([]EventLog, len(data)),
+		Data:  NewEventLogs(data),
 	}
+}
 
+// NewEventLogs converts domain event logs into response models.
+func NewEventLogs(data []ds.EventLog) []EventLog {
+	logs := make([]EventLog, len(data))
 	for i, d := range data {
-		r.Data[i] = EventLog{
+		logs[i] = EventLog{
 			ID:         d.ID,
 			Message:    d.RenderMessage(),
 			Date:       app.HumanTime(d.CreatedAt),
@@ -35,7 +39,7 @@ func NewFilterEventLog(data []ds.EventLog, count int) FilterEventLogs {
 		}
 	}
 
-	return r
+	return logs
 }
 
 // EventLogChanges represents the change in an event log.
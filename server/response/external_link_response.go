@@ -0,0 +1,15 @@
+package response
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterExternalLinks represents a paginated collection of external links.
+type FilterExternalLinks struct {
+	Data  []ds.ExternalLink `json:"data"`
+	Count int               `json:"count"`
+}
+
+// FilterExternalLinkChecks represents a paginated history of checks of an external link.
+type FilterExternalLinkChecks struct {
+	Data  []ds.ExternalLinkCheck `json:"data"`
+	Count int                    `json:"count"`
+}
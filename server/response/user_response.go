@@ -1,6 +1,11 @@
 package response
 
-import "github.com/gopl-dev/server/app/ds"
+import (
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/oauth/provider"
+)
 
 // UserSignIn contains user data returned after successful authentication.
 type UserSignIn struct {
@@ -8,3 +13,51 @@ type UserSignIn struct {
 	Username string `json:"username"`
 	Token    string `json:"token"`
 }
+
+// OAuthAccount describes a supported OAuth provider and whether it is linked to the user.
+type OAuthAccount struct {
+	Provider provider.Type `json:"provider"`
+	Name     string        `json:"name"`
+	// Enabled is false for a linked account of a provider that is disabled since.
+	Enabled  bool       `json:"enabled"`
+	Linked   bool       `json:"linked"`
+	LinkedAt *time.Time `json:"linked_at"`
+}
+
+// UserProfile is the public profile of a user along with their contributions.
+// Sections hidden by the user are omitted.
+type UserProfile struct {
+	Username       string                   `json:"username"`
+	Deleted        bool                     `json:"deleted"`
+	Bio            string                   `json:"bio,omitempty"`
+	AvatarFileID   ds.ID                    `json:"avatar_file_id,omitzero"`
+	Links          []ds.UserLink            `json:"links,omitempty"`
+	JoinedAt       *time.Time               `json:"joined_at,omitempty"`
+	Books          []ds.Entity              `json:"books,omitempty"`
+	Pages          []ds.Entity              `json:"pages,omitempty"`
+	ChangeRequests []ds.EntityChangeRequest `json:"change_requests,omitempty"`
+	Activity       []EventLog               `json:"activity,omitempty"`
+}
+
+// NewUserProfile converts the domain user profile into a response model.
+// Profiles of deleted users only carry the tombstone.
+func NewUserProfile(p *ds.UserProfile) UserProfile {
+	if p.Deleted() {
+		return UserProfile{
+			Username: ds.DeletedUsername,
+			Deleted:  true,
+		}
+	}
+
+	return UserProfile{
+		Username:       p.User.Username,
+		Bio:            p.User.Bio,
+		AvatarFileID:   p.User.AvatarFileID,
+		Links:          p.User.Links,
+		JoinedAt:       &p.User.CreatedAt,
+		Books:          p.Books,
+		Pages:          p.Pages,
+		ChangeRequests: p.ChangeRequests,
+		Activity:       NewEventLogs(p.EventLogs),
+	}
+}
@@ -0,0 +1,16 @@
+package response
+
+import "github.com/gopl-dev/server/app/ds"
+
+// CreateWebhook represents the created webhook along with its secret,
+// which is not returned anywhere else.
+type CreateWebhook struct {
+	*ds.Webhook
+	Secret string `json:"secret"`
+}
+
+// FilterWebhookDeliveries represents a paginated collection of webhook deliveries.
+type FilterWebhookDeliveries struct {
+	Data  []ds.WebhookDelivery `json:"data"`
+	Count int                  `json:"count"`
+}
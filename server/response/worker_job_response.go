@@ -0,0 +1,9 @@
+package response
+
+import "github.com/gopl-dev/server/app/ds"
+
+// FilterWorkerJobRuns represents a paginated collection of job runs.
+type FilterWorkerJobRuns struct {
+	Data  []ds.WorkerJobRun `json:"data"`
+	Count int               `json:"count"`
+}
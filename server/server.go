@@ -3,8 +3,8 @@ package server
 
 import (
 	"crypto/tls"
-	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -14,12 +14,10 @@ import (
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/oauth"
 	"github.com/gopl-dev/server/server/endpoint"
 	"github.com/gopl-dev/server/server/handler"
 	"github.com/gopl-dev/server/server/middleware"
-	"github.com/markbates/goth"
-	"github.com/markbates/goth/providers/github"
-	"github.com/markbates/goth/providers/google"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/acme/autocert"
 )
@@ -27,21 +25,26 @@ import (
 // RWTimeout defines server's Read&Write timeout in seconds.
 const RWTimeout = 10 * time.Second
 
+// ShutdownTimeout limits how long the graceful shutdown waits for in-flight requests.
+const ShutdownTimeout = 30 * time.Second
+
 // New creates new server.
 func New(s *service.Service, t trace.Tracer) *http.Server {
 	conf := app.Config().Server
 
-	registerOAuthProviders()
+	oauth.Setup(app.Config())
 
 	h := handler.New(s, t)
 	mw := middleware.New(s, t)
 	r := endpoint.NewRouter(mw, h)
 
 	r.HandleAssets()
+	r.HandleHealthChecks()
 	r.HandleOpenAPIDocs()
 
 	// Middlewares that is common to "web" and "api" endpoint groups
 	common := r.Use(
+		mw.RequestID,
 		mw.Tracing,
 		mw.Recovery,
 		mw.Logging,
@@ -96,7 +99,7 @@ func New(s *service.Service, t trace.Tracer) *http.Server {
 			}
 			err := pongTLS.ListenAndServe()
 			if err != nil {
-				log.Println("autocert ListenAndServe: ", err.Error())
+				slog.Error("autocert ListenAndServe", "error", err)
 			}
 		}()
 	}
@@ -109,16 +112,3 @@ func New(s *service.Service, t trace.Tracer) *http.Server {
 		WriteTimeout: RWTimeout,
 	}
 }
-
-func registerOAuthProviders() {
-	c := app.Config()
-
-	callbackURL := func(providerName string) string {
-		return fmt.Sprintf("%sauth/%s/callback/", c.Server.Addr, providerName)
-	}
-
-	goth.UseProviders(
-		google.New(c.GoogleOAuth.ClientID, c.GoogleOAuth.ClientSecret, callbackURL("google")),
-		github.New(c.GithubOAuth.ClientID, c.GithubOAuth.ClientSecret, callbackURL("github")),
-	)
-}
@@ -0,0 +1,115 @@
+// Package sitemap renders XML sitemaps and sitemap indexes
+// as defined by the sitemaps.org protocol.
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the maximum number of URLs a single sitemap may contain.
+const MaxURLs = 50000
+
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a single location of the sitemap.
+// Loc must be absolute.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Sitemap is a reference to a sitemap from the sitemap index.
+type Sitemap struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Encode renders the sitemap of the given URLs.
+func Encode(urls []URL) ([]byte, error) {
+	set := urlSet{
+		XMLNS: xmlns,
+		URLs:  make([]entry, len(urls)),
+	}
+
+	for i, u := range urls {
+		set.URLs[i] = newEntry(u.Loc, u.LastMod)
+	}
+
+	return marshal(set)
+}
+
+// EncodeIndex renders the sitemap index referencing the given sitemaps.
+func EncodeIndex(sitemaps []Sitemap) ([]byte, error) {
+	index := sitemapIndex{
+		XMLNS:    xmlns,
+		Sitemaps: make([]entry, len(sitemaps)),
+	}
+
+	for i, s := range sitemaps {
+		index.Sitemaps[i] = newEntry(s.Loc, s.LastMod)
+	}
+
+	return marshal(index)
+}
+
+// Split splits the URLs into chunks of at most size URLs.
+// A size out of range (0, MaxURLs] is treated as MaxURLs.
+func Split(urls []URL, size int) (chunks [][]URL) {
+	if size <= 0 || size > MaxURLs {
+		size = MaxURLs
+	}
+
+	for len(urls) > size {
+		chunks = append(chunks, urls[:size])
+		urls = urls[size:]
+	}
+
+	return append(chunks, urls)
+}
+
+// LastMod returns the latest modification time of the URLs.
+func LastMod(urls []URL) (t time.Time) {
+	for _, u := range urls {
+		if u.LastMod.After(t) {
+			t = u.LastMod
+		}
+	}
+
+	return
+}
+
+func newEntry(loc string, lastMod time.Time) entry {
+	e := entry{Loc: loc}
+	if !lastMod.IsZero() {
+		e.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+
+	return e
+}
+
+func marshal(v any) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
@@ -33,6 +33,7 @@ type RequestArgs struct {
 	bodyReader   io.Reader
 	authToken    string
 	headers      Headers
+	remoteAddr   string
 	bindResponse any
 	assertStatus int
 }
@@ -73,6 +74,10 @@ func makeRequest(t *testing.T, r RequestArgs) *httptest.ResponseRecorder {
 		req.Header.Set(k, v)
 	}
 
+	if r.remoteAddr != "" {
+		req.RemoteAddr = r.remoteAddr
+	}
+
 	token := authToken
 	if r.authToken != "" {
 		token = r.authToken
@@ -0,0 +1,219 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func bookAuthorOf(a *ds.Author) ds.BookAuthor {
+	return ds.BookAuthor{ID: a.ID, Slug: a.Slug, Name: a.Name}
+}
+
+func TestGetAuthor(t *testing.T) {
+	author := create[ds.Author](t)
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+		Authors: []ds.BookAuthor{bookAuthorOf(author)},
+	})
+
+	// not listed until approved
+	create(t, ds.Book{Authors: []ds.BookAuthor{bookAuthorOf(author)}})
+
+	var resp response.Author
+	GET(t, pf("/authors/%s/", author.Slug), &resp)
+
+	assert.Equal(t, author.ID, resp.ID)
+	assert.Equal(t, author.Name, resp.Name)
+	assert.Equal(t, 1, resp.BookCount)
+	if assert.Len(t, resp.Books, 1) {
+		assert.Equal(t, book.PublicID, resp.Books[0].PublicID)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "/authors/" + random.String(32) + "/",
+			assertStatus: http.StatusNotFound,
+		})
+	})
+
+	t.Run("without approved public books", func(t *testing.T) {
+		author := create[ds.Author](t)
+		create(t, ds.Book{Authors: []ds.BookAuthor{bookAuthorOf(author)}})
+		create(t, ds.Book{
+			Entity: &ds.Entity{
+				Status:     ds.EntityStatusApproved,
+				Visibility: ds.EntityVisibilityPrivate,
+			},
+			Authors: []ds.BookAuthor{bookAuthorOf(author)},
+		})
+
+		login(t)
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         pf("/authors/%s/", author.Slug),
+			assertStatus: http.StatusNotFound,
+		})
+
+		var list response.FilterAuthors
+		GET(t, Query{Path: "authors", Params: request.FilterAuthors{Name: author.Name}}, &list)
+		assert.Empty(t, list.Data)
+
+		loginAsAdmin(t)
+		var adminResp response.Author
+		GET(t, pf("/authors/%s/", author.Slug), &adminResp)
+		assert.Equal(t, author.ID, adminResp.ID)
+
+		GET(t, Query{Path: "authors", Params: request.FilterAuthors{Name: author.Name}}, &list)
+		assert.Len(t, list.Data, 1)
+	})
+}
+
+func TestCreateBook_ResolvesAuthors(t *testing.T) {
+	login(t)
+
+	author := create[ds.Author](t)
+	topic := create(t, ds.Topic{Type: ds.EntityTypeBook})
+	newName := random.String(32)
+
+	req := request.CreateBook{
+		Title:       random.Title(),
+		Summary:     random.String(),
+		Description: random.String(),
+		ReleaseDate: random.ReleaseDate(),
+		Authors: []ds.BookAuthor{
+			{Name: newName, Link: "https://example.com/"},
+			{Name: strings.ToUpper(author.Name)},
+			{Name: author.Name},
+		},
+		Homepage: random.URL(),
+		Topics:   []ds.ID{topic.ID},
+	}
+
+	var resp ds.Book
+	CREATE(t, "books", req, &resp)
+
+	// the same author listed twice is linked once
+	if !assert.Len(t, resp.Authors, 2) {
+		return
+	}
+
+	assert.Equal(t, newName, resp.Authors[0].Name)
+	assert.Equal(t, author.ID, resp.Authors[1].ID)
+
+	test.AssertInDB(t, tt.DB, "authors", test.Data{
+		"id":   resp.Authors[0].ID,
+		"name": newName,
+		"slug": strings.ToLower(newName),
+	})
+	test.AssertInDB(t, tt.DB, "book_authors", test.Data{
+		"book_id":   resp.ID,
+		"author_id": author.ID,
+		"position":  1,
+	})
+}
+
+func TestMergeAuthor(t *testing.T) {
+	loginAsAdmin(t)
+
+	source := create[ds.Author](t)
+	target := create[ds.Author](t)
+
+	sourceOnly := create(t, ds.Book{
+		Entity:  &ds.Entity{Status: ds.EntityStatusApproved, Visibility: ds.EntityVisibilityPublic},
+		Authors: []ds.BookAuthor{bookAuthorOf(source)},
+	})
+	both := create(t, ds.Book{
+		Entity:  &ds.Entity{Status: ds.EntityStatusApproved, Visibility: ds.EntityVisibilityPublic},
+		Authors: []ds.BookAuthor{bookAuthorOf(source), bookAuthorOf(target)},
+	})
+
+	var resp ds.Author
+	POST(t, pf("/authors/%s/merge/", source.ID), request.MergeAuthor{Into: target.Slug}, &resp)
+
+	assert.Equal(t, target.ID, resp.ID)
+	assert.Equal(t, 2, resp.BookCount)
+	assert.Len(t, resp.Links, len(target.Links)+len(source.Links))
+
+	test.AssertInDB(t, tt.DB, "authors", test.Data{
+		"id":             source.ID,
+		"merged_into_id": target.ID,
+	})
+	test.AssertInDB(t, tt.DB, "book_authors", test.Data{
+		"book_id":   sourceOnly.ID,
+		"author_id": target.ID,
+	})
+	test.AssertInDB(t, tt.DB, "book_authors", test.Data{
+		"book_id":   both.ID,
+		"author_id": target.ID,
+		"position":  1,
+	})
+	test.AssertNotInDB(t, tt.DB, "book_authors", test.Data{
+		"author_id": source.ID,
+	})
+
+	t.Run("merged author resolves to the target", func(t *testing.T) {
+		var resp response.Author
+		GET(t, pf("/authors/%s/", source.Slug), &resp)
+
+		assert.Equal(t, target.ID, resp.ID)
+		assert.Len(t, resp.Books, 2)
+	})
+
+	t.Run("already merged", func(t *testing.T) {
+		other := create[ds.Author](t)
+		POST(t, pf("/authors/%s/merge/", source.ID), request.MergeAuthor{Into: other.Slug}, nil,
+			http.StatusUnprocessableEntity)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		POST(t, pf("/authors/%s/merge/", target.ID), request.MergeAuthor{Into: target.Slug}, nil,
+			http.StatusUnprocessableEntity)
+	})
+
+	t.Run("not admin", func(t *testing.T) {
+		login(t)
+		other := create[ds.Author](t)
+
+		POST(t, pf("/authors/%s/merge/", other.ID), request.MergeAuthor{Into: target.Slug}, nil,
+			http.StatusUnauthorized)
+	})
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	loginAsAdmin(t)
+
+	author := create[ds.Author](t)
+
+	req := request.UpdateAuthor{
+		Name:  random.String(),
+		Bio:   "**Gopher** since 2009",
+		Links: []ds.AuthorLink{{Title: "Blog", URL: "https://example.com/blog"}},
+	}
+
+	var resp ds.Author
+	UPDATE(t, pf("/authors/%s/", author.ID), req, &resp)
+
+	bioHTML, err := app.MarkdownToHTML(req.Bio)
+	test.CheckErr(t, err)
+
+	assert.Equal(t, author.Slug, resp.Slug)
+	test.AssertInDB(t, tt.DB, "authors", test.Data{
+		"id":      author.ID,
+		"name":    req.Name,
+		"bio_raw": req.Bio,
+		"bio":     bioHTML,
+	})
+}
@@ -0,0 +1,153 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindNewBookDuplicates(t *testing.T) {
+	login(t)
+
+	author := create[ds.Author](t)
+	existing := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Title:      "The Go Programming Language " + random.String(8),
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+		Authors: []ds.BookAuthor{bookAuthorOf(author)},
+		ISBN13:  "9780134190440",
+	})
+
+	findDuplicates := func(req request.CreateBook) []ds.BookDuplicate {
+		var resp []ds.BookDuplicate
+		POST(t, "/books/duplicates/", req, &resp)
+		return resp
+	}
+
+	t.Run("similar title and shared author", func(t *testing.T) {
+		resp := findDuplicates(request.CreateBook{
+			Title:   "Go Programming Language " + existing.Title[len(existing.Title)-8:],
+			Authors: []ds.BookAuthor{{Name: author.Name}},
+		})
+
+		assert.True(t, hasBookDuplicate(resp, existing.ID))
+		for _, d := range resp {
+			assert.Empty(t, d.Status)
+		}
+	})
+
+	t.Run("same ISBN", func(t *testing.T) {
+		resp := findDuplicates(request.CreateBook{
+			Title:  random.String(32),
+			ISBN10: "0-13-419044-0",
+		})
+
+		assert.True(t, hasBookDuplicate(resp, existing.ID))
+	})
+
+	t.Run("different book", func(t *testing.T) {
+		resp := findDuplicates(request.CreateBook{
+			Title:   random.String(32),
+			Authors: []ds.BookAuthor{{Name: author.Name}},
+		})
+
+		assert.False(t, hasBookDuplicate(resp, existing.ID))
+	})
+
+	t.Run("books under review are found by admins only", func(t *testing.T) {
+		hidden := create(t, ds.Book{
+			Entity: &ds.Entity{
+				Title:  "Under Review " + random.String(16),
+				Status: ds.EntityStatusUnderReview,
+			},
+		})
+
+		resp := findDuplicates(request.CreateBook{Title: hidden.Title})
+		assert.False(t, hasBookDuplicate(resp, hidden.ID))
+
+		loginAsAdmin(t)
+
+		resp = findDuplicates(request.CreateBook{Title: hidden.Title})
+		assert.True(t, hasBookDuplicate(resp, hidden.ID))
+		for _, d := range resp {
+			if d.ID == hidden.ID {
+				assert.Equal(t, ds.EntityStatusUnderReview, d.Status)
+			}
+		}
+	})
+}
+
+func TestMergeBook(t *testing.T) {
+	loginAsAdmin(t)
+
+	target := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+	})
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Title:      target.Title,
+			Status:     ds.EntityStatusUnderReview,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+		ISBN13: "9781234567897",
+	})
+
+	t.Run("flagged to the reviewer", func(t *testing.T) {
+		var resp []ds.BookDuplicate
+		GET(t, pf("/books/%s/duplicates/", book.ID), &resp)
+
+		assert.True(t, hasBookDuplicate(resp, target.ID))
+	})
+
+	var resp ds.Book
+	POST(t, pf("/books/%s/merge/", book.ID), request.MergeBook{IntoID: target.ID}, &resp)
+
+	assert.Equal(t, target.ID, resp.ID)
+
+	// topics and links are carried over
+	test.AssertInDB(t, tt.DB, "entity_topics", test.Data{
+		"entity_id": target.ID,
+		"topic_id":  book.Topics[0].ID,
+	})
+	test.AssertInDB(t, tt.DB, "books", test.Data{
+		"id":     target.ID,
+		"isbn13": book.ISBN13,
+	})
+
+	// the new book is rejected as a duplicate
+	test.AssertInDB(t, tt.DB, "entities", test.Data{
+		"id":     book.ID,
+		"status": ds.EntityStatusRejected,
+	})
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"entity_id": book.ID,
+		"type":      ds.EventLogEntityRejected,
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		other := create(t, ds.Book{Entity: &ds.Entity{Status: ds.EntityStatusUnderReview}})
+
+		POST(t, pf("/books/%s/merge/", other.ID), request.MergeBook{IntoID: other.ID}, nil,
+			http.StatusUnprocessableEntity)
+	})
+}
+
+func hasBookDuplicate(duplicates []ds.BookDuplicate, id ds.ID) bool {
+	for _, d := range duplicates {
+		if d.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
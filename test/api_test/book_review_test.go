@@ -0,0 +1,261 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveBookReview(t *testing.T) {
+	user := login(t)
+
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+	})
+
+	req := request.SaveBookReview{
+		Rating: 4,
+		Body:   "**Great** book",
+	}
+
+	var resp ds.BookReview
+	UPDATE(t, pf("/books/%s/review/", book.ID), req, &resp)
+
+	bodyHTML, err := app.MarkdownToHTML(req.Body)
+	test.CheckErr(t, err)
+
+	test.AssertInDB(t, tt.DB, "book_reviews", test.Data{
+		"id":       resp.ID,
+		"book_id":  book.ID,
+		"user_id":  user.ID,
+		"rating":   req.Rating,
+		"body_raw": req.Body,
+		"body":     bodyHTML,
+	})
+
+	test.AssertInDB(t, tt.DB, "books", test.Data{
+		"id":           book.ID,
+		"rating":       4,
+		"rating_count": 1,
+	})
+
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   user.ID,
+		"type":      ds.EventLogBookReviewed,
+		"entity_id": book.ID,
+		"is_public": true,
+	})
+
+	t.Run("review again updates the review", func(t *testing.T) {
+		req := request.SaveBookReview{Rating: 2, Body: "Not that great after all"}
+
+		var resp2 ds.BookReview
+		UPDATE(t, pf("/books/%s/review/", book.ID), req, &resp2)
+		assert.Equal(t, resp.ID, resp2.ID)
+
+		test.AssertInDB(t, tt.DB, "book_reviews", test.Data{
+			"id":     resp.ID,
+			"rating": 2,
+		})
+		test.AssertNotInDB(t, tt.DB, "book_reviews", test.Data{
+			"book_id": book.ID,
+			"rating":  4,
+		})
+	})
+
+	t.Run("rating out of range", func(t *testing.T) {
+		Request(t, RequestArgs{
+			method:       http.MethodPut,
+			path:         pf("/books/%s/review/", book.ID),
+			body:         request.SaveBookReview{Rating: 6, Body: "Too good"},
+			assertStatus: http.StatusUnprocessableEntity,
+		})
+	})
+
+	t.Run("book under review", func(t *testing.T) {
+		book := create(t, ds.Book{
+			Entity: &ds.Entity{
+				Status: ds.EntityStatusUnderReview,
+			},
+		})
+
+		Request(t, RequestArgs{
+			method:       http.MethodPut,
+			path:         pf("/books/%s/review/", book.ID),
+			body:         req,
+			assertStatus: http.StatusUnprocessableEntity,
+		})
+	})
+
+	t.Run("delete review", func(t *testing.T) {
+		var status response.Status
+		DELETE(t, pf("/books/%s/review/", book.ID), &status)
+
+		test.AssertNotInDB(t, tt.DB, "book_reviews", test.Data{"id": resp.ID})
+		test.AssertInDB(t, tt.DB, "books", test.Data{
+			"id":           book.ID,
+			"rating":       0,
+			"rating_count": 0,
+		})
+	})
+}
+
+func TestFilterBookReviews(t *testing.T) {
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+	})
+
+	for _, rating := range []int{5, 5, 4, 1} {
+		create(t, ds.BookReview{BookID: book.ID, Rating: rating})
+	}
+	create(t, ds.BookReview{BookID: book.ID, Rating: 1, Hidden: true})
+
+	var resp response.FilterBookReviews
+	GET(t, pf("/books/%s/reviews/", book.ID), &resp)
+
+	assert.Len(t, resp.Data, 4)
+	assert.Equal(t, 4, resp.Count)
+	assert.Equal(t, 4, resp.Rating.Count)
+	assert.InDelta(t, 3.75, resp.Rating.Average, 0.001)
+	assert.Equal(t, [5]int{1, 0, 0, 1, 2}, resp.Rating.Distribution)
+}
+
+func TestFilterBooks_SortByRating(t *testing.T) {
+	authors := []ds.BookAuthor{{Name: ds.NewID().String()}}
+
+	books := make([]*ds.Book, 3)
+	for i := range books {
+		books[i] = create(t, ds.Book{
+			Entity: &ds.Entity{
+				Status:     ds.EntityStatusApproved,
+				Visibility: ds.EntityVisibilityPublic,
+			},
+			Authors: authors,
+		})
+	}
+
+	create(t, ds.BookReview{BookID: books[0].ID, Rating: 3})
+	create(t, ds.BookReview{BookID: books[1].ID, Rating: 5})
+	create(t, ds.BookReview{BookID: books[2].ID, Rating: 4})
+
+	var resp response.FilterBooks
+	GET(t, Query{
+		Path: "books",
+		Params: request.FilterBooks{
+			Author: authors[0].Name,
+			Sort:   ds.BookSortRating,
+		},
+	}, &resp)
+
+	assert.Len(t, resp.Data, 3)
+
+	ids := make([]ds.ID, len(resp.Data))
+	for i, b := range resp.Data {
+		ids[i] = b.ID
+	}
+	assert.Equal(t, []ds.ID{books[1].ID, books[2].ID, books[0].ID}, ids)
+	assert.InDelta(t, 5.0, resp.Data[0].Rating, 0.001)
+	assert.Equal(t, 1, resp.Data[0].RatingCount)
+}
+
+func TestReportBookReview(t *testing.T) {
+	user := login(t)
+
+	review := create[ds.BookReview](t)
+
+	req := request.ReportBookReview{Reason: "Spam"}
+
+	var resp response.Status
+	POST(t, pf("/book-reviews/%s/report/", review.ID), req, &resp)
+	// reporting again changes nothing
+	POST(t, pf("/book-reviews/%s/report/", review.ID), req, &resp)
+
+	test.AssertInDB(t, tt.DB, "book_review_reports", test.Data{
+		"review_id": review.ID,
+		"user_id":   user.ID,
+		"reason":    req.Reason,
+	})
+
+	test.AssertInDB(t, tt.DB, "book_reviews", test.Data{
+		"id":           review.ID,
+		"report_count": 1,
+	})
+
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   user.ID,
+		"type":      ds.EventLogBookReviewReported,
+		"entity_id": review.BookID,
+		"is_public": false,
+	})
+
+	t.Run("own review", func(t *testing.T) {
+		own := create(t, ds.BookReview{UserID: new(user.ID)})
+
+		POST(t, pf("/book-reviews/%s/report/", own.ID), req, nil, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestHideBookReview(t *testing.T) {
+	review := create(t, ds.BookReview{Rating: 5})
+
+	t.Run("admin only", func(t *testing.T) {
+		login(t)
+
+		Request(t, RequestArgs{
+			method:       http.MethodPut,
+			path:         pf("/book-reviews/%s/hide/", review.ID),
+			assertStatus: http.StatusUnauthorized,
+		})
+	})
+
+	admin := loginAsAdmin(t)
+
+	var resp ds.BookReview
+	UPDATE(t, pf("/book-reviews/%s/hide/", review.ID), struct{}{}, &resp)
+	assert.True(t, resp.Hidden)
+
+	test.AssertInDB(t, tt.DB, "book_reviews", test.Data{
+		"id":        review.ID,
+		"hidden":    true,
+		"hidden_by": admin.ID,
+	})
+
+	test.AssertInDB(t, tt.DB, "books", test.Data{
+		"id":           review.BookID,
+		"rating_count": 0,
+	})
+
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   admin.ID,
+		"type":      ds.EventLogBookReviewHidden,
+		"entity_id": review.BookID,
+		"is_public": false,
+	})
+
+	UPDATE(t, pf("/book-reviews/%s/unhide/", review.ID), struct{}{}, &resp)
+	assert.False(t, resp.Hidden)
+
+	test.AssertInDB(t, tt.DB, "books", test.Data{
+		"id":           review.BookID,
+		"rating":       5,
+		"rating_count": 1,
+	})
+
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   admin.ID,
+		"type":      ds.EventLogBookReviewUnhidden,
+		"entity_id": review.BookID,
+	})
+}
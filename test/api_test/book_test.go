@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,10 +36,17 @@ func TestCreateBook_Basic(t *testing.T) {
 		Authors:     factory.NewBookAuthors(),
 		Homepage:    random.URL(),
 		Topics:      []ds.ID{topic.ID},
+		ISBN13:      "978-0-13-419044-0",
+		Subtitle:    " " + random.String() + " ",
+		Series:      random.String(),
+
+		SeriesPosition: 2,
 	}
 
 	var resp ds.Book
 	CREATE(t, "books", req, &resp)
+	assert.Equal(t, "9780134190440", resp.ISBN13)
+	assert.Equal(t, strings.TrimSpace(req.Subtitle), resp.Subtitle)
 
 	summaryHTML, err := app.MarkdownToHTML(req.Summary)
 	test.CheckErr(t, err)
@@ -71,13 +79,25 @@ func TestCreateBook_Basic(t *testing.T) {
 
 	// check book created
 	test.AssertInDB(t, tt.DB, "books", test.Data{
-		"authors":           req.Authors,
 		"homepage":          req.Homepage,
 		"description_raw":   req.Description,
 		"description":       descriptionHTML,
 		"release_date":      req.ReleaseDate,
 		"release_date_sort": releaseDateSort,
-	})
+		"isbn13":            "9780134190440",
+		"series":            req.Series,
+		"series_position":   2,
+		"title_sort":        ds.TitleSortKey(req.Title),
+	})
+
+	// authors resolved and linked in order
+	for i, a := range resp.Authors {
+		test.AssertInDB(t, tt.DB, "book_authors", test.Data{
+			"book_id":   resp.ID,
+			"author_id": a.ID,
+			"position":  i,
+		})
+	}
 
 	// check log created
 	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
@@ -231,6 +251,34 @@ func TestFilterBooks(t *testing.T) {
 		assert.Len(t, resp.Data, 1)
 		assert.Equal(t, resp.Data[0].PublicID, book.PublicID)
 	})
+
+	t.Run("sort by title", func(t *testing.T) {
+		authors := []ds.BookAuthor{{Name: random.String(32)}}
+		for _, title := range []string{"The Zen of Go", "Concurrency in Go", "A Mastering Go"} {
+			create(t, ds.Book{
+				Entity: &ds.Entity{
+					Title:      title,
+					Status:     ds.EntityStatusApproved,
+					Visibility: ds.EntityVisibilityPublic,
+				},
+				Authors: authors,
+			})
+		}
+
+		req.Params = request.FilterBooks{
+			Author: authors[0].Name,
+			Sort:   ds.BookSortTitle,
+		}
+
+		GET(t, req, &resp)
+		assert.Len(t, resp.Data, 3)
+
+		titles := make([]string, len(resp.Data))
+		for i, b := range resp.Data {
+			titles[i] = b.Title
+		}
+		assert.Equal(t, []string{"Concurrency in Go", "A Mastering Go", "The Zen of Go"}, titles)
+	})
 }
 
 func TestUpdateBook_WithReview(t *testing.T) {
@@ -392,6 +440,64 @@ func TestUpdateBook_WithoutReview(t *testing.T) {
 	})
 }
 
+func TestUpdateBook_Editions(t *testing.T) {
+	loginAsAdmin(t)
+
+	book := create[ds.Book](t)
+	req := request.UpdateBook{
+		CreateBook: request.CreateBook{
+			Title:       book.Title,
+			Summary:     book.SummaryRaw,
+			Description: book.DescriptionRaw,
+			ReleaseDate: book.ReleaseDate,
+			Authors:     book.Authors,
+			Homepage:    book.Homepage,
+			CoverFileID: book.CoverFileID,
+			Editions: []ds.BookEdition{{
+				Number:    2,
+				GoVersion: "go1.22",
+				ISBN13:    "978-0-13-419044-0",
+				Links: []ds.BookEditionLink{
+					{Format: ds.BookFormatEbook, URL: " https://example.com/ebook "},
+					{Format: ds.BookFormatPrint, URL: ""},
+				},
+			}},
+		},
+	}
+
+	var resp ds.EntityChangeRequest
+	UPDATE(t, pf("/books/%s/", book.ID), req, &resp)
+	assert.Equal(t, ds.EntityChangeCommitted, resp.Status)
+
+	// editions are sanitized
+	test.AssertInDB(t, tt.DB, "book_editions", test.Data{
+		"book_id":    book.ID,
+		"number":     2,
+		"go_version": "1.22",
+		"isbn13":     "9780134190440",
+		"links":      []ds.BookEditionLink{{Format: ds.BookFormatEbook, URL: "https://example.com/ebook"}},
+	})
+
+	// editions left out of the request are kept
+	req.Editions = nil
+	req.Summary = random.Edit(book.SummaryRaw)
+	UPDATE(t, pf("/books/%s/", book.ID), req, &resp)
+	test.AssertInDB(t, tt.DB, "book_editions", test.Data{
+		"book_id": book.ID,
+	})
+
+	var state service.EntityChange
+	GET(t, pf("/books/%s/edit/", book.ID), &state)
+	assert.Len(t, state.Data["editions"], 1)
+
+	// an empty list removes them
+	req.Editions = []ds.BookEdition{}
+	UPDATE(t, pf("/books/%s/", book.ID), req, &resp)
+	test.AssertNotInDB(t, tt.DB, "book_editions", test.Data{
+		"book_id": book.ID,
+	})
+}
+
 func TestApproveNewBook(t *testing.T) {
 	admin := loginAsAdmin(t)
 
@@ -571,3 +677,111 @@ func TestSearchBooks(t *testing.T) {
 		}
 	})
 }
+
+func TestImportBooks(t *testing.T) {
+	admin := loginAsAdmin(t)
+
+	topic := create(t, ds.Topic{Type: ds.EntityTypeBook})
+	existing := create(t, ds.Book{Entity: &ds.Entity{Type: ds.EntityTypeBook}})
+
+	valid := ds.BookImportRow{
+		Title:       random.Title(),
+		Summary:     random.String(),
+		Authors:     factory.NewBookAuthors(),
+		ReleaseDate: random.ReleaseDate(),
+		Homepage:    random.URL(),
+		Topics:      []string{topic.PublicID},
+	}
+	rows := []ds.BookImportRow{
+		valid,
+		{Title: valid.Title, Topics: []string{topic.Name}},
+		{Title: existing.Title, Topics: []string{topic.Name}},
+		{Title: random.Title(), Topics: []string{"no-such-topic"}},
+	}
+
+	var report ds.BookImportReport
+	POST(t, "/books/import/?dry_run=true", rows, &report)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 2, report.Duplicates)
+	assert.Equal(t, 1, report.Failed)
+	assert.Contains(t, report.Rows[3].InputErrors, "topics")
+	test.AssertNotInDB(t, tt.DB, "entities", test.Data{"title": valid.Title})
+
+	report = ds.BookImportReport{}
+	POST(t, "/books/import/", rows, &report)
+
+	assert.Equal(t, 1, report.Created)
+	if assert.Equal(t, ds.BookImportCreated, report.Rows[0].Status) {
+		test.AssertInDB(t, tt.DB, "entities", test.Data{
+			"id":       report.Rows[0].BookID,
+			"title":    valid.Title,
+			"owner_id": admin.ID,
+			"status":   ds.EntityStatusUnderReview,
+		})
+
+		test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+			"entity_id": report.Rows[0].BookID,
+			"user_id":   admin.ID,
+			"type":      ds.EventLogEntitySubmitted,
+		})
+
+		test.AssertInDB(t, tt.DB, "entity_topics", test.Data{
+			"entity_id": report.Rows[0].BookID,
+			"topic_id":  topic.ID,
+		})
+	}
+
+	t.Run("isbn", func(t *testing.T) {
+		report := ds.BookImportReport{}
+		POST(t, "/books/import/?dry_run=true&topics="+topic.PublicID, []ds.BookImportRow{
+			{ISBN: "978-1-61729-178-4"},
+			{ISBN: "9780134190441"},
+		}, &report)
+
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, "Go in Action", report.Rows[0].Title)
+		assert.Equal(t, ds.BookImportFailed, report.Rows[1].Status)
+	})
+
+	t.Run("not admin", func(t *testing.T) {
+		login(t)
+
+		var errResp handler.Error
+		POST(t, "/books/import/", rows, &errResp, http.StatusUnauthorized)
+	})
+}
+
+func TestLookupBookMetadata(t *testing.T) {
+	login(t)
+
+	var meta service.BookMetadata
+	GET(t, "/books/isbn/978-0-13-419044-0/", &meta)
+
+	assert.Equal(t, "The Go Programming Language", meta.Title)
+	assert.Equal(t, "0134190440", meta.ISBN10)
+	assert.Equal(t, "9780134190440", meta.ISBN13)
+	assert.Equal(t, "October 26, 2015", meta.ReleaseDate)
+	assert.Len(t, meta.Authors, 2)
+
+	t.Run("invalid ISBN", func(t *testing.T) {
+		var errResp handler.Error
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "/books/isbn/9780134190441/",
+			bindResponse: &errResp,
+			assertStatus: http.StatusUnprocessableEntity,
+		})
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var errResp handler.Error
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "/books/isbn/9780262033848/",
+			bindResponse: &errResp,
+			assertStatus: http.StatusNotFound,
+		})
+	})
+}
@@ -2,10 +2,12 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/ds/prop"
 	"github.com/gopl-dev/server/diff"
 	"github.com/gopl-dev/server/server/request"
 	"github.com/gopl-dev/server/server/response"
@@ -131,6 +133,7 @@ func TestApplyChangeRequestToBook(t *testing.T) {
 	homepagePatch := random.Patch(book.Homepage)
 	releaseDatePatch := app.MakePatch(book.ReleaseDate, random.ReleaseDate())
 
+	authorName := random.String(32)
 	cr := create(t, ds.EntityChangeRequest{
 		EntityID: book.ID,
 		UserID:   user.ID,
@@ -143,7 +146,7 @@ func TestApplyChangeRequestToBook(t *testing.T) {
 			"homepage":      homepagePatch,
 			"release_date":  releaseDatePatch,
 			"topics":        []string{newTopic.PublicID},
-			"authors":       []ds.BookAuthor{{Name: random.String(), Link: random.String()}},
+			"authors":       []ds.BookAuthor{{Name: authorName, Link: random.URL()}},
 		},
 	})
 
@@ -185,9 +188,15 @@ func TestApplyChangeRequestToBook(t *testing.T) {
 		"cover_file_id":   cr.Diff["cover_file_id"],
 		"homepage":        patchedHomepage,
 		"release_date":    patchedReleaseDate,
-		"authors":         cr.Diff["authors"],
 	})
 
+	// the new author should be created and replace the old ones
+	patched, err := tt.Service.GetBookByID(context.Background(), book.ID)
+	test.CheckErr(t, err)
+	if assert.Len(t, patched.Authors, 1) {
+		assert.Equal(t, authorName, patched.Authors[0].Name)
+	}
+
 	// old cover should be deleted
 	test.AssertInDB(t, tt.DB, "files", test.Data{
 		"id":         cover.ID,
@@ -236,6 +245,65 @@ func TestApplyChangeRequestToBook(t *testing.T) {
 	assert.Equal(t, app.ServerURL("/books/"+book.PublicID), emailVars["view_url"])
 }
 
+func TestApplyChangeRequestToBook_Editions(t *testing.T) {
+	_ = loginAsAdmin(t)
+
+	user := create[ds.User](t)
+	book := create[ds.Book](t)
+
+	editions := []ds.BookEdition{
+		{
+			Number:      1,
+			GoVersion:   "1.5",
+			Publisher:   random.String(),
+			ISBN13:      "9780134190440",
+			PageCount:   380,
+			ReleaseDate: "2015",
+			Links:       []ds.BookEditionLink{{Format: ds.BookFormatPrint, URL: "https://example.com/buy"}},
+		},
+		{
+			Number:    2,
+			GoVersion: "1.22",
+			Links:     []ds.BookEditionLink{{Format: ds.BookFormatOnline, URL: "https://example.com/read"}},
+		},
+	}
+	cr := create(t, ds.EntityChangeRequest{
+		EntityID: book.ID,
+		UserID:   user.ID,
+		Status:   ds.EntityChangePending,
+		Diff: map[string]any{
+			"editions": editions,
+		},
+	})
+
+	var diffResp response.ChangeRequestDiff
+	GET(t, pf("change-requests/%s/diff/", cr.ID), &diffResp)
+
+	assert.Len(t, diffResp.Diff, 1)
+	assert.Equal(t, "editions", diffResp.Diff[0].Key)
+	assert.Equal(t, prop.List, diffResp.Diff[0].Type)
+	assert.Len(t, diffResp.Diff[0].Proposed, len(editions))
+
+	var resp response.Status
+	UPDATE(t, pf("change-requests/%s/apply/", cr.ID), struct{}{}, &resp)
+
+	test.AssertInDB(t, tt.DB, "book_editions", test.Data{
+		"book_id":    book.ID,
+		"position":   0,
+		"number":     1,
+		"go_version": "1.5",
+		"isbn13":     "9780134190440",
+		"page_count": 380,
+		"links":      editions[0].Links,
+	})
+	test.AssertInDB(t, tt.DB, "book_editions", test.Data{
+		"book_id":    book.ID,
+		"position":   1,
+		"number":     2,
+		"go_version": "1.22",
+	})
+}
+
 func TestApplyChangeRequestToPage(t *testing.T) {
 	admin := loginAsAdmin(t)
 
@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkBrokenLink creates a book linking to a page that is gone
+// and checks it enough times for the link to be broken.
+func checkBrokenLink(t *testing.T) (*ds.Book, string) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+		Homepage: srv.URL + "/gone",
+	})
+
+	tt.PrepareExternalLinkChecks(t, srv)
+
+	ctx := context.Background()
+	for range ds.ExternalLinkBrokenAfter {
+		_, err := tt.DB.Exec(ctx, "UPDATE external_links SET next_check_at = NOW() WHERE url = $1", book.Homepage)
+		test.CheckErr(t, err)
+
+		err = tt.Service.CheckDueExternalLinks(ctx)
+		test.CheckErr(t, err)
+	}
+
+	return book, book.Homepage
+}
+
+func TestFilterExternalLinks(t *testing.T) {
+	loginAsAdmin(t)
+
+	book, url := checkBrokenLink(t)
+
+	var resp response.FilterExternalLinks
+	GET(t, "/external-links/?status=broken&per_page=1000", &resp)
+
+	var link *ds.ExternalLink
+	for _, l := range resp.Data {
+		assert.Equal(t, ds.ExternalLinkBroken, l.Status)
+		if l.URL == url {
+			link = &l
+		}
+	}
+
+	if !assert.NotNil(t, link, "broken link is not listed") {
+		return
+	}
+
+	assert.Equal(t, http.StatusNotFound, link.StatusCode)
+	if assert.Len(t, link.Sources, 1) {
+		assert.Equal(t, ds.ExternalLinkSourceBook, link.Sources[0].SourceType)
+		assert.Equal(t, book.ID, link.Sources[0].SourceID)
+		assert.Equal(t, book.Title, link.Sources[0].Title)
+		assert.Equal(t, book.PublicID, link.Sources[0].PublicID)
+	}
+
+	t.Run("checks", func(t *testing.T) {
+		var resp response.FilterExternalLinkChecks
+		GET(t, pf("/external-links/%s/checks/", link.ID), &resp)
+
+		assert.Equal(t, ds.ExternalLinkBrokenAfter, resp.Count)
+		if assert.NotEmpty(t, resp.Data) {
+			assert.Equal(t, ds.ExternalLinkBroken, resp.Data[0].Status)
+		}
+	})
+
+	t.Run("not admin", func(t *testing.T) {
+		login(t)
+
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "/external-links/",
+			assertStatus: http.StatusUnauthorized,
+		})
+	})
+}
+
+func TestBookViewBrokenLinks(t *testing.T) {
+	login(t)
+
+	book, url := checkBrokenLink(t)
+
+	resp := makeRequest(t, RequestArgs{
+		method: http.MethodGet,
+		path:   "/books/" + book.PublicID + "/",
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	body := resp.Body.String()
+	assert.Contains(t, body, "1 broken link")
+	assert.Contains(t, body, url)
+
+	t.Run("not shown to guests", func(t *testing.T) {
+		resp := makeRequest(t, RequestArgs{
+			method:    http.MethodGet,
+			path:      "/books/" + book.PublicID + "/",
+			authToken: "-",
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "broken link")
+	})
+}
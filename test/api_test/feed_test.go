@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/feed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBooksFeed(t *testing.T) {
+	book := create(t, ds.Book{Entity: &ds.Entity{
+		Status:     ds.EntityStatusApproved,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+	hidden := create(t, ds.Book{Entity: &ds.Entity{
+		Status:     ds.EntityStatusUnderReview,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+
+	for _, f := range feed.Formats {
+		t.Run(string(f), func(t *testing.T) {
+			resp := makeRequest(t, RequestArgs{
+				method: http.MethodGet,
+				path:   "/books/feed." + f.Ext(),
+			})
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, f.ContentType(), responseContentType(resp))
+			assert.NotEmpty(t, resp.Header().Get("ETag"))
+			assert.NotEmpty(t, resp.Header().Get("Last-Modified"))
+			assert.Contains(t, resp.Body.String(), book.PublicID)
+			assert.NotContains(t, resp.Body.String(), hidden.PublicID)
+
+			t.Run("not modified", func(t *testing.T) {
+				notModified := makeRequest(t, RequestArgs{
+					method:  http.MethodGet,
+					path:    "/books/feed." + f.Ext(),
+					headers: Headers{"If-None-Match": resp.Header().Get("ETag")},
+				})
+				assert.Equal(t, http.StatusNotModified, notModified.Code)
+				assert.Empty(t, notModified.Body.String())
+			})
+		})
+	}
+
+	t.Run("topic", func(t *testing.T) {
+		resp := makeRequest(t, RequestArgs{
+			method: http.MethodGet,
+			path:   "/books/topics/" + book.Topics[0].PublicID + "/feed.atom",
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), book.PublicID)
+		assert.Contains(t, resp.Body.String(), book.Topics[0].Name)
+	})
+
+	t.Run("unknown topic", func(t *testing.T) {
+		resp := makeRequest(t, RequestArgs{
+			method: http.MethodGet,
+			path:   "/books/topics/no-such-topic/feed.atom",
+		})
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestPagesFeed(t *testing.T) {
+	p := create(t, ds.Page{Entity: &ds.Entity{
+		Status:     ds.EntityStatusApproved,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+
+	resp := makeRequest(t, RequestArgs{
+		method: http.MethodGet,
+		path:   "/pages/feed.json",
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, feed.JSON.ContentType(), responseContentType(resp))
+	assert.Contains(t, resp.Body.String(), p.PublicID)
+}
+
+func TestActivityLogFeed(t *testing.T) {
+	user := create[ds.User](t)
+	log := create(t, ds.EventLog{
+		UserID:    &user.ID,
+		Type:      ds.EventLogUserEmailConfirmed,
+		IsPublic:  true,
+		CreatedAt: time.Now().Add(time.Minute),
+	})
+
+	resp := makeRequest(t, RequestArgs{
+		method: http.MethodGet,
+		path:   "/activity-log/feed.rss",
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, feed.RSS.ContentType(), responseContentType(resp))
+	assert.Contains(t, resp.Body.String(), log.ID.String())
+	assert.Contains(t, resp.Body.String(), user.Username)
+}
@@ -0,0 +1,40 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopl-dev/server/metrics"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, metrics.Path, nil)
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	return rr.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	err := metrics.RegisterDB(tt.DB, tt.Service)
+	test.CheckErr(t, err)
+
+	var resp response.ServerStatus
+	GET(t, "status", &resp)
+
+	body := scrapeMetrics(t)
+
+	// requests are labeled with the route pattern
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/api/status/",status="200"}`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/api/status/"}`)
+	assert.Contains(t, body, "db_pool_total_conns")
+	assert.Contains(t, body, "app_change_requests_pending")
+}
@@ -1,9 +1,14 @@
 package api_test
 
 import (
+	"encoding/json"
+	"net/http"
 	"testing"
 
+	"github.com/gopl-dev/server/health"
+	"github.com/gopl-dev/server/server/middleware"
 	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,3 +19,53 @@ func TestGetServerStatus(t *testing.T) {
 	assert.Equal(t, resp.Env, tt.Conf.App.Env)
 	assert.Equal(t, resp.Version, tt.Conf.App.Version)
 }
+
+func TestLiveness(t *testing.T) {
+	resp := makeRequest(t, RequestArgs{method: http.MethodGet, path: "/healthz"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var report health.Report
+	err := json.Unmarshal(resp.Body.Bytes(), &report)
+	test.CheckErr(t, err)
+	assert.Equal(t, health.StatusOK, report.Status)
+}
+
+func TestReadiness(t *testing.T) {
+	resp := makeRequest(t, RequestArgs{method: http.MethodGet, path: "/readyz"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var report health.Report
+	err := json.Unmarshal(resp.Body.Bytes(), &report)
+	test.CheckErr(t, err)
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	names := make([]string, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		names = append(names, c.Name)
+		assert.Equal(t, health.StatusOK, c.Status, c.Name+": "+c.Error)
+	}
+	assert.Equal(t, []string{"shutdown", "db", "migrations", "storage", "email"}, names)
+}
+
+func TestRequestID(t *testing.T) {
+	statusPath := "/" + tt.Conf.Server.APIBasePath + "/status/"
+
+	resp := makeRequest(t, RequestArgs{method: http.MethodGet, path: statusPath})
+	assert.Len(t, resp.Header().Get(middleware.RequestIDHeader), 32)
+
+	// the ID set by a reverse proxy is kept
+	resp = makeRequest(t, RequestArgs{
+		method:  http.MethodGet,
+		path:    statusPath,
+		headers: Headers{middleware.RequestIDHeader: "proxy-id-1"},
+	})
+	assert.Equal(t, "proxy-id-1", resp.Header().Get(middleware.RequestIDHeader))
+
+	// invalid IDs are replaced
+	resp = makeRequest(t, RequestArgs{
+		method:  http.MethodGet,
+		path:    statusPath,
+		headers: Headers{middleware.RequestIDHeader: "bad id\n"},
+	})
+	assert.Len(t, resp.Header().Get(middleware.RequestIDHeader), 32)
+}
@@ -0,0 +1,94 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookViewShareMeta(t *testing.T) {
+	book := create(t, ds.Book{Entity: &ds.Entity{
+		Status:     ds.EntityStatusApproved,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+
+	resp := makeRequest(t, RequestArgs{
+		method: http.MethodGet,
+		path:   "/books/" + book.PublicID + "/",
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	body := resp.Body.String()
+	assert.Contains(t, body, `<meta property="og:type" content="book">`)
+	assert.Contains(t, body, `/books/`+book.PublicID+`/social.jpg`)
+	assert.Contains(t, body, `<meta name="twitter:card" content="summary_large_image">`)
+	assert.Contains(t, body, `type="application/ld+json"`)
+	assert.Contains(t, body, `"@type":"Book"`)
+
+	t.Run("social image", func(t *testing.T) {
+		resp := makeRequest(t, RequestArgs{
+			method: http.MethodGet,
+			path:   "/books/" + book.PublicID + "/social.jpg",
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "image/jpeg", responseContentType(resp))
+		assert.NotEmpty(t, resp.Header().Get("ETag"))
+
+		notModified := makeRequest(t, RequestArgs{
+			method:  http.MethodGet,
+			path:    "/books/" + book.PublicID + "/social.jpg",
+			headers: Headers{"If-None-Match": resp.Header().Get("ETag")},
+		})
+		assert.Equal(t, http.StatusNotModified, notModified.Code)
+	})
+
+	t.Run("previous version is deleted", func(t *testing.T) {
+		getImage := func() string {
+			resp := makeRequest(t, RequestArgs{
+				method: http.MethodGet,
+				path:   "/books/" + book.PublicID + "/social.jpg",
+			})
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			var key string
+			err := tt.DB.QueryRow(t.Context(), "SELECT path FROM social_previews WHERE entity_id = $1", book.ID).Scan(&key)
+			test.CheckErr(t, err)
+
+			return key
+		}
+
+		oldKey := getImage()
+
+		_, err := tt.DB.Exec(t.Context(), "UPDATE entities SET title = $1 WHERE id = $2", random.Title(), book.ID)
+		test.CheckErr(t, err)
+
+		newKey := getImage()
+		assert.NotEqual(t, oldKey, newKey)
+
+		_, _, err = file.Open(t.Context(), oldKey)
+		assert.Error(t, err)
+
+		fh, _, err := file.Open(t.Context(), newKey)
+		test.CheckErr(t, err)
+		_ = fh.Close()
+	})
+}
+
+func TestUserProfileShareMeta(t *testing.T) {
+	user := create[ds.User](t)
+
+	resp := makeRequest(t, RequestArgs{
+		method: http.MethodGet,
+		path:   "/users/" + user.Username + "/",
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	body := resp.Body.String()
+	assert.Contains(t, body, `<meta property="og:type" content="profile">`)
+	assert.Contains(t, body, `"@type":"ProfilePage"`)
+}
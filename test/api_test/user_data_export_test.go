@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/test"
+)
+
+func TestRequestUserDataExport(t *testing.T) {
+	user := create(t, ds.User{EmailConfirmed: true})
+	token := loginAs(t, user)
+
+	Request(t, RequestArgs{
+		method:       http.MethodPost,
+		path:         "/users/data-export/",
+		authToken:    token,
+		assertStatus: http.StatusOK,
+	})
+
+	test.AssertInDB(t, tt.DB, "user_data_exports", test.Data{
+		"user_id": user.ID,
+		"status":  ds.UserDataExportPending,
+	})
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   user.ID,
+		"type":      ds.EventLogUserDataExportRequested,
+		"is_public": false,
+	})
+
+	t.Run("already in progress", func(t *testing.T) {
+		Request(t, RequestArgs{
+			method:       http.MethodPost,
+			path:         "/users/data-export/",
+			authToken:    token,
+			assertStatus: http.StatusUnprocessableEntity,
+		})
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		Request(t, RequestArgs{
+			method:       http.MethodPost,
+			path:         "/users/data-export/",
+			assertStatus: http.StatusUnauthorized,
+		})
+	})
+}
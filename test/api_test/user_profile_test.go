@@ -0,0 +1,139 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserProfile(t *testing.T) {
+	user := create(t, ds.User{EmailConfirmed: true})
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			OwnerID:    user.ID,
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+	})
+	create(t, ds.Book{
+		Entity: &ds.Entity{
+			OwnerID:    user.ID,
+			Status:     ds.EntityStatusUnderReview,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+	})
+	privateBook := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPrivate,
+		},
+	})
+	changeRequest := create(t, ds.EntityChangeRequest{
+		EntityID: book.ID,
+		UserID:   user.ID,
+		Status:   ds.EntityChangeCommitted,
+	})
+	create(t, ds.EntityChangeRequest{
+		EntityID: privateBook.ID,
+		UserID:   user.ID,
+		Status:   ds.EntityChangeCommitted,
+	})
+	create(t, ds.EventLog{UserID: &user.ID, IsPublic: true})
+	create(t, ds.EventLog{UserID: &user.ID, IsPublic: false})
+
+	var resp response.UserProfile
+	GET(t, "/users/"+user.Username+"/", &resp)
+
+	assert.Equal(t, user.Username, resp.Username)
+	assert.False(t, resp.Deleted)
+	if assert.Len(t, resp.Books, 1) {
+		assert.Equal(t, book.ID, resp.Books[0].ID)
+	}
+	// changes of private entities are not shown
+	if assert.Len(t, resp.ChangeRequests, 1) {
+		assert.Equal(t, changeRequest.ID, resp.ChangeRequests[0].ID)
+	}
+	assert.Len(t, resp.Activity, 1)
+
+	t.Run("hidden sections", func(t *testing.T) {
+		_, err := tt.DB.Exec(t.Context(), "UPDATE users SET show_books = false, show_activity = false WHERE id = $1", user.ID)
+		test.CheckErr(t, err)
+
+		var resp response.UserProfile
+		GET(t, "/users/"+user.Username+"/", &resp)
+
+		assert.Empty(t, resp.Books)
+		assert.Empty(t, resp.Activity)
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		deleted := create(t, ds.User{DeletedAt: new(time.Now())})
+
+		var resp response.UserProfile
+		GET(t, "/users/"+deleted.Username+"/", &resp)
+
+		assert.True(t, resp.Deleted)
+		assert.Equal(t, ds.DeletedUsername, resp.Username)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "/users/no-such-user/",
+			assertStatus: http.StatusNotFound,
+		})
+	})
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	user := create(t, ds.User{EmailConfirmed: true})
+	token := loginAs(t, user)
+
+	req := request.UpdateUserProfile{
+		Bio:       "Gopher",
+		Links:     []ds.UserLink{{Title: "Blog", URL: "https://example.com"}},
+		ShowBooks: true,
+	}
+
+	var resp response.Status
+	Request(t, RequestArgs{
+		method:       http.MethodPut,
+		path:         "/users/profile/",
+		body:         req,
+		authToken:    token,
+		bindResponse: &resp,
+		assertStatus: http.StatusOK,
+	})
+
+	test.AssertInDB(t, tt.DB, "users", test.Data{
+		"id":                   user.ID,
+		"bio":                  req.Bio,
+		"show_books":           true,
+		"show_pages":           false,
+		"show_change_requests": false,
+		"show_activity":        false,
+	})
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id":   user.ID,
+		"type":      ds.EventLogUserProfileUpdated,
+		"is_public": false,
+	})
+
+	t.Run("avatar of another user", func(t *testing.T) {
+		f := create(t, ds.File{Purpose: ds.FilePurposeUserAvatar})
+
+		Request(t, RequestArgs{
+			method:       http.MethodPut,
+			path:         "/users/profile/",
+			body:         request.UpdateUserProfile{AvatarFileID: f.ID},
+			authToken:    token,
+			assertStatus: http.StatusUnprocessableEntity,
+		})
+	})
+}
@@ -1,14 +1,22 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gopl-dev/server/app"
 	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/ratelimit"
 	"github.com/gopl-dev/server/server/handler"
 	"github.com/gopl-dev/server/server/request"
 	"github.com/gopl-dev/server/server/response"
@@ -118,6 +126,65 @@ func TestUserSignIn(t *testing.T) {
 	})
 }
 
+func TestUserSignInThrottledByIP(t *testing.T) {
+	conf := tt.Conf.RateLimit
+	if !conf.Enabled {
+		t.Skip("rate limit is disabled")
+	}
+
+	ip := fmt.Sprintf("198.51.100.%d", random.Int(1, 254))
+	signIn := func() *httptest.ResponseRecorder {
+		body, err := json.Marshal(request.UserSignIn{
+			Email:    random.Email(),
+			Password: random.String(),
+		})
+		test.CheckErr(t, err)
+
+		headers := Headers{}
+		if conf.ClientIPHeader != "" {
+			headers[conf.ClientIPHeader] = ip
+		}
+
+		return makeRequest(t, RequestArgs{
+			method:     http.MethodPost,
+			path:       path.Join("/", tt.Conf.Server.APIBasePath, "/users/sign-in") + "/",
+			bodyReader: bytes.NewReader(body),
+			headers:    headers,
+			remoteAddr: ip + ":1234",
+		})
+	}
+
+	// every attempt is made with another email, so only the IP limit applies
+	attempts := 0
+	for ; attempts < 100; attempts++ {
+		if signIn().Code == http.StatusTooManyRequests {
+			break
+		}
+	}
+	if attempts == 100 {
+		t.Fatal("sign-in was never throttled")
+	}
+
+	// denied requests don't extend the lock, so it's lifted when the oldest allowed request leaves the window
+	resp := signIn()
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+
+	retryAfter, err := strconv.Atoi(resp.Header().Get("Retry-After"))
+	test.CheckErr(t, err)
+	assert.Positive(t, retryAfter)
+	assert.LessOrEqual(t, retryAfter, int((15 * time.Minute).Seconds()))
+
+	if conf.Driver == ratelimit.MemoryDriver {
+		return
+	}
+
+	var hits int
+	err = tt.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM rate_limit_hits WHERE key = $1", "ip:sign_in:"+ip).Scan(&hits)
+	test.CheckErr(t, err)
+	assert.Equal(t, attempts, hits)
+}
+
 func TestChangePassword(t *testing.T) {
 	oldPassword := random.String(10)
 	newPassword := random.String(10)
@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/request"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	admin := loginAsAdmin(t)
+
+	req := request.CreateWebhook{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{string(ds.EventLogEntityAdded)},
+		Active:     true,
+	}
+
+	var resp response.CreateWebhook
+	CREATE(t, "webhooks/", req, &resp)
+
+	assert.NotEmpty(t, resp.Secret)
+	test.AssertInDB(t, tt.DB, "webhooks", test.Data{
+		"id":         resp.ID,
+		"url":        req.URL,
+		"secret":     resp.Secret,
+		"active":     true,
+		"created_by": admin.ID,
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		req := req
+		req.EventTypes = []string{"unknown"}
+
+		POST(t, "webhooks/", req, nil, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("admin only", func(t *testing.T) {
+		login(t)
+
+		POST(t, "webhooks/", req, nil, http.StatusUnauthorized)
+	})
+}
+
+func TestTestWebhook(t *testing.T) {
+	loginAsAdmin(t)
+
+	webhook := create[ds.Webhook](t)
+
+	var resp ds.WebhookDelivery
+	CREATE(t, pf("webhooks/%s/test/", webhook.ID), nil, &resp)
+
+	test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+		"id":         resp.ID,
+		"webhook_id": webhook.ID,
+		"event_type": ds.WebhookEventPing,
+		"status":     ds.WebhookDeliveryPending,
+	})
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	loginAsAdmin(t)
+
+	delivery := create(t, ds.WebhookDelivery{
+		Status:   ds.WebhookDeliveryFailed,
+		Attempts: 6,
+	})
+
+	var resp ds.WebhookDelivery
+	CREATE(t, pf("webhooks/%s/deliveries/%s/redeliver/", delivery.WebhookID, delivery.ID), nil, &resp)
+
+	assert.NotEqual(t, delivery.ID, resp.ID)
+	test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+		"id":         resp.ID,
+		"webhook_id": delivery.WebhookID,
+		"status":     ds.WebhookDeliveryPending,
+		"attempts":   0,
+	})
+
+	var deliveries response.FilterWebhookDeliveries
+	GET(t, pf("webhooks/%s/deliveries/", delivery.WebhookID), &deliveries)
+	assert.Equal(t, 2, deliveries.Count)
+}
+
+func TestWebhookDeliveryEnqueuedOnEvent(t *testing.T) {
+	webhook := create(t, ds.Webhook{
+		EventTypes: []string{string(ds.EventLogEntityAdded)},
+	})
+
+	book := create(t, ds.Book{Entity: &ds.Entity{Status: ds.EntityStatusApproved}})
+	err := tt.Service.LogEntityCreated(t.Context(), book.Entity)
+	test.CheckErr(t, err)
+
+	test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+		"webhook_id": webhook.ID,
+		"event_type": ds.EventLogEntityAdded,
+		"status":     ds.WebhookDeliveryPending,
+	})
+
+	t.Run("private event", func(t *testing.T) {
+		webhook := create(t, ds.Webhook{
+			EventTypes: []string{string(ds.EventLogUserEmailConfirmed)},
+		})
+
+		user := create[ds.User](t)
+		err := tt.Service.LogEmailConfirmed(t.Context(), user.Email, user.ID)
+		test.CheckErr(t, err)
+
+		test.AssertNotInDB(t, tt.DB, "webhook_deliveries", test.Data{
+			"webhook_id": webhook.ID,
+		})
+	})
+}
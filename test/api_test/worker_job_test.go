@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/server/response"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func createWorkerJob(t *testing.T) string {
+	t.Helper()
+
+	name := "TEST:" + random.String()
+	err := tt.Service.RegisterWorkerJobs(context.Background(), []string{name})
+	test.CheckErr(t, err)
+
+	return name
+}
+
+func TestGetWorkerJobs(t *testing.T) {
+	loginAsAdmin(t)
+
+	name := createWorkerJob(t)
+	run, err := tt.Service.StartWorkerJobRun(context.Background(), name, ds.WorkerJobTriggerCLI)
+	test.CheckErr(t, err)
+
+	var jobs []ds.WorkerJob
+	GET(t, "worker-jobs/", &jobs)
+
+	var job *ds.WorkerJob
+	for i := range jobs {
+		if jobs[i].Name == name {
+			job = &jobs[i]
+		}
+	}
+
+	if assert.NotNil(t, job) && assert.NotNil(t, job.LastRun) {
+		assert.Equal(t, run.ID, job.LastRun.ID)
+		assert.Equal(t, ds.WorkerJobRunRunning, job.LastRun.Status)
+	}
+
+	var runs response.FilterWorkerJobRuns
+	GET(t, pf("worker-jobs/%s/runs/", url.PathEscape(name)), &runs)
+	assert.Equal(t, 1, runs.Count)
+
+	t.Run("admin only", func(t *testing.T) {
+		login(t)
+
+		Request(t, RequestArgs{
+			method:       http.MethodGet,
+			path:         "worker-jobs/",
+			assertStatus: http.StatusUnauthorized,
+		})
+	})
+}
+
+func TestPauseAndResumeWorkerJob(t *testing.T) {
+	loginAsAdmin(t)
+
+	name := createWorkerJob(t)
+
+	UPDATE(t, pf("worker-jobs/%s/pause/", url.PathEscape(name)), nil, nil)
+	test.AssertInDB(t, tt.DB, "worker_jobs", test.Data{
+		"name":   name,
+		"paused": true,
+	})
+
+	UPDATE(t, pf("worker-jobs/%s/resume/", url.PathEscape(name)), nil, nil)
+	test.AssertInDB(t, tt.DB, "worker_jobs", test.Data{
+		"name":   name,
+		"paused": false,
+	})
+}
+
+func TestRunWorkerJob(t *testing.T) {
+	loginAsAdmin(t)
+
+	name := createWorkerJob(t)
+
+	POST(t, pf("worker-jobs/%s/run/", url.PathEscape(name)), nil, nil)
+	test.AssertInDB(t, tt.DB, "worker_jobs", test.Data{
+		"name":             name,
+		"run_requested_at": test.NotNull,
+	})
+
+	names, err := tt.Service.TakeRequestedWorkerJobs(context.Background())
+	test.CheckErr(t, err)
+	assert.Contains(t, names, name)
+
+	t.Run("unknown job", func(t *testing.T) {
+		POST(t, "worker-jobs/UNKNOWN/run/", nil, nil, http.StatusNotFound)
+	})
+}
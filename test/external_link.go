@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app/service"
+)
+
+// onlyHostsTransport lets requests reach the given hosts only, failing the others.
+type onlyHostsTransport struct {
+	hosts []string
+	next  http.RoundTripper
+}
+
+func (t onlyHostsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !slices.Contains(t.hosts, req.URL.Host) {
+		return nil, fmt.Errorf("%s is not reachable in tests", req.URL.Host)
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// PrepareExternalLinkChecks makes the check of external links reach only the given test servers,
+// without waiting between requests, until the end of the test.
+// The links found in the database are synced, and those of other hosts (created by other tests)
+// are postponed, so the next check only checks the links of the servers.
+func (a *App) PrepareExternalLinkChecks(t *testing.T, servers ...*httptest.Server) {
+	t.Helper()
+
+	client, interval := service.ExternalLinkHTTPClient, service.ExternalLinkHostInterval
+	t.Cleanup(func() {
+		service.ExternalLinkHTTPClient, service.ExternalLinkHostInterval = client, interval
+	})
+
+	hosts := make([]string, len(servers))
+	prefixes := make([]string, len(servers))
+	for i, srv := range servers {
+		hosts[i] = strings.TrimPrefix(srv.URL, "http://")
+		prefixes[i] = srv.URL + "/%"
+	}
+
+	service.ExternalLinkHTTPClient = &http.Client{
+		Timeout:   time.Second,
+		Transport: onlyHostsTransport{hosts: hosts, next: http.DefaultTransport},
+	}
+	service.ExternalLinkHostInterval = 0
+
+	ctx := context.Background()
+	err := a.Service.SyncExternalLinks(ctx)
+	CheckErr(t, err)
+
+	_, err = a.DB.Exec(ctx, `UPDATE external_links SET next_check_at = NOW() + INTERVAL '1 day'
+		WHERE NOT url LIKE ANY($1)`, prefixes)
+	CheckErr(t, err)
+}
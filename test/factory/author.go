@@ -0,0 +1,63 @@
+package factory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	fake "github.com/brianvoe/gofakeit/v7"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/test/factory/random"
+)
+
+// NewAuthor creates a new Author model populated with fake data.
+func (f *Factory) NewAuthor(overrideOpt ...ds.Author) (m *ds.Author) {
+	name := fake.BookAuthor()
+	bio := fake.Paragraph()
+
+	m = &ds.Author{
+		ID:        ds.NewID(),
+		Slug:      ds.AuthorSlug(name) + "-" + strings.ToLower(random.String(8)), //nolint:mnd
+		Name:      name,
+		BioRaw:    bio,
+		Bio:       "<p>" + bio + "</p>",
+		Links:     []ds.AuthorLink{{Title: "Homepage", URL: fake.URL()}},
+		CreatedAt: time.Now(),
+	}
+
+	if len(overrideOpt) == 1 {
+		merge(m, overrideOpt[0])
+	}
+
+	return
+}
+
+// CreateAuthor creates and persists a new Author record in the repository.
+func (f *Factory) CreateAuthor(overrideOpt ...ds.Author) (m *ds.Author, err error) {
+	m = f.NewAuthor(overrideOpt...)
+
+	err = f.repo.CreateAuthor(context.Background(), m)
+	return
+}
+
+// createBookAuthors creates an author record for each of the book authors not referring to one,
+// and links the authors to the book.
+func (f *Factory) createBookAuthors(book *ds.Book) error {
+	for i, ba := range book.Authors {
+		if !ba.ID.IsNil() {
+			continue
+		}
+
+		a, err := f.CreateAuthor(ds.Author{
+			Name: ba.Name,
+			Slug: ds.AuthorSlug(ba.Name) + "-" + strings.ToLower(random.String(8)), //nolint:mnd
+		})
+		if err != nil {
+			return err
+		}
+
+		book.Authors[i] = ds.BookAuthor{ID: a.ID, Slug: a.Slug, Name: a.Name}
+	}
+
+	return f.repo.ReplaceBookAuthors(context.Background(), book.ID, book.Authors)
+}
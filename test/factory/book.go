@@ -52,6 +52,11 @@ func (f *Factory) CreateBook(overrideOpt ...ds.Book) (m *ds.Book, err error) {
 		return
 	}
 
+	err = f.createBookAuthors(m)
+	if err != nil {
+		return
+	}
+
 	// book required to have at least one topic
 	if len(m.Topics) == 0 {
 		topic, err := f.CreateTopic(ds.Topic{Type: ds.EntityTypeBook})
@@ -0,0 +1,73 @@
+package factory
+
+import (
+	"context"
+	"time"
+
+	fake "github.com/brianvoe/gofakeit/v7"
+	"github.com/gopl-dev/server/app/ds"
+)
+
+// NewBookReview ...
+func (f *Factory) NewBookReview(overrideOpt ...ds.BookReview) (m *ds.BookReview) {
+	body := fake.Sentence(10) //nolint:mnd
+
+	m = &ds.BookReview{
+		ID:        ds.NewID(),
+		BookID:    ds.NilID,
+		Rating:    fake.Number(ds.BookReviewMinRating, ds.BookReviewMaxRating),
+		BodyRaw:   body,
+		Body:      "<p>" + body + "</p>",
+		CreatedAt: time.Now(),
+	}
+
+	if len(overrideOpt) == 1 {
+		merge(m, overrideOpt[0])
+	}
+
+	return
+}
+
+// CreateBookReview creates the review along with the book and the reviewer, unless given,
+// and updates the rating of the book.
+func (f *Factory) CreateBookReview(overrideOpt ...ds.BookReview) (m *ds.BookReview, err error) {
+	m = f.NewBookReview(overrideOpt...)
+
+	if m.BookID.IsNil() {
+		b, err := f.CreateBook(ds.Book{Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		m.BookID = b.ID
+	}
+
+	if m.UserID == nil {
+		u, err := f.CreateUser()
+		if err != nil {
+			return nil, err
+		}
+
+		m.UserID = new(u.ID)
+		m.Username = u.Username
+	}
+
+	err = f.repo.CreateBookReview(context.Background(), m)
+	if err != nil {
+		return
+	}
+
+	// the moderator doesn't matter to the tests, so it's the reviewer
+	if m.Hidden {
+		err = f.repo.SetBookReviewHidden(context.Background(), m, true, *m.UserID)
+		if err != nil {
+			return
+		}
+	}
+
+	err = f.repo.RefreshBookRating(context.Background(), m.BookID)
+	return
+}
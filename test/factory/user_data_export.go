@@ -0,0 +1,43 @@
+package factory
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/test/factory/random"
+)
+
+// NewUserDataExport ...
+func (f *Factory) NewUserDataExport(overrideOpt ...ds.UserDataExport) (m *ds.UserDataExport) {
+	m = &ds.UserDataExport{
+		ID:        ds.NilID,
+		UserID:    ds.NilID,
+		Status:    ds.UserDataExportPending,
+		Token:     random.String(),
+		CreatedAt: time.Now(),
+	}
+
+	if len(overrideOpt) == 1 {
+		merge(m, overrideOpt[0])
+	}
+
+	return
+}
+
+// CreateUserDataExport ...
+func (f *Factory) CreateUserDataExport(overrideOpt ...ds.UserDataExport) (m *ds.UserDataExport, err error) {
+	m = f.NewUserDataExport(overrideOpt...)
+
+	if m.UserID.IsNil() {
+		u, err := f.CreateUser()
+		if err != nil {
+			return nil, err
+		}
+
+		m.UserID = u.ID
+	}
+
+	err = f.repo.CreateUserDataExport(context.Background(), m)
+	return
+}
@@ -0,0 +1,74 @@
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	fake "github.com/brianvoe/gofakeit/v7"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/test/factory/random"
+)
+
+// NewWebhook ...
+func (f *Factory) NewWebhook(overrideOpt ...ds.Webhook) (m *ds.Webhook) {
+	m = &ds.Webhook{
+		ID:          ds.NewID(),
+		URL:         fake.URL(),
+		Secret:      random.String(),
+		EventTypes:  []string{},
+		Description: fake.Sentence(3), //nolint:mnd
+		Active:      true,
+		CreatedAt:   time.Now(),
+	}
+
+	if len(overrideOpt) == 1 {
+		merge(m, overrideOpt[0])
+	}
+
+	return
+}
+
+// CreateWebhook ...
+func (f *Factory) CreateWebhook(overrideOpt ...ds.Webhook) (m *ds.Webhook, err error) {
+	m = f.NewWebhook(overrideOpt...)
+
+	err = f.repo.CreateWebhook(context.Background(), m)
+	return
+}
+
+// NewWebhookDelivery ...
+func (f *Factory) NewWebhookDelivery(overrideOpt ...ds.WebhookDelivery) (m *ds.WebhookDelivery) {
+	m = &ds.WebhookDelivery{
+		ID:            ds.NewID(),
+		WebhookID:     ds.NilID,
+		EventType:     ds.WebhookEventPing,
+		Payload:       json.RawMessage(`{"event":"ping"}`),
+		Status:        ds.WebhookDeliveryPending,
+		NextAttemptAt: new(time.Now()),
+		CreatedAt:     time.Now(),
+	}
+
+	if len(overrideOpt) == 1 {
+		merge(m, overrideOpt[0])
+	}
+
+	return
+}
+
+// CreateWebhookDelivery ...
+func (f *Factory) CreateWebhookDelivery(overrideOpt ...ds.WebhookDelivery) (m *ds.WebhookDelivery, err error) {
+	m = f.NewWebhookDelivery(overrideOpt...)
+
+	if m.WebhookID.IsNil() {
+		w, err := f.CreateWebhook()
+		if err != nil {
+			return nil, err
+		}
+
+		m.WebhookID = w.ID
+	}
+
+	err = f.repo.CreateWebhookDelivery(context.Background(), m)
+	return
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticateUserLockout(t *testing.T) {
+	conf := tt.Conf.RateLimit
+	if !conf.Enabled {
+		t.Skip("rate limit is disabled")
+	}
+
+	ctx := context.Background()
+	password := random.String()
+	user := create(t, ds.User{Password: password})
+
+	for range conf.LockoutAttempts {
+		_, _, err := tt.Service.AuthenticateUser(ctx, user.Email, random.String())
+		assert.ErrorIs(t, err, service.ErrInvalidEmailOrPassword)
+	}
+
+	// the correct password doesn't help while the account is locked
+	_, _, err := tt.Service.AuthenticateUser(ctx, user.Email, password)
+	assert.ErrorIs(t, err, service.ErrAccountLocked)
+
+	appErr, ok := err.(app.Error) //nolint:errorlint
+	if assert.True(t, ok) {
+		assert.Positive(t, appErr.RetryAfter)
+	}
+
+	test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+		"user_id": user.ID,
+		"type":    ds.EventLogUserAccountLocked,
+	})
+
+	vars := test.LoadEmailVars(t, user.Email)
+	assert.Equal(t, user.Username, vars["Username"])
+	assert.Equal(t, conf.LockoutAttempts, vars["Attempts"])
+}
+
+func TestAuthenticateUserResetsFailedAttempts(t *testing.T) {
+	conf := tt.Conf.RateLimit
+	if !conf.Enabled {
+		t.Skip("rate limit is disabled")
+	}
+
+	ctx := context.Background()
+	password := random.String()
+	user := create(t, ds.User{Password: password})
+
+	for range conf.LockoutAttempts - 1 {
+		_, _, err := tt.Service.AuthenticateUser(ctx, user.Email, random.String())
+		assert.ErrorIs(t, err, service.ErrInvalidEmailOrPassword)
+	}
+
+	// successful sign-in forgets previous failures
+	_, _, err := tt.Service.AuthenticateUser(ctx, user.Email, password)
+	test.CheckErr(t, err)
+
+	_, _, err = tt.Service.AuthenticateUser(ctx, user.Email, random.String())
+	assert.ErrorIs(t, err, service.ErrInvalidEmailOrPassword)
+}
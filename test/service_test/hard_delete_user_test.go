@@ -31,6 +31,10 @@ func TestCleanupDeletedUser(t *testing.T) {
 	_, err = factory.Five(tt.Factory.CreateChangeEmailRequest, ds.ChangeEmailRequest{UserID: user.ID})
 	test.CheckErr(t, err)
 
+	// book reviews
+	review, err := tt.Factory.CreateBookReview(ds.BookReview{UserID: new(user.ID)})
+	test.CheckErr(t, err)
+
 	err = tt.Service.CleanupDeletedUser(ctx, user.ID)
 	test.CheckErr(t, err)
 
@@ -38,6 +42,11 @@ func TestCleanupDeletedUser(t *testing.T) {
 	test.AssertNotInDB(t, tt.DB, "email_confirmations", test.Data{"user_id": user.ID})
 	test.AssertNotInDB(t, tt.DB, "password_reset_tokens", test.Data{"user_id": user.ID})
 	test.AssertNotInDB(t, tt.DB, "change_email_requests", test.Data{"user_id": user.ID})
+	test.AssertNotInDB(t, tt.DB, "book_reviews", test.Data{"user_id": user.ID})
+
+	// the review stays, anonymized, and still counts towards the rating
+	test.AssertInDB(t, tt.DB, "book_reviews", test.Data{"id": review.ID, "user_id": nil})
+	test.AssertInDB(t, tt.DB, "books", test.Data{"id": review.BookID, "rating_count": 1})
 
 	user, err = tt.Service.GetUserByID(ctx, user.ID)
 	test.CheckErr(t, err)
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/oauth/provider"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/markbates/goth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkOAuthAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("link new provider", func(t *testing.T) {
+		user := create[ds.User](t)
+		oauthUser := goth.User{
+			Provider: provider.GitHub.String(),
+			UserID:   random.String(),
+		}
+
+		err := tt.Service.LinkOAuthAccount(ctx, user.ID, oauthUser)
+		test.CheckErr(t, err)
+
+		test.AssertInDB(t, tt.DB, "oauth_user_accounts", test.Data{
+			"user_id":          user.ID,
+			"provider":         oauthUser.Provider,
+			"provider_user_id": oauthUser.UserID,
+		})
+		test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+			"user_id": user.ID,
+			"type":    ds.EventLogUserOAuthAccountLinked,
+		})
+	})
+
+	t.Run("account linked to another user", func(t *testing.T) {
+		user := create[ds.User](t)
+		acc := create[ds.OAuthUserAccount](t)
+
+		err := tt.Service.LinkOAuthAccount(ctx, user.ID, goth.User{
+			Provider: acc.Provider.String(),
+			UserID:   acc.ProviderUserID,
+		})
+		assert.ErrorIs(t, err, service.ErrOAuthAccountLinkedToAnotherUser)
+	})
+
+	t.Run("provider already linked", func(t *testing.T) {
+		acc := create[ds.OAuthUserAccount](t)
+
+		err := tt.Service.LinkOAuthAccount(ctx, acc.UserID, goth.User{
+			Provider: acc.Provider.String(),
+			UserID:   random.String(),
+		})
+		assert.ErrorIs(t, err, service.ErrOAuthProviderAlreadyLinked)
+	})
+}
+
+func TestUnlinkOAuthAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("user with password", func(t *testing.T) {
+		acc := create[ds.OAuthUserAccount](t)
+
+		err := tt.Service.UnlinkOAuthAccount(ctx, acc.UserID, acc.Provider)
+		test.CheckErr(t, err)
+
+		test.AssertNotInDB(t, tt.DB, "oauth_user_accounts", test.Data{"id": acc.ID})
+		test.AssertInDB(t, tt.DB, "event_logs", test.Data{
+			"user_id": acc.UserID,
+			"type":    ds.EventLogUserOAuthAccountUnlinked,
+		})
+	})
+
+	t.Run("last sign-in method", func(t *testing.T) {
+		acc := create[ds.OAuthUserAccount](t)
+
+		// OAuth-only users have a random non-hash placeholder instead of a password
+		_, err := tt.DB.Exec(ctx, "UPDATE users SET password = $1 WHERE id = $2", random.String(32), acc.UserID)
+		test.CheckErr(t, err)
+
+		err = tt.Service.UnlinkOAuthAccount(ctx, acc.UserID, acc.Provider)
+		assert.ErrorIs(t, err, service.ErrCannotUnlinkLastSignInMethod)
+
+		test.AssertInDB(t, tt.DB, "oauth_user_accounts", test.Data{"id": acc.ID})
+	})
+
+	// OIDC is not enabled in the test config
+	t.Run("disabled provider", func(t *testing.T) {
+		acc := create(t, ds.OAuthUserAccount{Provider: provider.OIDC})
+
+		_, err := tt.DB.Exec(ctx, "UPDATE users SET password = $1 WHERE id = $2", random.String(32), acc.UserID)
+		test.CheckErr(t, err)
+
+		err = tt.Service.UnlinkOAuthAccount(ctx, acc.UserID, acc.Provider)
+		test.CheckErr(t, err)
+
+		test.AssertNotInDB(t, tt.DB, "oauth_user_accounts", test.Data{"id": acc.ID})
+	})
+
+	t.Run("last enabled sign-in method", func(t *testing.T) {
+		acc := create(t, ds.OAuthUserAccount{Provider: provider.Google})
+		create(t, ds.OAuthUserAccount{UserID: acc.UserID, Provider: provider.OIDC})
+
+		_, err := tt.DB.Exec(ctx, "UPDATE users SET password = $1 WHERE id = $2", random.String(32), acc.UserID)
+		test.CheckErr(t, err)
+
+		err = tt.Service.UnlinkOAuthAccount(ctx, acc.UserID, acc.Provider)
+		assert.ErrorIs(t, err, service.ErrCannotUnlinkLastSignInMethod)
+
+		test.AssertInDB(t, tt.DB, "oauth_user_accounts", test.Data{"id": acc.ID})
+	})
+
+	t.Run("not linked", func(t *testing.T) {
+		user := create[ds.User](t)
+
+		err := tt.Service.UnlinkOAuthAccount(ctx, user.ID, provider.Google)
+		assert.ErrorIs(t, err, service.ErrOAuthAccountNotLinked)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/service"
 	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/oauth"
 	"github.com/gopl-dev/server/test/factory"
 	"github.com/gopl-dev/server/tracing"
 	"github.com/jackc/pgx/v5"
@@ -47,11 +48,18 @@ func NewApp() *App {
 		log.Fatal(err)
 	}
 
+	oauth.Setup(app.Config())
+
+	services, err := service.New(db, tracer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	return &App{
 		Conf:    app.Config(),
 		Tracer:  tracer,
 		DB:      db,
-		Service: service.New(db, tracer),
+		Service: services,
 		Factory: factory.New(db),
 	}
 }
@@ -0,0 +1,111 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUpdateAuthorInput(t *testing.T) {
+	t.Parallel()
+
+	link := ds.AuthorLink{Title: "Homepage", URL: "https://example.com/"}
+
+	cases := []struct {
+		name      string
+		valid     bool
+		expectErr string
+		argName   string
+		data      service.UpdateAuthorInput
+	}{
+		{
+			name:      "blank name",
+			expectErr: "Name is required",
+			argName:   "name",
+			data:      service.UpdateAuthorInput{Name: "  "},
+		},
+		{
+			name:      "name too long",
+			expectErr: "Name must be at most 255 characters",
+			argName:   "name",
+			data:      service.UpdateAuthorInput{Name: strings.Repeat("a", 256)},
+		},
+		{
+			name:      "bio too long",
+			expectErr: "Bio must be at most 10000 characters",
+			argName:   "bio",
+			data:      service.UpdateAuthorInput{Name: "Rob Pike", Bio: strings.Repeat("a", 10001)},
+		},
+		{
+			name:      "invalid link URL",
+			expectErr: "Invalid URL",
+			argName:   "links[0].url",
+			data: service.UpdateAuthorInput{Name: "Rob Pike", Links: []ds.AuthorLink{
+				{Title: "Homepage", URL: "not a url"},
+			}},
+		},
+		{
+			name:      "link without title",
+			expectErr: "Title is required",
+			argName:   "links[0].title",
+			data: service.UpdateAuthorInput{Name: "Rob Pike", Links: []ds.AuthorLink{
+				{URL: "https://example.com/"},
+			}},
+		},
+		{
+			name:      "too many links",
+			expectErr: "At most 10 links are allowed",
+			argName:   "links",
+			data: service.UpdateAuthorInput{Name: "Rob Pike", Links: []ds.AuthorLink{
+				link, link, link, link, link, link, link, link, link, link, link,
+			}},
+		},
+		{
+			valid: true,
+			name:  "valid input",
+			data: service.UpdateAuthorInput{
+				Name:  "Rob Pike",
+				Bio:   "Co-creator of **Go**.",
+				Links: []ds.AuthorLink{link},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := service.Normalize(&c.data)
+			checkValidatedInput(t, c.valid, err, c.argName, c.expectErr)
+		})
+	}
+}
+
+func TestAuthorSlug(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "alan-a-a-donovan", ds.AuthorSlug("Alan A. A. Donovan"))
+	assert.Equal(t, "brian-w-kernighan", ds.AuthorSlug("  Brian W. Kernighan "))
+
+	slug := ds.AuthorSlug("???")
+	assert.True(t, strings.HasPrefix(slug, "author-"))
+	assert.NotEqual(t, slug, ds.AuthorSlug("???"))
+}
+
+func TestBookAuthorsData(t *testing.T) {
+	t.Parallel()
+
+	b := ds.Book{Authors: []ds.BookAuthor{
+		{ID: ds.NewID(), Slug: "rob-pike", Name: "Rob Pike"},
+		{Name: "Ken Thompson", Link: "https://example.com/"},
+	}}
+
+	// only the editable data is compared by change requests
+	assert.Equal(t, []ds.BookAuthor{
+		{Name: "Rob Pike"},
+		{Name: "Ken Thompson", Link: "https://example.com/"},
+	}, b.AuthorsData())
+}
@@ -0,0 +1,56 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBookDuplicatesQuery(t *testing.T) {
+	t.Parallel()
+
+	id := ds.NewID()
+	q := ds.NewBookDuplicatesQuery(&ds.Book{
+		Entity: &ds.Entity{ID: id, Title: " The Go Programming Language "},
+		Authors: []ds.BookAuthor{
+			{Name: "Alan A. A. Donovan"},
+			{Name: " "},
+		},
+		ISBN10: "0-13-419044-0",
+		Editions: []ds.BookEdition{
+			{Number: 1, ISBN13: "978-0-13-419044-0"},
+		},
+	})
+
+	assert.Equal(t, id, q.ExcludeID)
+	assert.Equal(t, "The Go Programming Language", q.Title)
+	assert.Equal(t, []string{"alan a. a. donovan"}, q.Authors)
+	assert.Equal(t, []string{"alan-a-a-donovan"}, q.AuthorSlugs)
+	// the ISBN-10 and the ISBN-13 of the edition are the same book
+	assert.Equal(t, []string{"0134190440", "9780134190440"}, q.ISBNs)
+}
+
+func TestBookDuplicateLikely(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		likely bool
+		d      ds.BookDuplicate
+	}{
+		{name: "same ISBN", likely: true, d: ds.BookDuplicate{SameISBN: true}},
+		{name: "similar title, shared author", likely: true, d: ds.BookDuplicate{TitleSimilarity: 0.4, SharedAuthors: 1}},
+		{name: "similar title only", likely: false, d: ds.BookDuplicate{TitleSimilarity: 0.4}},
+		{name: "nearly the same title", likely: true, d: ds.BookDuplicate{TitleSimilarity: 0.8}},
+		{name: "shared author only", likely: false, d: ds.BookDuplicate{TitleSimilarity: 0.1, SharedAuthors: 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.likely, c.d.Likely())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBookEditions(t *testing.T) {
+	t.Parallel()
+
+	newBook := func(e ds.BookEdition) *ds.Book {
+		return &ds.Book{
+			Entity:         &ds.Entity{Title: "The Go Programming Language"},
+			DescriptionRaw: "The Go Programming Language",
+			Description:    "<p>The Go Programming Language</p>",
+			ReleaseDate:    "2015",
+			Authors:        []ds.BookAuthor{{Name: "Alan A. A. Donovan"}},
+			Editions:       []ds.BookEdition{e},
+		}
+	}
+
+	valid := ds.BookEdition{
+		Number:      2,
+		GoVersion:   "1.22",
+		Publisher:   "Addison-Wesley",
+		ISBN13:      "9780134190440",
+		PageCount:   380,
+		ReleaseDate: "October 2015",
+		Links: []ds.BookEditionLink{
+			{Format: ds.BookFormatPrint, URL: "https://example.com/buy"},
+			{Format: ds.BookFormatOnline, URL: "https://example.com/read", Title: "Read online"},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		edit      func(e *ds.BookEdition)
+		argName   string
+		expectErr string
+	}{
+		{name: "valid", edit: func(*ds.BookEdition) {}},
+		{name: "no number", edit: func(e *ds.BookEdition) { e.Number = 0 },
+			argName: "editions[0].number", expectErr: "must be 1 or greater"},
+		{name: "go version", edit: func(e *ds.BookEdition) { e.GoVersion = "go1" },
+			argName: "editions[0].go_version", expectErr: "must be a Go version, e.g. 1.22"},
+		{name: "isbn", edit: func(e *ds.BookEdition) { e.ISBN13 = "9780134190441" },
+			argName: "editions[0].isbn13", expectErr: "invalid ISBN-13"},
+		{name: "page count", edit: func(e *ds.BookEdition) { e.PageCount = -1 },
+			argName: "editions[0].page_count", expectErr: "must not be negative"},
+		{name: "release date", edit: func(e *ds.BookEdition) { e.ReleaseDate = "10/2015" },
+			argName: "editions[0].release_date", expectErr: "format must one of: 2006; January 2006; January 2, 2006"},
+		{name: "link format", edit: func(e *ds.BookEdition) { e.Links[0].Format = "audio" },
+			argName: "editions[0].links[0].format", expectErr: "must be one of: print, ebook, online"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := valid
+			e.Links = append([]ds.BookEditionLink(nil), valid.Links...)
+			c.edit(&e)
+
+			err := service.ValidateCreate(newBook(e))
+			checkValidatedInput(t, c.argName == "", err, c.argName, c.expectErr)
+		})
+	}
+}
+
+func TestBookEditionsData(t *testing.T) {
+	t.Parallel()
+
+	stored := &ds.Book{Entity: &ds.Entity{}, Editions: []ds.BookEdition{{
+		ID:       ds.NewID(),
+		BookID:   ds.NewID(),
+		Position: 0,
+		Number:   1,
+		Links:    []ds.BookEditionLink{{Format: ds.BookFormatEbook, URL: "https://example.com"}},
+	}}}
+	submitted := &ds.Book{Entity: &ds.Entity{}, Editions: []ds.BookEdition{{
+		Number: 1,
+		Links:  []ds.BookEditionLink{{Format: ds.BookFormatEbook, URL: "https://example.com"}},
+	}}}
+
+	assert.Equal(t, stored.Data()["editions"], submitted.Data()["editions"],
+		"database identity must not make a diff")
+	assert.Equal(t, []ds.BookEdition{}, new(ds.Book).EditionsData())
+}
+
+func TestBookEditionTitle(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1st edition", (&ds.BookEdition{Number: 1}).Title())
+	assert.Equal(t, "2nd edition (Go 1.22)", (&ds.BookEdition{Number: 2, GoVersion: "1.22"}).Title())
+	assert.Equal(t, "3rd edition", (&ds.BookEdition{Number: 3}).Title())
+	assert.Equal(t, "11th edition", (&ds.BookEdition{Number: 11}).Title())
+	assert.Equal(t, "21st edition", (&ds.BookEdition{Number: 21}).Title())
+}
@@ -0,0 +1,111 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBookImportCSV(t *testing.T) {
+	t.Parallel()
+
+	src := "\ufeffTitle,authors,topics,release_date,cover\n" +
+		`The Go Programming Language,"Alan A. A. Donovan <https://example.com/donovan>; Brian W. Kernighan",go; Books ,2015,covers/gopl.png` + "\n" +
+		"Learning Go,,,,\n"
+
+	rows, err := service.ParseBookImport(strings.NewReader(src), service.BookImportCSV)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []ds.BookImportRow{
+		{
+			Title: "The Go Programming Language",
+			Authors: []ds.BookAuthor{
+				{Name: "Alan A. A. Donovan", Link: "https://example.com/donovan"},
+				{Name: "Brian W. Kernighan"},
+			},
+			Topics:      []string{"go", "Books"},
+			ReleaseDate: "2015",
+			Cover:       "covers/gopl.png",
+		},
+		{
+			Title:   "Learning Go",
+			Authors: []ds.BookAuthor{},
+			Topics:  []string{},
+		},
+	}, rows)
+}
+
+func TestParseBookImportErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		format  string
+		src     string
+		wantErr error
+	}{
+		{
+			name:    "unknown format",
+			format:  "xml",
+			src:     "<books/>",
+			wantErr: service.ErrUnknownBookImportFormat,
+		},
+		{
+			name:    "unknown column",
+			format:  service.BookImportCSV,
+			src:     "title,publisher\nLearning Go,O'Reilly Media\n",
+			wantErr: service.ErrUnknownBookImportColumn,
+		},
+		{
+			name:    "no title column",
+			format:  service.BookImportCSV,
+			src:     "authors\nJon Bodner\n",
+			wantErr: service.ErrNoBookImportTitleColumn,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := service.ParseBookImport(strings.NewReader(c.src), c.format)
+			assert.ErrorIs(t, err, c.wantErr)
+		})
+	}
+}
+
+func TestParseBookImportJSON(t *testing.T) {
+	t.Parallel()
+
+	rows, err := service.ParseBookImport(strings.NewReader(
+		`[{"title": "Learning Go", "authors": [{"name": "Jon Bodner"}], "topics": ["go"]}]`), service.BookImportJSON)
+	if assert.NoError(t, err) && assert.Len(t, rows, 1) {
+		assert.Equal(t, "Learning Go", rows[0].Title)
+		assert.Equal(t, []ds.BookAuthor{{Name: "Jon Bodner"}}, rows[0].Authors)
+		assert.Equal(t, []string{"go"}, rows[0].Topics)
+	}
+
+	_, err = service.ParseBookImport(strings.NewReader(`[{"title": "Learning Go", "publisher": "O'Reilly Media"}]`),
+		service.BookImportJSON)
+	assert.Error(t, err)
+}
+
+func TestParseBookImportISBNList(t *testing.T) {
+	t.Parallel()
+
+	rows, err := service.ParseBookImport(strings.NewReader("# Go books\n978-0-13-419044-0\n\n 1492077216 \n"),
+		service.BookImportISBNList)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []ds.BookImportRow{{ISBN: "978-0-13-419044-0"}, {ISBN: "1492077216"}}, rows)
+	}
+
+	rows, err = service.ParseBookImport(strings.NewReader("isbn,topics\n9780134190440,go\n"), service.BookImportCSV)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []ds.BookImportRow{{ISBN: "9780134190440", Topics: []string{"go"}}}, rows)
+	}
+}
@@ -0,0 +1,104 @@
+package validation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/bookmeta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"2015":             "2015",
+		"Oct 2015":         "October 2015",
+		"2015-10":          "October 2015",
+		"Oct 26, 2015":     "October 26, 2015",
+		"October 26, 2015": "October 26, 2015",
+		"26 October 2015":  "October 26, 2015",
+		"2015-10-26":       "October 26, 2015",
+		"circa 2015":       "",
+	}
+
+	for in, want := range cases {
+		assert.Equal(t, want, bookmeta.NormalizeReleaseDate(in), in)
+	}
+}
+
+func TestOpenLibraryProvider(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/books", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bibkeys") != "ISBN:0134190440" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"ISBN:0134190440": {
+			"key": "/books/OL1M",
+			"title": "The Go Programming Language",
+			"authors": [{"url": "https://openlibrary.org/authors/OL1A", "name": "Alan A. A. Donovan"},
+				{"url": "https://openlibrary.org/authors/OL2A", "name": "Brian W. Kernighan"}],
+			"publishers": [{"name": "Addison-Wesley"}],
+			"publish_date": "Oct 26, 2015",
+			"number_of_pages": 380,
+			"identifiers": {"isbn_13": ["978-0-13-419044-0"]},
+			"cover": {"small": "https://covers.example.com/1-S.jpg", "large": "https://covers.example.com/1-L.jpg"}
+		}}`))
+	})
+	mux.HandleFunc("GET /books/OL1M.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"works": [{"key": "/works/OL1W"}]}`))
+	})
+	mux.HandleFunc("GET /works/OL1W.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"description": {"type": "/type/text", "value": "The authoritative resource."}}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	p := bookmeta.NewOpenLibraryProvider(srv.URL, srv.Client())
+
+	m, err := p.Lookup(context.Background(), "0134190440")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &bookmeta.Metadata{
+			ISBN10:      "0134190440",
+			ISBN13:      "9780134190440",
+			Title:       "The Go Programming Language",
+			Authors:     []ds.BookAuthor{{Name: "Alan A. A. Donovan"}, {Name: "Brian W. Kernighan"}},
+			Publisher:   "Addison-Wesley",
+			Description: "The authoritative resource.",
+			ReleaseDate: "October 26, 2015",
+			PageCount:   380,
+			CoverURL:    "https://covers.example.com/1-L.jpg",
+		}, m)
+	}
+
+	_, err = p.Lookup(context.Background(), "9781492077213")
+	assert.ErrorIs(t, err, bookmeta.ErrNotFound)
+}
+
+func TestFixtureProvider(t *testing.T) {
+	t.Parallel()
+
+	p, err := bookmeta.NewFixtureProvider("")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	m, err := p.Lookup(context.Background(), "1492077216")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Learning Go", m.Title)
+		assert.Equal(t, "1492077216", m.ISBN10)
+		assert.Equal(t, "9781492077213", m.ISBN13)
+		assert.Equal(t, "March 2021", m.ReleaseDate)
+	}
+
+	_, err = p.Lookup(context.Background(), "9780134190441")
+	assert.ErrorIs(t, err, bookmeta.ErrNotFound)
+}
@@ -0,0 +1,96 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBookReviewInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		valid     bool
+		expectErr string
+		argName   string
+		data      service.BookReviewInput
+	}{
+		{
+			name:      "no rating",
+			expectErr: "Rating is required",
+			argName:   "rating",
+			data:      service.BookReviewInput{Body: "Great"},
+		},
+		{
+			name:      "rating too high",
+			expectErr: "Rating must be from 1 to 5",
+			argName:   "rating",
+			data:      service.BookReviewInput{Rating: 6, Body: "Great"},
+		},
+		{
+			name:      "negative rating",
+			expectErr: "Rating must be from 1 to 5",
+			argName:   "rating",
+			data:      service.BookReviewInput{Rating: -1, Body: "Great"},
+		},
+		{
+			name:      "blank body",
+			expectErr: "Review is required",
+			argName:   "body",
+			data:      service.BookReviewInput{Rating: 4, Body: "  \n "},
+		},
+		{
+			name:      "body too long",
+			expectErr: "Review must be at most 5000 characters",
+			argName:   "body",
+			data:      service.BookReviewInput{Rating: 4, Body: strings.Repeat("a", 5001)},
+		},
+		{
+			valid: true,
+			name:  "valid input",
+			data:  service.BookReviewInput{Rating: 5, Body: "**A must read** for every gopher."},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := service.Normalize(&c.data)
+			checkValidatedInput(t, c.valid, err, c.argName, c.expectErr)
+		})
+	}
+}
+
+func TestNewBookRating(t *testing.T) {
+	t.Parallel()
+
+	r := ds.NewBookRating(map[int]int{5: 2, 4: 1, 1: 1, 0: 3, 6: 1})
+
+	assert.Equal(t, 4, r.Count)
+	assert.InDelta(t, 3.75, r.Average, 0.001)
+	assert.Equal(t, [5]int{1, 0, 0, 1, 2}, r.Distribution)
+	assert.Equal(t, 50, r.Percent(5))
+	assert.Equal(t, 25, r.Percent(1))
+	assert.Equal(t, 0, r.Percent(2))
+	assert.Equal(t, 0, r.Percent(6))
+
+	empty := ds.NewBookRating(nil)
+	assert.Zero(t, empty.Average)
+	assert.Zero(t, empty.Percent(5))
+}
+
+func TestBookReviewAuthor(t *testing.T) {
+	t.Parallel()
+
+	r := ds.BookReview{UserID: new(ds.NewID()), Username: "gopher"}
+	assert.Equal(t, "gopher", r.Author())
+
+	r.UserID = nil
+	assert.True(t, r.Anonymous())
+	assert.Equal(t, "Anonymous", r.Author())
+}
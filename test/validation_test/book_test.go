@@ -0,0 +1,59 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleSortKey(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"The Go Programming Language": "go programming language",
+		"  A Tour of Go":              "tour of go",
+		"An Introduction to Go":       "introduction to go",
+		"Anatomy of Go":               "anatomy of go",
+		"Theory of Go":                "theory of go",
+		"Go in Action":                "go in action",
+		"The":                         "the",
+	}
+
+	for title, key := range cases {
+		assert.Equal(t, key, ds.TitleSortKey(title), title)
+	}
+}
+
+func TestValidateBookSeries(t *testing.T) {
+	t.Parallel()
+
+	newBook := func(series string, position int) *ds.Book {
+		return &ds.Book{
+			Entity:         &ds.Entity{Title: "The Go Programming Language"},
+			DescriptionRaw: "The Go Programming Language",
+			Description:    "<p>The Go Programming Language</p>",
+			ReleaseDate:    "2015",
+			Authors:        []ds.BookAuthor{{Name: "Alan A. A. Donovan"}},
+			Series:         series,
+			SeriesPosition: position,
+		}
+	}
+
+	checkValidatedInput(t, true, service.ValidateCreate(newBook("", 0)), "", "")
+	checkValidatedInput(t, true, service.ValidateCreate(newBook("Go Series", 2)), "", "")
+	checkValidatedInput(t, false, service.ValidateCreate(newBook("", 2)), "series_position", "series is required")
+	checkValidatedInput(t, false, service.ValidateCreate(newBook("Go Series", -1)), "series_position", "must not be negative")
+}
+
+func TestBookSortValid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range ds.BookSorts {
+		assert.True(t, s.Valid(), s)
+	}
+
+	assert.False(t, ds.BookSort("").Valid())
+	assert.False(t, ds.BookSort("price").Valid())
+}
@@ -0,0 +1,55 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+)
+
+func TestValidateChangeUsernameInput(t *testing.T) {
+	t.Parallel()
+
+	id := ds.NewID()
+
+	cases := []struct {
+		name      string
+		valid     bool
+		expectErr string
+		argName   string
+		data      service.ChangeUsernameInput
+	}{
+		{
+			name:      "empty username",
+			expectErr: "Username is required",
+			argName:   "new_username",
+			data:      service.ChangeUsernameInput{id, " ", "password"},
+		},
+		{
+			name:      "reserved username",
+			expectErr: "Username is reserved",
+			argName:   "new_username",
+			data:      service.ChangeUsernameInput{id, "sign-in", "password"},
+		},
+		{
+			name:      "reserved username in other case",
+			expectErr: "Username is reserved",
+			argName:   "new_username",
+			data:      service.ChangeUsernameInput{id, "Data-Export", "password"},
+		},
+		{
+			valid: true,
+			name:  "valid input",
+			data:  service.ChangeUsernameInput{id, "signed-in", "password"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := service.Normalize(&c.data)
+			checkValidatedInput(t, c.valid, err, c.argName, c.expectErr)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package validation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopl-dev/server/server/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		header string
+		values []string
+		ip     string
+	}{
+		{name: "no header configured", values: []string{"10.0.0.1"}, ip: "192.0.2.1"},
+		{name: "header missing", header: "X-Real-IP", ip: "192.0.2.1"},
+		{name: "single value", header: "X-Real-IP", values: []string{"203.0.113.7"}, ip: "203.0.113.7"},
+		{
+			name:   "spoofed X-Forwarded-For",
+			header: "X-Forwarded-For",
+			values: []string{"1.2.3.4, 5.6.7.8, 203.0.113.7"},
+			ip:     "203.0.113.7",
+		},
+		{
+			name:   "spoofed X-Forwarded-For in a separate header",
+			header: "X-Forwarded-For",
+			values: []string{"1.2.3.4", "203.0.113.7"},
+			ip:     "203.0.113.7",
+		},
+		{name: "not an IP", header: "X-Real-IP", values: []string{"unknown"}, ip: "192.0.2.1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			for _, v := range c.values {
+				r.Header.Add("X-Forwarded-For", v)
+				r.Header.Add("X-Real-IP", v)
+			}
+
+			assert.Equal(t, c.ip, middleware.ClientIP(r, c.header))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package validation_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig() *app.ConfigT {
+	c := new(app.ConfigT)
+	c.App.Env = app.DevEnv
+	c.Server.Port = "8080"
+	c.Server.Addr = "http://localhost:8080"
+	c.DB.Host = "localhost"
+	c.DB.Port = "5432"
+	c.DB.User = "postgres"
+	c.DB.Name = "gopl"
+	c.Files.StorageDriver = "in-memory-fs"
+	c.Email.Driver = "test"
+	c.Session.Key = "key"
+	c.Session.DurationHours = 24
+
+	return c
+}
+
+func TestConfigApplyEnv(t *testing.T) {
+	t.Parallel()
+
+	secret := filepath.Join(t.TempDir(), "db_password")
+	err := os.WriteFile(secret, []byte("from-file\n"), 0o600)
+	assert.NoError(t, err)
+
+	c := validConfig()
+	err = app.ApplyEnv(c, []string{
+		"GOPL_DB_HOST=db.internal",
+		"GOPL_DB_PASSWORD_FILE=" + secret,
+		"GOPL_DB_LOG_QUERIES=true",
+		"GOPL_SESSION_DURATION_HOURS=48",
+		"GOPL_ADMINS=a, b",
+		"GOPL_OAUTH_GITHUB_CLIENT_ID=id",
+		"GOPL_OAUTH_GITHUB_CLIENT_SECRET=secret",
+		"OTHER_DB_HOST=ignored",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "db.internal", c.DB.Host)
+	assert.Equal(t, "from-file", c.DB.Password)
+	assert.True(t, c.DB.LogQueries)
+	assert.Equal(t, 48, c.Session.DurationHours)
+	assert.Equal(t, []string{"a", "b"}, c.Admins)
+	assert.Equal(t, "id", c.OAuth["github"].ClientID)
+	assert.Equal(t, "secret", c.OAuth["github"].ClientSecret)
+}
+
+func TestConfigApplyEnvInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	c := validConfig()
+	err := app.ApplyEnv(c, []string{
+		"GOPL_DB_LOG_QUERIES=maybe",
+		"GOPL_SESSION_DURATION_HOURS=day",
+		"GOPL_SESSION_KEY_FILE=/not/exists",
+	})
+
+	// all invalid variables are reported at once
+	assert.ErrorIs(t, err, app.ErrInvalidEnvValue)
+	assert.ErrorContains(t, err, "GOPL_DB_LOG_QUERIES")
+	assert.ErrorContains(t, err, "GOPL_SESSION_DURATION_HOURS")
+	assert.ErrorContains(t, err, "GOPL_SESSION_KEY_FILE")
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validConfig().Validate())
+
+	c := validConfig()
+	c.App.Env = "release"
+	c.DB.Host = ""
+	c.Server.Addr = "localhost"
+	c.Email.Driver = "smtp"
+	c.Log.Level = "verbose"
+
+	err := c.Validate()
+	assert.ErrorContains(t, err, "app.env")
+	assert.ErrorContains(t, err, "db.host: required")
+	assert.ErrorContains(t, err, "server.addr")
+	assert.ErrorContains(t, err, "email.smtp.host: required")
+	assert.ErrorContains(t, err, "log")
+}
+
+func TestConfigApplyDeprecated(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`
+oauth:
+  github:
+    client_id: new-id
+    client_secret: new-secret
+google_oauth:
+  client_id: google-id
+  client_secret: google-secret
+github_oauth:
+  client_id: old-id
+  client_secret: old-secret
+`), 0o600)
+	assert.NoError(t, err)
+
+	c, err := app.ConfigFromFile(file)
+	assert.NoError(t, err)
+
+	warnings := c.ApplyDeprecated()
+	if assert.Len(t, warnings, 2) {
+		assert.Contains(t, warnings[0], "google_oauth")
+		assert.Contains(t, warnings[1], "github_oauth")
+	}
+
+	assert.Equal(t, "google-id", c.OAuth["google"].ClientID)
+	assert.Equal(t, "google-secret", c.OAuth["google"].ClientSecret)
+	// the new key wins
+	assert.Equal(t, "new-id", c.OAuth["github"].ClientID)
+
+	assert.Empty(t, validConfig().ApplyDeprecated())
+}
+
+func TestConfigRedacted(t *testing.T) {
+	t.Parallel()
+
+	c := validConfig()
+	c.DB.Password = "db-password"
+	c.OAuth = map[string]app.OAuthProviderConfig{
+		"github": {ClientID: "id", ClientSecret: "secret"},
+	}
+
+	r := c.Redacted()
+	assert.Equal(t, app.RedactedValue, r.DB.Password)
+	assert.Equal(t, app.RedactedValue, r.Session.Key)
+	assert.Equal(t, app.RedactedValue, r.OAuth["github"].ClientSecret)
+	assert.Equal(t, "id", r.OAuth["github"].ClientID)
+
+	// the original is not changed
+	assert.Equal(t, "db-password", c.DB.Password)
+	assert.Equal(t, "secret", c.OAuth["github"].ClientSecret)
+}
@@ -37,6 +37,17 @@ func TestCreateOAuthUserAccount(t *testing.T) {
 				ProviderUserID: "123",
 			}},
 		},
+		{
+			// providers are enabled only by config, none is registered here
+			name:      "provider not enabled",
+			expectErr: "Invalid provider type",
+			argName:   "provider",
+			data: service.CreateOAuthUserAccountInput{&ds.OAuthUserAccount{
+				UserID:         ds.NewID(),
+				Provider:       provider.Bitbucket,
+				ProviderUserID: "123",
+			}},
+		},
 		{
 			name:      "empty provider user id",
 			expectErr: "provider_user_id is required",
@@ -0,0 +1,138 @@
+package validation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalLinkURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{in: " https://Go.dev/doc/#install ", out: "https://go.dev/doc/", ok: true},
+		{in: "HTTP://example.com:8080/a?b=c", out: "http://example.com:8080/a?b=c", ok: true},
+		{in: "mailto:gopher@example.com"},
+		{in: "/books/"},
+		{in: "#intro"},
+		{in: "ftp://example.com/file"},
+		{in: "https://"},
+	}
+
+	for _, c := range cases {
+		out, ok := ds.ExternalLinkURL(c.in)
+		assert.Equal(t, c.ok, ok, c.in)
+		assert.Equal(t, c.out, out, c.in)
+	}
+}
+
+func TestExternalLinkHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	port := srv.URL[strings.LastIndex(srv.URL, ":")+1:]
+	urls := []string{
+		srv.URL,
+		// the address is checked after DNS resolution
+		"http://localhost:" + port,
+		"http://[::1]:" + port,
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://[::ffff:192.168.0.1]/",
+	}
+
+	for _, u := range urls {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, u, nil)
+		assert.NoError(t, err)
+
+		resp, err := service.ExternalLinkHTTPClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		assert.ErrorIs(t, err, service.ErrExternalLinkAddressNotAllowed, u)
+	}
+}
+
+func TestMarkdownLinks(t *testing.T) {
+	t.Parallel()
+
+	md := `# Links
+
+See [the spec](https://go.dev/ref/spec "Spec"), <https://pkg.go.dev/> and https://go.dev/blog/.
+Relative [books](/books/) are links too.
+
+![gopher](https://go.dev/images/gophers/ladder.svg)`
+
+	assert.Equal(t, []string{
+		"https://go.dev/ref/spec",
+		"https://pkg.go.dev/",
+		"https://go.dev/blog/",
+		"/books/",
+	}, app.MarkdownLinks(md))
+}
+
+func TestParseRobots(t *testing.T) {
+	t.Parallel()
+
+	const robots = `# robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/open
+Disallow: /*.pdf$
+
+User-agent: other-bot
+User-agent: gopl.dev-linkchecker
+Disallow: /no-checker
+Crawl-delay: 2.5
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+	t.Run("any agent", func(t *testing.T) {
+		t.Parallel()
+
+		r := ds.ParseRobots(robots, "some-bot")
+		assert.True(t, r.Allowed("/"))
+		assert.True(t, r.Allowed("/no-checker"))
+		assert.False(t, r.Allowed("/private"))
+		assert.False(t, r.Allowed("/private/docs"))
+		// the longest rule wins
+		assert.True(t, r.Allowed("/private/open/docs"))
+		assert.False(t, r.Allowed("/books/gopl.pdf"))
+		assert.True(t, r.Allowed("/books/gopl.pdf?page=2"))
+		assert.Zero(t, r.CrawlDelay)
+	})
+
+	t.Run("own group", func(t *testing.T) {
+		t.Parallel()
+
+		r := ds.ParseRobots(robots, "gopl.dev-linkchecker")
+		assert.False(t, r.Allowed("/no-checker"))
+		// the rules of "*" don't apply once there is a group of the agent
+		assert.True(t, r.Allowed("/private"))
+		assert.Equal(t, 2500*time.Millisecond, r.CrawlDelay)
+	})
+
+	t.Run("nothing disallowed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, ds.ParseRobots("User-agent: *\nDisallow:\n", "bot").Allowed("/private"))
+		assert.True(t, ds.ParseRobots("", "bot").Allowed("/private"))
+
+		var none *ds.RobotsRules
+		assert.True(t, none.Allowed("/private"))
+	})
+}
@@ -0,0 +1,41 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCachedCheck(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	errBroken := errors.New("broken")
+	check := health.Cached(health.Check{Name: "storage", Fn: func(context.Context) error {
+		calls++
+		if calls > 1 {
+			return errBroken
+		}
+
+		return nil
+	}}, 100*time.Millisecond)
+
+	assert.Equal(t, "storage", check.Name)
+
+	for range 3 {
+		report := health.Run(context.Background(), check)
+		assert.True(t, report.OK())
+	}
+	assert.Equal(t, 1, calls)
+
+	time.Sleep(150 * time.Millisecond)
+
+	report := health.Run(context.Background(), check)
+	assert.False(t, report.OK())
+	assert.Equal(t, errBroken.Error(), report.Checks[0].Error)
+	assert.Equal(t, 2, calls)
+}
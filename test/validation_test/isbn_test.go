@@ -0,0 +1,85 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "9780134190440", ds.NormalizeISBN("978-0-13-419044-0"))
+	assert.Equal(t, "9780134190440", ds.NormalizeISBN(" ISBN-13: 978 0 13 419044 0 "))
+	assert.Equal(t, "080442957X", ds.NormalizeISBN("0-8044-2957-x"))
+}
+
+func TestValidISBN(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		isbn   string
+		valid  bool
+		isbn10 bool
+	}{
+		{isbn: "0134190440", valid: true, isbn10: true},
+		{isbn: "080442957X", valid: true, isbn10: true},
+		{isbn: "0134190441", isbn10: true},
+		{isbn: "X134190440", isbn10: true},
+		{isbn: "013419044", isbn10: true},
+		{isbn: "9780134190440", valid: true},
+		{isbn: "9791032305690", valid: true},
+		{isbn: "9780134190441"},
+		{isbn: "978013419044X"},
+		{isbn: "97801341904400"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.isbn, func(t *testing.T) {
+			t.Parallel()
+
+			if c.isbn10 {
+				assert.Equal(t, c.valid, ds.ValidISBN10(c.isbn))
+			} else {
+				assert.Equal(t, c.valid, ds.ValidISBN13(c.isbn))
+			}
+
+			assert.Equal(t, c.valid, ds.ValidISBN(c.isbn))
+		})
+	}
+}
+
+func TestConvertISBN(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "9780134190440", ds.ISBN10To13("0134190440"))
+	assert.Equal(t, "9780804429573", ds.ISBN10To13("080442957X"))
+	assert.Equal(t, "0134190440", ds.ISBN13To10("9780134190440"))
+	assert.Equal(t, "080442957X", ds.ISBN13To10("9780804429573"))
+
+	assert.Empty(t, ds.ISBN10To13("0134190441"))
+	assert.Empty(t, ds.ISBN13To10("9791032305690"), "979 prefix has no ISBN-10")
+}
+
+func TestValidateBookISBN(t *testing.T) {
+	t.Parallel()
+
+	newBook := func(isbn10, isbn13 string) *ds.Book {
+		return &ds.Book{
+			Entity:         &ds.Entity{Title: "The Go Programming Language"},
+			DescriptionRaw: "The Go Programming Language",
+			Description:    "<p>The Go Programming Language</p>",
+			ReleaseDate:    "2015",
+			Authors:        []ds.BookAuthor{{Name: "Alan A. A. Donovan"}},
+			ISBN10:         isbn10,
+			ISBN13:         isbn13,
+		}
+	}
+
+	checkValidatedInput(t, true, service.ValidateCreate(newBook("", "")), "", "")
+	checkValidatedInput(t, true, service.ValidateCreate(newBook("0134190440", "9780134190440")), "", "")
+	checkValidatedInput(t, false, service.ValidateCreate(newBook("0134190441", "")), "isbn10", "invalid ISBN-10")
+	checkValidatedInput(t, false, service.ValidateCreate(newBook("", "9780134190441")), "isbn13", "invalid ISBN-13")
+}
@@ -0,0 +1,164 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/gopl-dev/server/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		src      string
+		wantUp   string
+		wantDown string
+	}{
+		{
+			name:   "up only",
+			src:    "CREATE TABLE t (id INT);\n",
+			wantUp: "CREATE TABLE t (id INT);",
+		},
+		{
+			name:     "up and down",
+			src:      "CREATE TABLE t (id INT);\n\n-- +down\nDROP TABLE t;\n",
+			wantUp:   "CREATE TABLE t (id INT);",
+			wantDown: "DROP TABLE t;",
+		},
+		{
+			name:   "commented down",
+			src:    "CREATE TABLE t (id INT);\n-- +down\n-- DROP TABLE t;\n",
+			wantUp: "CREATE TABLE t (id INT);",
+		},
+		{
+			name:   "marker must be on its own line",
+			src:    "CREATE TABLE t (id INT); -- +down\n",
+			wantUp: "CREATE TABLE t (id INT); -- +down",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			up, down := app.ParseMigration(c.src)
+			assert.Equal(t, c.wantUp, up)
+			assert.Equal(t, c.wantDown, down)
+		})
+	}
+}
+
+func TestMigrationChecksumIgnoresDown(t *testing.T) {
+	t.Parallel()
+
+	up1, _ := app.ParseMigration("CREATE TABLE t (id INT);\n")
+	up2, _ := app.ParseMigration("CREATE TABLE t (id INT);\n-- +down\nDROP TABLE t;\n")
+	up3, _ := app.ParseMigration("CREATE TABLE t (id BIGINT);\n")
+
+	assert.Equal(t, app.MigrationChecksum(up1), app.MigrationChecksum(up2))
+	assert.NotEqual(t, app.MigrationChecksum(up1), app.MigrationChecksum(up3))
+}
+
+func TestLintMigrationFile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		src       string
+		wantRules []string
+	}{
+		{
+			name: "new table",
+			src: "CREATE TABLE t (id INT);\nCREATE INDEX t_id_idx ON t (id);\n" +
+				"ALTER TABLE t ALTER COLUMN id SET NOT NULL;\n",
+		},
+		{
+			name:      "index on existing table",
+			src:       "CREATE INDEX books_title_idx ON books (title);\n",
+			wantRules: []string{app.LintIndexNotConcurrent},
+		},
+		{
+			name:      "concurrent index in transaction",
+			src:       "CREATE INDEX CONCURRENTLY books_title_idx ON books (title);\n",
+			wantRules: []string{app.LintConcurrentlyInTx},
+		},
+		{
+			name: "concurrent index without transaction",
+			src:  app.MigrationNoTxDirective + "\nCREATE INDEX CONCURRENTLY IF NOT EXISTS books_title_idx ON books (title);\n",
+		},
+		{
+			name:      "set not null",
+			src:       "ALTER TABLE books ALTER COLUMN title SET NOT NULL;\n",
+			wantRules: []string{app.LintSetNotNull},
+		},
+		{
+			name:      "column type",
+			src:       "ALTER TABLE books ALTER COLUMN title TYPE VARCHAR(100);\n",
+			wantRules: []string{app.LintTableRewrite},
+		},
+		{
+			name:      "volatile default",
+			src:       "ALTER TABLE books ADD COLUMN token UUID DEFAULT gen_random_uuid();\n",
+			wantRules: []string{app.LintTableRewrite},
+		},
+		{
+			name: "constant default",
+			src:  "ALTER TABLE books ADD COLUMN hidden BOOL NOT NULL DEFAULT FALSE;\n",
+		},
+		{
+			name:      "foreign key",
+			src:       "ALTER TABLE books ADD CONSTRAINT books_owner_fk FOREIGN KEY (owner_id) REFERENCES users (id);\n",
+			wantRules: []string{app.LintConstraintValidation},
+		},
+		{
+			name: "foreign key not valid",
+			src:  "ALTER TABLE books ADD CONSTRAINT books_owner_fk FOREIGN KEY (owner_id) REFERENCES users (id) NOT VALID;\n",
+		},
+		{
+			name: "backfill with ddl",
+			src: "ALTER TABLE books ADD COLUMN sort_title TEXT;\n" +
+				"DO $$ BEGIN UPDATE books SET sort_title = lower(title); END $$;\n",
+			wantRules: []string{app.LintBackfillInTx},
+		},
+		{
+			name: "comments and strings are ignored",
+			src:  "-- CREATE INDEX books_title_idx ON books (title);\nSELECT 'ALTER TABLE books ALTER COLUMN title SET NOT NULL;';\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues, err := app.LintMigrationFile("29990101000000_test.sql", c.src)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			rules := make([]string, 0, len(issues))
+			for _, i := range issues {
+				rules = append(rules, i.Rule)
+			}
+
+			if len(c.wantRules) == 0 {
+				assert.Empty(t, rules)
+			} else {
+				assert.Equal(t, c.wantRules, rules)
+			}
+		})
+	}
+}
+
+func TestLintSetNotNullRequiresEarlierCheck(t *testing.T) {
+	t.Parallel()
+
+	// the check constraint added in the same transaction doesn't help, the table stays locked anyway
+	issues, err := app.LintMigrationFile("29990101000000_test.sql",
+		"ALTER TABLE books ADD CONSTRAINT books_title_nn CHECK (title IS NOT NULL) NOT VALID;\n"+
+			"ALTER TABLE books ALTER COLUMN title SET NOT NULL;\n")
+	if assert.NoError(t, err) && assert.Len(t, issues, 1) {
+		assert.Equal(t, app.LintSetNotNull, issues[0].Rule)
+	}
+}
@@ -50,6 +50,12 @@ func TestValidateRegisterUserInput(t *testing.T) {
 			argName:   "username",
 			data:      service.RegisterUserInput{"a.a.a.a", validEmail, validPassword},
 		},
+		{
+			name:      "reserved username",
+			expectErr: "Username is reserved",
+			argName:   "username",
+			data:      service.RegisterUserInput{"Settings", validEmail, validPassword},
+		},
 		{
 			name:      "invalid email",
 			expectErr: "must be a valid email",
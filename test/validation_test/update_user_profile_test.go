@@ -0,0 +1,83 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+)
+
+func TestValidateUpdateUserProfileInput(t *testing.T) {
+	t.Parallel()
+
+	id := ds.NewID()
+	link := ds.UserLink{Title: "Blog", URL: "https://example.com"}
+
+	cases := []struct {
+		name      string
+		valid     bool
+		expectErr string
+		argName   string
+		data      service.UpdateUserProfileInput
+	}{
+		{
+			name:      "invalid ID",
+			expectErr: "Invalid UUID",
+			argName:   "user_id",
+			data:      service.UpdateUserProfileInput{UserID: ds.NilID},
+		},
+		{
+			name:      "bio too long",
+			expectErr: "Bio must be at most 500 characters",
+			argName:   "bio",
+			data:      service.UpdateUserProfileInput{UserID: id, Bio: strings.Repeat("a", service.UserBioMaxLen+1)},
+		},
+		{
+			name:      "too many links",
+			expectErr: "At most 5 links are allowed",
+			argName:   "links",
+			data: service.UpdateUserProfileInput{UserID: id, Links: []ds.UserLink{
+				link, link, link, link, link, link,
+			}},
+		},
+		{
+			name:      "link without title",
+			expectErr: "Title is required",
+			argName:   "links[0].title",
+			data:      service.UpdateUserProfileInput{UserID: id, Links: []ds.UserLink{{URL: link.URL}}},
+		},
+		{
+			name:      "link with non-http URL",
+			expectErr: "URL must start with http:// or https://",
+			argName:   "links[0].url",
+			data: service.UpdateUserProfileInput{UserID: id, Links: []ds.UserLink{
+				{Title: "x", URL: "javascript://example.com/%0Aalert(1)"},
+			}},
+		},
+		{
+			valid: true,
+			name:  "empty links are dropped",
+			data:  service.UpdateUserProfileInput{UserID: id, Links: []ds.UserLink{{Title: " ", URL: " "}}},
+		},
+		{
+			valid: true,
+			name:  "valid input",
+			data: service.UpdateUserProfileInput{
+				UserID:    id,
+				Bio:       "Gopher",
+				Links:     []ds.UserLink{link},
+				ShowBooks: true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := service.Normalize(&c.data)
+			checkValidatedInput(t, c.valid, err, c.argName, c.expectErr)
+		})
+	}
+}
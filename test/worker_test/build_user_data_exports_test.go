@@ -0,0 +1,117 @@
+package worker_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/email"
+	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	builduserdataexports "github.com/gopl-dev/server/worker/build_user_data_exports"
+)
+
+func TestBuildUserDataExports(t *testing.T) {
+	ctx := context.Background()
+	user := create[ds.User](t)
+	create(t, ds.UserSession{UserID: user.ID})
+
+	content := []byte(random.String())
+	filePath, err := file.Store(ctx, bytes.NewReader(content), random.String())
+	test.CheckErr(t, err)
+
+	f := create(t, ds.File{OwnerID: user.ID, Path: filePath, Name: "notes.txt"})
+	// the path of the factory file points to nothing
+	missing := create(t, ds.File{OwnerID: user.ID})
+	temp := create(t, ds.File{OwnerID: user.ID, Path: filePath, Temp: true})
+	exp := create(t, ds.UserDataExport{UserID: user.ID})
+
+	runJob(t, builduserdataexports.NewJob())
+
+	test.AssertInDB(t, tt.DB, "user_data_exports", test.Data{
+		"id":           exp.ID,
+		"status":       ds.UserDataExportReady,
+		"expires_at":   test.NotNull,
+		"completed_at": test.NotNull,
+	})
+
+	var path string
+	err = tt.DB.QueryRow(ctx, "SELECT path FROM user_data_exports WHERE id = $1", exp.ID).Scan(&path)
+	test.CheckErr(t, err)
+
+	archive, err := file.Load(ctx, path)
+	test.CheckErr(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	test.CheckErr(t, err)
+
+	names := make([]string, 0, len(zr.File))
+	for _, zf := range zr.File {
+		names = append(names, zf.Name)
+	}
+
+	uploadName := "files/" + f.ID.String() + "-notes.txt"
+	for _, name := range []string{
+		"account.json",
+		"sessions.json",
+		"oauth_accounts.json",
+		"files.json",
+		"entities.json",
+		"change_requests.json",
+		"event_logs.json",
+		uploadName,
+	} {
+		if !slices.Contains(names, name) {
+			t.Fatalf("archive is missing %s, got %v", name, names)
+		}
+	}
+
+	upload, err := zr.Open(uploadName)
+	test.CheckErr(t, err)
+
+	uploadContent, err := io.ReadAll(upload)
+	test.CheckErr(t, err)
+
+	if !bytes.Equal(uploadContent, content) {
+		t.Fatalf("uploaded file content mismatch")
+	}
+
+	filesJSON, err := zr.Open("files.json")
+	test.CheckErr(t, err)
+
+	var files []struct {
+		ID          ds.ID  `json:"id"`
+		ArchivePath string `json:"archive_path"`
+		Error       string `json:"error"`
+	}
+	err = json.NewDecoder(filesJSON).Decode(&files)
+	test.CheckErr(t, err)
+
+	byID := make(map[ds.ID]int, len(files))
+	for i, ef := range files {
+		byID[ef.ID] = i
+	}
+
+	if i, ok := byID[f.ID]; !ok || files[i].ArchivePath != uploadName || files[i].Error != "" {
+		t.Fatalf("expected %s in files.json with archive path %s, got %+v", f.ID, uploadName, files)
+	}
+	if i, ok := byID[missing.ID]; !ok || files[i].ArchivePath != "" || files[i].Error == "" {
+		t.Fatalf("expected missing file %s in files.json with an error, got %+v", missing.ID, files)
+	}
+	if _, ok := byID[temp.ID]; ok {
+		t.Fatalf("temporary file %s is not expected in files.json", temp.ID)
+	}
+
+	mail, err := email.LoadTestEmail(user.Email)
+	test.CheckErr(t, err)
+
+	if mail.Variables()["token"] != exp.Token {
+		t.Fatalf("expected email with token %s", exp.Token)
+	}
+}
@@ -0,0 +1,161 @@
+package worker_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/test"
+	checkexternallinks "github.com/gopl-dev/server/worker/check_external_links"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExternalLinks(t *testing.T) {
+	var privateHits atomic.Int32
+	var userAgent atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.UserAgent())
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/gone", http.NotFound)
+	mux.HandleFunc("/private", func(_ http.ResponseWriter, _ *http.Request) {
+		privateHits.Add(1)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	// the site rate limiting us is another host, as the rest of its links are left for the next run
+	busy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", "7200")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(busy.Close)
+
+	author := create(t, ds.Author{Links: []ds.AuthorLink{{Title: "Blog", URL: srv.URL + "/gone"}}})
+	book := create(t, ds.Book{
+		Entity: &ds.Entity{
+			Status:     ds.EntityStatusApproved,
+			Visibility: ds.EntityVisibilityPublic,
+		},
+		Homepage: srv.URL + "/ok",
+		Authors:  []ds.BookAuthor{{ID: author.ID, Slug: author.Slug, Name: author.Name}},
+	})
+	// submissions are not checked until approved
+	create(t, ds.Book{
+		Entity:   &ds.Entity{Status: ds.EntityStatusUnderReview},
+		Homepage: srv.URL + "/submitted",
+	})
+	create(t, ds.Page{
+		ContentRaw: fmt.Sprintf("See [the docs](%s/no-head#intro), <%s/private> and <%s/busy>.", srv.URL, srv.URL, busy.URL),
+	})
+
+	tt.PrepareExternalLinkChecks(t, srv, busy)
+	runJob(t, checkexternallinks.NewJob())
+
+	assert.Equal(t, service.ExternalLinkUserAgent, userAgent.Load())
+	assert.Zero(t, privateHits.Load())
+	test.AssertNotInDB(t, tt.DB, "external_links", test.Data{"url": srv.URL + "/submitted"})
+
+	test.AssertInDB(t, tt.DB, "external_links", test.Data{
+		"url":         srv.URL + "/ok",
+		"status":      ds.ExternalLinkOK,
+		"status_code": http.StatusOK,
+		"fail_count":  0,
+		"checked_at":  test.NotNull,
+	})
+	test.AssertInDB(t, tt.DB, "external_link_sources", test.Data{
+		"source_type": ds.ExternalLinkSourceBook,
+		"source_id":   book.ID,
+		"field":       "homepage",
+	})
+	// HEAD is not supported, GET is
+	test.AssertInDB(t, tt.DB, "external_links", test.Data{
+		"url":    srv.URL + "/no-head",
+		"status": ds.ExternalLinkOK,
+	})
+	test.AssertInDB(t, tt.DB, "external_links", test.Data{
+		"url":         srv.URL + "/gone",
+		"status":      ds.ExternalLinkFailing,
+		"status_code": http.StatusNotFound,
+		"fail_count":  1,
+	})
+	test.AssertInDB(t, tt.DB, "external_links", test.Data{
+		"url":    srv.URL + "/private",
+		"status": ds.ExternalLinkSkipped,
+	})
+	// rate limited links keep their status
+	test.AssertInDB(t, tt.DB, "external_links", test.Data{
+		"url":    busy.URL + "/busy",
+		"status": ds.ExternalLinkPending,
+	})
+	test.AssertInDB(t, tt.DB, "external_link_checks", test.Data{
+		"link_id":     linkID(t, busy.URL+"/busy"),
+		"status":      ds.ExternalLinkRateLimited,
+		"status_code": http.StatusTooManyRequests,
+	})
+
+	t.Run("link failing again is broken", func(t *testing.T) {
+		gone := linkID(t, srv.URL+"/gone")
+		_, err := tt.DB.Exec(context.Background(), "UPDATE external_links SET next_check_at = NOW() WHERE id = $1", gone)
+		test.CheckErr(t, err)
+
+		runJob(t, checkexternallinks.NewJob())
+
+		test.AssertInDB(t, tt.DB, "external_links", test.Data{
+			"id":         gone,
+			"status":     ds.ExternalLinkBroken,
+			"fail_count": ds.ExternalLinkBrokenAfter,
+		})
+		test.AssertInDB(t, tt.DB, "external_link_checks", test.Data{
+			"link_id": gone,
+			"status":  ds.ExternalLinkBroken,
+			"method":  http.MethodGet,
+		})
+
+		broken, err := tt.Service.GetBrokenExternalLinks(context.Background(), ds.ExternalLinkSourceAuthor, author.ID)
+		test.CheckErr(t, err)
+		if assert.Len(t, broken, 1) {
+			assert.Equal(t, gone, broken[0].ID)
+		}
+	})
+
+	t.Run("link removed from its source is deleted", func(t *testing.T) {
+		gone := linkID(t, srv.URL+"/gone")
+		_, err := tt.DB.Exec(context.Background(), "UPDATE authors SET links = '[]' WHERE id = $1", author.ID)
+		test.CheckErr(t, err)
+
+		runJob(t, checkexternallinks.NewJob())
+
+		test.AssertNotInDB(t, tt.DB, "external_links", test.Data{"id": gone})
+		test.AssertNotInDB(t, tt.DB, "external_link_checks", test.Data{"link_id": gone})
+	})
+}
+
+func linkID(t *testing.T, url string) (id ds.ID) {
+	t.Helper()
+
+	err := tt.DB.QueryRow(context.Background(), "SELECT id FROM external_links WHERE url = $1", url).Scan(&id)
+	test.CheckErr(t, err)
+
+	return id
+}
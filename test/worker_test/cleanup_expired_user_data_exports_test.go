@@ -0,0 +1,28 @@
+package worker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/test"
+	cleanupexpireduserdataexports "github.com/gopl-dev/server/worker/cleanup_expired_user_data_exports"
+)
+
+func TestCleanupExpiredUserDataExports(t *testing.T) {
+	expired := create(t, ds.UserDataExport{
+		Status:    ds.UserDataExportReady,
+		ExpiresAt: new(time.Now().Add(-time.Hour)),
+	})
+	valid := create(t, ds.UserDataExport{
+		Status:    ds.UserDataExportReady,
+		ExpiresAt: new(time.Now().Add(time.Hour)),
+	})
+	pending := create[ds.UserDataExport](t)
+
+	runJob(t, cleanupexpireduserdataexports.NewJob())
+
+	test.AssertNotInDB(t, tt.DB, "user_data_exports", test.Data{"id": expired.ID})
+	test.AssertInDB(t, tt.DB, "user_data_exports", test.Data{"id": valid.ID})
+	test.AssertInDB(t, tt.DB, "user_data_exports", test.Data{"id": pending.ID})
+}
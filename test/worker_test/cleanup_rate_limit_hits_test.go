@@ -0,0 +1,28 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/ratelimit"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	cleanupratelimithits "github.com/gopl-dev/server/worker/cleanup_rate_limit_hits"
+)
+
+func TestCleanupRateLimitHits(t *testing.T) {
+	ctx := context.Background()
+	staleKey := random.String()
+	freshKey := random.String()
+
+	_, err := tt.DB.Exec(ctx, "INSERT INTO rate_limit_hits (key, hit_at) VALUES ($1, $2), ($3, $4)",
+		staleKey, time.Now().Add(-ratelimit.MaxWindow-time.Minute),
+		freshKey, time.Now())
+	test.CheckErr(t, err)
+
+	runJob(t, cleanupratelimithits.NewJob())
+
+	test.AssertNotInDB(t, tt.DB, "rate_limit_hits", test.Data{"key": staleKey})
+	test.AssertInDB(t, tt.DB, "rate_limit_hits", test.Data{"key": freshKey})
+}
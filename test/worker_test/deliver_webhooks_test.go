@@ -0,0 +1,123 @@
+package worker_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/test"
+	deliverwebhooks "github.com/gopl-dev/server/worker/deliver_webhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	var (
+		received  []byte
+		signature string
+		timestamp string
+		status    = http.StatusOK
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(service.WebhookSignatureHeader)
+		timestamp = r.Header.Get(service.WebhookTimestampHeader)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	webhook := create(t, ds.Webhook{URL: srv.URL})
+	delivery := create(t, ds.WebhookDelivery{WebhookID: webhook.ID})
+
+	runJob(t, deliverwebhooks.NewJob())
+
+	assert.JSONEq(t, string(delivery.Payload), string(received))
+	assert.Equal(t, service.WebhookSignature(webhook.Secret, timestamp, received), signature)
+	test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+		"id":              delivery.ID,
+		"status":          ds.WebhookDeliverySucceeded,
+		"attempts":        1,
+		"response_status": http.StatusOK,
+		"next_attempt_at": nil,
+		"delivered_at":    test.NotNull,
+	})
+
+	t.Run("failed attempt is rescheduled", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		delivery := create(t, ds.WebhookDelivery{WebhookID: webhook.ID})
+
+		runJob(t, deliverwebhooks.NewJob())
+
+		test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+			"id":              delivery.ID,
+			"status":          ds.WebhookDeliveryPending,
+			"attempts":        1,
+			"response_status": http.StatusInternalServerError,
+			"next_attempt_at": test.NotNull,
+			"delivered_at":    nil,
+		})
+	})
+
+	t.Run("last attempt marks delivery as failed", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		delivery := create(t, ds.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Attempts:  service.WebhookMaxAttempts - 1,
+		})
+
+		runJob(t, deliverwebhooks.NewJob())
+
+		test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+			"id":              delivery.ID,
+			"status":          ds.WebhookDeliveryFailed,
+			"attempts":        service.WebhookMaxAttempts,
+			"next_attempt_at": nil,
+		})
+	})
+}
+
+func TestConcurrentWebhookDeliveriesAreSentOnce(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.Header.Get(service.WebhookDeliveryHeader)]++
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	webhook := create(t, ds.Webhook{URL: srv.URL})
+	deliveries := make([]*ds.WebhookDelivery, 5)
+	for i := range deliveries {
+		deliveries[i] = create(t, ds.WebhookDelivery{WebhookID: webhook.ID})
+	}
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			err := tt.Service.ProcessDueWebhookDeliveries(t.Context())
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	for _, d := range deliveries {
+		assert.Equal(t, 1, received[d.ID.String()], "delivery %s", d.ID)
+		test.AssertInDB(t, tt.DB, "webhook_deliveries", test.Data{
+			"id":       d.ID,
+			"status":   ds.WebhookDeliverySucceeded,
+			"attempts": 1,
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package worker_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/file"
+	"github.com/gopl-dev/server/test"
+	generatesitemap "github.com/gopl-dev/server/worker/generate_sitemap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSitemap(t *testing.T) {
+	ctx := context.Background()
+
+	book := create(t, ds.Book{Entity: &ds.Entity{
+		Status:     ds.EntityStatusApproved,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+	draft := create(t, ds.Book{Entity: &ds.Entity{
+		Status:     ds.EntityStatusUnderReview,
+		Visibility: ds.EntityVisibilityPublic,
+	}})
+
+	runJob(t, generatesitemap.NewJob())
+
+	index, err := file.Load(ctx, path.Join(service.SitemapDir, service.SitemapIndexFile))
+	test.CheckErr(t, err)
+
+	assert.Contains(t, string(index), "<urlset")
+	assert.Contains(t, string(index), book.PublicID)
+	assert.Contains(t, string(index), book.Topics[0].PublicID)
+	assert.NotContains(t, string(index), draft.PublicID)
+
+	t.Run("split into index", func(t *testing.T) {
+		perFile := service.SitemapURLsPerFile
+		service.SitemapURLsPerFile = 2
+		t.Cleanup(func() { service.SitemapURLsPerFile = perFile })
+
+		runJob(t, generatesitemap.NewJob())
+
+		index, err := file.Load(ctx, path.Join(service.SitemapDir, service.SitemapIndexFile))
+		test.CheckErr(t, err)
+
+		assert.Contains(t, string(index), "<sitemapindex")
+		assert.Contains(t, string(index), "/sitemaps/"+service.SitemapFileName(1))
+
+		first, err := file.Load(ctx, path.Join(service.SitemapDir, service.SitemapFileName(1)))
+		test.CheckErr(t, err)
+		assert.Contains(t, string(first), "<urlset")
+
+		// back to a single file, numbered sitemaps are removed
+		service.SitemapURLsPerFile = perFile
+		runJob(t, generatesitemap.NewJob())
+
+		_, err = file.Load(ctx, path.Join(service.SitemapDir, service.SitemapFileName(1)))
+		assert.Error(t, err)
+	})
+}
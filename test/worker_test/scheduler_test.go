@@ -0,0 +1,233 @@
+package worker_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/gopl-dev/server/worker"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoSchedulersRunJobOnce(t *testing.T) {
+	ctx := context.Background()
+
+	runs := new(atomic.Int32)
+	job := testJob{
+		name:     "TEST:" + random.String(),
+		schedule: gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(time.Now().Add(time.Second))),
+		runs:     runs,
+	}
+
+	schedulers := make([]*worker.Scheduler, 2)
+	for i := range schedulers {
+		s, err := worker.NewScheduler(ctx, tt.Service, tt.DB, []worker.Job{job})
+		test.CheckErr(t, err)
+
+		schedulers[i] = s
+		s.Start()
+	}
+
+	time.Sleep(3 * time.Second)
+
+	for _, s := range schedulers {
+		test.CheckErr(t, s.Shutdown())
+	}
+
+	assert.Equal(t, int32(1), runs.Load())
+
+	var count int
+	err := tt.DB.QueryRow(ctx, "SELECT COUNT(*) FROM worker_job_runs WHERE job_name = $1", job.name).Scan(&count)
+	test.CheckErr(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestSchedulersStartedAtDifferentTimesRunJobOnce(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var starts []time.Time
+	job := testJob{
+		name:     "TEST:" + random.String(),
+		schedule: gocron.CronJob("*/2 * * * * *", true),
+		onRun: func() {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+		},
+	}
+
+	schedulers := make([]*worker.Scheduler, 2)
+	for i := range schedulers {
+		if i > 0 {
+			// the other replica is started in the middle of the interval
+			time.Sleep(time.Second)
+		}
+
+		s, err := worker.NewScheduler(ctx, tt.Service, tt.DB, []worker.Job{job})
+		test.CheckErr(t, err)
+		s.Locker.MinHold = time.Second
+
+		schedulers[i] = s
+		s.Start()
+	}
+
+	time.Sleep(5 * time.Second)
+
+	for _, s := range schedulers {
+		test.CheckErr(t, s.Shutdown())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// runs are planned at the same times by both schedulers, so each one happens once
+	assert.GreaterOrEqual(t, len(starts), 2)
+	for i := 1; i < len(starts); i++ {
+		assert.Greater(t, starts[i].Sub(starts[i-1]), 1500*time.Millisecond, "job run twice in the interval")
+	}
+}
+
+func TestSchedulerRunsMoreJobsThanPoolConnections(t *testing.T) {
+	ctx := context.Background()
+
+	conf := tt.DB.Config()
+	conf.MaxConns = 2
+	pool, err := pgxpool.NewWithConfig(ctx, conf)
+	test.CheckErr(t, err)
+	db := &app.DB{Pool: pool}
+	t.Cleanup(db.Close)
+
+	services, err := service.New(db, tt.Tracer)
+	test.CheckErr(t, err)
+
+	runs := new(atomic.Int32)
+	start := gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(time.Now().Add(time.Second)))
+	jobs := make([]worker.Job, 3*conf.MaxConns)
+	for i := range jobs {
+		jobs[i] = testJob{
+			name:     "TEST:" + random.String(),
+			schedule: start,
+			runs:     runs,
+		}
+	}
+
+	s, err := worker.NewScheduler(ctx, services, db, jobs)
+	test.CheckErr(t, err)
+	s.Start()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for runs.Load() < int32(len(jobs)) && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	test.CheckErr(t, s.Shutdown())
+	assert.Equal(t, int32(len(jobs)), runs.Load())
+}
+
+func TestRequestedRunOfLockedJobIsSkipped(t *testing.T) {
+	ctx := context.Background()
+
+	runs := new(atomic.Int32)
+	job := testJob{name: "TEST:" + random.String(), runs: runs}
+
+	s, err := worker.NewScheduler(ctx, tt.Service, tt.DB, []worker.Job{job})
+	test.CheckErr(t, err)
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	// the job is running on another replica
+	other := worker.NewLocker(tt.DB)
+	other.MinHold = 0
+	t.Cleanup(other.Close)
+
+	lock, err := other.Lock(ctx, job.name)
+	test.CheckErr(t, err)
+	t.Cleanup(func() { _ = lock.Unlock(ctx) })
+
+	err = tt.Service.RequestWorkerJobRun(ctx, job.name)
+	test.CheckErr(t, err)
+
+	s.RunRequested()
+
+	assert.Eventually(t, func() bool {
+		var count int
+		err := tt.DB.QueryRow(ctx, "SELECT COUNT(*) FROM worker_job_runs WHERE job_name = $1 AND status = $2",
+			job.name, ds.WorkerJobRunSkipped).Scan(&count)
+		return err == nil && count == 1
+	}, 5*time.Second, 100*time.Millisecond, "skipped run is not recorded")
+
+	assert.Zero(t, runs.Load())
+	test.AssertInDB(t, tt.DB, "worker_job_runs", test.Data{
+		"job_name": job.name,
+		"trigger":  ds.WorkerJobTriggerManual,
+		"status":   ds.WorkerJobRunSkipped,
+		"error":    worker.ErrLocked.Error(),
+	})
+}
+
+func TestLockerReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	key := "TEST:" + random.String()
+
+	l1 := worker.NewLocker(tt.DB)
+	l1.MinHold = 0
+	l2 := worker.NewLocker(tt.DB)
+
+	lock, err := l1.Lock(ctx, key)
+	test.CheckErr(t, err)
+
+	_, err = l2.Lock(ctx, key)
+	assert.ErrorIs(t, err, worker.ErrLocked)
+
+	test.CheckErr(t, lock.Unlock(ctx))
+
+	lock, err = l2.Lock(ctx, key)
+	test.CheckErr(t, err)
+	test.CheckErr(t, lock.Unlock(ctx))
+	l2.Close()
+}
+
+func TestLockerLockLost(t *testing.T) {
+	ctx := context.Background()
+	key := "TEST:" + random.String()
+
+	l1 := worker.NewLocker(tt.DB)
+	l1.CheckInterval = 100 * time.Millisecond
+	l2 := worker.NewLocker(tt.DB)
+	t.Cleanup(l2.Close)
+
+	lock, err := l1.Lock(ctx, key)
+	test.CheckErr(t, err)
+	jobCtx := l1.Context(ctx, key)
+
+	// kill the session holding the lock, as if the connection was lost;
+	// a bigint advisory lock ID is split into classid (high half) and objid (low half)
+	_, err = tt.DB.Exec(ctx, `SELECT pg_terminate_backend(pid) FROM pg_locks
+		WHERE locktype = 'advisory' AND objsubid = 1
+			AND classid = (($1::bigint >> 32) & 4294967295)::oid
+			AND objid = ($1::bigint & 4294967295)::oid`, worker.LockID(key))
+	test.CheckErr(t, err)
+
+	select {
+	case <-jobCtx.Done():
+		assert.ErrorIs(t, context.Cause(jobCtx), worker.ErrLockLost)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job context is not canceled after lock is lost")
+	}
+
+	// unlocking the lost lock is a no-op
+	test.CheckErr(t, lock.Unlock(ctx))
+
+	// lock is free for others
+	_, err = l2.Lock(ctx, key)
+	test.CheckErr(t, err)
+}
@@ -0,0 +1,93 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
+	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/test"
+	"github.com/gopl-dev/server/test/factory/random"
+	"github.com/gopl-dev/server/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+type testJob struct {
+	name     string
+	items    int
+	err      error
+	schedule gocron.JobDefinition
+	runs     *atomic.Int32
+	onRun    func()
+}
+
+func (j testJob) Name() string {
+	return j.name
+}
+
+func (j testJob) Schedule() gocron.JobDefinition {
+	if j.schedule != nil {
+		return j.schedule
+	}
+
+	return gocron.DurationJob(time.Hour)
+}
+
+func (j testJob) Do(ctx context.Context, _ *service.Service, _ *app.DB) error {
+	if j.runs != nil {
+		j.runs.Add(1)
+	}
+	if j.onRun != nil {
+		j.onRun()
+	}
+
+	ds.AddProcessedItems(ctx, j.items)
+	return j.err
+}
+
+func TestRunRecordsJobRun(t *testing.T) {
+	job := testJob{name: "TEST:" + random.String(), items: 3}
+
+	err := worker.Run(context.Background(), tt.Service, tt.DB, job, ds.WorkerJobTriggerCLI)
+	test.CheckErr(t, err)
+
+	test.AssertInDB(t, tt.DB, "worker_job_runs", test.Data{
+		"job_name":        job.name,
+		"trigger":         ds.WorkerJobTriggerCLI,
+		"status":          ds.WorkerJobRunSucceeded,
+		"items_processed": 3,
+		"error":           "",
+		"finished_at":     test.NotNull,
+	})
+
+	t.Run("failed run", func(t *testing.T) {
+		job := testJob{name: "TEST:" + random.String(), err: errors.New("boom")}
+
+		err := worker.Run(context.Background(), tt.Service, tt.DB, job, ds.WorkerJobTriggerManual)
+		assert.ErrorIs(t, err, job.err)
+
+		test.AssertInDB(t, tt.DB, "worker_job_runs", test.Data{
+			"job_name":    job.name,
+			"trigger":     ds.WorkerJobTriggerManual,
+			"status":      ds.WorkerJobRunFailed,
+			"error":       "boom",
+			"finished_at": test.NotNull,
+		})
+	})
+}
+
+func TestFindJob(t *testing.T) {
+	for _, j := range worker.Jobs() {
+		found, err := worker.FindJob(j.Name())
+		test.CheckErr(t, err)
+		assert.Equal(t, j.Name(), found.Name())
+	}
+
+	_, err := worker.FindJob("UNKNOWN")
+	assert.ErrorIs(t, err, worker.ErrJobNotFound)
+}
@@ -2,7 +2,7 @@ package tracing
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gopl-dev/server/app"
@@ -37,7 +37,7 @@ func NewUptraceTracer(ctx context.Context) trace.Tracer {
 		// Non-inherited new context, use function like `context.WithXXX` instead (contextcheck)
 		err := uptrace.Shutdown(shutdownCtx)
 		if err != nil {
-			log.Printf("uptrace.Shutdown failed: %v\n", err)
+			slog.Error("uptrace shutdown", "error", err)
 		}
 	}()
 
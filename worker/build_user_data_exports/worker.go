@@ -0,0 +1,35 @@
+// Package builduserdataexports ...
+package builduserdataexports
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for building requested user data exports.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "BUILD:USER_DATA_EXPORTS"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run at the start of every minute, so users get their data shortly after the request.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.CronJob("* * * * *", false)
+}
+
+// Do executes the job's task, which is to build the archives of pending
+// user data exports and email the download links.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.ProcessPendingUserDataExports(ctx)
+}
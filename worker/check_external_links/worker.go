@@ -0,0 +1,35 @@
+// Package checkexternallinks ...
+package checkexternallinks
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for checking external links for broken ones.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "CHECK:EXTERNAL_LINKS"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run every hour, at half past.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.CronJob("30 * * * *", false)
+}
+
+// Do executes the job's task, which is to collect the links to other sites found in books,
+// authors and pages, and to check those which check is due, respecting robots.txt and rate limits of the sites.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.CheckDueExternalLinks(ctx)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/service"
 )
 
@@ -32,7 +33,12 @@ func (w Job) Schedule() gocron.JobDefinition {
 
 // Do executes the job's task, which is to delete all records
 // from the change_email_requests table where the expiration date is in the past.
-func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) (err error) {
-	_, err = db.Exec(ctx, "DELETE FROM change_email_requests WHERE expires_at < NOW()")
-	return
+func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) error {
+	tag, err := db.Exec(ctx, "DELETE FROM change_email_requests WHERE expires_at < NOW()")
+	if err != nil {
+		return err
+	}
+
+	ds.AddProcessedItems(ctx, int(tag.RowsAffected()))
+	return nil
 }
@@ -5,6 +5,7 @@ package cleanupdeletedbooks
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
@@ -53,7 +54,7 @@ processBatch:
 		return nil
 	}
 
-	println("[CLEANUP-BOOKS]:", count, "books about to be permanently removed from system")
+	slog.InfoContext(ctx, "books about to be permanently removed from system", "count", count)
 
 	ids := make([]ds.ID, len(books))
 	fileIDs := make([]ds.ID, 0)
@@ -100,6 +101,8 @@ processBatch:
 		return
 	}
 
+	ds.AddProcessedItems(ctx, len(ids))
+
 	if count > batchSize {
 		goto processBatch
 	}
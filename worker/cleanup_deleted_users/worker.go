@@ -52,5 +52,11 @@ func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error)
 		})
 	}
 
-	return eg.Wait()
+	err = eg.Wait()
+	if err != nil {
+		return
+	}
+
+	ds.AddProcessedItems(ctx, len(users))
+	return nil
 }
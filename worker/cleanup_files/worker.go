@@ -4,6 +4,7 @@ package cleanupfiles
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
@@ -52,7 +53,7 @@ processBatch:
 		return
 	}
 
-	println("[CLEANUP-FILES]:", count, "files about to be removed from system")
+	slog.InfoContext(ctx, "files about to be removed from system", "count", count)
 
 	for _, f := range files {
 		// detach from entities
@@ -72,6 +73,8 @@ processBatch:
 		if err != nil {
 			return err
 		}
+
+		ds.AddProcessedItems(ctx, 1)
 	}
 
 	if count > batchSize {
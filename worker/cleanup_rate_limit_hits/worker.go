@@ -0,0 +1,37 @@
+// Package cleanupratelimithits ...
+package cleanupratelimithits
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for cleaning up stale rate limit hits.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "CLEANUP:RATE_LIMIT_HITS"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run once daily at 1 AM.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.DailyJob(1,
+		gocron.NewAtTimes(gocron.NewAtTime(1, 0, 0)),
+	)
+}
+
+// Do executes the job's task, which is to delete rate limit hits
+// that are too old to affect any rule.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.CleanupRateLimitHits(ctx)
+}
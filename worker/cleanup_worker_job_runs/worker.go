@@ -0,0 +1,36 @@
+// Package cleanupworkerjobruns ...
+package cleanupworkerjobruns
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for cleaning up the history of job runs.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "CLEANUP:WORKER_JOB_RUNS"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run once daily at 3 AM.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.DailyJob(1,
+		gocron.NewAtTimes(gocron.NewAtTime(3, 0, 0)), //nolint:mnd
+	)
+}
+
+// Do executes the job's task, which is to delete job runs older than ds.CleanupWorkerJobRunsAfterDays.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.CleanupWorkerJobRuns(ctx)
+}
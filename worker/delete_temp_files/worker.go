@@ -32,8 +32,12 @@ func (w Job) Schedule() gocron.JobDefinition {
 }
 
 // Do mark temp files as deleted after ds.DeleteTempFilesAfterDays.
-func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) (err error) {
-	_, err = db.Exec(ctx, "UPDATE files SET deleted_at = NOW() WHERE temp IS TRUE AND created_at < NOW() - ($1 * INTERVAL '1 day') AND deleted_at IS NULL", ds.DeleteTempFilesAfterDays)
-
-	return err
+func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) error {
+	tag, err := db.Exec(ctx, "UPDATE files SET deleted_at = NOW() WHERE temp IS TRUE AND created_at < NOW() - ($1 * INTERVAL '1 day') AND deleted_at IS NULL", ds.DeleteTempFilesAfterDays)
+	if err != nil {
+		return err
+	}
+
+	ds.AddProcessedItems(ctx, int(tag.RowsAffected()))
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/service"
 )
 
@@ -31,10 +32,15 @@ func (w Job) Schedule() gocron.JobDefinition {
 }
 
 // Do mark unconfirmed users as deleted (Another job will take care of cleanup of deleted users).
-func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) (err error) {
-	_, err = db.Exec(ctx,
+func (w Job) Do(ctx context.Context, _ *service.Service, db *app.DB) error {
+	tag, err := db.Exec(ctx,
 		"UPDATE users SET deleted_at = created_at "+
 			"WHERE email_confirmed IS FALSE AND deleted_at IS NULL "+
 			"AND created_at < NOW() - INTERVAL '24 hours'")
-	return
+	if err != nil {
+		return err
+	}
+
+	ds.AddProcessedItems(ctx, int(tag.RowsAffected()))
+	return nil
 }
@@ -0,0 +1,35 @@
+// Package deliverwebhooks ...
+package deliverwebhooks
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for sending webhook deliveries.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "DELIVER:WEBHOOKS"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run at the start of every minute.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.CronJob("* * * * *", false)
+}
+
+// Do executes the job's task, which is to send pending webhook deliveries
+// which attempt is due, rescheduling the failed ones.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.ProcessDueWebhookDeliveries(ctx)
+}
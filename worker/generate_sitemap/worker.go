@@ -0,0 +1,35 @@
+// Package generatesitemap ...
+package generatesitemap
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/service"
+)
+
+// Job implements the worker.Job interface for regenerating the sitemap.
+type Job struct{}
+
+// NewJob ...
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Name returns the unique name of the job.
+func (w Job) Name() string {
+	return "GENERATE:SITEMAP"
+}
+
+// Schedule defines when the job should run.
+// This job is scheduled to run at the start of every hour.
+func (w Job) Schedule() gocron.JobDefinition {
+	return gocron.CronJob("0 * * * *", false)
+}
+
+// Do executes the job's task, which is to write the sitemap of the public content
+// into the file storage.
+func (w Job) Do(ctx context.Context, s *service.Service, _ *app.DB) (err error) {
+	return s.GenerateSitemap(ctx)
+}
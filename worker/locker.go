@@ -0,0 +1,241 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/gopl-dev/server/app"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrLocked is returned when the job is being run by another scheduler.
+	ErrLocked = errors.New("job is locked by another scheduler")
+
+	// ErrLockLost is the cause of the job context cancellation
+	// when the connection holding the lock is lost.
+	ErrLockLost = errors.New("job lock lost")
+)
+
+const (
+	// defaultLockMinHold is how long a lock is held at least, even if the job finishes earlier.
+	// It covers the time splay between schedulers, so a scheduler which clock
+	// is a bit behind doesn't start the run which is already done.
+	defaultLockMinHold = 10 * time.Second
+
+	// defaultLockCheckInterval defines how often the connection holding the lock is checked.
+	defaultLockCheckInterval = 5 * time.Second
+)
+
+// Locker implements gocron.Locker with Postgres session-level advisory locks,
+// so a job run is executed by one scheduler only when several server replicas share a database.
+//
+// The lock is held by a dedicated connection for the duration of the run.
+// The connection is opened with the settings of the database pool, but it's not taken from the pool:
+// jobs started at the same time would take all the connections of the pool with their locks,
+// leaving none to the jobs themselves.
+// If the connection is lost, Postgres releases the lock; the locker notices that
+// and cancels the context returned by Context, so the job can stop gracefully.
+type Locker struct {
+	db *app.DB
+
+	// MinHold is how long a lock is held at least. See defaultLockMinHold.
+	MinHold time.Duration
+
+	// CheckInterval defines how often the connection holding the lock is checked.
+	CheckInterval time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*advisoryLock
+}
+
+// NewLocker returns a Locker backed by the given database.
+func NewLocker(db *app.DB) *Locker {
+	return &Locker{
+		db:            db,
+		MinHold:       defaultLockMinHold,
+		CheckInterval: defaultLockCheckInterval,
+		locks:         map[string]*advisoryLock{},
+	}
+}
+
+// Lock tries to obtain the lock of the key without waiting.
+// ErrLocked is returned if the lock is held by another scheduler (or a previous run is still holding it).
+func (l *Locker) Lock(ctx context.Context, key string) (gocron.Lock, error) {
+	l.mu.Lock()
+	_, held := l.locks[key]
+	l.mu.Unlock()
+	if held {
+		return nil, ErrLocked
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, l.db.Config().ConnConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", LockID(key)).Scan(&ok)
+	if err != nil {
+		_ = conn.Close(ctx)
+		return nil, err
+	}
+	if !ok {
+		_ = conn.Close(ctx)
+		return nil, ErrLocked
+	}
+
+	lockCtx, cancel := context.WithCancelCause(context.Background())
+	lock := &advisoryLock{
+		locker:     l,
+		key:        key,
+		conn:       conn,
+		acquiredAt: time.Now(),
+		ctx:        lockCtx,
+		cancel:     cancel,
+	}
+
+	l.mu.Lock()
+	l.locks[key] = lock
+	l.mu.Unlock()
+
+	go lock.watch(l.CheckInterval)
+
+	return lock, nil
+}
+
+// Context returns a copy of ctx that is canceled with ErrLockLost as the cause
+// when the lock of the key is lost. If the key is not locked, ctx is returned as is.
+func (l *Locker) Context(ctx context.Context, key string) context.Context {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	l.mu.Unlock()
+	if !ok {
+		return ctx
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	context.AfterFunc(lock.ctx, func() {
+		cancel(context.Cause(lock.ctx))
+	})
+
+	return ctx
+}
+
+// Close releases all locks immediately, including the ones kept for MinHold.
+func (l *Locker) Close() {
+	l.mu.Lock()
+	locks := make([]*advisoryLock, 0, len(l.locks))
+	for _, lock := range l.locks {
+		locks = append(locks, lock)
+	}
+	l.mu.Unlock()
+
+	for _, lock := range locks {
+		lock.release(context.Background(), nil)
+	}
+}
+
+// LockID maps the key into the 64-bit space of advisory lock IDs.
+func LockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("worker:" + key))
+
+	return int64(h.Sum64()) //nolint:gosec
+}
+
+type advisoryLock struct {
+	locker     *Locker
+	key        string
+	acquiredAt time.Time
+
+	// connMu serializes the use of conn by watch and release, as a connection is not safe for concurrent use
+	connMu sync.Mutex
+	conn   *pgx.Conn
+
+	// ctx is canceled once the lock is released or lost
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelCauseFunc
+
+	once sync.Once
+}
+
+// Unlock releases the lock once MinHold has passed since it was obtained.
+func (lock *advisoryLock) Unlock(_ context.Context) error {
+	wait := lock.locker.MinHold - time.Since(lock.acquiredAt)
+	if wait <= 0 {
+		lock.release(context.Background(), nil)
+		return nil
+	}
+
+	time.AfterFunc(wait, func() {
+		lock.release(context.Background(), nil)
+	})
+
+	return nil
+}
+
+// watch checks the connection holding the lock until the lock is released.
+func (lock *advisoryLock) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lock.ctx.Done():
+			return
+		case <-ticker.C:
+			err := lock.ping(interval)
+			if err != nil {
+				slog.Error("job lock lost", "job", lock.key, "error", err)
+				lock.release(context.Background(), ErrLockLost)
+				return
+			}
+		}
+	}
+}
+
+// ping checks the connection holding the lock, unless the lock is already released.
+// The ping is limited by timeout, so a broken connection doesn't keep release waiting.
+func (lock *advisoryLock) ping(timeout time.Duration) error {
+	lock.connMu.Lock()
+	defer lock.connMu.Unlock()
+
+	if lock.ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(lock.ctx, timeout)
+	defer cancel()
+
+	return lock.conn.Ping(ctx)
+}
+
+// release unlocks and closes the connection; cause is nil unless the lock is lost.
+func (lock *advisoryLock) release(ctx context.Context, cause error) {
+	lock.once.Do(func() {
+		lock.connMu.Lock()
+		defer lock.connMu.Unlock()
+
+		lock.cancel(cause)
+
+		if cause == nil {
+			_, err := lock.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", LockID(lock.key))
+			if err != nil {
+				slog.Error("unlock job", "job", lock.key, "error", err)
+			}
+		}
+
+		// closing the session releases the lock anyway
+		_ = lock.conn.Close(ctx)
+
+		lock.locker.mu.Lock()
+		delete(lock.locker.locks, lock.key)
+		lock.locker.mu.Unlock()
+	})
+}
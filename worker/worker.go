@@ -4,18 +4,37 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
 	"github.com/gopl-dev/server/app"
+	"github.com/gopl-dev/server/app/ds"
 	"github.com/gopl-dev/server/app/service"
+	"github.com/gopl-dev/server/logging"
+	"github.com/gopl-dev/server/metrics"
 	"github.com/gopl-dev/server/tracing"
+	"github.com/gopl-dev/server/worker/build_user_data_exports"
+	"github.com/gopl-dev/server/worker/check_external_links"
 	"github.com/gopl-dev/server/worker/cleanup_change_email_requests"
+	"github.com/gopl-dev/server/worker/cleanup_deleted_books"
 	"github.com/gopl-dev/server/worker/cleanup_deleted_users"
 	"github.com/gopl-dev/server/worker/cleanup_expired_password_change_requests"
+	"github.com/gopl-dev/server/worker/cleanup_expired_user_data_exports"
 	"github.com/gopl-dev/server/worker/cleanup_expired_user_sessions"
+	"github.com/gopl-dev/server/worker/cleanup_files"
+	"github.com/gopl-dev/server/worker/cleanup_rate_limit_hits"
+	"github.com/gopl-dev/server/worker/cleanup_worker_job_runs"
 	"github.com/gopl-dev/server/worker/delete_temp_files"
 	"github.com/gopl-dev/server/worker/delete_unconfirmed_users"
+	"github.com/gopl-dev/server/worker/deliver_webhooks"
+	"github.com/gopl-dev/server/worker/generate_sitemap"
 )
 
 // List of registered jobs.
@@ -25,7 +44,16 @@ var jobs = []Job{
 	deleteunconfirmedusers.NewJob(),
 	cleanupexpiredusersessions.NewJob(),
 	cleanupdeletedusers.NewJob(),
+	cleanupdeletedbooks.NewJob(),
+	cleanupfiles.NewJob(),
 	deletetempfiles.NewJob(),
+	cleanupratelimithits.NewJob(),
+	builduserdataexports.NewJob(),
+	cleanupexpireduserdataexports.NewJob(),
+	generatesitemap.NewJob(),
+	deliverwebhooks.NewJob(),
+	cleanupworkerjobruns.NewJob(),
+	checkexternallinks.NewJob(),
 }
 
 // Job defines the interface for a background worker job.
@@ -35,12 +63,85 @@ type Job interface {
 	Name() string
 
 	// Schedule defines when the job should run.
+	// It must be aligned to the clock (e.g. gocron.DailyJob or gocron.CronJob), so the schedulers
+	// of all replicas plan the same run times and the lock lets only one of them run it.
+	// A gocron.DurationJob is timed from the start of each scheduler and would run once per replica.
 	Schedule() gocron.JobDefinition
 
 	// Do executes the job's task.
 	Do(ctx context.Context, s *service.Service, db *app.DB) error
 }
 
+// ErrJobNotFound is returned when there is no registered job with the given name.
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobRunning is returned when the job is already running in this process.
+var ErrJobRunning = errors.New("job is already running")
+
+// requestedRunsPollInterval defines how often the worker checks for runs requested by admins.
+const requestedRunsPollInterval = 10 * time.Second
+
+// running holds a lock for every job that is currently running in this process,
+// so scheduled, requested and CLI runs of the same job don't overlap.
+var running sync.Map // job name => *sync.Mutex
+
+// Jobs returns all registered jobs.
+func Jobs() []Job {
+	return slices.Clone(jobs)
+}
+
+// FindJob returns the registered job with the given name.
+func FindJob(name string) (Job, error) {
+	for _, j := range jobs {
+		if j.Name() == name {
+			return j, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+}
+
+// Run executes the job once and stores the record of the run: its duration, outcome,
+// and the number of items processed, as reported with ds.AddProcessedItems.
+// The error returned is the one of the job, or ErrJobRunning if it's already running.
+func Run(ctx context.Context, s *service.Service, db *app.DB, j Job, trigger ds.WorkerJobTrigger) error {
+	v, _ := running.LoadOrStore(j.Name(), &sync.Mutex{})
+	mu := v.(*sync.Mutex) //nolint:forcetypeassert
+	if !mu.TryLock() {
+		return ErrJobRunning
+	}
+	defer mu.Unlock()
+
+	ctx = logging.WithAttrs(ctx, "job", j.Name())
+
+	run, err := s.StartWorkerJobRun(ctx, j.Name(), trigger)
+	if err != nil {
+		return fmt.Errorf("start run: %w", err)
+	}
+
+	ctx = logging.WithAttrs(ctx, "run_id", run.ID.String())
+	slog.InfoContext(ctx, "job started", "trigger", trigger)
+
+	start := time.Now()
+	jobErr := j.Do(run.ToContext(ctx), s, db)
+	dur := time.Since(start)
+	metrics.WorkerJobRun(j.Name(), dur, jobErr)
+
+	// the outcome is recorded even if the job was interrupted by shutdown
+	err = s.FinishWorkerJobRun(context.WithoutCancel(ctx), run, jobErr)
+	if err != nil {
+		slog.ErrorContext(ctx, "finish job run", "error", err)
+	}
+
+	if jobErr != nil {
+		slog.ErrorContext(ctx, "job failed", "duration", dur, "items_processed", run.ItemsProcessed, "error", jobErr)
+	} else {
+		slog.InfoContext(ctx, "job finished", "duration", dur, "items_processed", run.ItemsProcessed)
+	}
+
+	return jobErr
+}
+
 // Start initializes and starts the background worker scheduler.
 // It sets up the database connection, tracer, and registers all defined jobs.
 // The scheduler runs until the provided context is canceled.
@@ -50,47 +151,186 @@ func Start(ctx context.Context) error {
 		log.Fatal(err)
 	}
 
-	s, err := gocron.NewScheduler()
+	db, err := app.NewDB(ctx)
 	if err != nil {
 		return err
 	}
 
-	db, err := app.NewDB(ctx)
+	services, err := service.New(db, tracer)
 	if err != nil {
 		return err
 	}
 
-	services := service.New(db, tracer)
+	s, err := NewScheduler(ctx, services, db, jobs)
+	if err != nil {
+		return err
+	}
+
+	s.Start()
+
+	go func() {
+		<-ctx.Done()
+
+		err := s.Shutdown()
+		if err != nil {
+			slog.Error("worker shutdown", "error", err)
+		}
+
+		db.Close()
+	}()
+
+	return nil
+}
+
+// Scheduler runs jobs on their schedules and the runs requested by admins.
+// Schedulers of all server replicas connected to the same database coordinate with
+// advisory locks, so every job run is executed by one of them only.
+type Scheduler struct {
+	gocron.Scheduler
+
+	// Locker is the distributed locker of job runs.
+	Locker *Locker
+
+	ctx      context.Context //nolint:containedctx
+	services *service.Service
+	db       *app.DB
+	jobs     []Job
+}
+
+// NewScheduler creates a scheduler of the given jobs. It is not started.
+func NewScheduler(ctx context.Context, services *service.Service, db *app.DB, jobs []Job) (*Scheduler, error) {
+	locker := NewLocker(db)
+
+	gs, err := gocron.NewScheduler(
+		// replicas must plan the same run times whatever their local time zone is
+		gocron.WithLocation(time.UTC),
+		gocron.WithDistributedLocker(locker),
+		gocron.WithGlobalJobOptions(gocron.WithEventListeners(
+			gocron.AfterLockError(func(_ uuid.UUID, name string, err error) {
+				slog.Info("job skipped", "job", name, "reason", err)
+			}),
+		)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		Scheduler: gs,
+		Locker:    locker,
+		ctx:       ctx,
+		services:  services,
+		db:        db,
+		jobs:      jobs,
+	}
+
+	names := make([]string, len(jobs))
+	for i, j := range jobs {
+		names[i] = j.Name()
+	}
+
+	err = services.RegisterWorkerJobs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, j := range jobs {
-		_, err = s.NewJob(j.Schedule(),
+		_, err = gs.NewJob(j.Schedule(),
 			gocron.NewTask(func() {
-				println("[WORKER] Executing job:", j.Name())
-				err := j.Do(ctx, services, db)
-				if err != nil {
-					println("[WORKER] [ERROR]", err.Error())
-				}
+				s.runScheduled(j)
 			}),
+			gocron.WithName(j.Name()),
+			// a run that takes longer than the interval must not overlap with the next one
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
 		)
 		if err != nil {
-			println("[WORKER]", j.Name(), err.Error())
-			return err
+			return nil, fmt.Errorf("register job %s: %w", j.Name(), err)
 		}
-		println(j.Name(), "worker's job registered")
+		slog.Debug("job registered", "job", j.Name())
 	}
 
-	s.Start()
+	_, err = gs.NewJob(gocron.DurationJob(requestedRunsPollInterval),
+		gocron.NewTask(s.RunRequested),
+		// requests are taken atomically, each run is locked on its own
+		gocron.WithDisabledDistributedJobLocker(true),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	go func() {
-		<-ctx.Done()
+	return s, nil
+}
 
-		db.Close()
+// Shutdown stops the scheduler, waiting for running jobs, and releases the locks.
+func (s *Scheduler) Shutdown() error {
+	err := s.Scheduler.Shutdown()
+	s.Locker.Close()
 
-		err = s.Shutdown()
-		if err != nil {
-			println("[WORKER] [SHUTDOWN]", err.Error())
+	return err
+}
+
+// runScheduled runs the job unless it is paused.
+// It's called by gocron with the lock of the job held.
+func (s *Scheduler) runScheduled(j Job) {
+	ctx := s.Locker.Context(s.ctx, j.Name())
+
+	state, err := s.services.GetWorkerJob(ctx, j.Name())
+	if err != nil {
+		slog.ErrorContext(ctx, "get job state", "job", j.Name(), "error", err)
+		return
+	}
+
+	if state.Paused {
+		slog.DebugContext(ctx, "job is paused", "job", j.Name())
+		return
+	}
+
+	// failures are logged by Run
+	_ = Run(ctx, s.services, s.db, j, ds.WorkerJobTriggerSchedule)
+}
+
+// RunRequested runs jobs requested by admins, each in its own goroutine.
+// A request is taken off the queue, so if the job is already running (here or on another replica),
+// a skipped run is recorded instead. It's called by the scheduler every requestedRunsPollInterval.
+func (s *Scheduler) RunRequested() {
+	names, err := s.services.TakeRequestedWorkerJobs(s.ctx)
+	if err != nil {
+		slog.ErrorContext(s.ctx, "take requested job runs", "error", err)
+		return
+	}
+
+	for _, name := range names {
+		idx := slices.IndexFunc(s.jobs, func(j Job) bool { return j.Name() == name })
+		if idx < 0 {
+			slog.ErrorContext(s.ctx, "requested job run", "job", name, "error", ErrJobNotFound)
+			continue
 		}
-	}()
+		j := s.jobs[idx]
 
-	return nil
+		go func() {
+			lock, err := s.Locker.Lock(s.ctx, j.Name())
+			if err != nil {
+				s.skipRequested(j, err)
+				return
+			}
+			defer func() { _ = lock.Unlock(s.ctx) }()
+
+			// failures are logged by Run
+			err = Run(s.Locker.Context(s.ctx, j.Name()), s.services, s.db, j, ds.WorkerJobTriggerManual)
+			if errors.Is(err, ErrJobRunning) {
+				s.skipRequested(j, err)
+			}
+		}()
+	}
+}
+
+// skipRequested records that the requested run of the job didn't start.
+func (s *Scheduler) skipRequested(j Job, reason error) {
+	slog.InfoContext(s.ctx, "requested job skipped", "job", j.Name(), "reason", reason)
+
+	err := s.services.SkipWorkerJobRun(s.ctx, j.Name(), ds.WorkerJobTriggerManual, reason)
+	if err != nil {
+		slog.ErrorContext(s.ctx, "record skipped job run", "job", j.Name(), "error", err)
+	}
 }